@@ -1,16 +1,30 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"time"
 
+	"AbdelrahmanDwedar/blogo/internal/config"
 	deliveryHttp "AbdelrahmanDwedar/blogo/internal/delivery/http"
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+	"AbdelrahmanDwedar/blogo/internal/domain/service"
 	"AbdelrahmanDwedar/blogo/internal/infrastructure/cache"
 	"AbdelrahmanDwedar/blogo/internal/infrastructure/database"
+	"AbdelrahmanDwedar/blogo/internal/infrastructure/federation"
+	"AbdelrahmanDwedar/blogo/internal/infrastructure/importer"
+	"AbdelrahmanDwedar/blogo/internal/infrastructure/mail"
+	"AbdelrahmanDwedar/blogo/internal/infrastructure/media"
+	"AbdelrahmanDwedar/blogo/internal/infrastructure/push"
+	"AbdelrahmanDwedar/blogo/internal/infrastructure/realtime"
+	"AbdelrahmanDwedar/blogo/internal/infrastructure/sitegen"
+	"AbdelrahmanDwedar/blogo/internal/infrastructure/webmention"
 	"AbdelrahmanDwedar/blogo/internal/usecase"
+	"AbdelrahmanDwedar/blogo/internal/worker"
 	"AbdelrahmanDwedar/blogo/pkg/auth"
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
@@ -22,6 +36,7 @@ func main() {
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using system environment variables")
 	}
+	cfg := config.Load()
 
 	// Initialize database
 	db, err := database.NewPostgresDB()
@@ -44,13 +59,154 @@ func main() {
 	// Initialize repositories
 	userRepo := database.NewUserRepository(db)
 	blogRepo := database.NewBlogRepository(db)
+	jobRepo := database.NewJobRepository(db)
+	commentRepo := database.NewCommentRepository(db)
+	mentionRepo := database.NewMentionRepository(db)
+	notificationRepo := database.NewNotificationRepository(db)
+	recommendationRepo := database.NewRecommendationRepository(db)
+	federationRepo := database.NewFederationRepository(db)
+	webmentionRepo := database.NewWebmentionRepository(db)
+	tokenRepo := database.NewAccessTokenRepository(db)
+	newsletterRepo := database.NewNewsletterRepository(db)
+	digestRepo := database.NewDigestRepository(db)
+	preferenceRepo := database.NewPreferenceRepository(db)
+	pushRepo := database.NewPushRepository(db)
+	bookmarkRepo := database.NewBookmarkRepository(db)
+
+	// Real-time events go through Redis when available so every API
+	// instance sees them, and stay in-process otherwise
+	var broker service.EventBroker = realtime.NewMemoryBroker()
+	var redisBroker *realtime.RedisBroker
+	if redisCache != nil {
+		redisBroker = realtime.NewRedisBroker(redisCache.Client())
+		broker = redisBroker
+	}
+
+	// Emails go through an SMTP server when one is configured and are
+	// written to files otherwise
+	var mailer service.Mailer
+	if cfg.SMTPHost != "" {
+		mailer, err = mail.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	} else {
+		log.Printf("📧 No SMTP_HOST set, writing emails to %s\n", cfg.MailDir)
+		mailer, err = mail.NewFileMailer(cfg.MailDir, cfg.MailFrom)
+	}
+	if err != nil {
+		log.Fatal("Failed to set up mailer:", err)
+	}
+
+	// Trending blogs are kept in Redis when available and in the database
+	// otherwise
+	var trendingRepo repository.TrendingRepository = database.NewTrendingRepository(db)
+	if redisCache != nil {
+		trendingRepo = redisCache
+	}
 
 	// Initialize use cases
 	userUC := usecase.NewUserUseCase(userRepo, redisCache)
-	blogUC := usecase.NewBlogUseCase(blogRepo, redisCache)
+	blogUC := usecase.NewBlogUseCase(blogRepo, userRepo, redisCache, cfg.ReactionKinds)
+	commentUC := usecase.NewCommentUseCase(commentRepo, blogRepo, userRepo, redisCache, cfg.CommentMaxDepth)
+	preferenceUC := usecase.NewPreferenceUseCase(preferenceRepo)
+	notificationUC := usecase.NewNotificationUseCase(notificationRepo, userRepo, blogRepo, commentRepo, preferenceUC)
+	mentionUC := usecase.NewMentionUseCase(mentionRepo, userRepo, blogRepo, notificationUC)
+	timelineUC := usecase.NewTimelineUseCase(blogRepo, userRepo, redisCache)
+	importUC := usecase.NewImportUseCase(blogRepo, blogUC, importer.NewParser())
+	streamUC := usecase.NewStreamUseCase(broker, userRepo)
+	trendingUC := usecase.NewTrendingUseCase(blogRepo, userRepo, trendingRepo)
+	recommendationUC := usecase.NewRecommendationUseCase(recommendationRepo, redisCache, cfg.RecommendationInterval)
+	federationUC := usecase.NewFederationUseCase(federationRepo, userRepo, blogRepo, jobRepo,
+		federation.NewClient(cfg.AllowPrivateNetworks), cfg.PublicURL)
+	syndicationUC := usecase.NewSyndicationUseCase(blogRepo, userRepo, redisCache, cfg.PublicURL)
+	webmentionUC := usecase.NewWebmentionUseCase(webmentionRepo, blogRepo, userRepo, jobRepo,
+		webmention.NewClient(cfg.AllowPrivateNetworks), cfg.PublicURL)
+	tokenUC := usecase.NewAccessTokenUseCase(tokenRepo)
+	micropubUC := usecase.NewMicropubUseCase(blogUC, cfg.PublicURL)
+	metaWeblogUC := usecase.NewMetaWeblogUseCase(blogUC, tokenUC, userRepo,
+		media.NewDirStore(cfg.MediaDir), cfg.PublicURL)
+	mailRenderer := mail.NewRenderer()
+	newsletterUC := usecase.NewNewsletterUseCase(newsletterRepo, userRepo, blogRepo, jobRepo,
+		mailer, mailRenderer, cfg.PublicURL)
+	digestUC := usecase.NewDigestUseCase(digestRepo, userRepo, newsletterRepo, jobRepo, preferenceUC,
+		mailer, mailRenderer, cfg.PublicURL)
+	notificationEmailUC := usecase.NewNotificationEmailUseCase(userRepo, blogRepo, commentRepo, newsletterRepo,
+		jobRepo, preferenceUC, mailer, mailRenderer, cfg.PublicURL)
+	pushUC := usecase.NewPushUseCase(pushRepo, userRepo, blogRepo, jobRepo, preferenceUC,
+		push.NewClient(cfg.VAPIDSubject, cfg.AllowPrivateNetworks), cfg.PublicURL)
+	bookmarkUC := usecase.NewBookmarkUseCase(bookmarkRepo, blogRepo, userRepo)
+	exportUC := usecase.NewExportUseCase(blogRepo, userRepo, jobRepo,
+		sitegen.NewRenderer(), sitegen.NewZipStore(cfg.ExportDir))
+
+	// Wire side effects of blog and comment changes
+	blogUC.Subscribe(mentionUC)
+	commentUC.Subscribe(mentionUC)
+	blogUC.Subscribe(timelineUC)
+	userUC.Subscribe(timelineUC)
+	userUC.Subscribe(notificationUC)
+	userUC.Subscribe(recommendationUC)
+	blogUC.SubscribeReactions(notificationUC)
+	commentUC.Subscribe(notificationUC)
+	notificationUC.Subscribe(entity.ChannelInApp, streamUC)
+	notificationUC.Subscribe(entity.ChannelEmail, notificationEmailUC)
+	notificationUC.Subscribe(entity.ChannelPush, pushUC)
+	blogUC.Subscribe(streamUC)
+	blogUC.Subscribe(federationUC)
+	blogUC.Subscribe(syndicationUC)
+	userUC.SubscribeProfiles(syndicationUC)
+	blogUC.Subscribe(webmentionUC)
+	blogUC.Subscribe(newsletterUC)
+
+	// Start background jobs
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if redisBroker != nil {
+		go redisBroker.Run(ctx)
+	}
+
+	go worker.Every(ctx, "purge-trash", cfg.TrashPurgeInterval, func() error {
+		purged, err := blogUC.PurgeTrash(cfg.TrashRetention)
+		if err == nil && purged > 0 {
+			log.Printf("🗑️  Purged %d blogs from trash\n", purged)
+		}
+		return err
+	})
+
+	go worker.Every(ctx, "refresh-recommendations", cfg.RecommendationInterval, func() error {
+		refreshed, err := recommendationUC.RefreshRecommendations()
+		if err == nil && refreshed > 0 {
+			log.Printf("🤝 Refreshed recommendations for %d users\n", refreshed)
+		}
+		return err
+	})
+
+	go worker.Every(ctx, "refresh-trending", cfg.TrendingInterval, trendingUC.RefreshTrending)
+
+	go worker.Every(ctx, "schedule-digests", cfg.DigestInterval, func() error {
+		queued, err := digestUC.ScheduleDigests()
+		if queued > 0 {
+			log.Printf("📬 Queued %d digests\n", queued)
+		}
+		return err
+	})
+
+	queue := worker.NewQueue(jobRepo)
+	queue.Handle(entity.JobExportSite, exportUC.RunExportJob)
+	queue.Handle(entity.JobDeliverActivity, federationUC.RunDeliveryJob)
+	queue.Handle(entity.JobSendWebmention, webmentionUC.RunSendJob)
+	queue.Handle(entity.JobVerifyWebmention, webmentionUC.RunVerifyJob)
+	queue.Handle(entity.JobSendConfirmation, newsletterUC.RunSendConfirmationJob)
+	queue.Handle(entity.JobSendNewsletters, newsletterUC.RunSendNewslettersJob)
+	queue.Handle(entity.JobSendNewsletter, newsletterUC.RunSendNewsletterJob)
+	queue.Handle(entity.JobSendDigest, digestUC.RunSendDigestJob)
+	queue.Handle(entity.JobSendNotification, notificationEmailUC.RunSendJob)
+	queue.Handle(entity.JobSendPush, pushUC.RunSendJob)
+	go queue.Run(ctx, cfg.JobPollInterval)
 
 	// Initialize HTTP handlers
-	handler := deliveryHttp.NewHandler(userUC, blogUC)
+	handler := deliveryHttp.NewHandler(userUC, blogUC, importUC, exportUC, commentUC, mentionUC, timelineUC,
+		notificationUC, streamUC, recommendationUC, trendingUC, federationUC,
+		syndicationUC, webmentionUC, tokenUC, micropubUC, metaWeblogUC, newsletterUC, cfg.BounceSecret, digestUC,
+		preferenceUC, notificationEmailUC, pushUC, bookmarkUC)
 
 	// Setup router
 	r := mux.NewRouter()
@@ -60,20 +216,119 @@ func main() {
 
 	// User routes
 	r.HandleFunc("/api/u/new", handler.UserHandler.CreateUser).Methods("POST")
-	r.HandleFunc("/api/u/{id:[0-9]+}", handler.UserHandler.GetUser).Methods("GET")
+	r.HandleFunc("/api/u/{id:[0-9]+}", auth.OptionalAuthMiddleware(handler.UserHandler.GetUser)).Methods("GET")
 	r.HandleFunc("/api/u/{id:[0-9]+}", auth.AuthMiddleware(handler.UserHandler.FollowUser)).Methods("POST")
 	r.HandleFunc("/api/u/{id:[0-9]+}/manage", auth.AuthMiddleware(handler.UserHandler.UpdateUser)).Methods("POST")
-	r.HandleFunc("/api/u/{id:[0-9]+}/following", handler.UserHandler.GetUserFollowing).Methods("GET")
-	r.HandleFunc("/api/u/{id:[0-9]+}/follows", handler.UserHandler.GetUserFollowers).Methods("GET")
+	r.HandleFunc("/api/u/{id:[0-9]+}/following", auth.OptionalAuthMiddleware(handler.UserHandler.GetUserFollowing)).Methods("GET")
+	r.HandleFunc("/api/u/{id:[0-9]+}/follows", auth.OptionalAuthMiddleware(handler.UserHandler.GetUserFollowers)).Methods("GET")
+	r.HandleFunc("/api/u/{id:[0-9]+}/block", auth.AuthMiddleware(handler.UserHandler.BlockUser)).Methods("POST")
+	r.HandleFunc("/api/u/{id:[0-9]+}/mute", auth.AuthMiddleware(handler.UserHandler.MuteUser)).Methods("POST")
+	r.HandleFunc("/api/u/{id:[0-9]+}/subscribe", handler.NewsletterHandler.Subscribe).Methods("POST")
+	r.HandleFunc("/api/u/recommendations", auth.AuthMiddleware(handler.RecommendationHandler.GetRecommendations)).Methods("GET")
+	r.HandleFunc("/api/u/me/follow-requests", auth.AuthMiddleware(handler.UserHandler.GetFollowRequests)).Methods("GET")
+	r.HandleFunc("/api/u/me/follow-requests/{id:[0-9]+}", auth.AuthMiddleware(handler.UserHandler.AnswerFollowRequest)).Methods("POST")
+	r.HandleFunc("/api/u/me/blocks", auth.AuthMiddleware(handler.UserHandler.GetBlockedUsers)).Methods("GET")
+	r.HandleFunc("/api/u/me/mutes", auth.AuthMiddleware(handler.UserHandler.GetMutedUsers)).Methods("GET")
+	r.HandleFunc("/api/u/me/trash", auth.AuthMiddleware(handler.BlogHandler.GetTrash)).Methods("GET")
+	r.HandleFunc("/api/u/me/export", auth.AuthMiddleware(handler.ExportHandler.RequestExport)).Methods("POST")
+	r.HandleFunc("/api/u/me/export/{job:[0-9]+}", auth.AuthMiddleware(handler.ExportHandler.GetExport)).Methods("GET")
+	r.HandleFunc("/api/u/me/export/{job:[0-9]+}/download", auth.AuthMiddleware(handler.ExportHandler.DownloadExport)).Methods("GET")
+	r.HandleFunc("/api/u/me/tokens", auth.AuthMiddleware(handler.AccessTokenHandler.GetTokens)).Methods("GET")
+	r.HandleFunc("/api/u/me/tokens", auth.AuthMiddleware(handler.AccessTokenHandler.CreateToken)).Methods("POST")
+	r.HandleFunc("/api/u/me/tokens/{id:[0-9]+}/delete", auth.AuthMiddleware(handler.AccessTokenHandler.RevokeToken)).Methods("POST")
+	r.HandleFunc("/api/u/me/digest", auth.AuthMiddleware(handler.DigestHandler.GetSettings)).Methods("GET")
+	r.HandleFunc("/api/u/me/digest", auth.AuthMiddleware(handler.DigestHandler.UpdateSettings)).Methods("PUT")
+	r.HandleFunc("/api/u/me/preferences", auth.AuthMiddleware(handler.PreferenceHandler.GetPreferences)).Methods("GET")
+	r.HandleFunc("/api/u/me/preferences", auth.AuthMiddleware(handler.PreferenceHandler.UpdatePreferences)).Methods("PUT")
+	r.HandleFunc("/api/u/me/push-subscriptions", auth.AuthMiddleware(handler.PushHandler.GetSubscriptions)).Methods("GET")
+	r.HandleFunc("/api/u/me/push-subscriptions", auth.AuthMiddleware(handler.PushHandler.Subscribe)).Methods("POST")
+	r.HandleFunc("/api/u/me/push-subscriptions/delete", auth.AuthMiddleware(handler.PushHandler.Unsubscribe)).Methods("POST")
 
 	// Blog routes
-	r.HandleFunc("/api/b", handler.BlogHandler.GetBlogs).Methods("GET")
+	r.HandleFunc("/api/b", auth.OptionalAuthMiddleware(handler.BlogHandler.GetBlogs)).Methods("GET")
 	r.HandleFunc("/api/b/new", auth.AuthMiddleware(handler.BlogHandler.CreateBlog)).Methods("POST")
-	r.HandleFunc("/api/b/{id:[0-9]+}", handler.BlogHandler.GetBlog).Methods("GET")
+	r.HandleFunc("/api/b/trending", auth.OptionalAuthMiddleware(handler.BlogHandler.GetTrendingBlogs)).Methods("GET")
+	r.HandleFunc("/api/b/import", auth.AuthMiddleware(handler.ImportHandler.ImportBlogs)).Methods("POST")
+	r.HandleFunc("/api/b/{id:[0-9]+}", auth.OptionalAuthMiddleware(handler.BlogHandler.GetBlog)).Methods("GET")
 	r.HandleFunc("/api/b/{id:[0-9]+}", auth.AuthMiddleware(handler.BlogHandler.LikeBlog)).Methods("POST")
 	r.HandleFunc("/api/b/{id:[0-9]+}/edit", auth.AuthMiddleware(handler.BlogHandler.UpdateBlog)).Methods("POST")
 	r.HandleFunc("/api/b/{id:[0-9]+}/delete", auth.AuthMiddleware(handler.BlogHandler.DeleteBlog)).Methods("POST")
-	r.HandleFunc("/api/b/{id:[0-9]+}/likes", handler.BlogHandler.GetBlogLikes).Methods("GET")
+	r.HandleFunc("/api/b/{id:[0-9]+}/restore", auth.AuthMiddleware(handler.BlogHandler.RestoreBlog)).Methods("POST")
+	r.HandleFunc("/api/b/{id:[0-9]+}/likes", auth.OptionalAuthMiddleware(handler.BlogHandler.GetBlogLikes)).Methods("GET")
+	r.HandleFunc("/api/b/{id:[0-9]+}/reactions", auth.OptionalAuthMiddleware(handler.BlogHandler.GetBlogReactions)).Methods("GET")
+	r.HandleFunc("/api/b/{id:[0-9]+}/webmentions", auth.OptionalAuthMiddleware(handler.WebmentionHandler.GetWebmentions)).Methods("GET")
+
+	// Home timeline
+	r.HandleFunc("/api/feed", auth.AuthMiddleware(handler.FeedHandler.GetFeed)).Methods("GET")
+
+	// Bookmarks and reading lists
+	r.HandleFunc("/api/b/{id:[0-9]+}/bookmark", auth.AuthMiddleware(handler.BookmarkHandler.BookmarkBlog)).Methods("POST")
+	r.HandleFunc("/api/u/me/bookmarks", auth.AuthMiddleware(handler.BookmarkHandler.GetBookmarks)).Methods("GET")
+	r.HandleFunc("/api/u/me/lists", auth.AuthMiddleware(handler.BookmarkHandler.GetMyLists)).Methods("GET")
+	r.HandleFunc("/api/u/me/lists", auth.AuthMiddleware(handler.BookmarkHandler.CreateList)).Methods("POST")
+	r.HandleFunc("/api/u/me/lists/{id:[0-9]+}/edit", auth.AuthMiddleware(handler.BookmarkHandler.UpdateList)).Methods("POST")
+	r.HandleFunc("/api/u/me/lists/{id:[0-9]+}/delete", auth.AuthMiddleware(handler.BookmarkHandler.DeleteList)).Methods("POST")
+	r.HandleFunc("/api/u/me/lists/{id:[0-9]+}/items", auth.AuthMiddleware(handler.BookmarkHandler.UpdateListItems)).Methods("POST")
+	r.HandleFunc("/api/u/{id:[0-9]+}/lists", auth.OptionalAuthMiddleware(handler.BookmarkHandler.GetUserLists)).Methods("GET")
+	r.HandleFunc("/api/lists/{id:[0-9]+}", auth.OptionalAuthMiddleware(handler.BookmarkHandler.GetList)).Methods("GET")
+
+	// Notification routes
+	r.HandleFunc("/api/notifications", auth.AuthMiddleware(handler.NotificationHandler.GetNotifications)).Methods("GET")
+	r.HandleFunc("/api/notifications/read", auth.AuthMiddleware(handler.NotificationHandler.MarkAllRead)).Methods("POST")
+	r.HandleFunc("/api/notifications/{id:[0-9]+}/read", auth.AuthMiddleware(handler.NotificationHandler.MarkRead)).Methods("POST")
+
+	// Real-time events
+	r.HandleFunc("/api/stream", auth.AuthMiddleware(handler.StreamHandler.Stream)).Methods("GET")
+
+	// Comment routes
+	r.HandleFunc("/api/b/{id:[0-9]+}/comments", auth.OptionalAuthMiddleware(handler.CommentHandler.GetComments)).Methods("GET")
+	r.HandleFunc("/api/b/{id:[0-9]+}/comments", auth.AuthMiddleware(handler.CommentHandler.CreateComment)).Methods("POST")
+	r.HandleFunc("/api/b/{id:[0-9]+}/comments/{comment:[0-9]+}/edit", auth.AuthMiddleware(handler.CommentHandler.UpdateComment)).Methods("POST")
+	r.HandleFunc("/api/b/{id:[0-9]+}/comments/{comment:[0-9]+}/delete", auth.AuthMiddleware(handler.CommentHandler.DeleteComment)).Methods("POST")
+	r.HandleFunc("/api/b/{id:[0-9]+}/comments/settings", auth.AuthMiddleware(handler.CommentHandler.UpdateCommentSettings)).Methods("POST")
+	r.HandleFunc("/api/b/{id:[0-9]+}/comments/{comment:[0-9]+}/pin", auth.AuthMiddleware(handler.CommentHandler.PinComment)).Methods("POST")
+	r.HandleFunc("/api/b/{id:[0-9]+}/comments/unpin", auth.AuthMiddleware(handler.CommentHandler.UnpinComment)).Methods("POST")
+	r.HandleFunc("/api/b/{id:[0-9]+}/comments/{comment:[0-9]+}/approve", auth.AuthMiddleware(handler.CommentHandler.ApproveComment)).Methods("POST")
+	r.HandleFunc("/api/b/{id:[0-9]+}/comments/{comment:[0-9]+}/reject", auth.AuthMiddleware(handler.CommentHandler.RejectComment)).Methods("POST")
+	r.HandleFunc("/api/u/me/mentions", auth.AuthMiddleware(handler.MentionHandler.GetMentions)).Methods("GET")
+	r.HandleFunc("/api/u/me/comments/pending", auth.AuthMiddleware(handler.CommentHandler.GetPendingComments)).Methods("GET")
+
+	// Syndication feeds
+	r.HandleFunc("/feeds/all.{format:rss|atom|json}", handler.SyndicationHandler.GetAllFeed).Methods("GET")
+	r.HandleFunc("/feeds/u/{id:[0-9]+}", handler.SyndicationHandler.GetAuthorFeed).Methods("GET")
+	r.HandleFunc("/feeds/u/{id:[0-9]+}.{format:rss|atom|json}", handler.SyndicationHandler.GetAuthorFeed).Methods("GET")
+	r.HandleFunc("/feeds/t/{tag:[^/.]+}", handler.SyndicationHandler.GetTagFeed).Methods("GET")
+	r.HandleFunc("/feeds/t/{tag:[^/.]+}.{format:rss|atom|json}", handler.SyndicationHandler.GetTagFeed).Methods("GET")
+
+	// ActivityPub federation
+	r.HandleFunc("/.well-known/webfinger", handler.FederationHandler.WebFinger).Methods("GET")
+	r.HandleFunc("/ap/users/{username}", handler.FederationHandler.GetActor).Methods("GET")
+	r.HandleFunc("/ap/users/{username}/outbox", handler.FederationHandler.GetOutbox).Methods("GET")
+	r.HandleFunc("/ap/users/{username}/followers", handler.FederationHandler.GetFollowers).Methods("GET")
+	r.HandleFunc("/ap/users/{username}/inbox", handler.FederationHandler.Inbox).Methods("POST")
+	r.HandleFunc("/ap/blogs/{id:[0-9]+}", handler.FederationHandler.GetArticle).Methods("GET")
+
+	// Webmention
+	r.HandleFunc("/webmention", handler.WebmentionHandler.Receive).Methods("POST")
+
+	// Micropub, authenticated with access tokens
+	r.HandleFunc("/micropub", handler.MicropubHandler.Query).Methods("GET")
+	r.HandleFunc("/micropub", handler.MicropubHandler.Publish).Methods("POST")
+
+	// MetaWeblog XML-RPC, authenticated with access tokens as application
+	// passwords, and the media files it uploads
+	r.HandleFunc("/xmlrpc", handler.MetaWeblogHandler.ServeXMLRPC).Methods("POST")
+	r.HandleFunc("/media/{name}", handler.MetaWeblogHandler.GetMedia).Methods("GET")
+
+	// Web Push key browsers subscribe with
+	r.HandleFunc("/api/push/public-key", handler.PushHandler.GetPublicKey).Methods("GET")
+
+	// Email newsletter, digest and notification links, and bounce reports
+	r.HandleFunc("/newsletter/confirm", handler.NewsletterHandler.Confirm).Methods("GET")
+	r.HandleFunc("/newsletter/unsubscribe", handler.NewsletterHandler.Unsubscribe).Methods("GET", "POST")
+	r.HandleFunc("/newsletter/bounces", handler.NewsletterHandler.RecordBounce).Methods("POST")
+	r.HandleFunc("/digest/unsubscribe", handler.DigestHandler.Unsubscribe).Methods("GET", "POST")
+	r.HandleFunc("/notifications/unsubscribe", handler.PreferenceHandler.UnsubscribeEmail).Methods("GET", "POST")
 
 	// Server configuration
 	port := os.Getenv("PORT")
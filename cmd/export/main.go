@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"AbdelrahmanDwedar/blogo/internal/infrastructure/database"
+	"AbdelrahmanDwedar/blogo/internal/infrastructure/sitegen"
+	"AbdelrahmanDwedar/blogo/internal/usecase"
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	username := flag.String("user", "", "username of the author whose blogs are exported (required)")
+	out := flag.String("out", "site", "output directory, or a .zip file")
+	baseURL := flag.String("base-url", "", "URL the site will be hosted at, used for absolute feed links")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: export -user <username> [-out site|site.zip] [-base-url https://example.com]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *username == "" || flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+
+	// Initialize database
+	db, err := database.NewPostgresDB()
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer db.Close()
+
+	if err := db.InitTables(); err != nil {
+		log.Fatal("Failed to initialize tables:", err)
+	}
+
+	// Initialize repositories
+	userRepo := database.NewUserRepository(db)
+	blogRepo := database.NewBlogRepository(db)
+	jobRepo := database.NewJobRepository(db)
+
+	author, err := userRepo.GetByUsername(*username)
+	if err != nil {
+		log.Fatalf("Failed to find user %s: %v", *username, err)
+	}
+
+	exportUC := usecase.NewExportUseCase(blogRepo, userRepo, jobRepo, sitegen.NewRenderer(), nil)
+
+	fmt.Printf("📤 Exporting blogs of %s to %s...\n", author.Username, *out)
+
+	if strings.HasSuffix(strings.ToLower(*out), ".zip") {
+		file, err := os.Create(*out)
+		if err != nil {
+			log.Fatal("Failed to create archive:", err)
+		}
+		sink := sitegen.NewZipSink(file)
+		if err := exportUC.ExportSite(author.ID, *baseURL, sink); err != nil {
+			sink.Close()
+			log.Fatal("Failed to export site:", err)
+		}
+		if err := sink.Close(); err != nil {
+			log.Fatal("Failed to write archive:", err)
+		}
+	} else {
+		sink, err := sitegen.NewDirSink(*out)
+		if err != nil {
+			log.Fatal("Failed to create output directory:", err)
+		}
+		if err := exportUC.ExportSite(author.ID, *baseURL, sink); err != nil {
+			log.Fatal("Failed to export site:", err)
+		}
+	}
+
+	fmt.Println("✅ Export complete")
+}
@@ -0,0 +1,248 @@
+// Command fakepush is a minimal stand-in for a browser's push service, for
+// trying out Web Push locally. It hands out subscriptions the way a
+// browser's PushManager would, then checks the VAPID authorization of
+// every push sent to them, decrypts it and logs it. Subscriptions can be
+// made to answer 410 Gone, as expired ones do, and the whole service to
+// fail every other push, to exercise cleanup and retries.
+//
+// Push endpoints must be https, so the service serves TLS with a
+// certificate for localhost it makes at startup and writes to -cert, for
+// the API to trust.
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/pkg/webpush"
+)
+
+// maxPushSize is the largest push body push services accept
+const maxPushSize = 4096
+
+// subscription is a subscription handed out by the fake push service,
+// with the messages pushed to it
+type subscription struct {
+	key      *ecdsa.PrivateKey
+	auth     []byte
+	gone     bool
+	messages []json.RawMessage
+}
+
+// service holds the state of the fake push service
+type service struct {
+	baseURL string
+	flaky   bool
+
+	mu            sync.Mutex
+	pushes        int
+	subscriptions map[string]*subscription
+}
+
+func main() {
+	listen := flag.String("listen", ":9092", "address to listen on")
+	baseURL := flag.String("url", "https://localhost:9092", "URL the fake push service is reached at")
+	certFile := flag.String("cert", "fakepush.pem", "file the service's certificate is written to")
+	flaky := flag.Bool("flaky", false, "answer every other push with 503 Service Unavailable")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: fakepush [-listen :9092] [-url https://localhost:9092] [-cert fakepush.pem] [-flaky]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	cert, err := selfSignedCertificate(*certFile)
+	if err != nil {
+		log.Fatalf("Failed to make certificate: %v", err)
+	}
+
+	svc := &service{
+		baseURL:       strings.TrimRight(*baseURL, "/"),
+		flaky:         *flaky,
+		subscriptions: map[string]*subscription{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/subscribe", svc.serveSubscribe)
+	mux.HandleFunc("/push/", svc.servePush)
+
+	server := &http.Server{
+		Addr:      *listen,
+		Handler:   mux,
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+	}
+	log.Printf("🛰️  Fake push service listening on %s with certificate %s; GET %s/subscribe for a subscription\n",
+		*listen, *certFile, svc.baseURL)
+	log.Fatal(server.ListenAndServeTLS("", ""))
+}
+
+// selfSignedCertificate makes a certificate for localhost, signed by its
+// own key, and writes it to path in PEM so clients can trust it
+func selfSignedCertificate(path string) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 62))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "fakepush"},
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(7 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644); err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+// serveSubscribe creates a subscription and returns it as a browser's
+// PushSubscription serializes, ready to be registered with blogo. With
+// ?gone=1 pushes to it are answered 410 Gone.
+func (svc *service) serveSubscribe(w http.ResponseWriter, r *http.Request) {
+	key, err := webpush.GenerateKey()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	auth := make([]byte, 16)
+	if _, err := rand.Read(auth); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	id := make([]byte, 12)
+	if _, err := rand.Read(id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	name := base64.RawURLEncoding.EncodeToString(id)
+
+	svc.mu.Lock()
+	svc.subscriptions[name] = &subscription{key: key, auth: auth, gone: r.URL.Query().Get("gone") != ""}
+	svc.mu.Unlock()
+
+	endpoint := svc.baseURL + "/push/" + name
+	log.Printf("📱 New subscription %s\n", endpoint)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"endpoint": endpoint,
+		"keys": map[string]string{
+			"p256dh": webpush.EncodePublicKey(&key.PublicKey),
+			"auth":   base64.RawURLEncoding.EncodeToString(auth),
+		},
+	})
+}
+
+// servePush receives a push with POST and lists the messages a
+// subscription received with GET
+func (svc *service) servePush(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/push/")
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	sub, ok := svc.subscriptions[name]
+	if !ok {
+		http.Error(w, "no such subscription", http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"messages": sub.messages})
+		return
+	case http.MethodPost:
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if sub.gone {
+		log.Printf("🗑️  Push to expired subscription %s answered 410\n", name)
+		http.Error(w, "subscription expired", http.StatusGone)
+		return
+	}
+
+	svc.pushes++
+	if svc.flaky && svc.pushes%2 == 1 {
+		log.Printf("💥 Push to %s answered 503\n", name)
+		http.Error(w, "try again later", http.StatusServiceUnavailable)
+		return
+	}
+
+	if r.Header.Get("TTL") == "" {
+		http.Error(w, "missing TTL header", http.StatusBadRequest)
+		return
+	}
+	if r.Header.Get("Content-Encoding") != "aes128gcm" {
+		http.Error(w, "content encoding must be aes128gcm", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	endpoint := svc.baseURL + r.URL.Path
+	publicKey, subject, err := webpush.VerifyVAPIDHeader(r.Header.Get("Authorization"), endpoint)
+	if err != nil {
+		log.Printf("⛔ Push to %s refused: %v\n", name, err)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPushSize+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxPushSize {
+		http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	payload, err := webpush.Decrypt(body, sub.key, sub.auth)
+	if err != nil {
+		log.Printf("⛔ Push to %s refused: %v\n", name, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if json.Valid(payload) {
+		sub.messages = append(sub.messages, payload)
+	} else {
+		quoted, _ := json.Marshal(string(payload))
+		sub.messages = append(sub.messages, quoted)
+	}
+	log.Printf("🔔 Push to %s from %s (key %.12s…): %s\n", name, subject, publicKey, payload)
+	w.WriteHeader(http.StatusCreated)
+}
@@ -0,0 +1,253 @@
+// Command fakeremote is a minimal stand-in for a remote ActivityPub server,
+// for trying out federation locally. It serves a single actor, logs every
+// activity delivered to its inbox after checking the signature, and can
+// follow, unfollow or like on a blogo server.
+package main
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/infrastructure/federation"
+	"AbdelrahmanDwedar/blogo/pkg/activitypub"
+	"AbdelrahmanDwedar/blogo/pkg/httpsig"
+)
+
+// remote holds the state of the fake server
+type remote struct {
+	baseURL    string
+	username   string
+	publicPEM  string
+	privatePEM string
+	client     *federation.Client
+}
+
+func main() {
+	listen := flag.String("listen", ":9090", "address to listen on")
+	baseURL := flag.String("url", "http://localhost:9090", "URL the fake server is reached at")
+	username := flag.String("user", "tester", "username of the fake actor")
+	follow := flag.String("follow", "", "follow a blogo user, given as user@host")
+	unfollow := flag.String("unfollow", "", "undo a follow of a blogo user, given as user@host")
+	like := flag.String("like", "", "like a blogo article, given by its ID (https://host/ap/blogs/1)")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: fakeremote [-listen :9090] [-url http://localhost:9090] [-follow alice@localhost:8080] [-unfollow alice@localhost:8080] [-like http://localhost:8080/ap/blogs/1]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	privatePEM, publicPEM, err := httpsig.GenerateKey()
+	if err != nil {
+		log.Fatal("Failed to generate key:", err)
+	}
+
+	rm := &remote{
+		baseURL:    strings.TrimRight(*baseURL, "/"),
+		username:   *username,
+		publicPEM:  publicPEM,
+		privatePEM: privatePEM,
+		client:     federation.NewClient(true),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/actor", rm.serveActor)
+	mux.HandleFunc("/inbox", rm.serveInbox)
+
+	go func() {
+		log.Printf("🛰️  Fake remote %s listening on %s\n", rm.actorIRI(), *listen)
+		log.Fatal(http.ListenAndServe(*listen, mux))
+	}()
+
+	// Give the listener a moment so blogo can fetch the actor while
+	// verifying the first activity
+	time.Sleep(200 * time.Millisecond)
+
+	if *follow != "" {
+		rm.send(*follow, activitypub.TypeFollow)
+	}
+	if *unfollow != "" {
+		rm.send(*unfollow, activitypub.TypeUndo)
+	}
+	if *like != "" {
+		rm.like(*like)
+	}
+
+	select {}
+}
+
+func (rm *remote) actorIRI() string {
+	return rm.baseURL + "/actor"
+}
+
+func (rm *remote) keyID() string {
+	return rm.actorIRI() + "#main-key"
+}
+
+func (rm *remote) activityID(kind string) string {
+	return fmt.Sprintf("%s/activities/%s-%d", rm.baseURL, strings.ToLower(kind), time.Now().UnixNano())
+}
+
+// serveActor serves the fake actor
+func (rm *remote) serveActor(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", activitypub.ContentType)
+	json.NewEncoder(w).Encode(&activitypub.Actor{
+		Context:           activitypub.Context,
+		ID:                rm.actorIRI(),
+		Type:              activitypub.TypePerson,
+		PreferredUsername: rm.username,
+		Inbox:             rm.baseURL + "/inbox",
+		PublicKey: &activitypub.PublicKey{
+			ID:           rm.keyID(),
+			Owner:        rm.actorIRI(),
+			PublicKeyPem: rm.publicPEM,
+		},
+	})
+}
+
+// serveInbox verifies and logs a delivered activity
+func (rm *remote) serveInbox(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	keyID, err := httpsig.Verify(r, body, rm.lookupKey)
+	if err != nil {
+		log.Printf("❌ Rejected delivery: %v\n", err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var activity activitypub.Activity
+	if err := json.Unmarshal(body, &activity); err != nil {
+		log.Printf("❌ Rejected malformed activity from %s\n", keyID)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("📥 %s of %s by %s\n", activity.Type, activity.ObjectID(), activity.Actor)
+	log.Printf("%s\n", body)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// lookupKey fetches the public key of a signing actor
+func (rm *remote) lookupKey(keyID string) (*rsa.PublicKey, error) {
+	actor, err := rm.client.FetchActor(keyID)
+	if err != nil {
+		return nil, err
+	}
+	if actor.PublicKeyID != keyID {
+		return nil, fmt.Errorf("key %s not found on actor %s", keyID, actor.ID)
+	}
+	return httpsig.ParsePublicKey(actor.PublicKeyPEM)
+}
+
+// resolve finds the actor of a user@host account through WebFinger
+func (rm *remote) resolve(account string) (string, error) {
+	_, host, ok := strings.Cut(strings.TrimPrefix(account, "@"), "@")
+	if !ok {
+		return "", fmt.Errorf("invalid account %q", account)
+	}
+
+	scheme := "https"
+	if u, err := url.Parse(rm.baseURL); err == nil && u.Scheme == "http" {
+		scheme = "http"
+	}
+
+	resp, err := http.Get(fmt.Sprintf("%s://%s/.well-known/webfinger?resource=%s",
+		scheme, host, url.QueryEscape("acct:"+account)))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("webfinger: %s", resp.Status)
+	}
+
+	var jrd activitypub.WebFinger
+	if err := json.NewDecoder(resp.Body).Decode(&jrd); err != nil {
+		return "", err
+	}
+	if link := jrd.ActorLink(); link != "" {
+		return link, nil
+	}
+	return "", fmt.Errorf("no actor for %s", account)
+}
+
+// send follows or unfollows a blogo user
+func (rm *remote) send(account, kind string) {
+	target, err := rm.resolve(account)
+	if err != nil {
+		log.Fatalf("Failed to resolve %s: %v", account, err)
+	}
+	inbox, err := rm.client.FetchActor(target)
+	if err != nil {
+		log.Fatalf("Failed to fetch %s: %v", target, err)
+	}
+
+	followID := rm.baseURL + "/activities/follow-" + url.PathEscape(target)
+	follow, err := activitypub.NewActivity(followID, activitypub.TypeFollow, rm.actorIRI(), target)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	activity := follow
+	if kind == activitypub.TypeUndo {
+		follow.Context = nil
+		activity, err = activitypub.NewActivity(rm.activityID(kind), kind, rm.actorIRI(), follow)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	rm.deliver(inbox.Inbox, activity)
+}
+
+// like likes a blogo article, delivering to its author's inbox
+func (rm *remote) like(articleID string) {
+	resp, err := http.Get(articleID)
+	if err != nil {
+		log.Fatalf("Failed to fetch %s: %v", articleID, err)
+	}
+	defer resp.Body.Close()
+
+	var article activitypub.Object
+	if err := json.NewDecoder(resp.Body).Decode(&article); err != nil || article.AttributedTo == "" {
+		log.Fatalf("Failed to read article %s: %v", articleID, err)
+	}
+	author, err := rm.client.FetchActor(article.AttributedTo)
+	if err != nil {
+		log.Fatalf("Failed to fetch %s: %v", article.AttributedTo, err)
+	}
+
+	like, err := activitypub.NewActivity(rm.activityID(activitypub.TypeLike), activitypub.TypeLike, rm.actorIRI(), articleID)
+	if err != nil {
+		log.Fatal(err)
+	}
+	rm.deliver(author.Inbox, like)
+}
+
+// deliver posts a signed activity to an inbox
+func (rm *remote) deliver(inbox string, activity *activitypub.Activity) {
+	data, err := json.Marshal(activity)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := rm.client.Deliver(inbox, data, rm.keyID(), rm.privatePEM); err != nil {
+		log.Fatalf("Failed to deliver %s to %s: %v", activity.Type, inbox, err)
+	}
+	log.Printf("📤 Sent %s of %s to %s\n", activity.Type, activity.ObjectID(), inbox)
+}
@@ -0,0 +1,154 @@
+// Command fakesite is a minimal stand-in for an IndieWeb site, for trying
+// out Webmentions locally. It serves a single post advertising its own
+// Webmention endpoint, logs every Webmention sent to it after verifying
+// the source, and can send a Webmention for its post to a blogo blog.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/infrastructure/webmention"
+)
+
+// site holds the state of the fake site
+type site struct {
+	baseURL string
+	link    string
+	gone    bool
+	client  *webmention.Client
+}
+
+func main() {
+	listen := flag.String("listen", ":9091", "address to listen on")
+	baseURL := flag.String("url", "http://localhost:9091", "URL the fake site is reached at")
+	link := flag.String("link", "", "URL of a blogo blog the post links to (http://localhost:8080/api/b/1)")
+	send := flag.Bool("send", false, "send a Webmention for the post to the linked blog")
+	gone := flag.Bool("gone", false, "answer 410 Gone for the post, as if it was deleted")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: fakesite [-listen :9091] [-url http://localhost:9091] [-link http://localhost:8080/api/b/1] [-send] [-gone]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *send && *link == "" {
+		log.Fatal("-send needs -link")
+	}
+
+	st := &site{
+		baseURL: strings.TrimRight(*baseURL, "/"),
+		link:    *link,
+		gone:    *gone,
+		client:  webmention.NewClient(true),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/post", st.servePost)
+	mux.HandleFunc("/webmention", st.serveWebmention)
+
+	go func() {
+		log.Printf("🛰️  Fake site %s listening on %s\n", st.postURL(), *listen)
+		log.Fatal(http.ListenAndServe(*listen, mux))
+	}()
+
+	// Give the listener a moment so blogo can fetch the post while
+	// verifying the Webmention
+	time.Sleep(200 * time.Millisecond)
+
+	if *send {
+		st.send()
+	}
+
+	select {}
+}
+
+func (st *site) postURL() string {
+	return st.baseURL + "/post"
+}
+
+// servePost serves the fake post, linking to the blogo blog when given
+func (st *site) servePost(w http.ResponseWriter, r *http.Request) {
+	if st.gone {
+		w.WriteHeader(http.StatusGone)
+		return
+	}
+
+	var body string
+	if st.link != "" {
+		body = fmt.Sprintf(`<p>Responding to <a href="%s">a blogo post</a>.</p>`, html.EscapeString(st.link))
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Link", `</webmention>; rel="webmention"`)
+	fmt.Fprintf(w, `<!DOCTYPE html>
+<html>
+<head>
+<title>A fake IndieWeb post</title>
+<link rel="webmention" href="/webmention">
+</head>
+<body>
+<article class="h-entry">
+<h1 class="p-name">A fake IndieWeb post</h1>
+%s
+</article>
+</body>
+</html>
+`, body)
+}
+
+// serveWebmention logs a received Webmention and verifies that its source
+// links to the post
+func (st *site) serveWebmention(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	source, target := r.FormValue("source"), r.FormValue("target")
+	if source == "" || target != st.postURL() {
+		log.Printf("❌ Rejected webmention from %q to %q\n", source, target)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("📥 Webmention from %s to %s\n", source, target)
+	w.WriteHeader(http.StatusAccepted)
+
+	go func() {
+		fetched, err := st.client.FetchSource(source, target)
+		switch {
+		case err != nil:
+			log.Printf("❌ Source %s: %v\n", source, err)
+		case fetched.LinksTarget:
+			log.Printf("✅ Source %s links to the post\n", source)
+		default:
+			log.Printf("❌ Source %s does not link to the post\n", source)
+		}
+	}()
+}
+
+// send discovers the Webmention endpoint of the linked blog and sends it a
+// Webmention for the post
+func (st *site) send() {
+	endpoint, err := st.client.DiscoverEndpoint(st.link)
+	if err != nil {
+		log.Printf("❌ Failed to discover endpoint of %s: %v\n", st.link, err)
+		return
+	}
+	if endpoint == "" {
+		log.Printf("❌ %s advertises no Webmention endpoint\n", st.link)
+		return
+	}
+
+	if err := st.client.Send(endpoint, st.postURL(), st.link); err != nil {
+		log.Printf("❌ Failed to send webmention to %s: %v\n", endpoint, err)
+		return
+	}
+	log.Printf("📤 Sent webmention for %s to %s\n", st.link, endpoint)
+}
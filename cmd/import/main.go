@@ -0,0 +1,124 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/infrastructure/cache"
+	"AbdelrahmanDwedar/blogo/internal/infrastructure/database"
+	"AbdelrahmanDwedar/blogo/internal/infrastructure/importer"
+	"AbdelrahmanDwedar/blogo/internal/usecase"
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	username := flag.String("user", "", "username of the author the posts are imported for (required)")
+	format := flag.String("format", "", "input format: wxr, markdown or json (detected from the path if omitted)")
+	dryRun := flag.Bool("dry-run", false, "validate and report without saving anything")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: import -user <username> [-format wxr|markdown|json] [-dry-run] <file or directory>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *username == "" || flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	path := flag.Arg(0)
+
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+
+	// Read the posts before touching the database
+	items, err := readItems(path, *format)
+	if err != nil {
+		log.Fatal("Failed to read posts:", err)
+	}
+
+	// Initialize database
+	db, err := database.NewPostgresDB()
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer db.Close()
+
+	if err := db.InitTables(); err != nil {
+		log.Fatal("Failed to initialize tables:", err)
+	}
+
+	// Initialize repositories
+	userRepo := database.NewUserRepository(db)
+	blogRepo := database.NewBlogRepository(db)
+
+	author, err := userRepo.GetByUsername(*username)
+	if err != nil {
+		log.Fatalf("Failed to find user %s: %v", *username, err)
+	}
+
+	if *dryRun {
+		fmt.Printf("🔍 Dry run: checking %d posts for %s...\n", len(items), author.Username)
+	} else {
+		fmt.Printf("📥 Importing %d posts for %s...\n", len(items), author.Username)
+	}
+
+	// Initialize cache (optional) so imported blogs show up in cached lists
+	redisCache := cache.NewRedisCache()
+	if redisCache != nil {
+		defer redisCache.Close()
+	}
+
+	// No listeners are subscribed here, so unlike imports through the API
+	// these blogs are not federated, syndicated or sent to subscribers
+	blogUC := usecase.NewBlogUseCase(blogRepo, userRepo, redisCache, nil)
+	importUC := usecase.NewImportUseCase(blogRepo, blogUC, importer.NewParser())
+	report := importUC.ImportBlogs(author.ID, items, *dryRun)
+
+	for _, item := range report.Items {
+		switch item.Status {
+		case entity.ImportStatusImported:
+			fmt.Printf("✅ %s: %s (ID: %d)\n", item.Source, item.Slug, item.BlogID)
+		case entity.ImportStatusValid:
+			fmt.Printf("✔️  %s: %s\n", item.Source, item.Slug)
+		default:
+			fmt.Printf("❌ %s: %s\n", item.Source, item.Error)
+		}
+	}
+
+	fmt.Printf("\nTotal: %d, ok: %d, failed: %d\n", report.Total, report.Imported, report.Failed)
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// readItems parses a file, or every Markdown file below a directory
+func readItems(path, format string) ([]*entity.ImportItem, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		if format != "" && format != importer.FormatMarkdown {
+			return nil, fmt.Errorf("directories can only be imported as %s", importer.FormatMarkdown)
+		}
+		return importer.ParseMarkdownDir(os.DirFS(path))
+	}
+
+	if format == "" {
+		format = importer.DetectFormat(path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return importer.Parse(format, filepath.Base(path), data)
+}
@@ -0,0 +1,57 @@
+// Command migrate runs one-off data migrations that must not happen on
+// every startup because they cannot be undone by rolling back the code.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"AbdelrahmanDwedar/blogo/internal/infrastructure/database"
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: migrate <migration>")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Migrations:")
+		fmt.Fprintln(os.Stderr, "  likes   copy likes into like reactions and rename the likes table to likes_legacy")
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+
+	// Initialize database
+	db, err := database.NewPostgresDB()
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer db.Close()
+
+	if err := db.InitTables(); err != nil {
+		log.Fatal("Failed to initialize tables:", err)
+	}
+
+	switch flag.Arg(0) {
+	case "likes":
+		copied, err := db.MigrateLikes()
+		if err != nil {
+			log.Fatal("Failed to migrate likes:", err)
+		}
+		fmt.Printf("✅ Copied %d likes into reactions; the old rows are kept in likes_legacy\n", copied)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
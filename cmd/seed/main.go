@@ -117,15 +117,15 @@ func main() {
 	// Create some follow relationships
 	if len(userIDs) >= 3 {
 		// Alice follows Bob and Charlie
-		userRepo.Follow(userIDs[0], userIDs[1])
-		userRepo.Follow(userIDs[0], userIDs[2])
+		userRepo.Follow(userIDs[0], userIDs[1], entity.FollowAccepted)
+		userRepo.Follow(userIDs[0], userIDs[2], entity.FollowAccepted)
 
 		// Bob follows Alice
-		userRepo.Follow(userIDs[1], userIDs[0])
+		userRepo.Follow(userIDs[1], userIDs[0], entity.FollowAccepted)
 
 		// Charlie follows everyone
-		userRepo.Follow(userIDs[2], userIDs[0])
-		userRepo.Follow(userIDs[2], userIDs[1])
+		userRepo.Follow(userIDs[2], userIDs[0], entity.FollowAccepted)
+		userRepo.Follow(userIDs[2], userIDs[1], entity.FollowAccepted)
 
 		fmt.Println("✅ Created follow relationships")
 	}
@@ -133,18 +133,18 @@ func main() {
 	// Create some likes
 	if len(blogIDs) >= 3 && len(userIDs) >= 3 {
 		// Alice likes blogs 2 and 3
-		blogRepo.Like(blogIDs[1], userIDs[0])
-		blogRepo.Like(blogIDs[2], userIDs[0])
+		blogRepo.React(blogIDs[1], userIDs[0], entity.DefaultReaction)
+		blogRepo.React(blogIDs[2], userIDs[0], entity.DefaultReaction)
 
 		// Bob likes blog 1 and 5
-		blogRepo.Like(blogIDs[0], userIDs[1])
+		blogRepo.React(blogIDs[0], userIDs[1], entity.DefaultReaction)
 		if len(blogIDs) >= 5 {
-			blogRepo.Like(blogIDs[4], userIDs[1])
+			blogRepo.React(blogIDs[4], userIDs[1], entity.DefaultReaction)
 		}
 
 		// Charlie likes all blogs
 		for _, blogID := range blogIDs {
-			blogRepo.Like(blogID, userIDs[2])
+			blogRepo.React(blogID, userIDs[2], entity.DefaultReaction)
 		}
 
 		fmt.Println("✅ Created likes")
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds runtime settings read from the environment
+type Config struct {
+	// TrashRetention is how long soft-deleted blogs stay restorable
+	TrashRetention time.Duration
+
+	// TrashPurgeInterval is how often expired trash is purged
+	TrashPurgeInterval time.Duration
+
+	// JobPollInterval is how often the background job queue is polled
+	JobPollInterval time.Duration
+
+	// ExportDir is where static site export archives are stored
+	ExportDir string
+
+	// MediaDir is where media files uploaded for blogs are stored
+	MediaDir string
+
+	// ReactionKinds are the reactions users can leave on blogs; "like" is
+	// always available
+	ReactionKinds []string
+
+	// CommentMaxDepth is how many levels of replies a comment thread may have
+	CommentMaxDepth int
+
+	// RecommendationInterval is how often who-to-follow recommendations are
+	// recomputed
+	RecommendationInterval time.Duration
+
+	// TrendingInterval is how often trending blogs are recomputed
+	TrendingInterval time.Duration
+
+	// DigestInterval is how often due digest emails are looked for
+	DigestInterval time.Duration
+
+	// PublicURL is the URL this server is reached at from the internet,
+	// which feed links, ActivityPub IDs and WebFinger addresses are built on
+	PublicURL string
+
+	// SMTPHost is the mail server emails are sent through; when empty they
+	// are written to MailDir instead
+	SMTPHost string
+
+	// SMTPPort is the port of the mail server
+	SMTPPort int
+
+	// SMTPUsername and SMTPPassword log in to the mail server, if set
+	SMTPUsername string
+	SMTPPassword string
+
+	// MailFrom is the sender of emails
+	MailFrom string
+
+	// MailDir is where emails are written when no mail server is set
+	MailDir string
+
+	// BounceSecret authenticates bounce reports from the mail provider;
+	// bounce reports are refused when it is empty
+	BounceSecret string
+
+	// VAPIDSubject is the mailto: or https: URL push services can reach
+	// this server's operator at about the pushes it sends
+	VAPIDSubject string
+
+	// AllowPrivateNetworks lets requests to URLs from users and remote
+	// servers reach loopback and private addresses, for testing against
+	// local fakes. It must stay off in production.
+	AllowPrivateNetworks bool
+}
+
+// Load reads the configuration from environment variables, falling back to
+// defaults for anything unset or malformed
+func Load() *Config {
+	return &Config{
+		TrashRetention:         getDuration("TRASH_RETENTION", 30*24*time.Hour),
+		TrashPurgeInterval:     getDuration("TRASH_PURGE_INTERVAL", time.Hour),
+		JobPollInterval:        getDuration("JOB_POLL_INTERVAL", 5*time.Second),
+		ExportDir:              getString("EXPORT_DIR", "exports"),
+		MediaDir:               getString("MEDIA_DIR", "media"),
+		ReactionKinds:          getList("REACTION_KINDS", []string{"like", "love", "laugh", "wow", "sad", "celebrate"}),
+		CommentMaxDepth:        getInt("COMMENT_MAX_DEPTH", 5),
+		RecommendationInterval: getDuration("RECOMMENDATION_INTERVAL", time.Hour),
+		TrendingInterval:       getDuration("TRENDING_INTERVAL", 10*time.Minute),
+		DigestInterval:         getDuration("DIGEST_INTERVAL", time.Hour),
+		PublicURL:              getString("PUBLIC_URL", "http://localhost:8080"),
+		SMTPHost:               os.Getenv("SMTP_HOST"),
+		SMTPPort:               getInt("SMTP_PORT", 587),
+		SMTPUsername:           os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:           os.Getenv("SMTP_PASSWORD"),
+		MailFrom:               getString("MAIL_FROM", "Blogo <no-reply@localhost>"),
+		MailDir:                getString("MAIL_DIR", "mail"),
+		BounceSecret:           os.Getenv("NEWSLETTER_BOUNCE_SECRET"),
+		VAPIDSubject:           getString("VAPID_SUBJECT", "mailto:no-reply@localhost"),
+		AllowPrivateNetworks:   getBool("ALLOW_PRIVATE_NETWORKS", false),
+	}
+}
+
+func getString(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		log.Printf("⚠️  Invalid %s %q, using %d\n", key, value, fallback)
+		return fallback
+	}
+	return n
+}
+
+func getBool(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("⚠️  Invalid %s %q, using %t\n", key, value, fallback)
+		return fallback
+	}
+	return b
+}
+
+func getList(key string, fallback []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	return strings.Split(value, ",")
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("⚠️  Invalid %s %q, using %s\n", key, value, fallback)
+		return fallback
+	}
+	return d
+}
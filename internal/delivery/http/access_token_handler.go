@@ -0,0 +1,106 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/usecase"
+	"AbdelrahmanDwedar/blogo/pkg/auth"
+	"AbdelrahmanDwedar/blogo/pkg/response"
+)
+
+// AccessTokenHandler handles the access tokens users create for
+// third-party clients
+type AccessTokenHandler struct {
+	tokenUC *usecase.AccessTokenUseCase
+}
+
+// NewAccessTokenHandler creates a new access token handler
+func NewAccessTokenHandler(tokenUC *usecase.AccessTokenUseCase) *AccessTokenHandler {
+	return &AccessTokenHandler{tokenUC: tokenUC}
+}
+
+// CreateToken creates an access token for the authenticated user. The
+// token's secret is only included in this response.
+func (h *AccessTokenHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req struct {
+		Name   string   `json:"name"`
+		Scopes []string `json:"scopes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	token, secret, err := h.tokenUC.CreateToken(claims.UserID, req.Name, req.Scopes)
+	if err != nil {
+		switch err {
+		case entity.ErrInvalidTokenName:
+			response.Error(w, http.StatusBadRequest, "Token name is required and must be at most 100 characters")
+		case entity.ErrInvalidScope:
+			response.Error(w, http.StatusBadRequest, "Scopes must be one or more of 'create', 'update' and 'delete'")
+		default:
+			response.Error(w, http.StatusInternalServerError, "Failed to create token")
+		}
+		return
+	}
+
+	response.Created(w, map[string]interface{}{
+		"token":  token,
+		"secret": secret,
+	})
+}
+
+// GetTokens lists the authenticated user's access tokens
+func (h *AccessTokenHandler) GetTokens(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	tokens, err := h.tokenUC.GetTokens(claims.UserID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to get tokens")
+		return
+	}
+
+	response.Success(w, map[string]interface{}{
+		"tokens": tokens,
+	})
+}
+
+// RevokeToken revokes one of the authenticated user's access tokens
+func (h *AccessTokenHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	tokenID, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid token ID")
+		return
+	}
+
+	if err := h.tokenUC.RevokeToken(tokenID, claims.UserID); err != nil {
+		if err == entity.ErrAccessTokenNotFound {
+			response.Error(w, http.StatusNotFound, "Token not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to revoke token")
+		return
+	}
+
+	response.Success(w, map[string]string{
+		"message": "Token revoked",
+	})
+}
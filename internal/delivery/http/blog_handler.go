@@ -3,6 +3,7 @@ package http
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 
 	"AbdelrahmanDwedar/blogo/internal/domain/entity"
 	"AbdelrahmanDwedar/blogo/internal/usecase"
@@ -12,12 +13,16 @@ import (
 
 // BlogHandler handles blog-related HTTP requests
 type BlogHandler struct {
-	blogUC *usecase.BlogUseCase
+	blogUC     *usecase.BlogUseCase
+	mentionUC  *usecase.MentionUseCase
+	trendingUC *usecase.TrendingUseCase
+	bookmarkUC *usecase.BookmarkUseCase
 }
 
 // NewBlogHandler creates a new blog handler
-func NewBlogHandler(blogUC *usecase.BlogUseCase) *BlogHandler {
-	return &BlogHandler{blogUC: blogUC}
+func NewBlogHandler(blogUC *usecase.BlogUseCase, mentionUC *usecase.MentionUseCase,
+	trendingUC *usecase.TrendingUseCase, bookmarkUC *usecase.BookmarkUseCase) *BlogHandler {
+	return &BlogHandler{blogUC: blogUC, mentionUC: mentionUC, trendingUC: trendingUC, bookmarkUC: bookmarkUC}
 }
 
 // CreateBlog creates a new blog post
@@ -29,9 +34,10 @@ func (h *BlogHandler) CreateBlog(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Title       string `json:"title"`
-		Description string `json:"description"`
-		Body        string `json:"body"`
+		Title       string   `json:"title"`
+		Description string   `json:"description"`
+		Body        string   `json:"body"`
+		Tags        []string `json:"tags"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -39,7 +45,7 @@ func (h *BlogHandler) CreateBlog(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	blog, err := h.blogUC.CreateBlog(req.Title, req.Description, req.Body, claims.UserID)
+	blog, err := h.blogUC.CreateBlog(req.Title, req.Description, req.Body, req.Tags, claims.UserID)
 	if err != nil {
 		if err == entity.ErrInvalidTitle || err == entity.ErrInvalidBody {
 			response.Error(w, http.StatusBadRequest, err.Error())
@@ -49,15 +55,37 @@ func (h *BlogHandler) CreateBlog(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("ETag", blogETag(blog.Version))
 	response.Created(w, blog)
 }
 
-// GetBlogs retrieves all blogs
+// GetBlogs retrieves all blogs, sorted by the sort query parameter: latest
+// (the default), top or trending
 func (h *BlogHandler) GetBlogs(w http.ResponseWriter, r *http.Request) {
 	limit, offset := getPaginationParams(r)
+	sort := r.URL.Query().Get("sort")
 
-	blogs, err := h.blogUC.GetAllBlogs(limit, offset)
+	var blogs []*entity.Blog
+	var err error
+	if sort == entity.SortTrending {
+		blogs, err = h.trendingUC.GetTrending(r.URL.Query().Get("window"), getViewerID(r), limit, offset)
+	} else {
+		blogs, err = h.blogUC.GetAllBlogs(getViewerID(r), sort, limit, offset)
+	}
 	if err != nil {
+		if err == entity.ErrInvalidSort {
+			response.Error(w, http.StatusBadRequest, "Invalid sort. Use 'latest', 'top' or 'trending'")
+			return
+		}
+		if err == entity.ErrInvalidTrendingWindow {
+			response.Error(w, http.StatusBadRequest, "Invalid window. Use '24h', '7d' or '30d'")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to get blogs")
+		return
+	}
+
+	if err := h.bookmarkUC.MarkBookmarked(getViewerID(r), blogs...); err != nil {
 		response.Error(w, http.StatusInternalServerError, "Failed to get blogs")
 		return
 	}
@@ -69,6 +97,39 @@ func (h *BlogHandler) GetBlogs(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// GetTrendingBlogs retrieves the blogs with the most recent engagement
+// within the window query parameter: 24h (the default), 7d or 30d
+func (h *BlogHandler) GetTrendingBlogs(w http.ResponseWriter, r *http.Request) {
+	limit, offset := getPaginationParams(r)
+	window := r.URL.Query().Get("window")
+
+	blogs, err := h.trendingUC.GetTrending(window, getViewerID(r), limit, offset)
+	if err != nil {
+		if err == entity.ErrInvalidTrendingWindow {
+			response.Error(w, http.StatusBadRequest, "Invalid window. Use '24h', '7d' or '30d'")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to get trending blogs")
+		return
+	}
+
+	if err := h.bookmarkUC.MarkBookmarked(getViewerID(r), blogs...); err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to get trending blogs")
+		return
+	}
+
+	if window == "" {
+		window = entity.TrendingWindows[0].Name
+	}
+
+	response.Success(w, map[string]interface{}{
+		"blogs":  blogs,
+		"window": window,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
 // GetBlog retrieves a single blog by ID
 func (h *BlogHandler) GetBlog(w http.ResponseWriter, r *http.Request) {
 	blogID, err := getIDFromPath(r, "id")
@@ -77,7 +138,7 @@ func (h *BlogHandler) GetBlog(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	blog, err := h.blogUC.GetBlogByID(blogID)
+	blog, err := h.blogUC.GetBlogByID(blogID, getViewerID(r))
 	if err != nil {
 		if err == entity.ErrBlogNotFound {
 			response.Error(w, http.StatusNotFound, "Blog not found")
@@ -87,6 +148,20 @@ func (h *BlogHandler) GetBlog(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := h.mentionUC.RenderBlog(blog); err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to get blog")
+		return
+	}
+
+	if err := h.bookmarkUC.MarkBookmarked(getViewerID(r), blog); err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to get blog")
+		return
+	}
+
+	h.blogUC.RecordView(blog, getViewerID(r), getClientIP(r))
+
+	w.Header().Set("ETag", blogETag(blog.Version))
+	w.Header().Set("Link", `</webmention>; rel="webmention"`)
 	response.Success(w, blog)
 }
 
@@ -104,10 +179,18 @@ func (h *BlogHandler) UpdateBlog(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Edits must name the version they are based on
+	version, ok := getIfMatchVersion(r)
+	if !ok {
+		response.Error(w, http.StatusPreconditionRequired, "If-Match header with the blog ETag is required")
+		return
+	}
+
 	var req struct {
-		Title       string `json:"title"`
-		Description string `json:"description"`
-		Body        string `json:"body"`
+		Title       string   `json:"title"`
+		Description string   `json:"description"`
+		Body        string   `json:"body"`
+		Tags        []string `json:"tags"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -115,8 +198,20 @@ func (h *BlogHandler) UpdateBlog(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	blog, err := h.blogUC.UpdateBlog(blogID, req.Title, req.Description, req.Body, claims.UserID)
+	blog, err := h.blogUC.UpdateBlog(blogID, req.Title, req.Description, req.Body, req.Tags, claims.UserID, version)
 	if err != nil {
+		if err == entity.ErrVersionConflict {
+			w.Header().Set("ETag", blogETag(blog.Version))
+			response.JSON(w, http.StatusPreconditionFailed, map[string]interface{}{
+				"error":   "Blog has been modified since it was fetched",
+				"version": blog.Version,
+			})
+			return
+		}
+		if err == entity.ErrInvalidTitle || err == entity.ErrInvalidBody {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		if err == entity.ErrNotBlogOwner {
 			response.Error(w, http.StatusForbidden, "You can only edit your own blogs")
 			return
@@ -129,6 +224,7 @@ func (h *BlogHandler) UpdateBlog(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("ETag", blogETag(blog.Version))
 	response.Success(w, blog)
 }
 
@@ -161,11 +257,63 @@ func (h *BlogHandler) DeleteBlog(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response.Success(w, map[string]string{
-		"message": "Blog deleted successfully",
+		"message": "Blog moved to trash",
 	})
 }
 
-// LikeBlog likes or unlikes a blog
+// GetTrash retrieves the authenticated user's trashed blogs
+func (h *BlogHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	limit, offset := getPaginationParams(r)
+
+	blogs, err := h.blogUC.GetTrash(claims.UserID, limit, offset)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to get trash")
+		return
+	}
+
+	response.Success(w, map[string]interface{}{
+		"blogs":  blogs,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// RestoreBlog restores a blog post from the trash
+func (h *BlogHandler) RestoreBlog(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	blogID, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid blog ID")
+		return
+	}
+
+	blog, err := h.blogUC.RestoreBlog(blogID, claims.UserID)
+	if err != nil {
+		if err == entity.ErrBlogNotFound {
+			response.Error(w, http.StatusNotFound, "Blog not found in trash")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to restore blog")
+		return
+	}
+
+	w.Header().Set("ETag", blogETag(blog.Version))
+	response.Success(w, blog)
+}
+
+// LikeBlog adds or removes a reaction to a blog. "like" and "unlike" act
+// on the default reaction; "react" and "unreact" take a kind.
 func (h *BlogHandler) LikeBlog(w http.ResponseWriter, r *http.Request) {
 	claims, err := auth.GetUserFromContext(r)
 	if err != nil {
@@ -180,7 +328,8 @@ func (h *BlogHandler) LikeBlog(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Action string `json:"action"` // "like" or "unlike"
+		Action string `json:"action"` // "like", "unlike", "react" or "unreact"
+		Kind   string `json:"kind"`   // reaction kind for "react" and "unreact"
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -188,22 +337,82 @@ func (h *BlogHandler) LikeBlog(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Action == "like" {
-		err = h.blogUC.LikeBlog(blogID, claims.UserID)
-	} else if req.Action == "unlike" {
-		err = h.blogUC.UnlikeBlog(blogID, claims.UserID)
-	} else {
-		response.Error(w, http.StatusBadRequest, "Invalid action. Use 'like' or 'unlike'")
+	kind := strings.ToLower(strings.TrimSpace(req.Kind))
+	switch req.Action {
+	case "like":
+		err = h.blogUC.ReactToBlog(blogID, claims.UserID, entity.DefaultReaction)
+	case "unlike":
+		err = h.blogUC.RemoveReaction(blogID, claims.UserID, entity.DefaultReaction)
+	case "react":
+		err = h.blogUC.ReactToBlog(blogID, claims.UserID, kind)
+	case "unreact":
+		err = h.blogUC.RemoveReaction(blogID, claims.UserID, kind)
+	default:
+		response.Error(w, http.StatusBadRequest, "Invalid action. Use 'like', 'unlike', 'react' or 'unreact'")
 		return
 	}
 
 	if err != nil {
+		if err == entity.ErrInvalidReaction {
+			response.Error(w, http.StatusBadRequest, "Invalid reaction kind. Use one of: "+strings.Join(h.blogUC.ReactionKinds(), ", "))
+			return
+		}
+		if err == entity.ErrBlogNotFound {
+			response.Error(w, http.StatusNotFound, "Blog not found")
+			return
+		}
+		if err == entity.ErrUserBlocked {
+			response.Error(w, http.StatusForbidden, "You cannot react to this blog")
+			return
+		}
 		response.Error(w, http.StatusInternalServerError, "Failed to "+req.Action+" blog")
 		return
 	}
 
 	response.Success(w, map[string]string{
-		"message": "Successfully " + req.Action + "d blog",
+		"message": "Successfully " + strings.TrimSuffix(req.Action, "e") + "ed blog",
+	})
+}
+
+// GetBlogReactions retrieves a blog's reaction counts and the users who
+// reacted, optionally filtered by ?kind=
+func (h *BlogHandler) GetBlogReactions(w http.ResponseWriter, r *http.Request) {
+	blogID, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid blog ID")
+		return
+	}
+
+	limit, offset := getPaginationParams(r)
+	kind := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("kind")))
+
+	blog, err := h.blogUC.GetBlogByID(blogID, getViewerID(r))
+	if err != nil {
+		if err == entity.ErrBlogNotFound {
+			response.Error(w, http.StatusNotFound, "Blog not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to get blog")
+		return
+	}
+
+	reactions, err := h.blogUC.GetBlogReactions(blogID, getViewerID(r), kind, limit, offset)
+	if err != nil {
+		if err == entity.ErrInvalidReaction {
+			response.Error(w, http.StatusBadRequest, "Invalid reaction kind. Use one of: "+strings.Join(h.blogUC.ReactionKinds(), ", "))
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to get reactions")
+		return
+	}
+
+	response.Success(w, map[string]interface{}{
+		"counts":    blog.Reactions,
+		"kinds":     h.blogUC.ReactionKinds(),
+		"kind":      kind,
+		"reactions": reactions,
+		"limit":     limit,
+		"offset":    offset,
 	})
 }
 
@@ -217,8 +426,12 @@ func (h *BlogHandler) GetBlogLikes(w http.ResponseWriter, r *http.Request) {
 
 	limit, offset := getPaginationParams(r)
 
-	likes, err := h.blogUC.GetBlogLikes(blogID, limit, offset)
+	likes, err := h.blogUC.GetBlogLikes(blogID, getViewerID(r), limit, offset)
 	if err != nil {
+		if err == entity.ErrBlogNotFound {
+			response.Error(w, http.StatusNotFound, "Blog not found")
+			return
+		}
 		response.Error(w, http.StatusInternalServerError, "Failed to get likes")
 		return
 	}
@@ -0,0 +1,333 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/usecase"
+	"AbdelrahmanDwedar/blogo/pkg/auth"
+	"AbdelrahmanDwedar/blogo/pkg/response"
+)
+
+// BookmarkHandler handles bookmarks and reading lists
+type BookmarkHandler struct {
+	bookmarkUC *usecase.BookmarkUseCase
+}
+
+// NewBookmarkHandler creates a new bookmark handler
+func NewBookmarkHandler(bookmarkUC *usecase.BookmarkUseCase) *BookmarkHandler {
+	return &BookmarkHandler{bookmarkUC: bookmarkUC}
+}
+
+// readingListRequest is the body creating or editing a reading list
+type readingListRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Public      bool   `json:"public"`
+}
+
+// BookmarkBlog bookmarks or unbookmarks a blog for the authenticated user
+func (h *BookmarkHandler) BookmarkBlog(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	blogID, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid blog ID")
+		return
+	}
+
+	var req struct {
+		Action string `json:"action"` // "bookmark" or "unbookmark"
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	switch req.Action {
+	case "bookmark":
+		_, err = h.bookmarkUC.Bookmark(claims.UserID, blogID)
+	case "unbookmark":
+		err = h.bookmarkUC.Unbookmark(claims.UserID, blogID)
+	default:
+		response.Error(w, http.StatusBadRequest, "Invalid action. Use 'bookmark' or 'unbookmark'")
+		return
+	}
+
+	if err != nil {
+		if err == entity.ErrBlogNotFound {
+			response.Error(w, http.StatusNotFound, "Blog not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to "+req.Action+" blog")
+		return
+	}
+
+	response.Success(w, map[string]string{
+		"message": "Successfully " + req.Action + "ed blog",
+	})
+}
+
+// GetBookmarks retrieves the authenticated user's bookmarks, newest first.
+// Pages are walked with the opaque next_cursor returned by each response.
+func (h *BookmarkHandler) GetBookmarks(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	limit, _ := getPaginationParams(r)
+
+	cursor, err := entity.ParseCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid cursor")
+		return
+	}
+
+	bookmarks, next, err := h.bookmarkUC.GetBookmarks(claims.UserID, cursor, limit)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to get bookmarks")
+		return
+	}
+
+	nextCursor := ""
+	if next != nil {
+		nextCursor = next.String()
+	}
+
+	response.Success(w, map[string]interface{}{
+		"bookmarks":   bookmarks,
+		"limit":       limit,
+		"next_cursor": nextCursor,
+	})
+}
+
+// GetMyLists lists the authenticated user's reading lists, private ones
+// included
+func (h *BookmarkHandler) GetMyLists(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	lists, err := h.bookmarkUC.GetLists(claims.UserID, claims.UserID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to get reading lists")
+		return
+	}
+
+	response.Success(w, map[string]interface{}{
+		"lists": lists,
+	})
+}
+
+// GetUserLists lists a user's public reading lists
+func (h *BookmarkHandler) GetUserLists(w http.ResponseWriter, r *http.Request) {
+	userID, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	lists, err := h.bookmarkUC.GetLists(userID, getViewerID(r))
+	if err != nil {
+		if err == entity.ErrUserNotFound {
+			response.Error(w, http.StatusNotFound, "User not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to get reading lists")
+		return
+	}
+
+	response.Success(w, map[string]interface{}{
+		"lists": lists,
+	})
+}
+
+// CreateList creates a reading list for the authenticated user
+func (h *BookmarkHandler) CreateList(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req readingListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	list, err := h.bookmarkUC.CreateList(claims.UserID, req.Name, req.Description, req.Public)
+	if err != nil {
+		writeReadingListError(w, err, "Failed to create reading list")
+		return
+	}
+
+	response.Created(w, list)
+}
+
+// GetList retrieves a reading list with a page of its blogs, most recently
+// added first. Public lists can be shared by their URL; private ones are
+// only found by their owner.
+func (h *BookmarkHandler) GetList(w http.ResponseWriter, r *http.Request) {
+	listID, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid list ID")
+		return
+	}
+
+	limit, _ := getPaginationParams(r)
+
+	cursor, err := entity.ParseCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid cursor")
+		return
+	}
+
+	list, items, next, err := h.bookmarkUC.GetList(listID, getViewerID(r), cursor, limit)
+	if err != nil {
+		writeReadingListError(w, err, "Failed to get reading list")
+		return
+	}
+
+	nextCursor := ""
+	if next != nil {
+		nextCursor = next.String()
+	}
+
+	response.Success(w, map[string]interface{}{
+		"list":        list,
+		"items":       items,
+		"limit":       limit,
+		"next_cursor": nextCursor,
+	})
+}
+
+// UpdateList renames one of the authenticated user's reading lists,
+// changes its description and makes it public or private
+func (h *BookmarkHandler) UpdateList(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	listID, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid list ID")
+		return
+	}
+
+	var req readingListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	list, err := h.bookmarkUC.UpdateList(listID, claims.UserID, req.Name, req.Description, req.Public)
+	if err != nil {
+		writeReadingListError(w, err, "Failed to update reading list")
+		return
+	}
+
+	response.Success(w, list)
+}
+
+// DeleteList deletes one of the authenticated user's reading lists
+func (h *BookmarkHandler) DeleteList(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	listID, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid list ID")
+		return
+	}
+
+	if err := h.bookmarkUC.DeleteList(listID, claims.UserID); err != nil {
+		writeReadingListError(w, err, "Failed to delete reading list")
+		return
+	}
+
+	response.Success(w, map[string]string{
+		"message": "Reading list deleted",
+	})
+}
+
+// UpdateListItems adds a blog to or removes it from one of the
+// authenticated user's reading lists
+func (h *BookmarkHandler) UpdateListItems(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	listID, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid list ID")
+		return
+	}
+
+	var req struct {
+		Action string `json:"action"` // "add" or "remove"
+		BlogID int64  `json:"blog_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	switch req.Action {
+	case "add":
+		_, err = h.bookmarkUC.AddToList(listID, claims.UserID, req.BlogID)
+	case "remove":
+		err = h.bookmarkUC.RemoveFromList(listID, claims.UserID, req.BlogID)
+	default:
+		response.Error(w, http.StatusBadRequest, "Invalid action. Use 'add' or 'remove'")
+		return
+	}
+
+	if err != nil {
+		writeReadingListError(w, err, "Failed to update reading list")
+		return
+	}
+
+	message := "Blog added to reading list"
+	if req.Action == "remove" {
+		message = "Blog removed from reading list"
+	}
+	response.Success(w, map[string]string{
+		"message": message,
+	})
+}
+
+// writeReadingListError answers a failed reading list request, with
+// fallback as the message for unexpected errors
+func writeReadingListError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case entity.ErrReadingListNotFound:
+		response.Error(w, http.StatusNotFound, "Reading list not found")
+	case entity.ErrBlogNotFound:
+		response.Error(w, http.StatusNotFound, "Blog not found")
+	case entity.ErrInvalidListName:
+		response.Error(w, http.StatusBadRequest, "List name is required and must be at most 100 characters")
+	case entity.ErrInvalidListDescription:
+		response.Error(w, http.StatusBadRequest, "List description must be at most 500 characters")
+	default:
+		response.Error(w, http.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,359 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/usecase"
+	"AbdelrahmanDwedar/blogo/pkg/auth"
+	"AbdelrahmanDwedar/blogo/pkg/response"
+)
+
+// CommentHandler handles comment-related HTTP requests
+type CommentHandler struct {
+	commentUC *usecase.CommentUseCase
+	mentionUC *usecase.MentionUseCase
+}
+
+// NewCommentHandler creates a new comment handler
+func NewCommentHandler(commentUC *usecase.CommentUseCase, mentionUC *usecase.MentionUseCase) *CommentHandler {
+	return &CommentHandler{commentUC: commentUC, mentionUC: mentionUC}
+}
+
+// commentError writes the response for errors shared by comment endpoints
+func commentError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case entity.ErrBlogNotFound:
+		response.Error(w, http.StatusNotFound, "Blog not found")
+	case entity.ErrCommentNotFound:
+		response.Error(w, http.StatusNotFound, "Comment not found")
+	case entity.ErrParentCommentInvalid:
+		response.Error(w, http.StatusBadRequest, "Parent comment not found on this blog")
+	case entity.ErrCommentTooDeep:
+		response.Error(w, http.StatusBadRequest, "Replies cannot be nested this deep")
+	case entity.ErrInvalidComment:
+		response.Error(w, http.StatusBadRequest, "Comment body is required and must be at most 10000 characters")
+	case entity.ErrNotCommentAuthor:
+		response.Error(w, http.StatusForbidden, "You can only modify your own comments")
+	case entity.ErrNotBlogOwner:
+		response.Error(w, http.StatusForbidden, "Only the blog's author can moderate its comments")
+	case entity.ErrCommentsClosed:
+		response.Error(w, http.StatusForbidden, "Comments are closed on this blog")
+	case entity.ErrInvalidCommentMode:
+		response.Error(w, http.StatusBadRequest, "Invalid comment mode. Use 'open', 'locked' or 'disabled'")
+	case entity.ErrCommentNotPending:
+		response.Error(w, http.StatusConflict, "Comment is not pending approval")
+	case entity.ErrUserBlocked:
+		response.Error(w, http.StatusForbidden, "You cannot comment here")
+	case entity.ErrCannotPinReply:
+		response.Error(w, http.StatusBadRequest, "Only top-level comments can be pinned")
+	default:
+		response.Error(w, http.StatusInternalServerError, fallback)
+	}
+}
+
+// CreateComment adds a comment or reply to a blog
+func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	blogID, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid blog ID")
+		return
+	}
+
+	var req struct {
+		Body     string `json:"body"`
+		ParentID *int64 `json:"parent_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	comment, err := h.commentUC.CreateComment(blogID, claims.UserID, req.ParentID, req.Body)
+	if err != nil {
+		commentError(w, err, "Failed to create comment")
+		return
+	}
+
+	// Held comments are accepted but not yet visible
+	if comment.Status == entity.CommentPending {
+		response.JSON(w, http.StatusAccepted, comment)
+		return
+	}
+	response.Created(w, comment)
+}
+
+// GetComments retrieves a blog's comment threads
+func (h *CommentHandler) GetComments(w http.ResponseWriter, r *http.Request) {
+	blogID, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid blog ID")
+		return
+	}
+
+	limit, offset := getPaginationParams(r)
+
+	comments, err := h.commentUC.GetComments(blogID, getViewerID(r), limit, offset)
+	if err != nil {
+		commentError(w, err, "Failed to get comments")
+		return
+	}
+
+	if err := h.mentionUC.RenderComments(comments); err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to get comments")
+		return
+	}
+
+	response.Success(w, map[string]interface{}{
+		"comments": comments,
+		"limit":    limit,
+		"offset":   offset,
+	})
+}
+
+// UpdateComment edits a comment
+func (h *CommentHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	blogID, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid blog ID")
+		return
+	}
+
+	commentID, err := getIDFromPath(r, "comment")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid comment ID")
+		return
+	}
+
+	var req struct {
+		Body string `json:"body"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	comment, err := h.commentUC.UpdateComment(blogID, commentID, claims.UserID, req.Body)
+	if err != nil {
+		commentError(w, err, "Failed to update comment")
+		return
+	}
+
+	response.Success(w, comment)
+}
+
+// DeleteComment deletes a comment
+func (h *CommentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	blogID, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid blog ID")
+		return
+	}
+
+	commentID, err := getIDFromPath(r, "comment")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid comment ID")
+		return
+	}
+
+	if err := h.commentUC.DeleteComment(blogID, commentID, claims.UserID); err != nil {
+		commentError(w, err, "Failed to delete comment")
+		return
+	}
+
+	response.Success(w, map[string]string{
+		"message": "Comment deleted successfully",
+	})
+}
+
+// UpdateCommentSettings opens, locks or disables comments on a blog and
+// turns moderation of non-followers on or off
+func (h *CommentHandler) UpdateCommentSettings(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	blogID, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid blog ID")
+		return
+	}
+
+	var req struct {
+		Mode     string `json:"mode"` // "open", "locked" or "disabled"
+		Moderate bool   `json:"moderate"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	blog, err := h.commentUC.UpdateCommentSettings(blogID, claims.UserID, req.Mode, req.Moderate)
+	if err != nil {
+		commentError(w, err, "Failed to update comment settings")
+		return
+	}
+
+	response.Success(w, blog)
+}
+
+// PinComment pins a comment to the top of a blog's comments
+func (h *CommentHandler) PinComment(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	blogID, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid blog ID")
+		return
+	}
+
+	commentID, err := getIDFromPath(r, "comment")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid comment ID")
+		return
+	}
+
+	if err := h.commentUC.PinComment(blogID, commentID, claims.UserID); err != nil {
+		commentError(w, err, "Failed to pin comment")
+		return
+	}
+
+	response.Success(w, map[string]string{
+		"message": "Comment pinned successfully",
+	})
+}
+
+// UnpinComment removes the pinned comment of a blog
+func (h *CommentHandler) UnpinComment(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	blogID, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid blog ID")
+		return
+	}
+
+	if err := h.commentUC.UnpinComment(blogID, claims.UserID); err != nil {
+		commentError(w, err, "Failed to unpin comment")
+		return
+	}
+
+	response.Success(w, map[string]string{
+		"message": "Comment unpinned successfully",
+	})
+}
+
+// GetPendingComments retrieves the comments awaiting approval across all of
+// the user's blogs
+func (h *CommentHandler) GetPendingComments(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	limit, offset := getPaginationParams(r)
+
+	comments, err := h.commentUC.GetPendingComments(claims.UserID, limit, offset)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to get pending comments")
+		return
+	}
+
+	response.Success(w, map[string]interface{}{
+		"comments": comments,
+		"limit":    limit,
+		"offset":   offset,
+	})
+}
+
+// ApproveComment publishes a comment held for approval
+func (h *CommentHandler) ApproveComment(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	blogID, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid blog ID")
+		return
+	}
+
+	commentID, err := getIDFromPath(r, "comment")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid comment ID")
+		return
+	}
+
+	comment, err := h.commentUC.ApproveComment(blogID, commentID, claims.UserID)
+	if err != nil {
+		commentError(w, err, "Failed to approve comment")
+		return
+	}
+
+	response.Success(w, comment)
+}
+
+// RejectComment discards a comment held for approval
+func (h *CommentHandler) RejectComment(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	blogID, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid blog ID")
+		return
+	}
+
+	commentID, err := getIDFromPath(r, "comment")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid comment ID")
+		return
+	}
+
+	if err := h.commentUC.RejectComment(blogID, commentID, claims.UserID); err != nil {
+		commentError(w, err, "Failed to reject comment")
+		return
+	}
+
+	response.Success(w, map[string]string{
+		"message": "Comment rejected successfully",
+	})
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/usecase"
+	"AbdelrahmanDwedar/blogo/pkg/auth"
+	"AbdelrahmanDwedar/blogo/pkg/response"
+)
+
+// DigestHandler handles users' digest email settings and the links that
+// turn digests off
+type DigestHandler struct {
+	digestUC *usecase.DigestUseCase
+}
+
+// NewDigestHandler creates a new digest handler
+func NewDigestHandler(digestUC *usecase.DigestUseCase) *DigestHandler {
+	return &DigestHandler{digestUC: digestUC}
+}
+
+// GetSettings returns how often the authenticated user gets a digest
+func (h *DigestHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	settings, err := h.digestUC.GetSettings(claims.UserID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to get digest settings")
+		return
+	}
+
+	response.Success(w, settings)
+}
+
+// UpdateSettings sets how often the authenticated user gets a digest
+func (h *DigestHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req entity.DigestSettings
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	settings, err := h.digestUC.UpdateSettings(claims.UserID, req.Frequency)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidDigestFrequency) {
+			response.Error(w, http.StatusBadRequest, "Frequency must be 'off', 'daily' or 'weekly'")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to update digest settings")
+		return
+	}
+
+	response.Success(w, settings)
+}
+
+// Unsubscribe turns digests off from the link in a digest email. GET, for
+// the link in the body, asks to confirm; POST, from that page or for
+// one-click unsubscribing by mail clients (RFC 8058), turns them off.
+func (h *DigestHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		renderUnsubscribePage(w, r, "Turn off digests",
+			"Stop getting digest emails?", "Turn off digests")
+		return
+	}
+
+	if err := h.digestUC.Unsubscribe(r.URL.Query().Get("token")); err != nil {
+		if errors.Is(err, auth.ErrInvalidLinkToken) {
+			response.Error(w, http.StatusBadRequest, "This unsubscribe link is invalid")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to turn off digests")
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "Digests turned off"})
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/usecase"
+	"AbdelrahmanDwedar/blogo/pkg/auth"
+	"AbdelrahmanDwedar/blogo/pkg/response"
+)
+
+// ExportHandler handles static site export HTTP requests
+type ExportHandler struct {
+	exportUC *usecase.ExportUseCase
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(exportUC *usecase.ExportUseCase) *ExportHandler {
+	return &ExportHandler{exportUC: exportUC}
+}
+
+// exportView adds the status and download links to an export job
+func exportView(job *entity.Job) map[string]interface{} {
+	view := map[string]interface{}{
+		"job":        job,
+		"status_url": fmt.Sprintf("/api/u/me/export/%d", job.ID),
+	}
+	if job.Status == entity.JobStatusDone {
+		view["download_url"] = fmt.Sprintf("/api/u/me/export/%d/download", job.ID)
+	}
+	return view
+}
+
+// RequestExport starts an asynchronous export of the user's blogs
+func (h *ExportHandler) RequestExport(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	job, err := h.exportUC.RequestExport(claims.UserID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to start export")
+		return
+	}
+
+	response.JSON(w, http.StatusAccepted, exportView(job))
+}
+
+// GetExport reports the status of an export
+func (h *ExportHandler) GetExport(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	jobID, err := getIDFromPath(r, "job")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid export ID")
+		return
+	}
+
+	job, err := h.exportUC.GetExport(claims.UserID, jobID)
+	if err != nil {
+		if err == entity.ErrJobNotFound {
+			response.Error(w, http.StatusNotFound, "Export not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to get export")
+		return
+	}
+
+	response.Success(w, exportView(job))
+}
+
+// DownloadExport streams the ZIP archive of a finished export
+func (h *ExportHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	jobID, err := getIDFromPath(r, "job")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid export ID")
+		return
+	}
+
+	archive, err := h.exportUC.OpenExport(claims.UserID, jobID)
+	if err != nil {
+		if err == entity.ErrJobNotFound {
+			response.Error(w, http.StatusNotFound, "Export not found")
+			return
+		}
+		if err == entity.ErrJobNotDone {
+			response.Error(w, http.StatusConflict, "Export is not finished yet")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to open export")
+		return
+	}
+	defer archive.Close()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="blog-export-%d.zip"`, jobID))
+	if _, err := io.Copy(w, archive); err != nil {
+		log.Printf("⚠️  Failed to send export %d: %v\n", jobID, err)
+	}
+}
@@ -0,0 +1,187 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/usecase"
+	"AbdelrahmanDwedar/blogo/pkg/activitypub"
+	"AbdelrahmanDwedar/blogo/pkg/httpsig"
+	"AbdelrahmanDwedar/blogo/pkg/response"
+	"github.com/gorilla/mux"
+)
+
+// maxInboxBody is the largest activity accepted by an inbox
+const maxInboxBody = 1 << 20
+
+// FederationHandler serves the ActivityPub and WebFinger endpoints other
+// fediverse servers talk to
+type FederationHandler struct {
+	federationUC *usecase.FederationUseCase
+}
+
+// NewFederationHandler creates a new federation handler
+func NewFederationHandler(federationUC *usecase.FederationUseCase) *FederationHandler {
+	return &FederationHandler{federationUC: federationUC}
+}
+
+// document writes a JSON document of the given media type
+func document(w http.ResponseWriter, contentType string, payload interface{}) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(payload)
+}
+
+// activity writes an ActivityPub document
+func activity(w http.ResponseWriter, payload interface{}) {
+	document(w, activitypub.ContentType, payload)
+}
+
+// WebFinger resolves acct:username@host resources to actors
+func (h *FederationHandler) WebFinger(w http.ResponseWriter, r *http.Request) {
+	resource := r.URL.Query().Get("resource")
+	if resource == "" {
+		response.Error(w, http.StatusBadRequest, "Missing resource")
+		return
+	}
+
+	jrd, err := h.federationUC.WebFinger(resource)
+	if err != nil {
+		if err == entity.ErrUserNotFound {
+			response.Error(w, http.StatusNotFound, "Resource not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to resolve resource")
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	document(w, activitypub.WebFingerContentType, jrd)
+}
+
+// GetActor serves a user's actor
+func (h *FederationHandler) GetActor(w http.ResponseWriter, r *http.Request) {
+	actor, err := h.federationUC.GetActor(mux.Vars(r)["username"])
+	if err != nil {
+		if err == entity.ErrUserNotFound {
+			response.Error(w, http.StatusNotFound, "User not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to get actor")
+		return
+	}
+
+	activity(w, actor)
+}
+
+// GetOutbox serves a user's outbox, or one of its pages with ?page=
+func (h *FederationHandler) GetOutbox(w http.ResponseWriter, r *http.Request) {
+	username := mux.Vars(r)["username"]
+
+	var payload interface{}
+	var err error
+	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+		page, convErr := strconv.Atoi(pageStr)
+		if convErr != nil || page < 1 {
+			response.Error(w, http.StatusBadRequest, "Invalid page")
+			return
+		}
+		payload, err = h.federationUC.GetOutboxPage(username, page)
+	} else {
+		payload, err = h.federationUC.GetOutbox(username)
+	}
+
+	if err != nil {
+		if err == entity.ErrUserNotFound {
+			response.Error(w, http.StatusNotFound, "User not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to get outbox")
+		return
+	}
+
+	activity(w, payload)
+}
+
+// GetFollowers serves a user's followers collection
+func (h *FederationHandler) GetFollowers(w http.ResponseWriter, r *http.Request) {
+	followers, err := h.federationUC.GetFollowers(mux.Vars(r)["username"])
+	if err != nil {
+		if err == entity.ErrUserNotFound {
+			response.Error(w, http.StatusNotFound, "User not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to get followers")
+		return
+	}
+
+	activity(w, followers)
+}
+
+// GetArticle serves the Article object of a blog
+func (h *FederationHandler) GetArticle(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid blog ID")
+		return
+	}
+
+	article, err := h.federationUC.GetArticle(id)
+	if err != nil {
+		if err == entity.ErrBlogNotFound {
+			response.Error(w, http.StatusNotFound, "Blog not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to get article")
+		return
+	}
+
+	activity(w, article)
+}
+
+// Inbox accepts an activity posted by a remote server, which must be
+// signed with an HTTP Signature by the activity's actor
+func (h *FederationHandler) Inbox(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxInboxBody+1))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Failed to read activity")
+		return
+	}
+	if len(body) > maxInboxBody {
+		response.Error(w, http.StatusRequestEntityTooLarge, "Activity too large")
+		return
+	}
+
+	keyID, err := httpsig.Verify(r, body, h.federationUC.PublicKey)
+	if err == httpsig.ErrBadSignature {
+		// The sender may have rotated its key since it was cached
+		keyID, err = httpsig.Verify(r, body, h.federationUC.RefreshPublicKey)
+	}
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Invalid signature")
+		return
+	}
+
+	err = h.federationUC.HandleInbox(mux.Vars(r)["username"], keyID, body)
+	if err != nil {
+		switch {
+		case errors.Is(err, entity.ErrUserNotFound):
+			response.Error(w, http.StatusNotFound, "User not found")
+		case errors.Is(err, entity.ErrBlogNotFound):
+			response.Error(w, http.StatusNotFound, "Blog not found")
+		case errors.Is(err, entity.ErrInvalidActivity):
+			response.Error(w, http.StatusBadRequest, "Invalid activity")
+		case errors.Is(err, entity.ErrInvalidSignature):
+			response.Error(w, http.StatusUnauthorized, "Invalid signature")
+		default:
+			response.Error(w, http.StatusInternalServerError, "Failed to process activity")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
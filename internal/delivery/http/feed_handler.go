@@ -0,0 +1,61 @@
+package http
+
+import (
+	"net/http"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/usecase"
+	"AbdelrahmanDwedar/blogo/pkg/auth"
+	"AbdelrahmanDwedar/blogo/pkg/response"
+)
+
+// FeedHandler handles home timeline HTTP requests
+type FeedHandler struct {
+	timelineUC *usecase.TimelineUseCase
+	bookmarkUC *usecase.BookmarkUseCase
+}
+
+// NewFeedHandler creates a new feed handler
+func NewFeedHandler(timelineUC *usecase.TimelineUseCase, bookmarkUC *usecase.BookmarkUseCase) *FeedHandler {
+	return &FeedHandler{timelineUC: timelineUC, bookmarkUC: bookmarkUC}
+}
+
+// GetFeed retrieves the newest blogs by the authors the current user follows.
+// Pages are walked with the opaque next_cursor returned by each response.
+func (h *FeedHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	limit, _ := getPaginationParams(r)
+
+	cursor, err := entity.ParseCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid cursor")
+		return
+	}
+
+	blogs, next, err := h.timelineUC.GetFeed(claims.UserID, cursor, limit)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to get feed")
+		return
+	}
+
+	if err := h.bookmarkUC.MarkBookmarked(claims.UserID, blogs...); err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to get feed")
+		return
+	}
+
+	nextCursor := ""
+	if next != nil {
+		nextCursor = next.String()
+	}
+
+	response.Success(w, map[string]interface{}{
+		"blogs":       blogs,
+		"limit":       limit,
+		"next_cursor": nextCursor,
+	})
+}
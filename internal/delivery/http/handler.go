@@ -2,25 +2,76 @@ package http
 
 import (
 	"fmt"
+	"net"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"AbdelrahmanDwedar/blogo/internal/usecase"
+	"AbdelrahmanDwedar/blogo/pkg/auth"
 	"AbdelrahmanDwedar/blogo/pkg/response"
 	"github.com/gorilla/mux"
 )
 
 // Handler aggregates all HTTP handlers
 type Handler struct {
-	UserHandler *UserHandler
-	BlogHandler *BlogHandler
+	UserHandler           *UserHandler
+	BlogHandler           *BlogHandler
+	ImportHandler         *ImportHandler
+	ExportHandler         *ExportHandler
+	CommentHandler        *CommentHandler
+	MentionHandler        *MentionHandler
+	FeedHandler           *FeedHandler
+	NotificationHandler   *NotificationHandler
+	StreamHandler         *StreamHandler
+	RecommendationHandler *RecommendationHandler
+	FederationHandler     *FederationHandler
+	SyndicationHandler    *SyndicationHandler
+	WebmentionHandler     *WebmentionHandler
+	AccessTokenHandler    *AccessTokenHandler
+	MicropubHandler       *MicropubHandler
+	MetaWeblogHandler     *MetaWeblogHandler
+	NewsletterHandler     *NewsletterHandler
+	DigestHandler         *DigestHandler
+	PreferenceHandler     *PreferenceHandler
+	PushHandler           *PushHandler
+	BookmarkHandler       *BookmarkHandler
 }
 
 // NewHandler creates a new handler with all use cases
-func NewHandler(userUC *usecase.UserUseCase, blogUC *usecase.BlogUseCase) *Handler {
+func NewHandler(userUC *usecase.UserUseCase, blogUC *usecase.BlogUseCase, importUC *usecase.ImportUseCase,
+	exportUC *usecase.ExportUseCase, commentUC *usecase.CommentUseCase, mentionUC *usecase.MentionUseCase,
+	timelineUC *usecase.TimelineUseCase, notificationUC *usecase.NotificationUseCase,
+	streamUC *usecase.StreamUseCase, recommendationUC *usecase.RecommendationUseCase,
+	trendingUC *usecase.TrendingUseCase, federationUC *usecase.FederationUseCase,
+	syndicationUC *usecase.SyndicationUseCase, webmentionUC *usecase.WebmentionUseCase,
+	tokenUC *usecase.AccessTokenUseCase, micropubUC *usecase.MicropubUseCase,
+	metaWeblogUC *usecase.MetaWeblogUseCase, newsletterUC *usecase.NewsletterUseCase, bounceSecret string,
+	digestUC *usecase.DigestUseCase, preferenceUC *usecase.PreferenceUseCase,
+	notificationEmailUC *usecase.NotificationEmailUseCase, pushUC *usecase.PushUseCase,
+	bookmarkUC *usecase.BookmarkUseCase) *Handler {
 	return &Handler{
-		UserHandler: NewUserHandler(userUC),
-		BlogHandler: NewBlogHandler(blogUC),
+		UserHandler:           NewUserHandler(userUC),
+		BlogHandler:           NewBlogHandler(blogUC, mentionUC, trendingUC, bookmarkUC),
+		ImportHandler:         NewImportHandler(importUC),
+		ExportHandler:         NewExportHandler(exportUC),
+		CommentHandler:        NewCommentHandler(commentUC, mentionUC),
+		MentionHandler:        NewMentionHandler(mentionUC),
+		FeedHandler:           NewFeedHandler(timelineUC, bookmarkUC),
+		NotificationHandler:   NewNotificationHandler(notificationUC),
+		StreamHandler:         NewStreamHandler(streamUC),
+		RecommendationHandler: NewRecommendationHandler(recommendationUC),
+		FederationHandler:     NewFederationHandler(federationUC),
+		SyndicationHandler:    NewSyndicationHandler(syndicationUC),
+		WebmentionHandler:     NewWebmentionHandler(webmentionUC),
+		AccessTokenHandler:    NewAccessTokenHandler(tokenUC),
+		MicropubHandler:       NewMicropubHandler(micropubUC, tokenUC),
+		MetaWeblogHandler:     NewMetaWeblogHandler(metaWeblogUC),
+		NewsletterHandler:     NewNewsletterHandler(newsletterUC, bounceSecret),
+		DigestHandler:         NewDigestHandler(digestUC),
+		PreferenceHandler:     NewPreferenceHandler(preferenceUC, notificationEmailUC),
+		PushHandler:           NewPushHandler(pushUC),
+		BookmarkHandler:       NewBookmarkHandler(bookmarkUC),
 	}
 }
 
@@ -40,6 +91,26 @@ func getIDFromPath(r *http.Request, key string) (int64, error) {
 	return id, nil
 }
 
+// getViewerID returns the ID of the authenticated user, or 0 for anonymous
+// requests
+func getViewerID(r *http.Request) int64 {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		return 0
+	}
+	return claims.UserID
+}
+
+// getClientIP returns the address the request came from. Forwarding
+// headers are ignored, since any client can set them.
+func getClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
 func getPaginationParams(r *http.Request) (limit, offset int) {
 	limitStr := r.URL.Query().Get("limit")
 	offsetStr := r.URL.Query().Get("offset")
@@ -62,4 +133,29 @@ func getPaginationParams(r *http.Request) (limit, offset int) {
 	return limit, offset
 }
 
+// blogETag formats a blog version as a strong entity tag
+func blogETag(version int64) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// getIfMatchVersion reads the blog version from the If-Match header. ok is
+// false when the header is absent. A value that is not one of our strong
+// ETags yields version -1, which never matches a stored blog.
+func getIfMatchVersion(r *http.Request) (version int64, ok bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		return 0, false
+	}
+
+	tag := strings.Trim(header, `"`)
+	if strings.HasPrefix(header, "W/") || tag == header {
+		return -1, true
+	}
+
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil {
+		return -1, true
+	}
+	return version, true
+}
 
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/usecase"
+	"AbdelrahmanDwedar/blogo/pkg/auth"
+	"AbdelrahmanDwedar/blogo/pkg/response"
+)
+
+// maxImportSize caps the size of an uploaded import file
+const maxImportSize = 32 << 20
+
+// ImportHandler handles blog import HTTP requests
+type ImportHandler struct {
+	importUC *usecase.ImportUseCase
+}
+
+// NewImportHandler creates a new import handler
+func NewImportHandler(importUC *usecase.ImportUseCase) *ImportHandler {
+	return &ImportHandler{importUC: importUC}
+}
+
+// ImportBlogs imports posts from an uploaded WordPress WXR export, Markdown
+// file or ZIP of Markdown files, or JSON file
+func (h *ImportHandler) ImportBlogs(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "A multipart 'file' upload is required")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Failed to read upload")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	report, err := h.importUC.ImportFile(claims.UserID, format, header.Filename, data, dryRun)
+	if err != nil {
+		if errors.Is(err, entity.ErrUnknownImportFormat) {
+			response.Error(w, http.StatusBadRequest, "Unknown format. Use 'wxr', 'markdown' or 'json'")
+			return
+		}
+		if errors.Is(err, entity.ErrImportTooLarge) {
+			response.Error(w, http.StatusRequestEntityTooLarge, err.Error())
+			return
+		}
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.Success(w, report)
+}
@@ -0,0 +1,42 @@
+package http
+
+import (
+	"net/http"
+
+	"AbdelrahmanDwedar/blogo/internal/usecase"
+	"AbdelrahmanDwedar/blogo/pkg/auth"
+	"AbdelrahmanDwedar/blogo/pkg/response"
+)
+
+// MentionHandler handles mention-related HTTP requests
+type MentionHandler struct {
+	mentionUC *usecase.MentionUseCase
+}
+
+// NewMentionHandler creates a new mention handler
+func NewMentionHandler(mentionUC *usecase.MentionUseCase) *MentionHandler {
+	return &MentionHandler{mentionUC: mentionUC}
+}
+
+// GetMentions retrieves the posts and comments that mention the current user
+func (h *MentionHandler) GetMentions(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	limit, offset := getPaginationParams(r)
+
+	mentions, err := h.mentionUC.GetMentions(claims.UserID, limit, offset)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to get mentions")
+		return
+	}
+
+	response.Success(w, map[string]interface{}{
+		"mentions": mentions,
+		"limit":    limit,
+		"offset":   offset,
+	})
+}
@@ -0,0 +1,388 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/usecase"
+	"AbdelrahmanDwedar/blogo/pkg/response"
+	"AbdelrahmanDwedar/blogo/pkg/xmlrpc"
+	"github.com/gorilla/mux"
+)
+
+// maxXMLRPCSize bounds the body of an XML-RPC call, leaving room for a
+// base64-encoded media file of the largest size accepted
+const maxXMLRPCSize = entity.MaxMediaSize*4/3 + 1<<20
+
+// metaWeblogMethod carries out an XML-RPC method, returning its result
+type metaWeblogMethod func(call *xmlrpc.Call) (interface{}, error)
+
+// MetaWeblogHandler handles the MetaWeblog XML-RPC API of desktop blogging
+// tools and serves the media files they upload
+type MetaWeblogHandler struct {
+	metaWeblogUC *usecase.MetaWeblogUseCase
+	methods      map[string]metaWeblogMethod
+}
+
+// NewMetaWeblogHandler creates a new MetaWeblog handler
+func NewMetaWeblogHandler(metaWeblogUC *usecase.MetaWeblogUseCase) *MetaWeblogHandler {
+	h := &MetaWeblogHandler{metaWeblogUC: metaWeblogUC}
+	h.methods = map[string]metaWeblogMethod{
+		"blogger.getUsersBlogs":     h.getUsersBlogs,
+		"metaWeblog.getUsersBlogs":  h.getUsersBlogs,
+		"metaWeblog.newPost":        h.newPost,
+		"metaWeblog.editPost":       h.editPost,
+		"metaWeblog.getPost":        h.getPost,
+		"metaWeblog.getRecentPosts": h.getRecentPosts,
+		"metaWeblog.getCategories":  h.getCategories,
+		"blogger.deletePost":        h.deletePost,
+		"metaWeblog.deletePost":     h.deletePost,
+		"metaWeblog.newMediaObject": h.newMediaObject,
+	}
+	return h
+}
+
+// ServeXMLRPC answers an XML-RPC method call. Errors are reported as
+// faults, which XML-RPC sends with a 200 status.
+func (h *MetaWeblogHandler) ServeXMLRPC(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxXMLRPCSize)
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+
+	call, err := xmlrpc.ParseCall(r.Body)
+	if err != nil {
+		xmlrpc.WriteFault(w, &xmlrpc.Fault{Code: xmlrpc.FaultParse, String: "Invalid XML-RPC request"})
+		return
+	}
+
+	method, ok := h.methods[call.Method]
+	if !ok {
+		xmlrpc.WriteFault(w, &xmlrpc.Fault{Code: xmlrpc.FaultMethodNotFound, String: "Unsupported method " + call.Method})
+		return
+	}
+
+	result, err := method(call)
+	if err != nil {
+		xmlrpc.WriteFault(w, metaWeblogFault(err))
+		return
+	}
+	xmlrpc.WriteResponse(w, result)
+}
+
+// metaWeblogFault returns the fault reporting an error, using the codes
+// WordPress uses, which clients know
+func metaWeblogFault(err error) *xmlrpc.Fault {
+	var fault *xmlrpc.Fault
+	switch {
+	case errors.As(err, &fault):
+		return fault
+	case errors.Is(err, entity.ErrInvalidCredentials):
+		return &xmlrpc.Fault{Code: 403, String: "Incorrect username or application password"}
+	case errors.Is(err, entity.ErrInsufficientScope):
+		return &xmlrpc.Fault{Code: 401, String: "The application password lacks the scope for this action"}
+	case errors.Is(err, entity.ErrNotBlogOwner):
+		return &xmlrpc.Fault{Code: 401, String: "You can only use your own weblog"}
+	case errors.Is(err, entity.ErrBlogNotFound):
+		return &xmlrpc.Fault{Code: 404, String: "Post not found"}
+	case errors.Is(err, entity.ErrInvalidTitle), errors.Is(err, entity.ErrInvalidBody):
+		return &xmlrpc.Fault{Code: 400, String: "Posts need a body"}
+	case errors.Is(err, entity.ErrInvalidMedia):
+		return &xmlrpc.Fault{Code: 400, String: "Unsupported media file; upload JPEG, PNG, GIF, WebP, MP3, MP4 or PDF files"}
+	case errors.Is(err, entity.ErrMediaTooLarge):
+		return &xmlrpc.Fault{Code: 400, String: "Media file is too large"}
+	case errors.Is(err, entity.ErrVersionConflict):
+		return &xmlrpc.Fault{Code: 409, String: "The post changed while it was being edited"}
+	default:
+		return &xmlrpc.Fault{Code: 500, String: "Failed to process request"}
+	}
+}
+
+// login authenticates the username and application password given as the
+// parameters at i and i+1
+func (h *MetaWeblogHandler) login(call *xmlrpc.Call, i int) (*entity.AccessToken, error) {
+	username, err := call.String(i)
+	if err != nil {
+		return nil, err
+	}
+	password, err := call.String(i + 1)
+	if err != nil {
+		return nil, err
+	}
+	return h.metaWeblogUC.Login(username, password)
+}
+
+// callID returns the numeric ID given as the parameter at i
+func callID(call *xmlrpc.Call, i int) (int64, error) {
+	n, err := call.Int(i)
+	if err != nil {
+		return 0, err
+	}
+	return int64(n), nil
+}
+
+// checkPublish rejects calls asking to save a draft, as blogs are published
+// as soon as they are saved
+func checkPublish(call *xmlrpc.Call, i int) error {
+	publish, err := call.Bool(i, true)
+	if err != nil {
+		return err
+	}
+	if !publish {
+		return &xmlrpc.Fault{Code: 400, String: "Drafts are not supported; publish the post instead"}
+	}
+	return nil
+}
+
+// getUsersBlogs returns the user's weblog: blogger.getUsersBlogs(appkey,
+// username, password)
+func (h *MetaWeblogHandler) getUsersBlogs(call *xmlrpc.Call) (interface{}, error) {
+	token, err := h.login(call, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := h.metaWeblogUC.GetUser(token)
+	if err != nil {
+		return nil, err
+	}
+
+	name := user.DisplayName
+	if name == "" {
+		name = user.Username
+	}
+	return []interface{}{map[string]interface{}{
+		"blogid":   strconv.FormatInt(user.ID, 10),
+		"blogName": name,
+		"url":      h.metaWeblogUC.WeblogURL(user.ID),
+		"isAdmin":  false,
+	}}, nil
+}
+
+// newPost publishes a blog: metaWeblog.newPost(blogid, username, password,
+// struct, publish)
+func (h *MetaWeblogHandler) newPost(call *xmlrpc.Call) (interface{}, error) {
+	weblogID, err := callID(call, 0)
+	if err != nil {
+		return nil, err
+	}
+	token, err := h.login(call, 1)
+	if err != nil {
+		return nil, err
+	}
+	post, err := metaWeblogPost(call, 3)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPublish(call, 4); err != nil {
+		return nil, err
+	}
+
+	blog, err := h.metaWeblogUC.NewPost(token, weblogID, post)
+	if err != nil {
+		return nil, err
+	}
+	return strconv.FormatInt(blog.ID, 10), nil
+}
+
+// editPost changes a blog: metaWeblog.editPost(postid, username, password,
+// struct, publish)
+func (h *MetaWeblogHandler) editPost(call *xmlrpc.Call) (interface{}, error) {
+	blogID, err := callID(call, 0)
+	if err != nil {
+		return nil, err
+	}
+	token, err := h.login(call, 1)
+	if err != nil {
+		return nil, err
+	}
+	post, err := metaWeblogPost(call, 3)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPublish(call, 4); err != nil {
+		return nil, err
+	}
+
+	if _, err := h.metaWeblogUC.EditPost(token, blogID, post); err != nil {
+		return nil, err
+	}
+	return true, nil
+}
+
+// getPost returns a blog: metaWeblog.getPost(postid, username, password)
+func (h *MetaWeblogHandler) getPost(call *xmlrpc.Call) (interface{}, error) {
+	blogID, err := callID(call, 0)
+	if err != nil {
+		return nil, err
+	}
+	token, err := h.login(call, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	blog, err := h.metaWeblogUC.GetPost(token, blogID)
+	if err != nil {
+		return nil, err
+	}
+	return h.postStruct(blog), nil
+}
+
+// getRecentPosts returns the newest blogs of the weblog:
+// metaWeblog.getRecentPosts(blogid, username, password, numberOfPosts)
+func (h *MetaWeblogHandler) getRecentPosts(call *xmlrpc.Call) (interface{}, error) {
+	weblogID, err := callID(call, 0)
+	if err != nil {
+		return nil, err
+	}
+	token, err := h.login(call, 1)
+	if err != nil {
+		return nil, err
+	}
+	count, err := call.Int(3)
+	if err != nil {
+		return nil, err
+	}
+
+	blogs, err := h.metaWeblogUC.GetRecentPosts(token, weblogID, count)
+	if err != nil {
+		return nil, err
+	}
+
+	posts := []interface{}{}
+	for _, blog := range blogs {
+		posts = append(posts, h.postStruct(blog))
+	}
+	return posts, nil
+}
+
+// getCategories returns no categories, as tags are free-form:
+// metaWeblog.getCategories(blogid, username, password)
+func (h *MetaWeblogHandler) getCategories(call *xmlrpc.Call) (interface{}, error) {
+	if _, err := h.login(call, 1); err != nil {
+		return nil, err
+	}
+	return []interface{}{}, nil
+}
+
+// deletePost moves a blog to the trash: blogger.deletePost(appkey, postid,
+// username, password, publish)
+func (h *MetaWeblogHandler) deletePost(call *xmlrpc.Call) (interface{}, error) {
+	blogID, err := callID(call, 1)
+	if err != nil {
+		return nil, err
+	}
+	token, err := h.login(call, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.metaWeblogUC.DeletePost(token, blogID); err != nil {
+		return nil, err
+	}
+	return true, nil
+}
+
+// newMediaObject stores an uploaded file: metaWeblog.newMediaObject(blogid,
+// username, password, struct{name, type, bits})
+func (h *MetaWeblogHandler) newMediaObject(call *xmlrpc.Call) (interface{}, error) {
+	weblogID, err := callID(call, 0)
+	if err != nil {
+		return nil, err
+	}
+	token, err := h.login(call, 1)
+	if err != nil {
+		return nil, err
+	}
+	file, err := call.Struct(3)
+	if err != nil {
+		return nil, err
+	}
+
+	name, _ := file["name"].(string)
+	contentType, _ := file["type"].(string)
+	bits, _ := file["bits"].([]byte)
+
+	url, err := h.metaWeblogUC.NewMediaObject(token, weblogID, name, contentType, bits)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"url": url}, nil
+}
+
+// metaWeblogPost reads the post struct given as the parameter at i. The
+// description is the body and mt_excerpt the description; categories and
+// mt_keywords together are the tags.
+func metaWeblogPost(call *xmlrpc.Call, i int) (*usecase.MetaWeblogPost, error) {
+	fields, err := call.Struct(i)
+	if err != nil {
+		return nil, err
+	}
+
+	post := &usecase.MetaWeblogPost{}
+	if title, ok := fields["title"].(string); ok {
+		post.Title = &title
+	}
+	if excerpt, ok := fields["mt_excerpt"].(string); ok {
+		post.Excerpt = &excerpt
+	}
+	if body, ok := fields["description"].(string); ok {
+		post.Body = &body
+	}
+
+	if categories, ok := fields["categories"].([]interface{}); ok {
+		post.Tags = []string{}
+		for _, category := range categories {
+			if tag, ok := category.(string); ok {
+				post.Tags = append(post.Tags, tag)
+			}
+		}
+	}
+	if keywords, ok := fields["mt_keywords"].(string); ok {
+		if post.Tags == nil {
+			post.Tags = []string{}
+		}
+		post.Tags = append(post.Tags, strings.Split(keywords, ",")...)
+	}
+
+	return post, nil
+}
+
+// postStruct returns a blog as a MetaWeblog post struct
+func (h *MetaWeblogHandler) postStruct(blog *entity.Blog) map[string]interface{} {
+	link := h.metaWeblogUC.BlogURL(blog.ID)
+	return map[string]interface{}{
+		"postid":      strconv.FormatInt(blog.ID, 10),
+		"userid":      strconv.FormatInt(blog.AuthorID, 10),
+		"title":       blog.Title,
+		"description": blog.Body,
+		"mt_excerpt":  blog.Description,
+		"categories":  blog.Tags,
+		"mt_keywords": strings.Join(blog.Tags, ", "),
+		"dateCreated": blog.CreatedAt,
+		"link":        link,
+		"permaLink":   link,
+	}
+}
+
+// GetMedia serves an uploaded media file
+func (h *MetaWeblogHandler) GetMedia(w http.ResponseWriter, r *http.Request) {
+	contentType, file, err := h.metaWeblogUC.OpenMedia(mux.Vars(r)["name"])
+	if err != nil {
+		if errors.Is(err, entity.ErrMediaNotFound) {
+			response.Error(w, http.StatusNotFound, "Media file not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to open media file")
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	// Media file names are unique, so files never change
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	io.Copy(w, file)
+}
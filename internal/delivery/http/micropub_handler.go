@@ -0,0 +1,177 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/usecase"
+	"AbdelrahmanDwedar/blogo/pkg/micropub"
+	"AbdelrahmanDwedar/blogo/pkg/response"
+)
+
+// maxMicropubSize bounds the body of a Micropub request
+const maxMicropubSize = 1 << 20
+
+// MicropubHandler handles Micropub requests, authenticated with access
+// tokens rather than session tokens
+type MicropubHandler struct {
+	micropubUC *usecase.MicropubUseCase
+	tokenUC    *usecase.AccessTokenUseCase
+}
+
+// NewMicropubHandler creates a new Micropub handler
+func NewMicropubHandler(micropubUC *usecase.MicropubUseCase, tokenUC *usecase.AccessTokenUseCase) *MicropubHandler {
+	return &MicropubHandler{micropubUC: micropubUC, tokenUC: tokenUC}
+}
+
+// micropubError writes a Micropub error response
+func micropubError(w http.ResponseWriter, status int, code, description string) {
+	if status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+	response.JSON(w, status, &micropub.Error{Error: code, Description: description})
+}
+
+// authenticate returns the access token of a request, given as a bearer
+// token or, in form requests, as access_token. It writes the error
+// response itself when the request carries no valid token.
+func (h *MicropubHandler) authenticate(w http.ResponseWriter, r *http.Request, formToken string) (*entity.AccessToken, bool) {
+	secret := formToken
+	if header := r.Header.Get("Authorization"); header != "" {
+		scheme, value, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") {
+			micropubError(w, http.StatusUnauthorized, micropub.ErrorUnauthorized, "Invalid authorization header format")
+			return nil, false
+		}
+		secret = strings.TrimSpace(value)
+	}
+
+	token, err := h.tokenUC.Authenticate(secret)
+	if err != nil {
+		if errors.Is(err, entity.ErrAccessTokenNotFound) {
+			micropubError(w, http.StatusUnauthorized, micropub.ErrorUnauthorized, "A valid access token is required")
+			return nil, false
+		}
+		micropubError(w, http.StatusInternalServerError, "server_error", "Failed to check access token")
+		return nil, false
+	}
+	return token, true
+}
+
+// Query answers q=config, q=syndicate-to and q=source queries
+func (h *MicropubHandler) Query(w http.ResponseWriter, r *http.Request) {
+	token, ok := h.authenticate(w, r, "")
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	switch query.Get("q") {
+	case "config":
+		response.Success(w, h.micropubUC.Config())
+	case "syndicate-to":
+		response.Success(w, map[string]interface{}{"syndicate-to": h.micropubUC.Config().SyndicateTo})
+	case "source":
+		properties := query["properties[]"]
+		if len(properties) == 0 {
+			properties = query["properties"]
+		}
+
+		entry, err := h.micropubUC.Source(token, query.Get("url"), properties)
+		if err != nil {
+			h.publishError(w, err)
+			return
+		}
+		response.Success(w, entry)
+	default:
+		micropubError(w, http.StatusBadRequest, micropub.ErrorInvalidRequest, "Unsupported query. Use q=config, q=syndicate-to or q=source")
+	}
+}
+
+// Publish creates, updates, deletes or undeletes a blog from a form-encoded
+// or JSON Micropub request
+func (h *MicropubHandler) Publish(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxMicropubSize)
+
+	var req *micropub.Request
+	var formToken string
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/json":
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			micropubError(w, http.StatusBadRequest, micropub.ErrorInvalidRequest, "Request body is too large")
+			return
+		}
+		req, err = micropub.ParseJSON(body)
+		if err != nil {
+			micropubError(w, http.StatusBadRequest, micropub.ErrorInvalidRequest, "Invalid Micropub JSON request")
+			return
+		}
+	case "application/x-www-form-urlencoded", "multipart/form-data":
+		var err error
+		if mediaType == "multipart/form-data" {
+			err = r.ParseMultipartForm(maxMicropubSize)
+		} else {
+			err = r.ParseForm()
+		}
+		if err != nil {
+			micropubError(w, http.StatusBadRequest, micropub.ErrorInvalidRequest, "Invalid form body")
+			return
+		}
+		formToken = r.PostForm.Get("access_token")
+		req, err = micropub.ParseForm(r.PostForm)
+		if err != nil {
+			micropubError(w, http.StatusBadRequest, micropub.ErrorInvalidRequest, "Invalid Micropub form request; updates must be sent as JSON")
+			return
+		}
+	default:
+		micropubError(w, http.StatusUnsupportedMediaType, micropub.ErrorInvalidRequest, "Use a form-encoded or JSON body")
+		return
+	}
+
+	token, ok := h.authenticate(w, r, formToken)
+	if !ok {
+		return
+	}
+
+	blog, err := h.micropubUC.Publish(token, req)
+	if err != nil {
+		h.publishError(w, err)
+		return
+	}
+
+	switch req.Action {
+	case micropub.ActionCreate:
+		w.Header().Set("Location", h.micropubUC.BlogURL(blog.ID))
+		response.Created(w, blog)
+	case micropub.ActionDelete:
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		response.Success(w, blog)
+	}
+}
+
+// publishError writes the Micropub error response for a failed request
+func (h *MicropubHandler) publishError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, micropub.ErrInvalidRequest):
+		micropubError(w, http.StatusBadRequest, micropub.ErrorInvalidRequest, "Invalid Micropub request")
+	case errors.Is(err, entity.ErrInvalidTitle), errors.Is(err, entity.ErrInvalidBody):
+		micropubError(w, http.StatusBadRequest, micropub.ErrorInvalidRequest, "Posts need content")
+	case errors.Is(err, entity.ErrBlogNotFound):
+		micropubError(w, http.StatusBadRequest, micropub.ErrorInvalidRequest, "Post not found")
+	case errors.Is(err, entity.ErrInsufficientScope):
+		micropubError(w, http.StatusForbidden, micropub.ErrorInsufficientScope, "The access token lacks the scope for this action")
+	case errors.Is(err, entity.ErrNotBlogOwner):
+		micropubError(w, http.StatusForbidden, micropub.ErrorForbidden, "You can only change your own posts")
+	case errors.Is(err, entity.ErrVersionConflict):
+		micropubError(w, http.StatusConflict, micropub.ErrorInvalidRequest, "The post changed while it was being updated")
+	default:
+		micropubError(w, http.StatusInternalServerError, "server_error", "Failed to process Micropub request")
+	}
+}
@@ -0,0 +1,140 @@
+package http
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/usecase"
+	"AbdelrahmanDwedar/blogo/pkg/auth"
+	"AbdelrahmanDwedar/blogo/pkg/response"
+)
+
+// NewsletterHandler handles email subscriptions to authors and the links
+// and bounce reports that come back from the emails
+type NewsletterHandler struct {
+	newsletterUC *usecase.NewsletterUseCase
+	bounceSecret string
+}
+
+// NewNewsletterHandler creates a new newsletter handler. Bounce reports
+// must carry bounceSecret as a bearer token; they are refused when it is
+// empty.
+func NewNewsletterHandler(newsletterUC *usecase.NewsletterUseCase, bounceSecret string) *NewsletterHandler {
+	return &NewsletterHandler{newsletterUC: newsletterUC, bounceSecret: bounceSecret}
+}
+
+// SubscribeRequest represents the request body for subscribing by email
+type SubscribeRequest struct {
+	Email string `json:"email"`
+}
+
+// Subscribe subscribes an email address to an author. The answer is the
+// same whether or not the address was subscribed before.
+func (h *NewsletterHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	authorID, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req SubscribeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.newsletterUC.Subscribe(authorID, req.Email); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrInvalidEmail):
+			response.Error(w, http.StatusBadRequest, "Invalid email address")
+		case errors.Is(err, entity.ErrUserNotFound):
+			response.Error(w, http.StatusNotFound, "User not found")
+		default:
+			response.Error(w, http.StatusInternalServerError, "Failed to subscribe")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusAccepted, map[string]string{
+		"message": "Check your inbox to confirm your subscription",
+	})
+}
+
+// Confirm confirms a subscription from the link in its confirmation email
+func (h *NewsletterHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.newsletterUC.Confirm(r.URL.Query().Get("token")); err != nil {
+		switch {
+		case errors.Is(err, auth.ErrInvalidLinkToken):
+			response.Error(w, http.StatusBadRequest, "This confirmation link is invalid or has expired")
+		case errors.Is(err, entity.ErrSubscriptionNotFound):
+			response.Error(w, http.StatusNotFound, "Subscription not found")
+		default:
+			response.Error(w, http.StatusInternalServerError, "Failed to confirm subscription")
+		}
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "Subscription confirmed"})
+}
+
+// Unsubscribe ends a subscription from the link in a newsletter email. GET,
+// for the link in the body, asks to confirm; POST, from that page or for
+// one-click unsubscribing by mail clients (RFC 8058), unsubscribes.
+func (h *NewsletterHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		renderUnsubscribePage(w, r, "Unsubscribe",
+			"Stop getting this author's new posts by email?", "Unsubscribe")
+		return
+	}
+
+	if err := h.newsletterUC.Unsubscribe(r.URL.Query().Get("token")); err != nil {
+		if errors.Is(err, auth.ErrInvalidLinkToken) {
+			response.Error(w, http.StatusBadRequest, "This unsubscribe link is invalid")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to unsubscribe")
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "Unsubscribed"})
+}
+
+// BounceRequest represents a bounce reported by the mail provider
+type BounceRequest struct {
+	Email string `json:"email"`
+	Type  string `json:"type"`
+}
+
+// RecordBounce records a bounce or complaint reported by the mail
+// provider, authenticated with the bounce secret
+func (h *NewsletterHandler) RecordBounce(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if h.bounceSecret == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.bounceSecret)) != 1 {
+		response.Error(w, http.StatusUnauthorized, "Invalid bounce secret")
+		return
+	}
+
+	var req BounceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.newsletterUC.RecordBounce(req.Email, req.Type); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrInvalidEmail):
+			response.Error(w, http.StatusBadRequest, "Invalid email address")
+		case errors.Is(err, entity.ErrInvalidBounce):
+			response.Error(w, http.StatusBadRequest, "Bounce type must be hard, soft or complaint")
+		default:
+			response.Error(w, http.StatusInternalServerError, "Failed to record bounce")
+		}
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "Bounce recorded"})
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"net/http"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/usecase"
+	"AbdelrahmanDwedar/blogo/pkg/auth"
+	"AbdelrahmanDwedar/blogo/pkg/response"
+)
+
+// NotificationHandler handles notification-related HTTP requests
+type NotificationHandler struct {
+	notificationUC *usecase.NotificationUseCase
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notificationUC *usecase.NotificationUseCase) *NotificationHandler {
+	return &NotificationHandler{notificationUC: notificationUC}
+}
+
+// GetNotifications retrieves the current user's notifications, grouped, with
+// the number of unread groups
+func (h *NotificationHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	limit, offset := getPaginationParams(r)
+
+	groups, unread, err := h.notificationUC.GetNotifications(claims.UserID, limit, offset)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to get notifications")
+		return
+	}
+
+	response.Success(w, map[string]interface{}{
+		"notifications": groups,
+		"unread_count":  unread,
+		"limit":         limit,
+		"offset":        offset,
+	})
+}
+
+// MarkRead marks a notification and the rest of its group read
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid notification ID")
+		return
+	}
+
+	if err := h.notificationUC.MarkRead(claims.UserID, id); err != nil {
+		if err == entity.ErrNotificationNotFound {
+			response.Error(w, http.StatusNotFound, "Notification not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to mark notification read")
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "Notification marked read"})
+}
+
+// MarkAllRead marks all of the current user's notifications read
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.notificationUC.MarkAllRead(claims.UserID); err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to mark notifications read")
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "All notifications marked read"})
+}
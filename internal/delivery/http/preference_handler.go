@@ -0,0 +1,96 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/usecase"
+	"AbdelrahmanDwedar/blogo/pkg/auth"
+	"AbdelrahmanDwedar/blogo/pkg/response"
+)
+
+// PreferenceHandler handles users' notification preferences and the links
+// in notification emails that turn them off
+type PreferenceHandler struct {
+	preferenceUC        *usecase.PreferenceUseCase
+	notificationEmailUC *usecase.NotificationEmailUseCase
+}
+
+// NewPreferenceHandler creates a new preference handler
+func NewPreferenceHandler(preferenceUC *usecase.PreferenceUseCase,
+	notificationEmailUC *usecase.NotificationEmailUseCase) *PreferenceHandler {
+	return &PreferenceHandler{preferenceUC: preferenceUC, notificationEmailUC: notificationEmailUC}
+}
+
+// GetPreferences returns the channels the authenticated user gets each
+// type of notification on
+func (h *PreferenceHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	prefs, err := h.preferenceUC.GetPreferences(claims.UserID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to get preferences")
+		return
+	}
+
+	response.Success(w, prefs)
+}
+
+// UpdatePreferences turns the channels in the request body on or off for
+// the authenticated user; channels left out keep their setting
+func (h *PreferenceHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var changes entity.NotificationPreferences
+	if err := json.NewDecoder(r.Body).Decode(&changes); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	prefs, err := h.preferenceUC.UpdatePreferences(claims.UserID, changes)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidPreference) {
+			response.Error(w, http.StatusBadRequest,
+				"Types must be follow, like, comment, mention or digest, on the channels in_app, email or push; digests are email only")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to update preferences")
+		return
+	}
+
+	response.Success(w, prefs)
+}
+
+// UnsubscribeEmail turns off emails of one notification type from the link
+// in a notification email. GET, for the link in the body, asks to confirm;
+// POST, from that page or for one-click unsubscribing by mail clients
+// (RFC 8058), turns them off.
+func (h *PreferenceHandler) UnsubscribeEmail(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		renderUnsubscribePage(w, r, "Turn off emails",
+			"Stop getting emails about notifications like this one?", "Turn off emails")
+		return
+	}
+
+	query := r.URL.Query()
+	if err := h.notificationEmailUC.Unsubscribe(query.Get("type"), query.Get("token")); err != nil {
+		if errors.Is(err, auth.ErrInvalidLinkToken) {
+			response.Error(w, http.StatusBadRequest, "This unsubscribe link is invalid")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to turn off emails")
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "Emails turned off"})
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/usecase"
+	"AbdelrahmanDwedar/blogo/pkg/auth"
+	"AbdelrahmanDwedar/blogo/pkg/response"
+)
+
+// PushHandler handles the Web Push subscriptions of users' devices
+type PushHandler struct {
+	pushUC *usecase.PushUseCase
+}
+
+// NewPushHandler creates a new push handler
+func NewPushHandler(pushUC *usecase.PushUseCase) *PushHandler {
+	return &PushHandler{pushUC: pushUC}
+}
+
+// pushSubscriptionRequest is a browser's PushSubscription as its toJSON
+// method serializes it
+type pushSubscriptionRequest struct {
+	Endpoint string `json:"endpoint"`
+	Keys     struct {
+		P256dh string `json:"p256dh"`
+		Auth   string `json:"auth"`
+	} `json:"keys"`
+}
+
+// GetPublicKey returns the VAPID public key browsers pass as
+// applicationServerKey when subscribing
+func (h *PushHandler) GetPublicKey(w http.ResponseWriter, r *http.Request) {
+	key, err := h.pushUC.PublicKey()
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to get push key")
+		return
+	}
+
+	response.Success(w, map[string]string{
+		"public_key": key,
+	})
+}
+
+// Subscribe registers the push subscription of one of the authenticated
+// user's devices, labelled with the device's user agent
+func (h *PushHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req pushSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	subscription, err := h.pushUC.Subscribe(claims.UserID, req.Endpoint, req.Keys.P256dh, req.Keys.Auth, r.UserAgent())
+	if err != nil {
+		if err == entity.ErrInvalidPushSubscription {
+			response.Error(w, http.StatusBadRequest, "Subscription needs an https endpoint URL and valid p256dh and auth keys")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to save subscription")
+		return
+	}
+
+	response.Created(w, subscription)
+}
+
+// GetSubscriptions lists the push subscriptions of the authenticated
+// user's devices
+func (h *PushHandler) GetSubscriptions(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	subscriptions, err := h.pushUC.GetSubscriptions(claims.UserID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to get subscriptions")
+		return
+	}
+
+	response.Success(w, map[string]interface{}{
+		"subscriptions": subscriptions,
+	})
+}
+
+// Unsubscribe removes the push subscription of one of the authenticated
+// user's devices, identified by its endpoint
+func (h *PushHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req pushSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := h.pushUC.Unsubscribe(claims.UserID, req.Endpoint); err != nil {
+		if err == entity.ErrPushSubscriptionNotFound {
+			response.Error(w, http.StatusNotFound, "Subscription not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to remove subscription")
+		return
+	}
+
+	response.Success(w, map[string]string{
+		"message": "Subscription removed",
+	})
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"net/http"
+
+	"AbdelrahmanDwedar/blogo/internal/usecase"
+	"AbdelrahmanDwedar/blogo/pkg/auth"
+	"AbdelrahmanDwedar/blogo/pkg/response"
+)
+
+// RecommendationHandler handles who-to-follow HTTP requests
+type RecommendationHandler struct {
+	recommendationUC *usecase.RecommendationUseCase
+}
+
+// NewRecommendationHandler creates a new recommendation handler
+func NewRecommendationHandler(recommendationUC *usecase.RecommendationUseCase) *RecommendationHandler {
+	return &RecommendationHandler{recommendationUC: recommendationUC}
+}
+
+// GetRecommendations suggests users for the current user to follow
+func (h *RecommendationHandler) GetRecommendations(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	limit, _ := getPaginationParams(r)
+
+	recommendations, err := h.recommendationUC.GetRecommendations(claims.UserID, limit)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to get recommendations")
+		return
+	}
+
+	response.Success(w, map[string]interface{}{
+		"recommendations": recommendations,
+		"limit":           limit,
+	})
+}
@@ -0,0 +1,120 @@
+package http
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/usecase"
+	"AbdelrahmanDwedar/blogo/pkg/auth"
+	"AbdelrahmanDwedar/blogo/pkg/response"
+)
+
+const (
+	// streamHeartbeat is how often an idle stream sends a comment so
+	// proxies and clients keep the connection open
+	streamHeartbeat = 25 * time.Second
+
+	// streamWriteTimeout bounds each write to a stream, so dead clients are
+	// noticed
+	streamWriteTimeout = 10 * time.Second
+
+	// streamRetry is how long clients wait before reconnecting, in
+	// milliseconds
+	streamRetry = 3000
+)
+
+// StreamHandler serves real-time event streams
+type StreamHandler struct {
+	streamUC *usecase.StreamUseCase
+}
+
+// NewStreamHandler creates a new stream handler
+func NewStreamHandler(streamUC *usecase.StreamUseCase) *StreamHandler {
+	return &StreamHandler{streamUC: streamUC}
+}
+
+// Stream pushes the current user's new notifications and new blogs by the
+// authors they follow as Server-Sent Events. Clients reconnecting with a
+// Last-Event-ID header first receive the events they missed.
+func (h *StreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		response.Error(w, http.StatusInternalServerError, "Streaming unsupported")
+		return
+	}
+
+	events, cancel, err := h.streamUC.Subscribe(claims.UserID, r.Header.Get("Last-Event-ID"))
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to open stream")
+		return
+	}
+	defer cancel()
+
+	// The server's read and write timeouts would end the stream, so the
+	// connection is taken over and its deadlines managed here
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to open stream")
+		return
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Time{})
+
+	write := func(s string) error {
+		conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		if _, err := rw.WriteString(s); err != nil {
+			return err
+		}
+		return rw.Flush()
+	}
+
+	err = write("HTTP/1.1 200 OK\r\n" +
+		"Content-Type: text/event-stream\r\n" +
+		"Cache-Control: no-cache\r\n" +
+		"Connection: close\r\n" +
+		"X-Accel-Buffering: no\r\n" +
+		"\r\n" +
+		fmt.Sprintf("retry: %d\n\n", streamRetry))
+	if err != nil {
+		return
+	}
+
+	// The client sends nothing more; reading only tells when it hangs up
+	closed := make(chan struct{})
+	go func(r *bufio.Reader) {
+		io.Copy(io.Discard, r)
+		close(closed)
+	}(rw.Reader)
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-heartbeat.C:
+			if err := write(": ping\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-events:
+			// A closed channel means the client fell behind; it reconnects
+			// and resumes from the backlog
+			if !ok {
+				return
+			}
+			if err := write(fmt.Sprintf("id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/usecase"
+	"AbdelrahmanDwedar/blogo/pkg/feed"
+	"AbdelrahmanDwedar/blogo/pkg/response"
+	"github.com/gorilla/mux"
+)
+
+// SyndicationHandler serves RSS, Atom and JSON feeds
+type SyndicationHandler struct {
+	syndicationUC *usecase.SyndicationUseCase
+}
+
+// NewSyndicationHandler creates a new syndication handler
+func NewSyndicationHandler(syndicationUC *usecase.SyndicationUseCase) *SyndicationHandler {
+	return &SyndicationHandler{syndicationUC: syndicationUC}
+}
+
+// getFeedFormat reads the feed format from the path, defaulting to Atom
+func getFeedFormat(r *http.Request) string {
+	if format := mux.Vars(r)["format"]; format != "" {
+		return format
+	}
+	return feed.FormatAtom
+}
+
+// serveFeed writes a rendered feed, answering conditional requests with
+// If-None-Match or If-Modified-Since with 304 Not Modified when it did not
+// change
+func serveFeed(w http.ResponseWriter, r *http.Request, rendered *entity.RenderedFeed) {
+	w.Header().Set("Content-Type", rendered.ContentType)
+	w.Header().Set("ETag", rendered.ETag)
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	http.ServeContent(w, r, "", rendered.Updated, bytes.NewReader(rendered.Body))
+}
+
+// GetAllFeed serves the feed of the newest blogs
+func (h *SyndicationHandler) GetAllFeed(w http.ResponseWriter, r *http.Request) {
+	rendered, err := h.syndicationUC.GetAllFeed(getFeedFormat(r))
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to get feed")
+		return
+	}
+
+	serveFeed(w, r, rendered)
+}
+
+// GetAuthorFeed serves the feed of an author's newest blogs
+func (h *SyndicationHandler) GetAuthorFeed(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	rendered, err := h.syndicationUC.GetAuthorFeed(id, getFeedFormat(r))
+	if err != nil {
+		if err == entity.ErrUserNotFound {
+			response.Error(w, http.StatusNotFound, "User not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to get feed")
+		return
+	}
+
+	serveFeed(w, r, rendered)
+}
+
+// GetTagFeed serves the feed of the newest blogs with a tag
+func (h *SyndicationHandler) GetTagFeed(w http.ResponseWriter, r *http.Request) {
+	rendered, err := h.syndicationUC.GetTagFeed(mux.Vars(r)["tag"], getFeedFormat(r))
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to get feed")
+		return
+	}
+
+	serveFeed(w, r, rendered)
+}
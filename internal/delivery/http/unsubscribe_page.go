@@ -0,0 +1,48 @@
+package http
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+)
+
+// unsubscribePage asks to confirm an unsubscribe link. Mail scanners and
+// link previews follow links in emails, so GET only shows this page; the
+// POST its form sends, like a mail client's one-click POST (RFC 8058),
+// unsubscribes.
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{.Title}}</title>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Question}}</p>
+<form method="post" action="{{.Action}}">
+<input type="hidden" name="List-Unsubscribe" value="One-Click">
+<button type="submit">{{.Button}}</button>
+</form>
+</body>
+</html>
+`))
+
+// renderUnsubscribePage answers a GET of an unsubscribe link with a form
+// that posts back to the same link
+func renderUnsubscribePage(w http.ResponseWriter, r *http.Request, title, question, button string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+
+	err := unsubscribePage.Execute(w, map[string]string{
+		"Title":    title,
+		"Question": question,
+		"Button":   button,
+		"Action":   r.URL.RequestURI(),
+	})
+	if err != nil {
+		log.Printf("⚠️  Failed to render unsubscribe page: %v\n", err)
+	}
+}
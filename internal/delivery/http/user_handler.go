@@ -3,6 +3,7 @@ package http
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 
 	"AbdelrahmanDwedar/blogo/internal/domain/entity"
 	"AbdelrahmanDwedar/blogo/internal/usecase"
@@ -62,7 +63,7 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, stats, err := h.userUC.GetUserWithStats(userID)
+	user, stats, err := h.userUC.GetUserWithStats(userID, getViewerID(r))
 	if err != nil {
 		if err == entity.ErrUserNotFound {
 			response.Error(w, http.StatusNotFound, "User not found")
@@ -102,6 +103,7 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		DisplayName  string `json:"display_name"`
 		Bio          string `json:"bio"`
 		ProfileImage string `json:"profile_image"`
+		Private      *bool  `json:"private"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -109,7 +111,7 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.userUC.UpdateUser(userID, req.DisplayName, req.Bio, req.ProfileImage)
+	user, err := h.userUC.UpdateUser(userID, req.DisplayName, req.Bio, req.ProfileImage, req.Private)
 	if err != nil {
 		response.Error(w, http.StatusInternalServerError, "Failed to update user")
 		return
@@ -141,8 +143,9 @@ func (h *UserHandler) FollowUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	status := ""
 	if req.Action == "follow" {
-		err = h.userUC.FollowUser(claims.UserID, userID)
+		status, err = h.userUC.FollowUser(claims.UserID, userID)
 	} else if req.Action == "unfollow" {
 		err = h.userUC.UnfollowUser(claims.UserID, userID)
 	} else {
@@ -155,10 +158,26 @@ func (h *UserHandler) FollowUser(w http.ResponseWriter, r *http.Request) {
 			response.Error(w, http.StatusBadRequest, err.Error())
 			return
 		}
+		if err == entity.ErrUserBlocked {
+			response.Error(w, http.StatusForbidden, "You cannot follow this user")
+			return
+		}
+		if err == entity.ErrUserNotFound {
+			response.Error(w, http.StatusNotFound, "User not found")
+			return
+		}
 		response.Error(w, http.StatusInternalServerError, "Failed to "+req.Action+" user")
 		return
 	}
 
+	if status == entity.FollowPending {
+		response.Success(w, map[string]string{
+			"message": "Follow request sent",
+			"status":  status,
+		})
+		return
+	}
+
 	response.Success(w, map[string]string{
 		"message": "Successfully " + req.Action + "ed user",
 	})
@@ -174,7 +193,7 @@ func (h *UserHandler) GetUserFollowers(w http.ResponseWriter, r *http.Request) {
 
 	limit, offset := getPaginationParams(r)
 
-	followers, err := h.userUC.GetFollowers(userID, limit, offset)
+	followers, err := h.userUC.GetFollowers(userID, getViewerID(r), limit, offset)
 	if err != nil {
 		response.Error(w, http.StatusInternalServerError, "Failed to get followers")
 		return
@@ -197,7 +216,7 @@ func (h *UserHandler) GetUserFollowing(w http.ResponseWriter, r *http.Request) {
 
 	limit, offset := getPaginationParams(r)
 
-	following, err := h.userUC.GetFollowing(userID, limit, offset)
+	following, err := h.userUC.GetFollowing(userID, getViewerID(r), limit, offset)
 	if err != nil {
 		response.Error(w, http.StatusInternalServerError, "Failed to get following")
 		return
@@ -210,4 +229,222 @@ func (h *UserHandler) GetUserFollowing(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// BlockUser blocks or unblocks a user
+func (h *UserHandler) BlockUser(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	userID, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req struct {
+		Action string `json:"action"` // "block" or "unblock"
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	switch req.Action {
+	case "block":
+		err = h.userUC.BlockUser(claims.UserID, userID)
+	case "unblock":
+		err = h.userUC.UnblockUser(claims.UserID, userID)
+	default:
+		response.Error(w, http.StatusBadRequest, "Invalid action. Use 'block' or 'unblock'")
+		return
+	}
+
+	if err != nil {
+		if err == entity.ErrCannotBlockSelf {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err == entity.ErrUserNotFound {
+			response.Error(w, http.StatusNotFound, "User not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to "+req.Action+" user")
+		return
+	}
+
+	response.Success(w, map[string]string{
+		"message": "Successfully " + req.Action + "ed user",
+	})
+}
+
+// MuteUser mutes or unmutes a user
+func (h *UserHandler) MuteUser(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	userID, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req struct {
+		Action string `json:"action"` // "mute" or "unmute"
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	switch req.Action {
+	case "mute":
+		err = h.userUC.MuteUser(claims.UserID, userID)
+	case "unmute":
+		err = h.userUC.UnmuteUser(claims.UserID, userID)
+	default:
+		response.Error(w, http.StatusBadRequest, "Invalid action. Use 'mute' or 'unmute'")
+		return
+	}
+
+	if err != nil {
+		if err == entity.ErrCannotMuteSelf {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err == entity.ErrUserNotFound {
+			response.Error(w, http.StatusNotFound, "User not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to "+req.Action+" user")
+		return
+	}
+
+	response.Success(w, map[string]string{
+		"message": "Successfully " + strings.TrimSuffix(req.Action, "e") + "ed user",
+	})
+}
+
+// GetBlockedUsers retrieves the users the current user has blocked
+func (h *UserHandler) GetBlockedUsers(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	limit, offset := getPaginationParams(r)
+
+	users, err := h.userUC.GetBlockedUsers(claims.UserID, limit, offset)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to get blocked users")
+		return
+	}
+
+	response.Success(w, map[string]interface{}{
+		"blocked": users,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// GetMutedUsers retrieves the users the current user has muted
+func (h *UserHandler) GetMutedUsers(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	limit, offset := getPaginationParams(r)
+
+	users, err := h.userUC.GetMutedUsers(claims.UserID, limit, offset)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to get muted users")
+		return
+	}
+
+	response.Success(w, map[string]interface{}{
+		"muted":  users,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetFollowRequests retrieves the pending follow requests of the current
+// user
+func (h *UserHandler) GetFollowRequests(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
 
+	limit, offset := getPaginationParams(r)
+
+	requests, err := h.userUC.GetFollowRequests(claims.UserID, limit, offset)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to get follow requests")
+		return
+	}
+
+	response.Success(w, map[string]interface{}{
+		"requests": requests,
+		"limit":    limit,
+		"offset":   offset,
+	})
+}
+
+// AnswerFollowRequest accepts or rejects a pending follow request of the
+// current user
+func (h *UserHandler) AnswerFollowRequest(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	followerID, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req struct {
+		Action string `json:"action"` // "accept" or "reject"
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	switch req.Action {
+	case "accept":
+		err = h.userUC.AcceptFollowRequest(claims.UserID, followerID)
+	case "reject":
+		err = h.userUC.RejectFollowRequest(claims.UserID, followerID)
+	default:
+		response.Error(w, http.StatusBadRequest, "Invalid action. Use 'accept' or 'reject'")
+		return
+	}
+
+	if err != nil {
+		if err == entity.ErrFollowRequestNotFound {
+			response.Error(w, http.StatusNotFound, "Follow request not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to "+req.Action+" follow request")
+		return
+	}
+
+	response.Success(w, map[string]string{
+		"message": "Follow request " + req.Action + "ed",
+	})
+}
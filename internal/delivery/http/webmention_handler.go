@@ -0,0 +1,78 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/usecase"
+	"AbdelrahmanDwedar/blogo/pkg/response"
+)
+
+// maxWebmentionSize bounds the form body of a received Webmention
+const maxWebmentionSize = 16 << 10
+
+// WebmentionHandler handles Webmention HTTP requests
+type WebmentionHandler struct {
+	webmentionUC *usecase.WebmentionUseCase
+}
+
+// NewWebmentionHandler creates a new Webmention handler
+func NewWebmentionHandler(webmentionUC *usecase.WebmentionUseCase) *WebmentionHandler {
+	return &WebmentionHandler{webmentionUC: webmentionUC}
+}
+
+// Receive is the Webmention endpoint. It accepts a form-encoded source and
+// target, and answers 202 Accepted while the source is verified.
+func (h *WebmentionHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxWebmentionSize)
+	if err := r.ParseForm(); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid form body")
+		return
+	}
+
+	webmention, err := h.webmentionUC.Receive(r.PostForm.Get("source"), r.PostForm.Get("target"))
+	if err != nil {
+		switch {
+		case errors.Is(err, entity.ErrInvalidWebmention):
+			response.Error(w, http.StatusBadRequest, "Source and target must be distinct URLs, and target a blog on this server")
+		case errors.Is(err, entity.ErrBlogNotFound):
+			response.Error(w, http.StatusBadRequest, "Target blog not found")
+		case errors.Is(err, entity.ErrWebmentionRateLimited):
+			response.Error(w, http.StatusTooManyRequests, "Too many webmentions from this source, try again later")
+		default:
+			response.Error(w, http.StatusInternalServerError, "Failed to accept webmention")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusAccepted, webmention)
+}
+
+// GetWebmentions retrieves the verified Webmentions of a blog, which are
+// its responses from elsewhere on the web
+func (h *WebmentionHandler) GetWebmentions(w http.ResponseWriter, r *http.Request) {
+	blogID, err := getIDFromPath(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid blog ID")
+		return
+	}
+
+	limit, offset := getPaginationParams(r)
+
+	webmentions, err := h.webmentionUC.GetWebmentions(blogID, getViewerID(r), limit, offset)
+	if err != nil {
+		if errors.Is(err, entity.ErrBlogNotFound) {
+			response.Error(w, http.StatusNotFound, "Blog not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to get webmentions")
+		return
+	}
+
+	response.Success(w, map[string]interface{}{
+		"webmentions": webmentions,
+		"limit":       limit,
+		"offset":      offset,
+	})
+}
@@ -0,0 +1,103 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// Access token scopes
+const (
+	ScopeCreate = "create"
+	ScopeUpdate = "update"
+	ScopeDelete = "delete"
+)
+
+// AccessTokenScopes lists the scopes an access token can be granted
+var AccessTokenScopes = []string{ScopeCreate, ScopeUpdate, ScopeDelete}
+
+// accessTokenPrefix starts every access token secret, so leaked tokens are
+// easy to recognize
+const accessTokenPrefix = "blogo_"
+
+// maxTokenNameLength bounds the name of an access token
+const maxTokenNameLength = 100
+
+// AccessToken is a long-lived bearer token a user creates for a
+// third-party client, limited to the scopes it was granted. Only a hash of
+// its secret is stored.
+type AccessToken struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"user_id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	TokenHash  string     `json:"-"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NewAccessToken creates an access token for userID along with its secret,
+// which is shown to the user once and never stored
+func NewAccessToken(userID int64, name string, scopes []string) (*AccessToken, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > maxTokenNameLength {
+		return nil, "", ErrInvalidTokenName
+	}
+
+	granted := []string{}
+	seen := map[string]bool{}
+	for _, scope := range scopes {
+		if !isAccessTokenScope(scope) {
+			return nil, "", ErrInvalidScope
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			granted = append(granted, scope)
+		}
+	}
+	if len(granted) == 0 {
+		return nil, "", ErrInvalidScope
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", err
+	}
+	secret := accessTokenPrefix + hex.EncodeToString(raw)
+
+	return &AccessToken{
+		UserID:    userID,
+		Name:      name,
+		Scopes:    granted,
+		TokenHash: HashAccessToken(secret),
+		CreatedAt: time.Now(),
+	}, secret, nil
+}
+
+// HashAccessToken returns the hash an access token secret is stored and
+// looked up by
+func HashAccessToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// HasScope checks if the token was granted scope
+func (t *AccessToken) HasScope(scope string) bool {
+	for _, granted := range t.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func isAccessTokenScope(scope string) bool {
+	for _, known := range AccessTokenScopes {
+		if scope == known {
+			return true
+		}
+	}
+	return false
+}
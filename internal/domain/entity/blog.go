@@ -2,30 +2,62 @@ package entity
 
 import "time"
 
+// Comment modes of a blog
+const (
+	// CommentsOpen accepts new comments
+	CommentsOpen = "open"
+
+	// CommentsLocked keeps existing comments visible but accepts no new ones
+	CommentsLocked = "locked"
+
+	// CommentsDisabled hides all comments and accepts no new ones
+	CommentsDisabled = "disabled"
+)
+
 // Blog represents a blog post entity in the domain
 type Blog struct {
-	ID          int64     `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Body        string    `json:"body"`
-	AuthorID    int64     `json:"author_id"`
-	Author      *User     `json:"author,omitempty"`
-	LikesCount  int       `json:"likes_count"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID               int64          `json:"id"`
+	Title            string         `json:"title"`
+	Description      string         `json:"description"`
+	Body             string         `json:"body"`
+	BodyHTML         string         `json:"body_html,omitempty"`
+	Slug             string         `json:"slug"`
+	Tags             []string       `json:"tags"`
+	AuthorID         int64          `json:"author_id"`
+	Author           *User          `json:"author,omitempty"`
+	LikesCount       int            `json:"likes_count"`
+	Reactions        map[string]int `json:"reactions"`
+	CommentsCount    int            `json:"comments_count"`
+	CommentMode      string         `json:"comment_mode"`
+	ModerateComments bool           `json:"moderate_comments"`
+	PinnedCommentID  *int64         `json:"pinned_comment_id"`
+	Version          int64          `json:"version"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        *time.Time     `json:"deleted_at,omitempty"`
+
+	// IsBookmarked tells an authenticated viewer whether they bookmarked
+	// the blog; it is left out for anonymous viewers
+	IsBookmarked *bool `json:"is_bookmarked,omitempty"`
 }
 
 // NewBlog creates a new blog entity
 func NewBlog(title, description, body string, authorID int64) *Blog {
 	now := time.Now()
-	return &Blog{
+	blog := &Blog{
 		Title:       title,
 		Description: description,
 		Body:        body,
+		Tags:        []string{},
+		Reactions:   map[string]int{},
+		CommentMode: CommentsOpen,
 		AuthorID:    authorID,
+		Version:     1,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
+	blog.SetSlug("")
+	return blog
 }
 
 // Update updates blog information
@@ -36,6 +68,50 @@ func (b *Blog) Update(title, description, body string) {
 	b.UpdatedAt = time.Now()
 }
 
+// SetSlug sets the blog's URL slug, deriving it from the title when slug
+// is empty or has no usable characters
+func (b *Blog) SetSlug(slug string) {
+	b.Slug = Slugify(slug)
+	if b.Slug == "" {
+		b.Slug = Slugify(b.Title)
+	}
+	if b.Slug == "" {
+		b.Slug = "post"
+	}
+}
+
+// SetTags replaces the blog's tags with their normalized, de-duplicated form
+func (b *Blog) SetTags(tags []string) {
+	b.Tags = NormalizeTags(tags)
+}
+
+// SetReactionCounts sets the per-kind reaction counts; the default
+// reaction doubles as the blog's likes count
+func (b *Blog) SetReactionCounts(counts map[string]int) {
+	if counts == nil {
+		counts = map[string]int{}
+	}
+	b.Reactions = counts
+	b.LikesCount = counts[DefaultReaction]
+}
+
+// SetCommentSettings changes how the blog accepts comments
+func (b *Blog) SetCommentSettings(mode string, moderate bool) error {
+	switch mode {
+	case CommentsOpen, CommentsLocked, CommentsDisabled:
+	default:
+		return ErrInvalidCommentMode
+	}
+	b.CommentMode = mode
+	b.ModerateComments = moderate
+	return nil
+}
+
+// AcceptsComments reports whether new comments can be added to the blog
+func (b *Blog) AcceptsComments() bool {
+	return b.CommentMode == CommentsOpen
+}
+
 // Validate validates blog data
 func (b *Blog) Validate() error {
 	if b.Title == "" {
@@ -54,5 +130,3 @@ func (b *Blog) Validate() error {
 func (b *Blog) IsOwnedBy(userID int64) bool {
 	return b.AuthorID == userID
 }
-
-
@@ -0,0 +1,103 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// maxListNameLength bounds the name of a reading list
+	maxListNameLength = 100
+
+	// maxListDescriptionLength bounds the description of a reading list
+	maxListDescriptionLength = 500
+)
+
+// Bookmark is a blog a user saved for later. Bookmarks are private to
+// their user.
+type Bookmark struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	BlogID    int64     `json:"blog_id"`
+	Blog      *Blog     `json:"blog,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewBookmark creates a bookmark of blogID for userID
+func NewBookmark(userID, blogID int64) *Bookmark {
+	return &Bookmark{
+		UserID:    userID,
+		BlogID:    blogID,
+		CreatedAt: time.Now(),
+	}
+}
+
+// ReadingList is a named collection of blogs a user keeps. Private lists
+// are only seen by their owner; public ones by anyone who can see the
+// owner, so they can be shared by their URL.
+type ReadingList struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"user_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Public      bool      `json:"public"`
+	ItemsCount  int       `json:"items_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NewReadingList creates a reading list for userID
+func NewReadingList(userID int64, name, description string, public bool) (*ReadingList, error) {
+	now := time.Now()
+	list := &ReadingList{
+		UserID:    userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := list.Update(name, description, public); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Update renames a reading list, changes its description and makes it
+// public or private
+func (l *ReadingList) Update(name, description string, public bool) error {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > maxListNameLength {
+		return ErrInvalidListName
+	}
+	description = strings.TrimSpace(description)
+	if len([]rune(description)) > maxListDescriptionLength {
+		return ErrInvalidListDescription
+	}
+
+	l.Name = name
+	l.Description = description
+	l.Public = public
+	l.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsOwnedBy checks if the reading list belongs to userID
+func (l *ReadingList) IsOwnedBy(userID int64) bool {
+	return l.UserID == userID
+}
+
+// ReadingListItem is a blog in a reading list
+type ReadingListItem struct {
+	ID        int64     `json:"id"`
+	ListID    int64     `json:"list_id"`
+	BlogID    int64     `json:"blog_id"`
+	Blog      *Blog     `json:"blog,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewReadingListItem creates an entry of blogID in listID
+func NewReadingListItem(listID, blogID int64) *ReadingListItem {
+	return &ReadingListItem{
+		ListID:    listID,
+		BlogID:    blogID,
+		CreatedAt: time.Now(),
+	}
+}
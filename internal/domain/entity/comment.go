@@ -0,0 +1,128 @@
+package entity
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxCommentLength is the longest comment body accepted, in characters
+const MaxCommentLength = 10000
+
+// Comment statuses
+const (
+	CommentPublished = "published"
+	CommentPending   = "pending"
+)
+
+// Comment represents a comment on a blog, or a reply to another comment
+type Comment struct {
+	ID        int64      `json:"id"`
+	BlogID    int64      `json:"blog_id"`
+	ParentID  *int64     `json:"parent_id"`
+	AuthorID  int64      `json:"author_id,omitempty"`
+	Author    *User      `json:"author,omitempty"`
+	Body      string     `json:"body"`
+	BodyHTML  string     `json:"body_html,omitempty"`
+	Depth     int        `json:"depth"`
+	Status    string     `json:"status"`
+	Pinned    bool       `json:"pinned,omitempty"`
+	Replies   []*Comment `json:"replies,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// NewComment creates a new top-level comment entity
+func NewComment(blogID, authorID int64, body string) *Comment {
+	now := time.Now()
+	return &Comment{
+		BlogID:    blogID,
+		AuthorID:  authorID,
+		Body:      strings.TrimSpace(body),
+		Status:    CommentPublished,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// ReplyTo makes the comment a reply to parent
+func (c *Comment) ReplyTo(parent *Comment) {
+	c.ParentID = &parent.ID
+	c.Depth = parent.Depth + 1
+}
+
+// Update updates the comment body
+func (c *Comment) Update(body string) {
+	c.Body = strings.TrimSpace(body)
+	c.UpdatedAt = time.Now()
+}
+
+// Validate validates comment data
+func (c *Comment) Validate() error {
+	if c.Body == "" || utf8.RuneCountInString(c.Body) > MaxCommentLength {
+		return ErrInvalidComment
+	}
+	if c.AuthorID == 0 {
+		return ErrInvalidAuthor
+	}
+	return nil
+}
+
+// IsOwnedBy checks if the comment was written by the given user
+func (c *Comment) IsOwnedBy(userID int64) bool {
+	return c.AuthorID == userID
+}
+
+// IsPublished reports whether the comment is visible in its thread
+func (c *Comment) IsPublished() bool {
+	return c.Status == CommentPublished && !c.IsDeleted()
+}
+
+// IsDeleted reports whether the comment has been deleted
+func (c *Comment) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
+// BuildCommentThreads nests comments under their parents, keeping the given
+// order among siblings. Deleted comments keep their place in the thread with
+// their author and body removed, and are dropped when nothing replies to them.
+func BuildCommentThreads(comments []*Comment) []*Comment {
+	byID := make(map[int64]*Comment, len(comments))
+	for _, comment := range comments {
+		comment.Replies = nil
+		byID[comment.ID] = comment
+	}
+
+	roots := []*Comment{}
+	for _, comment := range comments {
+		if comment.ParentID != nil {
+			if parent, ok := byID[*comment.ParentID]; ok {
+				parent.Replies = append(parent.Replies, comment)
+				continue
+			}
+		}
+		roots = append(roots, comment)
+	}
+
+	return pruneDeleted(roots)
+}
+
+// pruneDeleted drops deleted comments without live replies and redacts
+// the rest
+func pruneDeleted(comments []*Comment) []*Comment {
+	kept := comments[:0]
+	for _, comment := range comments {
+		comment.Replies = pruneDeleted(comment.Replies)
+		if comment.IsDeleted() {
+			if len(comment.Replies) == 0 {
+				continue
+			}
+			comment.AuthorID = 0
+			comment.Author = nil
+			comment.Body = ""
+		}
+		kept = append(kept, comment)
+	}
+	return kept
+}
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+)
+
+// Cursor marks a position in a list ordered newest first by creation time,
+// with the ID breaking ties. Clients receive it as an opaque string.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        int64
+}
+
+// NewCursor creates a cursor pointing just past the given item
+func NewCursor(createdAt time.Time, id int64) *Cursor {
+	return &Cursor{CreatedAt: createdAt, ID: id}
+}
+
+// String encodes the cursor for use in URLs
+func (c *Cursor) String() string {
+	raw := fmt.Sprintf("%d:%d", c.CreatedAt.UnixMicro(), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseCursor decodes a cursor produced by Cursor.String; an empty string
+// yields a nil cursor, meaning the start of the list
+func ParseCursor(s string) (*Cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var micros, id int64
+	if _, err := fmt.Sscanf(string(raw), "%d:%d", &micros, &id); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return NewCursor(time.UnixMicro(micros).UTC(), id), nil
+}
+
+// Before reports whether an item sorts after the cursor, i.e. is older or
+// was created at the same time with a lower ID
+func (c *Cursor) Before(createdAt time.Time, id int64) bool {
+	if createdAt.Equal(c.CreatedAt) {
+		return id < c.ID
+	}
+	return createdAt.Before(c.CreatedAt)
+}
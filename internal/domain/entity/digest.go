@@ -0,0 +1,69 @@
+package entity
+
+import "time"
+
+// Digest frequencies. Users are sent no digest until they opt in.
+const (
+	DigestOff    = "off"
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// DigestFrequencies are the frequencies digests are sent at
+var DigestFrequencies = []string{DigestDaily, DigestWeekly}
+
+// DigestSettings is how often a user gets a digest email
+type DigestSettings struct {
+	Frequency string `json:"frequency"`
+}
+
+// ValidateDigestFrequency checks that frequency is off, daily or weekly
+func ValidateDigestFrequency(frequency string) error {
+	switch frequency {
+	case DigestOff, DigestDaily, DigestWeekly:
+		return nil
+	}
+	return ErrInvalidDigestFrequency
+}
+
+// DigestPeriod returns the last complete period of a frequency before now,
+// in UTC: yesterday for daily digests and last week, Monday to Monday, for
+// weekly ones
+func DigestPeriod(frequency string, now time.Time) (start, end time.Time) {
+	now = now.UTC()
+	end = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if frequency == DigestWeekly {
+		// Weekday counts from Sunday; weeks start on Monday
+		end = end.AddDate(0, 0, -(int(end.Weekday())+6)%7)
+		return end.AddDate(0, 0, -7), end
+	}
+	return end.AddDate(0, 0, -1), end
+}
+
+// DigestEntry is a blog listed in a digest with its URL
+type DigestEntry struct {
+	Blog *Blog
+	URL  string
+}
+
+// Digest summarizes a period for a user: new blogs by the authors they
+// follow, the blogs the people they follow liked most, and their new
+// followers
+type Digest struct {
+	User         *User
+	Frequency    string
+	PeriodStart  time.Time
+	PeriodEnd    time.Time
+	NewBlogs     []*DigestEntry
+	TopBlogs     []*DigestEntry
+	NewFollowers []*User
+
+	// NewFollowersCount counts all new followers, of which NewFollowers
+	// lists the first
+	NewFollowersCount int
+}
+
+// IsEmpty checks if nothing happened in the digest's period
+func (d *Digest) IsEmpty() bool {
+	return len(d.NewBlogs) == 0 && len(d.TopBlogs) == 0 && d.NewFollowersCount == 0
+}
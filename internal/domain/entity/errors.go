@@ -17,11 +17,112 @@ var (
 	ErrInvalidTitle  = errors.New("invalid title")
 	ErrInvalidBody   = errors.New("invalid body")
 	ErrInvalidAuthor = errors.New("invalid author")
+	ErrDuplicateSlug = errors.New("slug already used by another blog")
 	ErrBlogNotFound  = errors.New("blog not found")
 	ErrNotBlogOwner  = errors.New("not blog owner")
+	ErrInvalidSort   = errors.New("invalid sort order")
 
-	// General errors
-	ErrInvalidID = errors.New("invalid ID")
-)
+	// ErrInvalidTrendingWindow is returned for unknown trending periods
+	ErrInvalidTrendingWindow = errors.New("invalid trending window")
+
+	// Import errors
+	ErrImportTooLarge      = errors.New("import archive too large")
+	ErrUnknownImportFormat = errors.New("unknown import format")
+
+	// Comment errors
+	ErrInvalidComment       = errors.New("invalid comment")
+	ErrCommentNotFound      = errors.New("comment not found")
+	ErrNotCommentAuthor     = errors.New("not comment author")
+	ErrParentCommentInvalid = errors.New("parent comment not found on this blog")
+	ErrCommentTooDeep       = errors.New("comment nested too deeply")
+	ErrCommentsClosed       = errors.New("blog does not accept comments")
+	ErrInvalidCommentMode   = errors.New("invalid comment mode")
+	ErrCommentNotPending    = errors.New("comment is not pending approval")
+	ErrCannotPinReply       = errors.New("only top-level comments can be pinned")
+
+	// Reaction errors
+	ErrInvalidReaction = errors.New("invalid reaction kind")
+
+	// ErrVersionConflict is returned when a blog was modified since the
+	// version the caller based its edit on
+	ErrVersionConflict = errors.New("blog version conflict")
+
+	// Block errors
+	ErrCannotBlockSelf = errors.New("cannot block yourself")
+	ErrCannotMuteSelf  = errors.New("cannot mute yourself")
+	ErrUserBlocked     = errors.New("user is blocked")
+
+	// Follow request errors
+	ErrFollowRequestNotFound = errors.New("follow request not found")
+
+	// Notification errors
+	ErrNotificationNotFound = errors.New("notification not found")
+
+	// Federation errors
+	ErrActorKeyNotFound    = errors.New("actor key not found")
+	ErrRemoteActorNotFound = errors.New("remote actor not found")
+	ErrInvalidSignature    = errors.New("invalid signature")
+	ErrInvalidActivity     = errors.New("invalid activity")
+	ErrDeliveryRejected    = errors.New("delivery rejected")
+
+	// Webmention errors
+	ErrInvalidWebmention     = errors.New("invalid webmention")
+	ErrWebmentionNotFound    = errors.New("webmention not found")
+	ErrWebmentionSourceGone  = errors.New("webmention source gone")
+	ErrWebmentionRejected    = errors.New("webmention rejected")
+	ErrWebmentionRateLimited = errors.New("too many webmentions from source")
 
+	// Access token errors
+	ErrAccessTokenNotFound = errors.New("access token not found")
+	ErrInvalidTokenName    = errors.New("invalid token name")
+	ErrInvalidScope        = errors.New("invalid scope")
+	ErrInsufficientScope   = errors.New("insufficient scope")
+	ErrInvalidCredentials  = errors.New("invalid credentials")
 
+	// Media errors
+	ErrInvalidMedia  = errors.New("invalid media file")
+	ErrMediaTooLarge = errors.New("media file too large")
+	ErrMediaNotFound = errors.New("media file not found")
+
+	// Newsletter errors
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrInvalidBounce        = errors.New("invalid bounce")
+
+	// Mail errors. ErrRecipientRejected is returned when the mail server
+	// refuses the recipient for good, ErrMailRejected for other permanent
+	// failures.
+	ErrRecipientRejected = errors.New("recipient rejected")
+	ErrMailRejected      = errors.New("mail rejected")
+
+	// Digest errors
+	ErrInvalidDigestFrequency = errors.New("invalid digest frequency")
+
+	// Preference errors
+	ErrInvalidPreference = errors.New("invalid notification preference")
+
+	// Push errors. ErrPushSubscriptionGone is returned when the push
+	// service reports a subscription expired or unsubscribed,
+	// ErrPushRejected for other pushes retrying cannot fix.
+	ErrPushSubscriptionNotFound = errors.New("push subscription not found")
+	ErrInvalidPushSubscription  = errors.New("invalid push subscription")
+	ErrVAPIDKeyNotFound         = errors.New("vapid key not found")
+	ErrPushSubscriptionGone     = errors.New("push subscription gone")
+	ErrPushRejected             = errors.New("push rejected")
+
+	// Bookmark errors
+	ErrReadingListNotFound    = errors.New("reading list not found")
+	ErrInvalidListName        = errors.New("invalid reading list name")
+	ErrInvalidListDescription = errors.New("invalid reading list description")
+
+	// Job errors
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobNotDone  = errors.New("job not finished")
+
+	// ErrJobNotRetryable is returned by job handlers for failures that
+	// retrying cannot fix
+	ErrJobNotRetryable = errors.New("job not retryable")
+
+	// General errors
+	ErrInvalidID     = errors.New("invalid ID")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
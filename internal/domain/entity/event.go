@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Event types pushed to users' real-time streams
+const (
+	EventNotification = "notification"
+	EventBlog         = "blog"
+)
+
+// Event is a real-time update for one user. IDs are assigned when the event
+// is published and let a reconnecting client resume where it left off.
+type Event struct {
+	ID   string          `json:"id"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// NewEvent creates an event of the given type carrying data as JSON
+func NewEvent(eventType string, data interface{}) (*Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s event: %w", eventType, err)
+	}
+	return &Event{Type: eventType, Data: raw}, nil
+}
@@ -0,0 +1,40 @@
+package entity
+
+import "time"
+
+// ActorKey is the RSA key pair a user's ActivityPub actor signs its
+// deliveries with
+type ActorKey struct {
+	UserID        int64
+	PublicKeyPEM  string
+	PrivateKeyPEM string
+	CreatedAt     time.Time
+}
+
+// RemoteActor is an actor on another ActivityPub server, cached so its
+// inbox and key need not be fetched for every activity
+type RemoteActor struct {
+	ID           string
+	Inbox        string
+	SharedInbox  string
+	PublicKeyID  string
+	PublicKeyPEM string
+	FetchedAt    time.Time
+}
+
+// DeliveryInbox returns the inbox activities for the actor are delivered
+// to, preferring its server's shared inbox
+func (a *RemoteActor) DeliveryInbox() string {
+	if a.SharedInbox != "" {
+		return a.SharedInbox
+	}
+	return a.Inbox
+}
+
+// RemoteFollower is a remote actor following a local user
+type RemoteFollower struct {
+	UserID    int64
+	ActorID   string
+	Inbox     string
+	CreatedAt time.Time
+}
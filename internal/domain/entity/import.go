@@ -0,0 +1,35 @@
+package entity
+
+// Import item statuses
+const (
+	ImportStatusImported = "imported"
+	ImportStatusValid    = "valid"
+	ImportStatusFailed   = "failed"
+)
+
+// ImportItem is a post read from an import source, or the reason it could
+// not be read
+type ImportItem struct {
+	Source string
+	Blog   *Blog
+	Err    error
+}
+
+// ImportItemResult reports what happened to a single imported post
+type ImportItemResult struct {
+	Source string `json:"source"`
+	Title  string `json:"title,omitempty"`
+	Slug   string `json:"slug,omitempty"`
+	BlogID int64  `json:"blog_id,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ImportReport summarizes an import run
+type ImportReport struct {
+	DryRun   bool                `json:"dry_run"`
+	Total    int                 `json:"total"`
+	Imported int                 `json:"imported"`
+	Failed   int                 `json:"failed"`
+	Items    []*ImportItemResult `json:"items"`
+}
@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Job statuses
+const (
+	JobStatusQueued  = "queued"
+	JobStatusRunning = "running"
+	JobStatusDone    = "done"
+	JobStatusFailed  = "failed"
+)
+
+// Job kinds
+const (
+	JobExportSite       = "export_site"
+	JobDeliverActivity  = "deliver_activity"
+	JobSendWebmention   = "send_webmention"
+	JobVerifyWebmention = "verify_webmention"
+	JobSendConfirmation = "send_confirmation"
+	JobSendNewsletters  = "send_newsletters"
+	JobSendNewsletter   = "send_newsletter"
+	JobSendDigest       = "send_digest"
+	JobSendNotification = "send_notification_email"
+	JobSendPush         = "send_push"
+)
+
+// Job is a unit of background work picked up by the job queue
+type Job struct {
+	ID          int64           `json:"id"`
+	Kind        string          `json:"kind"`
+	UserID      *int64          `json:"-"`
+	Payload     json.RawMessage `json:"-"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"-"`
+	Result      string          `json:"-"`
+	LastError   string          `json:"error,omitempty"`
+	RunAt       time.Time       `json:"run_at"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// NewJob creates a queued job with a JSON-encoded payload. userID, when
+// non-zero, records the user the job runs on behalf of.
+func NewJob(kind string, userID int64, payload interface{}, maxAttempts int) (*Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job := &Job{
+		Kind:        kind,
+		Payload:     data,
+		Status:      JobStatusQueued,
+		MaxAttempts: maxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if userID != 0 {
+		job.UserID = &userID
+	}
+	return job, nil
+}
+
+// IsOwnedBy checks if the job was requested by the given user
+func (j *Job) IsOwnedBy(userID int64) bool {
+	return j.UserID != nil && *j.UserID == userID
+}
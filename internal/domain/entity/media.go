@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// MaxMediaSize bounds the size of an uploaded media file
+const MaxMediaSize = 10 << 20
+
+// maxMediaStemLength bounds the part of a stored media file name taken from
+// the uploaded name
+const maxMediaStemLength = 60
+
+// mediaTypes maps the extensions of the media files that can be uploaded
+// to their content types. Formats browsers run scripts in, such as HTML and
+// SVG, are left out.
+var mediaTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+	".mp3":  "audio/mpeg",
+	".mp4":  "video/mp4",
+	".pdf":  "application/pdf",
+}
+
+// MediaFile is a file uploaded for use in blogs, such as an image
+type MediaFile struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// NewMediaFile creates a media file uploaded by a user. Its format is taken
+// from contentType, or else from the extension of name. The stored name is
+// unique and keeps a readable form of the original name.
+func NewMediaFile(userID int64, name, contentType string, data []byte) (*MediaFile, error) {
+	if len(data) == 0 {
+		return nil, ErrInvalidMedia
+	}
+	if len(data) > MaxMediaSize {
+		return nil, ErrMediaTooLarge
+	}
+
+	base := path.Base(strings.ReplaceAll(name, "\\", "/"))
+	ext := strings.ToLower(path.Ext(base))
+	if ext == ".jpeg" {
+		ext = ".jpg"
+	}
+	if _, ok := mediaTypes[ext]; !ok {
+		ext = ""
+	}
+	for known, knownType := range mediaTypes {
+		if strings.EqualFold(contentType, knownType) {
+			ext = known
+		}
+	}
+	if ext == "" {
+		return nil, ErrInvalidMedia
+	}
+
+	random := make([]byte, 6)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("generate media name: %w", err)
+	}
+
+	stem := strings.TrimRight(truncateRunes(Slugify(strings.TrimSuffix(base, path.Ext(base))), maxMediaStemLength), "-")
+	if stem == "" {
+		stem = "file"
+	}
+
+	return &MediaFile{
+		Name:        fmt.Sprintf("%d-%s-%s%s", userID, hex.EncodeToString(random), stem, ext),
+		ContentType: mediaTypes[ext],
+		Data:        data,
+	}, nil
+}
+
+// MediaContentType returns the content type of a stored media file by its
+// name, reporting false for names no upload could have
+func MediaContentType(name string) (string, bool) {
+	if name == "" || strings.ContainsAny(name, "/\\") || strings.HasPrefix(name, ".") {
+		return "", false
+	}
+	contentType, ok := mediaTypes[strings.ToLower(path.Ext(name))]
+	return contentType, ok
+}
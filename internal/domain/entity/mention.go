@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MaxMentions is how many distinct users a single blog or comment can
+// mention; further mentions are ignored
+const MaxMentions = 20
+
+// mentionPattern matches @username not preceded by a word character, so
+// e-mail addresses are not taken for mentions
+var mentionPattern = regexp.MustCompile(`(^|[^\w@])@([A-Za-z0-9_](?:[A-Za-z0-9_.-]*[A-Za-z0-9_])?)`)
+
+// Mention records that a blog, or a comment on it, mentions a user
+type Mention struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Username  string    `json:"username"`
+	BlogID    int64     `json:"blog_id"`
+	Blog      *Blog     `json:"blog,omitempty"`
+	CommentID *int64    `json:"comment_id,omitempty"`
+	AuthorID  int64     `json:"author_id"`
+	Author    *User     `json:"author,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ParseMentions returns the distinct usernames mentioned in text, in order
+// of first appearance
+func ParseMentions(text string) []string {
+	usernames := []string{}
+	seen := map[string]bool{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		username := match[2]
+		if seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+	}
+	return usernames
+}
+
+// RenderMentions HTML-escapes text and links every mention of a user in
+// mentions to that user's profile
+func RenderMentions(text string, mentions []*Mention) string {
+	users := make(map[string]int64, len(mentions))
+	for _, mention := range mentions {
+		users[mention.Username] = mention.UserID
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range mentionPattern.FindAllStringSubmatchIndex(text, -1) {
+		// loc[4]:loc[5] is the username; the @ sits just before it
+		userID, ok := users[text[loc[4]:loc[5]]]
+		if !ok {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[last : loc[4]-1]))
+		fmt.Fprintf(&b, `<a href="/api/u/%d" class="mention">@%s</a>`, userID, html.EscapeString(text[loc[4]:loc[5]]))
+		last = loc[5]
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+	return b.String()
+}
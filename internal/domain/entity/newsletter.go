@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Subscription statuses. Subscriptions stay pending until the address
+// owner follows the link in the confirmation email.
+const (
+	SubscriptionPending   = "pending"
+	SubscriptionConfirmed = "confirmed"
+)
+
+// Bounce kinds reported for email that could not be delivered. Hard
+// bounces and complaints suppress the address; soft bounces are temporary.
+const (
+	BounceHard      = "hard"
+	BounceSoft      = "soft"
+	BounceComplaint = "complaint"
+)
+
+// maxEmailLength matches the width of the email columns
+const maxEmailLength = 255
+
+// Subscription is a reader without an account receiving an author's new
+// blogs by email
+type Subscription struct {
+	ID          int64      `json:"id"`
+	AuthorID    int64      `json:"author_id"`
+	Email       string     `json:"email"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+}
+
+// NewSubscription creates a pending subscription of an email address to an
+// author
+func NewSubscription(authorID int64, email string) (*Subscription, error) {
+	email, err := NormalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Subscription{
+		AuthorID:  authorID,
+		Email:     email,
+		Status:    SubscriptionPending,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// IsConfirmed checks if the subscription was confirmed
+func (s *Subscription) IsConfirmed() bool {
+	return s.Status == SubscriptionConfirmed
+}
+
+// NormalizeEmail checks that raw is a bare email address and returns it
+// trimmed and lowercased
+func NormalizeEmail(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	addr, err := mail.ParseAddress(raw)
+	if err != nil || addr.Address != raw || len(raw) > maxEmailLength {
+		return "", ErrInvalidEmail
+	}
+	return strings.ToLower(raw), nil
+}
+
+// EmailMessage is an email ready to be sent, with a plain-text and an HTML
+// body
+type EmailMessage struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+
+	// Headers holds extra headers, such as List-Unsubscribe
+	Headers map[string]string
+}
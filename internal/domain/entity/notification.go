@@ -0,0 +1,120 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// Notification types
+const (
+	NotificationFollow        = "follow"
+	NotificationFollowRequest = "follow_request"
+	NotificationFollowAccept  = "follow_accept"
+	NotificationLike          = "like"
+	NotificationComment       = "comment"
+	NotificationReply         = "reply"
+	NotificationMention       = "mention"
+)
+
+// MaxGroupActors is how many of a notification group's actors are listed
+const MaxGroupActors = 3
+
+// Notification tells a user about something another user did
+type Notification struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Type      string    `json:"type"`
+	ActorID   int64     `json:"actor_id"`
+	Actor     *User     `json:"actor,omitempty"`
+	BlogID    *int64    `json:"blog_id,omitempty"`
+	CommentID *int64    `json:"comment_id,omitempty"`
+	Read      bool      `json:"read"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewNotification creates a new unread notification for userID
+func NewNotification(userID int64, notificationType string, actorID int64) *Notification {
+	return &Notification{
+		UserID:    userID,
+		Type:      notificationType,
+		ActorID:   actorID,
+		CreatedAt: time.Now(),
+	}
+}
+
+// GroupKey identifies the notifications shown together as one entry: likes
+// and comments on the same blog and follows are grouped, while mentions
+// and replies stay apart per comment
+func (n *Notification) GroupKey() string {
+	var blogID, commentID int64
+	if n.BlogID != nil {
+		blogID = *n.BlogID
+	}
+	if n.CommentID != nil && (n.Type == NotificationMention || n.Type == NotificationReply) {
+		commentID = *n.CommentID
+	}
+	return fmt.Sprintf("%s:%d:%d", n.Type, blogID, commentID)
+}
+
+// NotificationGroup is one entry of a user's notification list: every
+// notification with the same group key and read state, newest first
+type NotificationGroup struct {
+	// ID is the newest notification in the group; marking it read marks
+	// the whole group read
+	ID          int64     `json:"id"`
+	Type        string    `json:"type"`
+	BlogID      *int64    `json:"blog_id,omitempty"`
+	CommentID   *int64    `json:"comment_id,omitempty"`
+	Actors      []*User   `json:"actors"`
+	ActorsCount int       `json:"actors_count"`
+	Summary     string    `json:"summary"`
+	Read        bool      `json:"read"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Summarize fills in the group's Summary, e.g. "Alice and 12 others liked
+// your blog"
+func (g *NotificationGroup) Summarize() {
+	names := make([]string, 0, 2)
+	for _, actor := range g.Actors {
+		if len(names) == 2 {
+			break
+		}
+		name := actor.DisplayName
+		if name == "" {
+			name = actor.Username
+		}
+		names = append(names, name)
+	}
+
+	var who string
+	switch {
+	case len(names) == 0:
+		who = "Someone"
+	case g.ActorsCount == 1:
+		who = names[0]
+	case g.ActorsCount == 2 && len(names) == 2:
+		who = names[0] + " and " + names[1]
+	case g.ActorsCount == 2:
+		who = names[0] + " and 1 other"
+	default:
+		who = fmt.Sprintf("%s and %d others", names[0], g.ActorsCount-1)
+	}
+
+	g.Summary = who + " " + notificationActions[g.Type]
+}
+
+// Action describes what the actor did, e.g. "liked your blog"
+func (n *Notification) Action() string {
+	return notificationActions[n.Type]
+}
+
+var notificationActions = map[string]string{
+	NotificationFollow:        "started following you",
+	NotificationFollowRequest: "requested to follow you",
+	NotificationFollowAccept:  "accepted your follow request",
+	NotificationLike:          "liked your blog",
+	NotificationComment:       "commented on your blog",
+	NotificationReply:         "replied to your comment",
+	NotificationMention:       "mentioned you",
+}
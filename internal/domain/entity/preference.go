@@ -0,0 +1,97 @@
+package entity
+
+// Notification preference types; several notification types share one,
+// e.g. replies are comments
+const (
+	PreferenceFollow  = "follow"
+	PreferenceLike    = "like"
+	PreferenceComment = "comment"
+	PreferenceMention = "mention"
+	PreferenceDigest  = "digest"
+)
+
+// Notification channels
+const (
+	ChannelInApp = "in_app"
+	ChannelEmail = "email"
+	ChannelPush  = "push"
+)
+
+// defaultPreferences are the channels each preference type is sent on
+// until a user changes them, and at the same time the channels each type
+// can be sent on: digests are email only
+var defaultPreferences = map[string]map[string]bool{
+	PreferenceFollow:  {ChannelInApp: true, ChannelEmail: false, ChannelPush: true},
+	PreferenceLike:    {ChannelInApp: true, ChannelEmail: false, ChannelPush: false},
+	PreferenceComment: {ChannelInApp: true, ChannelEmail: true, ChannelPush: true},
+	PreferenceMention: {ChannelInApp: true, ChannelEmail: true, ChannelPush: true},
+	PreferenceDigest:  {ChannelEmail: true},
+}
+
+// preferenceTypes maps notification types onto their preference type
+var preferenceTypes = map[string]string{
+	NotificationFollow:        PreferenceFollow,
+	NotificationFollowRequest: PreferenceFollow,
+	NotificationFollowAccept:  PreferenceFollow,
+	NotificationLike:          PreferenceLike,
+	NotificationComment:       PreferenceComment,
+	NotificationReply:         PreferenceComment,
+	NotificationMention:       PreferenceMention,
+}
+
+// NotificationPreferences holds, per preference type, whether each channel
+// is on, e.g. prefs["like"]["email"]
+type NotificationPreferences map[string]map[string]bool
+
+// DefaultNotificationPreferences returns the preferences of a user who
+// never changed them
+func DefaultNotificationPreferences() NotificationPreferences {
+	prefs := NotificationPreferences{}
+	for prefType, channels := range defaultPreferences {
+		prefs[prefType] = map[string]bool{}
+		for channel, on := range channels {
+			prefs[prefType][channel] = on
+		}
+	}
+	return prefs
+}
+
+// Validate checks that every type and channel set exists and that the
+// type can be sent on the channel
+func (p NotificationPreferences) Validate() error {
+	for prefType, channels := range p {
+		allowed, ok := defaultPreferences[prefType]
+		if !ok {
+			return ErrInvalidPreference
+		}
+		for channel := range channels {
+			if _, ok := allowed[channel]; !ok {
+				return ErrInvalidPreference
+			}
+		}
+	}
+	return nil
+}
+
+// Apply overwrites the channels changes sets, leaving the others as they
+// are
+func (p NotificationPreferences) Apply(changes NotificationPreferences) {
+	for prefType, channels := range changes {
+		if p[prefType] == nil {
+			p[prefType] = map[string]bool{}
+		}
+		for channel, on := range channels {
+			p[prefType][channel] = on
+		}
+	}
+}
+
+// Enabled checks if a preference type is sent on a channel
+func (p NotificationPreferences) Enabled(prefType, channel string) bool {
+	return p[prefType][channel]
+}
+
+// PreferenceType returns the preference type a notification falls under
+func (n *Notification) PreferenceType() string {
+	return preferenceTypes[n.Type]
+}
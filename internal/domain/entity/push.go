@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// maxPushEndpointLength bounds the endpoint URL of a push subscription
+	maxPushEndpointLength = 2048
+
+	// maxUserAgentLength bounds the user agent a push subscription is
+	// labelled with
+	maxUserAgentLength = 255
+)
+
+// PushSubscription is a device's Web Push subscription: the push service
+// endpoint notifications are posted to and the keys they are encrypted
+// with. UserAgent labels the device for its owner.
+type PushSubscription struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Endpoint  string    `json:"endpoint"`
+	P256dh    string    `json:"-"`
+	Auth      string    `json:"-"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewPushSubscription creates a push subscription from what a browser's
+// PushSubscription reports. The endpoint must be https, as push services
+// are; p256dh must be an uncompressed P-256 point and auth a 16-byte
+// secret, both base64url encoded; they are stored without padding.
+func NewPushSubscription(userID int64, endpoint, p256dh, auth, userAgent string) (*PushSubscription, error) {
+	endpoint = strings.TrimSpace(endpoint)
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme != "https" || u.Host == "" ||
+		len(endpoint) > maxPushEndpointLength {
+		return nil, ErrInvalidPushSubscription
+	}
+
+	key, err := decodePushKey(p256dh)
+	if err != nil || len(key) != 65 || key[0] != 0x04 {
+		return nil, ErrInvalidPushSubscription
+	}
+	secret, err := decodePushKey(auth)
+	if err != nil || len(secret) != 16 {
+		return nil, ErrInvalidPushSubscription
+	}
+
+	userAgent = strings.TrimSpace(userAgent)
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	return &PushSubscription{
+		UserID:    userID,
+		Endpoint:  endpoint,
+		P256dh:    base64.RawURLEncoding.EncodeToString(key),
+		Auth:      base64.RawURLEncoding.EncodeToString(secret),
+		UserAgent: userAgent,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// decodePushKey decodes base64url with or without padding
+func decodePushKey(encoded string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(strings.TrimSpace(encoded), "="))
+}
+
+// VAPIDKey is the P-256 key pair this server identifies itself to push
+// services with, base64url encoded as browsers expect the public key
+type VAPIDKey struct {
+	PublicKey  string
+	PrivateKey string
+	CreatedAt  time.Time
+}
+
+// PushMessage is the payload of a push, shown by the service worker as a
+// notification
+type PushMessage struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	URL   string `json:"url"`
+}
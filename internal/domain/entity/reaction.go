@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultReaction is the reaction counted as a like
+	DefaultReaction = "like"
+
+	// MaxReactionKindLength is the longest reaction kind that can be stored
+	MaxReactionKindLength = 32
+)
+
+// Reaction represents a user's reaction of a given kind to a blog
+type Reaction struct {
+	BlogID    int64     `json:"blog_id"`
+	UserID    int64     `json:"user_id"`
+	User      *User     `json:"user,omitempty"`
+	Kind      string    `json:"kind"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NormalizeReactionKinds lowercases and de-duplicates reaction kinds,
+// making sure the default reaction is always available and listed first
+func NormalizeReactionKinds(kinds []string) []string {
+	normalized := []string{DefaultReaction}
+	seen := map[string]bool{DefaultReaction: true}
+	for _, kind := range kinds {
+		kind = strings.ToLower(strings.TrimSpace(kind))
+		if kind == "" || len(kind) > MaxReactionKindLength || seen[kind] {
+			continue
+		}
+		seen[kind] = true
+		normalized = append(normalized, kind)
+	}
+	return normalized
+}
@@ -0,0 +1,21 @@
+package entity
+
+// Recommendation reasons, from strongest to weakest signal
+const (
+	// RecommendedByFriends is for authors followed by users you follow
+	RecommendedByFriends = "followed_by_friends"
+
+	// RecommendedByLikes is for authors of blogs you liked
+	RecommendedByLikes = "liked_author"
+
+	// RecommendedPopular is for authors with many followers, suggested to
+	// users with too little activity for the other reasons
+	RecommendedPopular = "popular"
+)
+
+// Recommendation suggests a user to follow
+type Recommendation struct {
+	User   *User   `json:"user"`
+	Reason string  `json:"reason"`
+	Score  float64 `json:"score"`
+}
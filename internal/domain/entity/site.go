@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// Site is the content of a static site export: one author and their
+// published blogs, newest first
+type Site struct {
+	Author      *User
+	Blogs       []*Blog
+	BaseURL     string
+	GeneratedAt time.Time
+}
@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxSlugLength matches the width of the blogs.slug column
+const maxSlugLength = 200
+
+// Slugify turns free text into a lowercase, hyphen-separated identifier
+// suitable for URLs, e.g. "Getting Started with Go!" -> "getting-started-with-go"
+func Slugify(s string) string {
+	var b strings.Builder
+	pendingDash := false
+
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if pendingDash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingDash = false
+			b.WriteRune(r)
+			continue
+		}
+		pendingDash = true
+	}
+
+	slug := b.String()
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(truncateRunes(slug, maxSlugLength), "-")
+	}
+	return slug
+}
+
+// NormalizeTags slugifies tags, dropping empty ones and duplicates while
+// keeping their original order
+func NormalizeTags(tags []string) []string {
+	normalized := []string{}
+	seen := map[string]bool{}
+
+	for _, tag := range tags {
+		tag = Slugify(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// truncateRunes cuts s to at most n bytes without splitting a rune
+func truncateRunes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// RenderedFeed is a syndication feed rendered in one format, kept with what
+// conditional requests for it are checked against
+type RenderedFeed struct {
+	Body        []byte    `json:"body"`
+	ContentType string    `json:"content_type"`
+	ETag        string    `json:"etag"`
+	Updated     time.Time `json:"updated"`
+}
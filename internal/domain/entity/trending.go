@@ -0,0 +1,54 @@
+package entity
+
+import "time"
+
+// Blog list sort orders
+const (
+	SortLatest   = "latest"
+	SortTop      = "top"
+	SortTrending = "trending"
+)
+
+// Engagement weights of the trending score: a comment counts as much as
+// three likes, and a view as a tenth of a like
+const (
+	TrendingLikeWeight    = 1.0
+	TrendingCommentWeight = 3.0
+	TrendingViewWeight    = 0.1
+)
+
+// TrendingWindow is a period blogs trend over. Engagement within the
+// period counts for less the older it is, halving every HalfLife.
+type TrendingWindow struct {
+	Name     string
+	Period   time.Duration
+	HalfLife time.Duration
+}
+
+// TrendingWindows are the supported trending periods; the first is the
+// default
+var TrendingWindows = []TrendingWindow{
+	{Name: "24h", Period: 24 * time.Hour, HalfLife: 6 * time.Hour},
+	{Name: "7d", Period: 7 * 24 * time.Hour, HalfLife: 2 * 24 * time.Hour},
+	{Name: "30d", Period: 30 * 24 * time.Hour, HalfLife: 7 * 24 * time.Hour},
+}
+
+// ParseTrendingWindow looks up a trending window by name; an empty name
+// selects the default window
+func ParseTrendingWindow(name string) (TrendingWindow, error) {
+	if name == "" {
+		return TrendingWindows[0], nil
+	}
+	for _, window := range TrendingWindows {
+		if window.Name == name {
+			return window, nil
+		}
+	}
+	return TrendingWindow{}, ErrInvalidTrendingWindow
+}
+
+// TrendingBlog is a blog's score within a trending window
+type TrendingBlog struct {
+	BlogID int64
+	Score  float64
+}
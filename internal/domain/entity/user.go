@@ -10,6 +10,7 @@ type User struct {
 	DisplayName  string    `json:"display_name"`
 	Bio          string    `json:"bio,omitempty"`
 	ProfileImage string    `json:"profile_image,omitempty"`
+	Private      bool      `json:"private"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
@@ -21,6 +22,19 @@ type UserStats struct {
 	BlogsCount     int `json:"blogs_count"`
 }
 
+// Follow statuses. Follows of private accounts stay pending until the
+// followed user accepts them.
+const (
+	FollowAccepted = "accepted"
+	FollowPending  = "pending"
+)
+
+// FollowRequest is a pending follow of a private account
+type FollowRequest struct {
+	Follower  *User     `json:"follower"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 // NewUser creates a new user entity
 func NewUser(username, email, displayName string) *User {
 	now := time.Now()
@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Webmention statuses
+const (
+	WebmentionPending  = "pending"
+	WebmentionVerified = "verified"
+)
+
+// MaxWebmentionTargets is how many distinct URLs linked from a single blog
+// are sent Webmentions; further links are ignored
+const MaxWebmentionTargets = 50
+
+// linkPattern matches absolute http(s) URLs in plain text
+var linkPattern = regexp.MustCompile(`https?://[^\s<>"'` + "`" + `]+`)
+
+// Webmention is a response to a blog published elsewhere on the web: a page
+// at Source that links to the blog at Target. Received Webmentions are kept
+// pending until the source is fetched and found to link to the target.
+type Webmention struct {
+	ID         int64      `json:"id"`
+	BlogID     int64      `json:"blog_id"`
+	Source     string     `json:"source"`
+	Target     string     `json:"target"`
+	Status     string     `json:"status"`
+	Title      string     `json:"title,omitempty"`
+	VerifiedAt *time.Time `json:"verified_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// NewWebmention creates a pending Webmention of a blog
+func NewWebmention(blogID int64, source, target string) *Webmention {
+	now := time.Now()
+	return &Webmention{
+		BlogID:    blogID,
+		Source:    source,
+		Target:    target,
+		Status:    WebmentionPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// WebmentionSource is what fetching the source of a Webmention found
+type WebmentionSource struct {
+	LinksTarget bool
+	Title       string
+}
+
+// IsWebURL checks if raw is an absolute http or https URL
+func IsWebURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// ExtractLinks returns the distinct absolute http(s) URLs in text, in order
+// of first appearance, up to MaxWebmentionTargets. Punctuation ending a
+// sentence or closing a Markdown link is not taken as part of a URL.
+func ExtractLinks(text string) []string {
+	links := []string{}
+	seen := map[string]bool{}
+	for _, match := range linkPattern.FindAllString(text, -1) {
+		link := strings.TrimRight(match, ".,;:!?)]}*_")
+		if seen[link] || !IsWebURL(link) {
+			continue
+		}
+		seen[link] = true
+		links = append(links, link)
+		if len(links) == MaxWebmentionTargets {
+			break
+		}
+	}
+	return links
+}
@@ -0,0 +1,22 @@
+package repository
+
+import "AbdelrahmanDwedar/blogo/internal/domain/entity"
+
+// AccessTokenRepository stores the access tokens users create for
+// third-party clients
+type AccessTokenRepository interface {
+	// Create stores a new access token
+	Create(token *entity.AccessToken) error
+
+	// GetByHash retrieves an access token by the hash of its secret
+	GetByHash(hash string) (*entity.AccessToken, error)
+
+	// GetByUser retrieves a user's access tokens, newest first
+	GetByUser(userID int64) ([]*entity.AccessToken, error)
+
+	// Delete revokes one of a user's access tokens
+	Delete(id, userID int64) error
+
+	// Touch records that an access token was just used
+	Touch(id int64) error
+}
@@ -1,6 +1,10 @@
 package repository
 
-import "AbdelrahmanDwedar/blogo/internal/domain/entity"
+import (
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
 
 // BlogRepository defines the interface for blog data access
 type BlogRepository interface {
@@ -10,29 +14,82 @@ type BlogRepository interface {
 	// GetByID retrieves a blog by ID
 	GetByID(id int64) (*entity.Blog, error)
 
-	// GetAll retrieves all blogs with pagination
-	GetAll(limit, offset int) ([]*entity.Blog, error)
+	// GetBySlug retrieves an author's blog by its slug
+	GetBySlug(authorID int64, slug string) (*entity.Blog, error)
+
+	// GetAll retrieves all blogs with pagination, leaving out blogs by users
+	// in a block with viewerID and by private accounts viewerID doesn't
+	// follow
+	GetAll(viewerID int64, limit, offset int) ([]*entity.Blog, error)
+
+	// GetTop retrieves blogs with the most likes first, leaving out blogs by
+	// users in a block with viewerID and by private accounts viewerID
+	// doesn't follow
+	GetTop(viewerID int64, limit, offset int) ([]*entity.Blog, error)
 
 	// GetByAuthor retrieves blogs by a specific author
 	GetByAuthor(authorID int64, limit, offset int) ([]*entity.Blog, error)
 
+	// GetByTag retrieves the blogs of public accounts with a tag, newest
+	// first
+	GetByTag(tag string, limit, offset int) ([]*entity.Blog, error)
+
+	// GetByIDs retrieves the live blogs among ids, in the order of ids
+	GetByIDs(ids []int64) ([]*entity.Blog, error)
+
+	// GetFeed retrieves blogs by the authors a user follows and hasn't
+	// muted, newest first, starting after cursor
+	GetFeed(userID int64, cursor *entity.Cursor, limit int) ([]*entity.Blog, error)
+
 	// Update updates a blog post
 	Update(blog *entity.Blog) error
 
-	// Delete deletes a blog post
+	// Delete moves a blog post to its author's trash
 	Delete(id, authorID int64) error
 
-	// Like adds a like to a blog
-	Like(blogID, userID int64) error
+	// GetDeletedByAuthor retrieves the blogs in an author's trash
+	GetDeletedByAuthor(authorID int64, limit, offset int) ([]*entity.Blog, error)
+
+	// Restore moves a blog post out of its author's trash
+	Restore(id, authorID int64) error
+
+	// PurgeDeleted permanently removes blogs trashed before the given time
+	// and returns how many were removed
+	PurgeDeleted(before time.Time) (int64, error)
+
+	// UpdateCommentSettings saves a blog's comment mode and moderation flag
+	UpdateCommentSettings(blog *entity.Blog) error
+
+	// SetPinnedComment pins a comment to the top of a blog's comments, or
+	// unpins it when commentID is nil
+	SetPinnedComment(blogID int64, commentID *int64) error
+
+	// React adds a user's reaction of the given kind to a blog
+	React(blogID, userID int64, kind string) error
+
+	// Unreact removes a user's reaction of the given kind from a blog
+	Unreact(blogID, userID int64, kind string) error
+
+	// GetReactions retrieves a blog's reactions with their users, newest
+	// first, leaving out users in a block with viewerID; an empty kind
+	// returns reactions of every kind
+	GetReactions(blogID, viewerID int64, kind string, limit, offset int) ([]*entity.Reaction, error)
+
+	// HasReacted checks if a user has reacted to a blog with the given kind
+	HasReacted(blogID, userID int64, kind string) (bool, error)
 
-	// Unlike removes a like from a blog
-	Unlike(blogID, userID int64) error
+	// RecordView counts a view of a blog unless viewer, a key for the user
+	// or address viewing it, already viewed it this hour
+	RecordView(blogID int64, viewer string) error
 
-	// GetLikes retrieves users who liked a blog
-	GetLikes(blogID int64, limit, offset int) ([]*entity.User, error)
+	// PurgeViews removes view counts recorded before the given time, and
+	// the viewers recorded before the current hour
+	PurgeViews(before time.Time) (int64, error)
 
-	// IsLikedBy checks if a user has liked a blog
-	IsLikedBy(blogID, userID int64) (bool, error)
+	// ComputeTrending scores live blogs of public accounts by their likes,
+	// comments and views within a trending window and returns up to limit
+	// of the highest scoring
+	ComputeTrending(window entity.TrendingWindow, limit int) ([]*entity.TrendingBlog, error)
 }
 
 
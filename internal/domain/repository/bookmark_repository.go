@@ -0,0 +1,48 @@
+package repository
+
+import "AbdelrahmanDwedar/blogo/internal/domain/entity"
+
+// BookmarkRepository stores the blogs users bookmark and the reading lists
+// they keep. Trashed blogs are left out of both until they are restored.
+type BookmarkRepository interface {
+	// AddBookmark bookmarks a blog for a user, keeping the existing
+	// bookmark if there is one
+	AddBookmark(bookmark *entity.Bookmark) error
+
+	// RemoveBookmark removes a user's bookmark of a blog, if any
+	RemoveBookmark(userID, blogID int64) error
+
+	// GetBookmarks retrieves a user's bookmarks with their blogs, newest
+	// first, starting after cursor
+	GetBookmarks(userID int64, cursor *entity.Cursor, limit int) ([]*entity.Bookmark, error)
+
+	// GetBookmarkedIDs returns which of blogIDs a user bookmarked
+	GetBookmarkedIDs(userID int64, blogIDs []int64) (map[int64]bool, error)
+
+	// CreateList stores a new reading list
+	CreateList(list *entity.ReadingList) error
+
+	// GetList retrieves a reading list by ID
+	GetList(id int64) (*entity.ReadingList, error)
+
+	// GetLists retrieves a user's reading lists, newest first, or only the
+	// public ones
+	GetLists(userID int64, publicOnly bool) ([]*entity.ReadingList, error)
+
+	// UpdateList saves a reading list's name, description and visibility
+	UpdateList(list *entity.ReadingList) error
+
+	// DeleteList deletes a reading list with its items
+	DeleteList(id int64) error
+
+	// AddToList adds a blog to a reading list, keeping the existing item
+	// if it is already there
+	AddToList(item *entity.ReadingListItem) error
+
+	// RemoveFromList removes a blog from a reading list, if it is there
+	RemoveFromList(listID, blogID int64) error
+
+	// GetListItems retrieves the items of a reading list with their blogs,
+	// most recently added first, starting after cursor
+	GetListItems(listID int64, cursor *entity.Cursor, limit int) ([]*entity.ReadingListItem, error)
+}
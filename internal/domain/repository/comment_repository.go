@@ -0,0 +1,30 @@
+package repository
+
+import "AbdelrahmanDwedar/blogo/internal/domain/entity"
+
+// CommentRepository defines the interface for comment data access
+type CommentRepository interface {
+	// Create creates a new comment
+	Create(comment *entity.Comment) error
+
+	// GetByID retrieves a comment by ID, including deleted comments
+	GetByID(id int64) (*entity.Comment, error)
+
+	// GetThreads retrieves a page of a blog's published top-level comments,
+	// pinned comment first and then oldest first, followed by all of their
+	// published replies
+	GetThreads(blogID int64, limit, offset int) ([]*entity.Comment, error)
+
+	// GetPendingForAuthor retrieves comments awaiting approval across all
+	// blogs of an author, oldest first
+	GetPendingForAuthor(authorID int64, limit, offset int) ([]*entity.Comment, error)
+
+	// Approve publishes a pending comment
+	Approve(id int64) error
+
+	// Update updates a comment's body
+	Update(comment *entity.Comment) error
+
+	// Delete marks a comment as deleted, keeping its replies in place
+	Delete(id int64) error
+}
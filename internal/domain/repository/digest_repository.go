@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// DigestRepository stores users' digest settings, the periods digests were
+// sent for, and gathers what goes into a digest
+type DigestRepository interface {
+	// GetFrequency retrieves how often a user gets a digest, DigestOff
+	// when they never opted in
+	GetFrequency(userID int64) (string, error)
+
+	// SetFrequency saves how often a user gets a digest
+	SetFrequency(userID int64, frequency string) error
+
+	// ClaimPeriod records the period starting at periodStart as sent for
+	// every user getting digests at frequency who has no record of it yet,
+	// and returns their IDs. Each user is claimed once per period, however
+	// often this runs.
+	ClaimPeriod(frequency string, periodStart time.Time) ([]int64, error)
+
+	// ReleasePeriod deletes a user's claim on the period starting at
+	// periodStart, so that the next ClaimPeriod claims it again
+	ReleasePeriod(userID int64, frequency string, periodStart time.Time) error
+
+	// GetNewBlogs retrieves up to limit blogs created within a period by
+	// the authors a user follows and hasn't muted, newest first
+	GetNewBlogs(userID int64, since, until time.Time, limit int) ([]*entity.Blog, error)
+
+	// GetTopBlogs retrieves up to limit blogs the users a user follows
+	// liked most within a period, leaving out the user's own blogs and
+	// blogs they cannot see or muted the author of
+	GetTopBlogs(userID int64, since, until time.Time, limit int) ([]*entity.Blog, error)
+
+	// GetNewFollowers retrieves up to limit users who started following a
+	// user within a period, newest first
+	GetNewFollowers(userID int64, since, until time.Time, limit int) ([]*entity.User, error)
+
+	// CountNewFollowers counts the users who started following a user
+	// within a period
+	CountNewFollowers(userID int64, since, until time.Time) (int, error)
+}
@@ -0,0 +1,41 @@
+package repository
+
+import "AbdelrahmanDwedar/blogo/internal/domain/entity"
+
+// FederationRepository stores the state of ActivityPub federation: local
+// actors' keys, cached remote actors, and remote follows and likes
+type FederationRepository interface {
+	// GetKey retrieves a user's actor key pair
+	GetKey(userID int64) (*entity.ActorKey, error)
+
+	// CreateKey stores a user's actor key pair unless they already have one
+	CreateKey(key *entity.ActorKey) error
+
+	// GetRemoteActor retrieves a cached remote actor by ID
+	GetRemoteActor(id string) (*entity.RemoteActor, error)
+
+	// SaveRemoteActor creates or refreshes a cached remote actor
+	SaveRemoteActor(actor *entity.RemoteActor) error
+
+	// AddFollower records a remote actor following a user
+	AddFollower(follower *entity.RemoteFollower) error
+
+	// RemoveFollower removes a remote actor's follow of a user
+	RemoveFollower(userID int64, actorID string) error
+
+	// GetFollowerInboxes retrieves the distinct inboxes of a user's remote
+	// followers
+	GetFollowerInboxes(userID int64) ([]string, error)
+
+	// CountFollowers counts a user's remote followers
+	CountFollowers(userID int64) (int, error)
+
+	// AddLike records a remote actor liking a blog
+	AddLike(blogID int64, actorID, activityID string) error
+
+	// RemoveLike removes the like a remote actor made with an activity
+	RemoveLike(actorID, activityID string) error
+
+	// CountLikes counts the remote likes of a blog
+	CountLikes(blogID int64) (int, error)
+}
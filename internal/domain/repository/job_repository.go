@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// JobRepository defines the interface for the background job queue
+type JobRepository interface {
+	// Enqueue stores a new job
+	Enqueue(job *entity.Job) error
+
+	// GetByID retrieves a job by ID
+	GetByID(id int64) (*entity.Job, error)
+
+	// Claim marks up to limit due jobs of the given kinds as running for
+	// the lease duration and returns them. Running jobs whose lease has
+	// expired are claimed again.
+	Claim(kinds []string, limit int, lease time.Duration) ([]*entity.Job, error)
+
+	// Complete marks a job as done with its result
+	Complete(id int64, result string) error
+
+	// Retry puts a failed job back in the queue to run after delay
+	Retry(id int64, lastError string, delay time.Duration) error
+
+	// Fail marks a job as permanently failed
+	Fail(id int64, lastError string) error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import "AbdelrahmanDwedar/blogo/internal/domain/entity"
+
+// MentionRepository defines the interface for mention data access
+type MentionRepository interface {
+	// Replace sets the users mentioned by a blog, or by one of its comments
+	// when commentID is set, and returns the IDs of newly mentioned users
+	Replace(blogID int64, commentID *int64, authorID int64, userIDs []int64) ([]int64, error)
+
+	// GetForBlog retrieves the mentions in a blog's body
+	GetForBlog(blogID int64) ([]*entity.Mention, error)
+
+	// GetForComments retrieves the mentions in the given comments
+	GetForComments(commentIDs []int64) ([]*entity.Mention, error)
+
+	// GetByUser retrieves mentions of a user in live blogs and published
+	// comments, newest first, leaving out authors in a block with the user
+	// and blogs by private accounts the user doesn't follow
+	GetByUser(userID int64, limit, offset int) ([]*entity.Mention, error)
+}
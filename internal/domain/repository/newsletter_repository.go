@@ -0,0 +1,36 @@
+package repository
+
+import "AbdelrahmanDwedar/blogo/internal/domain/entity"
+
+// NewsletterRepository stores email subscriptions to authors and the
+// addresses that must no longer be mailed
+type NewsletterRepository interface {
+	// Subscribe stores a subscription unless the address is already
+	// subscribed to the author, and fills in the subscription as stored
+	Subscribe(subscription *entity.Subscription) error
+
+	// GetByID retrieves a subscription by ID
+	GetByID(id int64) (*entity.Subscription, error)
+
+	// Confirm marks a subscription confirmed
+	Confirm(id int64) (*entity.Subscription, error)
+
+	// Delete removes a subscription
+	Delete(id int64) error
+
+	// ClaimDeliveries records a blog as sent to each of an author's
+	// confirmed subscriptions, leaving out suppressed addresses and
+	// subscriptions it was already sent to, and returns their IDs. Each
+	// subscription is claimed once per blog, however often this runs.
+	ClaimDeliveries(authorID, blogID int64) ([]int64, error)
+
+	// ReleaseDeliveries deletes the claims of a blog on subscriptionIDs, so
+	// that the next ClaimDeliveries claims them again
+	ReleaseDeliveries(blogID int64, subscriptionIDs []int64) error
+
+	// Suppress stops all email to an address for the given reason
+	Suppress(email, reason string) error
+
+	// IsSuppressed checks if email to an address is suppressed
+	IsSuppressed(email string) (bool, error)
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// NotificationRepository defines the interface for notification data access
+type NotificationRepository interface {
+	// Create creates a new notification. An unread notification from the
+	// same actor in the same group is not duplicated; the notification's ID
+	// is left zero then.
+	Create(notification *entity.Notification) error
+
+	// RecordSend records a notification that is not stored as sent on other
+	// channels. It reports false, recording nothing, when the same actor's
+	// notification in the same group was already sent after since.
+	RecordSend(notification *entity.Notification, since time.Time) (bool, error)
+
+	// GetGroups retrieves a user's notifications grouped for display,
+	// newest first
+	GetGroups(userID int64, limit, offset int) ([]*entity.NotificationGroup, error)
+
+	// CountUnread counts a user's groups of unread notifications
+	CountUnread(userID int64) (int, error)
+
+	// MarkGroupRead marks the notification with the given ID read, along
+	// with the unread notifications grouped with it
+	MarkGroupRead(userID, id int64) error
+
+	// MarkAllRead marks all of a user's notifications read
+	MarkAllRead(userID int64) error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import "AbdelrahmanDwedar/blogo/internal/domain/entity"
+
+// PreferenceRepository stores the notification channels users turned on or
+// off
+type PreferenceRepository interface {
+	// GetPreferences retrieves the channels a user set, without defaults
+	GetPreferences(userID int64) (entity.NotificationPreferences, error)
+
+	// SavePreferences stores the channels set in prefs, leaving the
+	// others as they are
+	SavePreferences(userID int64, prefs entity.NotificationPreferences) error
+}
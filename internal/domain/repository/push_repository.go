@@ -0,0 +1,29 @@
+package repository
+
+import "AbdelrahmanDwedar/blogo/internal/domain/entity"
+
+// PushRepository stores the Web Push subscriptions of users' devices and
+// the VAPID key pushes are sent with
+type PushRepository interface {
+	// Subscribe stores a push subscription. A subscription with the same
+	// endpoint is replaced, moving it to the new user if need be.
+	Subscribe(subscription *entity.PushSubscription) error
+
+	// GetByID retrieves a push subscription by ID
+	GetByID(id int64) (*entity.PushSubscription, error)
+
+	// GetByUser retrieves a user's push subscriptions, newest first
+	GetByUser(userID int64) ([]*entity.PushSubscription, error)
+
+	// Unsubscribe deletes a user's push subscription by its endpoint
+	Unsubscribe(userID int64, endpoint string) error
+
+	// Delete deletes a push subscription the push service dropped
+	Delete(id int64) error
+
+	// GetVAPIDKey retrieves the VAPID key
+	GetVAPIDKey() (*entity.VAPIDKey, error)
+
+	// CreateVAPIDKey stores the VAPID key unless there already is one
+	CreateVAPIDKey(key *entity.VAPIDKey) error
+}
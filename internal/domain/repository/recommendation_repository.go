@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// RecommendationRepository computes who-to-follow recommendations
+type RecommendationRepository interface {
+	// ComputeRecommendations ranks up to limit users for the given user to
+	// follow, leaving out users they follow or asked to follow, muted, or
+	// are in a block with
+	ComputeRecommendations(userID int64, limit int) ([]*entity.Recommendation, error)
+}
+
+// RecommendationCache stores computed recommendations between refreshes
+type RecommendationCache interface {
+	// GetRecommendations retrieves a user's stored recommendations and
+	// records that the user asked for them. ok is false when none are
+	// stored.
+	GetRecommendations(userID int64) (recommendations []*entity.Recommendation, ok bool, err error)
+
+	// StoreRecommendations replaces a user's stored recommendations
+	StoreRecommendations(userID int64, recommendations []*entity.Recommendation, expiration time.Duration) error
+
+	// DeleteRecommendations drops a user's stored recommendations so they
+	// are recomputed
+	DeleteRecommendations(userID int64) error
+
+	// GetActiveRecommendationUsers retrieves the users who asked for
+	// recommendations since the given time, forgetting the others
+	GetActiveRecommendationUsers(since time.Time) ([]int64, error)
+}
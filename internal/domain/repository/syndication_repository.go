@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// SyndicationCache keeps rendered syndication feeds. ok is false when a
+// feed is not cached.
+type SyndicationCache interface {
+	// GetFeed retrieves a rendered feed by key
+	GetFeed(key string) (feed *entity.RenderedFeed, ok bool, err error)
+
+	// StoreFeed stores a rendered feed under key
+	StoreFeed(key string, feed *entity.RenderedFeed, expiration time.Duration) error
+
+	// DeleteFeeds drops every rendered feed
+	DeleteFeeds() error
+
+	// SetFeedsChangedAt records when the blogs or authors feeds are built
+	// from last changed
+	SetFeedsChangedAt(at time.Time) error
+
+	// GetFeedsChangedAt retrieves when the feeds last changed; ok is false
+	// when that is not known
+	GetFeedsChangedAt() (at time.Time, ok bool, err error)
+}
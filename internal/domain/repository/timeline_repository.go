@@ -0,0 +1,27 @@
+package repository
+
+import "AbdelrahmanDwedar/blogo/internal/domain/entity"
+
+// TimelineRepository stores precomputed home timelines: the IDs of blogs by
+// the authors each user follows, newest first
+type TimelineRepository interface {
+	// GetTimeline retrieves up to limit blog IDs from a user's timeline,
+	// starting after cursor. ok is false when no timeline is stored or it
+	// cannot serve the requested page, and the caller should fall back to
+	// the database.
+	GetTimeline(userID int64, cursor *entity.Cursor, limit int) (ids []int64, ok bool, err error)
+
+	// StoreTimeline replaces a user's timeline with the given blogs; complete
+	// tells whether they are all the blogs the timeline should contain
+	StoreTimeline(userID int64, blogs []*entity.Blog, complete bool) error
+
+	// AddToTimelines adds a blog to the stored timelines of the given users
+	AddToTimelines(userIDs []int64, blog *entity.Blog) error
+
+	// RemoveFromTimelines removes a blog from the stored timelines of the
+	// given users
+	RemoveFromTimelines(userIDs []int64, blogID int64) error
+
+	// DeleteTimeline drops a user's stored timeline so it is rebuilt
+	DeleteTimeline(userID int64) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "AbdelrahmanDwedar/blogo/internal/domain/entity"
+
+// TrendingRepository stores the precomputed trending blogs of each trending
+// window, highest score first
+type TrendingRepository interface {
+	// StoreTrending replaces the trending blogs of a window
+	StoreTrending(window string, blogs []*entity.TrendingBlog) error
+
+	// GetTrending retrieves a page of the IDs of a window's trending blogs
+	GetTrending(window string, limit, offset int) ([]int64, error)
+}
@@ -16,23 +16,73 @@ type UserRepository interface {
 	// Update updates user information
 	Update(user *entity.User) error
 
-	// Follow creates a follow relationship
-	Follow(followerID, followingID int64) error
+	// Follow creates a follow relationship with the given status and
+	// returns the status stored, which is unchanged if the relationship
+	// already existed
+	Follow(followerID, followingID int64, status string) (string, error)
 
-	// Unfollow removes a follow relationship
+	// Unfollow removes a follow relationship or a pending follow request
 	Unfollow(followerID, followingID int64) error
 
-	// GetFollowers retrieves users following the given user
-	GetFollowers(userID int64, limit, offset int) ([]*entity.User, error)
+	// GetFollowers retrieves users following the given user, leaving out
+	// users in a block with viewerID
+	GetFollowers(userID, viewerID int64, limit, offset int) ([]*entity.User, error)
 
-	// GetFollowing retrieves users that the given user is following
-	GetFollowing(userID int64, limit, offset int) ([]*entity.User, error)
+	// GetFollowing retrieves users that the given user is following, leaving
+	// out users in a block with viewerID
+	GetFollowing(userID, viewerID int64, limit, offset int) ([]*entity.User, error)
+
+	// GetFollowerIDs retrieves the IDs of the users following the given
+	// user, leaving out those who muted them
+	GetFollowerIDs(userID int64) ([]int64, error)
 
 	// IsFollowing checks if one user is following another
 	IsFollowing(followerID, followingID int64) (bool, error)
 
+	// GetFollowRequests retrieves the pending follow requests of the given
+	// user, newest first
+	GetFollowRequests(userID int64, limit, offset int) ([]*entity.FollowRequest, error)
+
+	// AcceptFollowRequest turns a pending follow request into a follow
+	AcceptFollowRequest(followerID, followingID int64) error
+
+	// RejectFollowRequest removes a pending follow request
+	RejectFollowRequest(followerID, followingID int64) error
+
+	// AcceptAllFollowRequests accepts every pending follow request of the
+	// given user and returns the IDs of the new followers
+	AcceptAllFollowRequests(userID int64) ([]int64, error)
+
 	// GetStats retrieves statistics for a user
 	GetStats(userID int64) (*entity.UserStats, error)
+
+	// Block blocks a user and removes the follows between the two users
+	Block(blockerID, blockedID int64) error
+
+	// Unblock removes a block
+	Unblock(blockerID, blockedID int64) error
+
+	// GetBlocked retrieves the users the given user has blocked
+	GetBlocked(userID int64, limit, offset int) ([]*entity.User, error)
+
+	// GetBlockIDs retrieves the IDs of the users who blocked or were
+	// blocked by the given user
+	GetBlockIDs(userID int64) ([]int64, error)
+
+	// IsBlocked checks if either user has blocked the other
+	IsBlocked(userID, otherID int64) (bool, error)
+
+	// Mute mutes a user
+	Mute(muterID, mutedID int64) error
+
+	// Unmute removes a mute
+	Unmute(muterID, mutedID int64) error
+
+	// GetMuted retrieves the users the given user has muted
+	GetMuted(userID int64, limit, offset int) ([]*entity.User, error)
+
+	// IsMuted checks if one user has muted another
+	IsMuted(muterID, mutedID int64) (bool, error)
 }
 
 
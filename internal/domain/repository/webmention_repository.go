@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// WebmentionRepository stores Webmentions received for blogs and the URLs
+// blogs have sent Webmentions to
+type WebmentionRepository interface {
+	// Save stores a pending Webmention, or updates an earlier one from the
+	// same source to the same blog so it is checked again. A verified
+	// Webmention stays verified until the check fails.
+	Save(webmention *entity.Webmention) error
+
+	// CountFromHost counts the Webmentions received or received again since
+	// a time from sources on host
+	CountFromHost(host string, since time.Time) (int, error)
+
+	// GetByID retrieves a Webmention by ID
+	GetByID(id int64) (*entity.Webmention, error)
+
+	// MarkVerified records that a Webmention's source links to its target
+	MarkVerified(id int64, title string) error
+
+	// Delete removes a Webmention
+	Delete(id int64) error
+
+	// GetVerified retrieves a page of a blog's verified Webmentions, newest
+	// first
+	GetVerified(blogID int64, limit, offset int) ([]*entity.Webmention, error)
+
+	// GetTargets retrieves the URLs a blog linked to when it was last sent
+	GetTargets(blogID int64) ([]string, error)
+
+	// SetTargets replaces the URLs a blog links to
+	SetTargets(blogID int64, targets []string) error
+}
@@ -0,0 +1,17 @@
+package service
+
+import "AbdelrahmanDwedar/blogo/internal/domain/entity"
+
+// EventBroker delivers real-time events to the open streams of users,
+// keeping a short backlog of each user's events for reconnecting clients
+type EventBroker interface {
+	// Publish sends an event to a user. The event's ID is ignored; the
+	// broker assigns one to the copy it delivers.
+	Publish(userID int64, event *entity.Event) error
+
+	// Subscribe opens a stream of a user's events. When lastEventID is set,
+	// the events after it that are still in the backlog are delivered
+	// first. The channel is closed when the subscriber falls too far
+	// behind; cancel ends the subscription.
+	Subscribe(userID int64, lastEventID string) (events <-chan *entity.Event, cancel func(), err error)
+}
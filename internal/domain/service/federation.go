@@ -0,0 +1,15 @@
+package service
+
+import "AbdelrahmanDwedar/blogo/internal/domain/entity"
+
+// FederationClient talks to other ActivityPub servers
+type FederationClient interface {
+	// FetchActor retrieves a remote actor by its IRI, returning
+	// ErrRemoteActorNotFound when the server reports it gone
+	FetchActor(iri string) (*entity.RemoteActor, error)
+
+	// Deliver posts an activity to a remote inbox, signed with the sending
+	// actor's key. Rejections that retrying cannot fix are reported as
+	// ErrDeliveryRejected.
+	Deliver(inbox string, activity []byte, keyID, privateKeyPEM string) error
+}
@@ -0,0 +1,11 @@
+package service
+
+import "AbdelrahmanDwedar/blogo/internal/domain/entity"
+
+// ImportParser reads posts exported from other blogging platforms
+type ImportParser interface {
+	// Parse reads the posts of an uploaded file in the given format, or
+	// in the format its name suggests when format is empty. It returns
+	// ErrUnknownImportFormat for formats it does not support.
+	Parse(format, name string, data []byte) ([]*entity.ImportItem, error)
+}
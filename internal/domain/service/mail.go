@@ -0,0 +1,38 @@
+package service
+
+import "AbdelrahmanDwedar/blogo/internal/domain/entity"
+
+// Mailer sends email
+type Mailer interface {
+	// Send delivers a message. Failures retrying cannot fix are reported
+	// as ErrRecipientRejected when the recipient does not exist and as
+	// ErrMailRejected otherwise.
+	Send(message *entity.EmailMessage) error
+}
+
+// NewsletterRenderer renders the emails sent to newsletter subscribers,
+// leaving the recipient to the caller
+type NewsletterRenderer interface {
+	// Confirmation renders the email asking to confirm a subscription
+	Confirmation(author *entity.User, confirmURL string) (*entity.EmailMessage, error)
+
+	// NewPost renders the email announcing a new blog
+	NewPost(author *entity.User, blog *entity.Blog, blogURL, unsubscribeURL string) (*entity.EmailMessage, error)
+}
+
+// DigestRenderer renders digest emails, leaving the recipient to the
+// caller
+type DigestRenderer interface {
+	// Digest renders a digest email with a link to stop digests
+	Digest(digest *entity.Digest, unsubscribeURL string) (*entity.EmailMessage, error)
+}
+
+// NotificationRenderer renders notification emails, leaving the recipient
+// to the caller
+type NotificationRenderer interface {
+	// Notification renders the email for a notification, whose Actor is
+	// set. blog and comment are those it is about, if any, and url is the
+	// page to open.
+	Notification(recipient *entity.User, notification *entity.Notification, blog *entity.Blog,
+		comment *entity.Comment, url, unsubscribeURL string) (*entity.EmailMessage, error)
+}
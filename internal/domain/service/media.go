@@ -0,0 +1,17 @@
+package service
+
+import (
+	"io"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// MediaStore keeps the media files users upload for their blogs
+type MediaStore interface {
+	// Save stores a media file under its name
+	Save(file *entity.MediaFile) error
+
+	// Open opens a stored media file for reading, returning
+	// ErrMediaNotFound when there is none with the name
+	Open(name string) (io.ReadCloser, error)
+}
@@ -0,0 +1,12 @@
+package service
+
+import "AbdelrahmanDwedar/blogo/internal/domain/entity"
+
+// PushSender sends Web Push messages to push services
+type PushSender interface {
+	// Send encrypts a payload for a subscription and posts it to the
+	// subscription's push service, identified with the VAPID private key.
+	// ErrPushSubscriptionGone is returned when the push service dropped the
+	// subscription, ErrPushRejected for other failures retrying cannot fix.
+	Send(subscription *entity.PushSubscription, payload []byte, vapidPrivateKey string) error
+}
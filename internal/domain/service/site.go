@@ -0,0 +1,36 @@
+package service
+
+import (
+	"io"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// FileSink receives the files of a generated static site
+type FileSink interface {
+	// WriteFile writes a file at a slash-separated path relative to the
+	// site root
+	WriteFile(name string, data []byte) error
+}
+
+// ArchiveWriter is a FileSink backed by an archive that must be closed
+// to be complete
+type ArchiveWriter interface {
+	FileSink
+	Close() error
+}
+
+// ArchiveStore keeps generated site archives for later download
+type ArchiveStore interface {
+	// Create starts a new archive with the given name
+	Create(name string) (ArchiveWriter, error)
+
+	// Open opens a finished archive for reading
+	Open(name string) (io.ReadCloser, error)
+}
+
+// SiteRenderer renders a static site
+type SiteRenderer interface {
+	// Render writes every page, feed and asset of the site to sink
+	Render(site *entity.Site, sink FileSink) error
+}
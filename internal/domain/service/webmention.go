@@ -0,0 +1,19 @@
+package service
+
+import "AbdelrahmanDwedar/blogo/internal/domain/entity"
+
+// WebmentionClient talks to other sites over the Webmention protocol
+type WebmentionClient interface {
+	// DiscoverEndpoint finds the Webmention endpoint a page advertises,
+	// returning an empty string when it advertises none
+	DiscoverEndpoint(target string) (string, error)
+
+	// Send notifies an endpoint that source links to target. Rejections
+	// that retrying cannot fix are reported as ErrWebmentionRejected.
+	Send(endpoint, source, target string) error
+
+	// FetchSource retrieves the source of a Webmention and checks whether
+	// it links to target, returning ErrWebmentionSourceGone when the source
+	// no longer exists
+	FetchSource(source, target string) (*entity.WebmentionSource, error)
+}
@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"github.com/redis/go-redis/v9"
+)
+
+// feedsChangedKey is outside the feeds: keys so that dropping the feeds
+// keeps it
+const feedsChangedKey = "feeds_changed_at"
+
+func feedKey(key string) string {
+	return "feeds:" + key
+}
+
+// GetFeed retrieves a rendered feed by key
+func (r *RedisCache) GetFeed(key string) (*entity.RenderedFeed, bool, error) {
+	if r == nil || r.client == nil {
+		return nil, false, nil
+	}
+
+	data, err := r.client.Get(r.ctx, feedKey(key)).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var feed entity.RenderedFeed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		return nil, false, fmt.Errorf("unmarshal feed: %w", err)
+	}
+	return &feed, true, nil
+}
+
+// StoreFeed stores a rendered feed under key
+func (r *RedisCache) StoreFeed(key string, feed *entity.RenderedFeed, expiration time.Duration) error {
+	if r == nil || r.client == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(feed)
+	if err != nil {
+		return fmt.Errorf("marshal feed: %w", err)
+	}
+	return r.client.Set(r.ctx, feedKey(key), data, expiration).Err()
+}
+
+// DeleteFeeds drops every rendered feed
+func (r *RedisCache) DeleteFeeds() error {
+	return r.DeletePattern(feedKey("*"))
+}
+
+// SetFeedsChangedAt records when the feeds last changed
+func (r *RedisCache) SetFeedsChangedAt(at time.Time) error {
+	if r == nil || r.client == nil {
+		return nil
+	}
+	return r.client.Set(r.ctx, feedsChangedKey, at.UTC().Format(time.RFC3339Nano), 0).Err()
+}
+
+// GetFeedsChangedAt retrieves when the feeds last changed
+func (r *RedisCache) GetFeedsChangedAt() (time.Time, bool, error) {
+	if r == nil || r.client == nil {
+		return time.Time{}, false, nil
+	}
+
+	value, err := r.client.Get(r.ctx, feedsChangedKey).Result()
+	if err == redis.Nil {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	at, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("parse feeds changed time: %w", err)
+	}
+	return at, true, nil
+}
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"github.com/redis/go-redis/v9"
+)
+
+// recommendationsActiveKey is a sorted set of the users who asked for
+// recommendations, scored by when they last did
+const recommendationsActiveKey = "recommendations:active"
+
+func recommendationsKey(userID int64) string {
+	return fmt.Sprintf("recommendations:%d", userID)
+}
+
+// GetRecommendations retrieves a user's stored recommendations and marks the
+// user active, so periodic refreshes keep them current
+func (r *RedisCache) GetRecommendations(userID int64) ([]*entity.Recommendation, bool, error) {
+	if r == nil || r.client == nil {
+		return nil, false, nil
+	}
+
+	err := r.client.ZAdd(r.ctx, recommendationsActiveKey, redis.Z{
+		Score:  float64(time.Now().Unix()),
+		Member: userID,
+	}).Err()
+	if err != nil {
+		return nil, false, err
+	}
+
+	data, err := r.client.Get(r.ctx, recommendationsKey(userID)).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var recommendations []*entity.Recommendation
+	if err := json.Unmarshal(data, &recommendations); err != nil {
+		return nil, false, fmt.Errorf("unmarshal recommendations: %w", err)
+	}
+	return recommendations, true, nil
+}
+
+// StoreRecommendations replaces a user's stored recommendations
+func (r *RedisCache) StoreRecommendations(userID int64, recommendations []*entity.Recommendation, expiration time.Duration) error {
+	if r == nil || r.client == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(recommendations)
+	if err != nil {
+		return fmt.Errorf("marshal recommendations: %w", err)
+	}
+
+	return r.client.Set(r.ctx, recommendationsKey(userID), data, expiration).Err()
+}
+
+// DeleteRecommendations drops a user's stored recommendations
+func (r *RedisCache) DeleteRecommendations(userID int64) error {
+	if r == nil || r.client == nil {
+		return nil
+	}
+	return r.client.Del(r.ctx, recommendationsKey(userID)).Err()
+}
+
+// GetActiveRecommendationUsers retrieves the users who asked for
+// recommendations since the given time and forgets those who haven't
+func (r *RedisCache) GetActiveRecommendationUsers(since time.Time) ([]int64, error) {
+	if r == nil || r.client == nil {
+		return nil, nil
+	}
+
+	cutoff := strconv.FormatInt(since.Unix(), 10)
+	err := r.client.ZRemRangeByScore(r.ctx, recommendationsActiveKey, "-inf", "("+cutoff).Err()
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := r.client.ZRange(r.ctx, recommendationsActiveKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -49,6 +49,15 @@ func (r *RedisCache) Close() error {
 	return r.client.Close()
 }
 
+// Client returns the underlying Redis client for features that need more
+// than caching, or nil when Redis is unavailable
+func (r *RedisCache) Client() *redis.Client {
+	if r == nil {
+		return nil
+	}
+	return r.client
+}
+
 // SetUser caches a user
 func (r *RedisCache) SetUser(user *entity.User, expiration time.Duration) error {
 	if r == nil || r.client == nil {
@@ -0,0 +1,184 @@
+package cache
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// timelineMaxSize is how many blogs a stored timeline keeps; older
+	// pages are served from the database
+	timelineMaxSize = 800
+
+	// timelineTTL is how long an unread timeline is kept
+	timelineTTL = 7 * 24 * time.Hour
+
+	// timelineTieSlack is how many extra entries are read to skip blogs
+	// sharing the cursor's timestamp
+	timelineTieSlack = 10
+
+	// Marker members, scored 0, that tell whether a timeline holds every
+	// blog it should or lost its oldest entries to trimming
+	timelineComplete = "complete"
+	timelinePartial  = "partial"
+)
+
+// addToTimeline adds a blog to an existing timeline and trims it, marking
+// the timeline partial when entries are dropped.
+// KEYS[1] timeline, ARGV[1] score, ARGV[2] blog ID, ARGV[3] max size.
+var addToTimeline = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+redis.call('ZADD', KEYS[1], ARGV[1], ARGV[2])
+local removed = redis.call('ZREMRANGEBYRANK', KEYS[1], 1, -(tonumber(ARGV[3]) + 1))
+if removed > 0 then
+	redis.call('ZREM', KEYS[1], 'complete')
+	redis.call('ZADD', KEYS[1], 0, 'partial')
+end
+return 1
+`)
+
+func timelineKey(userID int64) string {
+	return fmt.Sprintf("timeline:%d", userID)
+}
+
+// timelineScore orders timeline entries by creation time
+func timelineScore(createdAt time.Time) float64 {
+	return float64(createdAt.UnixMicro())
+}
+
+// GetTimeline retrieves up to limit blog IDs from a user's timeline,
+// starting after cursor. ok is false when no timeline is stored or the page
+// reaches past the entries a trimmed timeline still holds.
+func (r *RedisCache) GetTimeline(userID int64, cursor *entity.Cursor, limit int) ([]int64, bool, error) {
+	if r == nil || r.client == nil {
+		return nil, false, nil
+	}
+
+	key := timelineKey(userID)
+	exists, err := r.client.Exists(r.ctx, key).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if exists == 0 {
+		return nil, false, nil
+	}
+
+	maxScore := "+inf"
+	if cursor != nil {
+		maxScore = strconv.FormatFloat(timelineScore(cursor.CreatedAt), 'f', -1, 64)
+	}
+	entries, err := r.client.ZRevRangeByScoreWithScores(r.ctx, key, &redis.ZRangeBy{
+		Max:   maxScore,
+		Min:   "(0",
+		Count: int64(limit + timelineTieSlack),
+	}).Result()
+	if err != nil {
+		return nil, false, err
+	}
+
+	ids := []int64{}
+	for _, entry := range entries {
+		id, err := strconv.ParseInt(fmt.Sprint(entry.Member), 10, 64)
+		if err != nil {
+			continue
+		}
+		if cursor != nil && !cursor.Before(time.UnixMicro(int64(entry.Score)).UTC(), id) {
+			continue
+		}
+		ids = append(ids, id)
+		if len(ids) == limit {
+			break
+		}
+	}
+
+	// A short page from a trimmed timeline may be missing older blogs
+	if len(ids) < limit {
+		err := r.client.ZScore(r.ctx, key, timelineComplete).Err()
+		if err == redis.Nil {
+			return nil, false, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	r.client.Expire(r.ctx, key, timelineTTL)
+	return ids, true, nil
+}
+
+// StoreTimeline replaces a user's timeline with the given blogs
+func (r *RedisCache) StoreTimeline(userID int64, blogs []*entity.Blog, complete bool) error {
+	if r == nil || r.client == nil {
+		return nil
+	}
+
+	if len(blogs) > timelineMaxSize {
+		blogs = blogs[:timelineMaxSize]
+		complete = false
+	}
+
+	marker := timelinePartial
+	if complete {
+		marker = timelineComplete
+	}
+	members := []redis.Z{{Score: 0, Member: marker}}
+	for _, blog := range blogs {
+		members = append(members, redis.Z{Score: timelineScore(blog.CreatedAt), Member: blog.ID})
+	}
+
+	key := timelineKey(userID)
+	_, err := r.client.TxPipelined(r.ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(r.ctx, key)
+		pipe.ZAdd(r.ctx, key, members...)
+		pipe.Expire(r.ctx, key, timelineTTL)
+		return nil
+	})
+	return err
+}
+
+// AddToTimelines adds a blog to the stored timelines of the given users;
+// users without a stored timeline get theirs built on their next read
+func (r *RedisCache) AddToTimelines(userIDs []int64, blog *entity.Blog) error {
+	if r == nil || r.client == nil || len(userIDs) == 0 {
+		return nil
+	}
+
+	_, err := r.client.Pipelined(r.ctx, func(pipe redis.Pipeliner) error {
+		for _, userID := range userIDs {
+			addToTimeline.Eval(r.ctx, pipe, []string{timelineKey(userID)},
+				timelineScore(blog.CreatedAt), blog.ID, timelineMaxSize)
+		}
+		return nil
+	})
+	return err
+}
+
+// RemoveFromTimelines removes a blog from the stored timelines of the
+// given users
+func (r *RedisCache) RemoveFromTimelines(userIDs []int64, blogID int64) error {
+	if r == nil || r.client == nil || len(userIDs) == 0 {
+		return nil
+	}
+
+	_, err := r.client.Pipelined(r.ctx, func(pipe redis.Pipeliner) error {
+		for _, userID := range userIDs {
+			pipe.ZRem(r.ctx, timelineKey(userID), blogID)
+		}
+		return nil
+	})
+	return err
+}
+
+// DeleteTimeline drops a user's stored timeline so it is rebuilt
+func (r *RedisCache) DeleteTimeline(userID int64) error {
+	if r == nil || r.client == nil {
+		return nil
+	}
+	return r.client.Del(r.ctx, timelineKey(userID)).Err()
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"fmt"
+	"strconv"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"github.com/redis/go-redis/v9"
+)
+
+func trendingKey(window string) string {
+	return fmt.Sprintf("trending:%s", window)
+}
+
+// StoreTrending replaces the sorted set of a window's trending blogs. The
+// new set is built under a temporary key and renamed over the old one, so
+// readers never see it half-written.
+func (r *RedisCache) StoreTrending(window string, blogs []*entity.TrendingBlog) error {
+	if r == nil || r.client == nil {
+		return nil
+	}
+
+	key := trendingKey(window)
+	if len(blogs) == 0 {
+		return r.client.Del(r.ctx, key).Err()
+	}
+
+	members := make([]redis.Z, len(blogs))
+	for i, blog := range blogs {
+		members[i] = redis.Z{Score: blog.Score, Member: blog.BlogID}
+	}
+
+	tmp := key + ":next"
+	_, err := r.client.TxPipelined(r.ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(r.ctx, tmp)
+		pipe.ZAdd(r.ctx, tmp, members...)
+		pipe.Rename(r.ctx, tmp, key)
+		return nil
+	})
+	return err
+}
+
+// GetTrending retrieves a page of the IDs of a window's trending blogs,
+// highest score first
+func (r *RedisCache) GetTrending(window string, limit, offset int) ([]int64, error) {
+	if r == nil || r.client == nil {
+		return []int64{}, nil
+	}
+
+	members, err := r.client.ZRevRange(r.ctx, trendingKey(window), int64(offset), int64(offset+limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
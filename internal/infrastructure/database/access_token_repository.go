@@ -0,0 +1,142 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// accessTokenColumns lists the columns read back by scanAccessToken
+const accessTokenColumns = `id, user_id, name, scopes, token_hash, last_used_at, created_at`
+
+// AccessTokenRepository implements repository.AccessTokenRepository for
+// PostgreSQL
+type AccessTokenRepository struct {
+	db *PostgresDB
+}
+
+// NewAccessTokenRepository creates a new access token repository
+func NewAccessTokenRepository(db *PostgresDB) *AccessTokenRepository {
+	return &AccessTokenRepository{db: db}
+}
+
+func scanAccessToken(row rowScanner) (*entity.AccessToken, error) {
+	token := &entity.AccessToken{}
+	var lastUsedAt sql.NullTime
+	err := row.Scan(
+		&token.ID, &token.UserID, &token.Name, pq.Array(&token.Scopes), &token.TokenHash,
+		&lastUsedAt, &token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if lastUsedAt.Valid {
+		token.LastUsedAt = &lastUsedAt.Time
+	}
+	return token, nil
+}
+
+// Create stores a new access token
+func (r *AccessTokenRepository) Create(token *entity.AccessToken) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	err := r.db.Client.QueryRow(`
+		INSERT INTO access_tokens (user_id, name, scopes, token_hash, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, token.UserID, token.Name, pq.Array(token.Scopes), token.TokenHash, token.CreatedAt).Scan(&token.ID)
+
+	if err != nil {
+		return fmt.Errorf("create access token: %w", err)
+	}
+	return nil
+}
+
+// GetByHash retrieves an access token by the hash of its secret
+func (r *AccessTokenRepository) GetByHash(hash string) (*entity.AccessToken, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	token, err := scanAccessToken(r.db.Client.QueryRow(`
+		SELECT `+accessTokenColumns+`
+		FROM access_tokens
+		WHERE token_hash = $1
+	`, hash))
+
+	if err == sql.ErrNoRows {
+		return nil, entity.ErrAccessTokenNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get access token: %w", err)
+	}
+	return token, nil
+}
+
+// GetByUser retrieves a user's access tokens, newest first
+func (r *AccessTokenRepository) GetByUser(userID int64) ([]*entity.AccessToken, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		SELECT `+accessTokenColumns+`
+		FROM access_tokens
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get access tokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := []*entity.AccessToken{}
+	for rows.Next() {
+		token, err := scanAccessToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan access token: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate access tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// Delete revokes one of a user's access tokens
+func (r *AccessTokenRepository) Delete(id, userID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	result, err := r.db.Client.Exec(`
+		DELETE FROM access_tokens WHERE id = $1 AND user_id = $2
+	`, id, userID)
+	if err != nil {
+		return fmt.Errorf("delete access token: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete access token: %w", err)
+	}
+	if rows == 0 {
+		return entity.ErrAccessTokenNotFound
+	}
+	return nil
+}
+
+// Touch records that an access token was just used
+func (r *AccessTokenRepository) Touch(id int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`UPDATE access_tokens SET last_used_at = NOW() WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("touch access token: %w", err)
+	}
+	return nil
+}
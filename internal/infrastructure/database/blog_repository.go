@@ -2,11 +2,79 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"github.com/lib/pq"
 )
 
+// blogProjection is the projection shared by all blog queries; rows are
+// read back with scanBlog
+const blogProjection = `
+		SELECT b.id, b.title, b.description, b.body, b.slug, b.tags, b.author_id,
+		       u.id, u.username, u.email, u.display_name, u.bio, u.profile_image, u.private, u.created_at, u.updated_at,
+		       (SELECT COALESCE(json_object_agg(kind, total), '{}')
+		        FROM (SELECT kind, COUNT(*) AS total FROM reactions WHERE blog_id = b.id GROUP BY kind) rc) as reactions,
+		       (SELECT COUNT(*) FROM comments WHERE blog_id = b.id AND status = 'published' AND deleted_at IS NULL) as comments_count,
+		       b.comment_mode, b.moderate_comments, b.pinned_comment_id,
+		       b.version, b.created_at, b.updated_at, b.deleted_at
+		FROM blogs b
+		INNER JOIN users u ON b.author_id = u.id`
+
+// blogSelect selects live blogs only; callers extend the WHERE clause with AND
+const blogSelect = blogProjection + `
+		WHERE b.deleted_at IS NULL`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanBlog reads a single blogSelect row
+func scanBlog(row rowScanner) (*entity.Blog, error) {
+	blog := &entity.Blog{Author: &entity.User{}}
+	var reactions []byte
+	var pinnedCommentID sql.NullInt64
+	err := row.Scan(
+		&blog.ID, &blog.Title, &blog.Description, &blog.Body, &blog.Slug, pq.Array(&blog.Tags), &blog.AuthorID,
+		&blog.Author.ID, &blog.Author.Username, &blog.Author.Email, &blog.Author.DisplayName,
+		&blog.Author.Bio, &blog.Author.ProfileImage, &blog.Author.Private, &blog.Author.CreatedAt, &blog.Author.UpdatedAt,
+		&reactions, &blog.CommentsCount, &blog.CommentMode, &blog.ModerateComments, &pinnedCommentID, &blog.Version, &blog.CreatedAt, &blog.UpdatedAt, &blog.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	if err := json.Unmarshal(reactions, &counts); err != nil {
+		return nil, fmt.Errorf("decode reaction counts: %w", err)
+	}
+	blog.SetReactionCounts(counts)
+
+	if pinnedCommentID.Valid {
+		blog.PinnedCommentID = &pinnedCommentID.Int64
+	}
+	return blog, nil
+}
+
+// scanBlogs reads all remaining blogSelect rows
+func scanBlogs(rows *sql.Rows) ([]*entity.Blog, error) {
+	blogs := []*entity.Blog{}
+	for rows.Next() {
+		blog, err := scanBlog(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan blog: %w", err)
+		}
+		blogs = append(blogs, blog)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate blogs: %w", err)
+	}
+	return blogs, nil
+}
+
 // BlogRepository implements the blog repository interface
 type BlogRepository struct {
 	db *PostgresDB
@@ -23,11 +91,11 @@ func (r *BlogRepository) Create(blog *entity.Blog) error {
 	defer r.db.mu.Unlock()
 
 	err := r.db.Client.QueryRow(`
-		INSERT INTO blogs (title, description, body, author_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id
-	`, blog.Title, blog.Description, blog.Body, blog.AuthorID,
-		blog.CreatedAt, blog.UpdatedAt).Scan(&blog.ID)
+		INSERT INTO blogs (title, description, body, slug, tags, author_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, version
+	`, blog.Title, blog.Description, blog.Body, blog.Slug, pq.Array(blog.Tags), blog.AuthorID,
+		blog.CreatedAt, blog.UpdatedAt).Scan(&blog.ID, &blog.Version)
 
 	if err != nil {
 		return fmt.Errorf("create blog: %w", err)
@@ -40,21 +108,9 @@ func (r *BlogRepository) GetByID(id int64) (*entity.Blog, error) {
 	r.db.mu.RLock()
 	defer r.db.mu.RUnlock()
 
-	blog := &entity.Blog{Author: &entity.User{}}
-	err := r.db.Client.QueryRow(`
-		SELECT b.id, b.title, b.description, b.body, b.author_id,
-		       u.id, u.username, u.email, u.display_name, u.bio, u.profile_image, u.created_at, u.updated_at,
-		       (SELECT COUNT(*) FROM likes WHERE blog_id = b.id) as likes_count,
-		       b.created_at, b.updated_at
-		FROM blogs b
-		INNER JOIN users u ON b.author_id = u.id
-		WHERE b.id = $1
-	`, id).Scan(
-		&blog.ID, &blog.Title, &blog.Description, &blog.Body, &blog.AuthorID,
-		&blog.Author.ID, &blog.Author.Username, &blog.Author.Email, &blog.Author.DisplayName,
-		&blog.Author.Bio, &blog.Author.ProfileImage, &blog.Author.CreatedAt, &blog.Author.UpdatedAt,
-		&blog.LikesCount, &blog.CreatedAt, &blog.UpdatedAt,
-	)
+	blog, err := scanBlog(r.db.Client.QueryRow(blogSelect+`
+		AND b.id = $1
+	`, id))
 
 	if err == sql.ErrNoRows {
 		return nil, entity.ErrBlogNotFound
@@ -66,42 +122,70 @@ func (r *BlogRepository) GetByID(id int64) (*entity.Blog, error) {
 	return blog, nil
 }
 
-// GetAll retrieves all blogs with pagination
-func (r *BlogRepository) GetAll(limit, offset int) ([]*entity.Blog, error) {
+// GetBySlug retrieves an author's live blog by its slug
+func (r *BlogRepository) GetBySlug(authorID int64, slug string) (*entity.Blog, error) {
 	r.db.mu.RLock()
 	defer r.db.mu.RUnlock()
 
-	rows, err := r.db.Client.Query(`
-		SELECT b.id, b.title, b.description, b.body, b.author_id,
-		       u.id, u.username, u.email, u.display_name, u.bio, u.profile_image, u.created_at, u.updated_at,
-		       (SELECT COUNT(*) FROM likes WHERE blog_id = b.id) as likes_count,
-		       b.created_at, b.updated_at
-		FROM blogs b
-		INNER JOIN users u ON b.author_id = u.id
+	blog, err := scanBlog(r.db.Client.QueryRow(blogSelect+`
+		AND b.author_id = $1 AND b.slug = $2
 		ORDER BY b.created_at DESC
-		LIMIT $1 OFFSET $2
-	`, limit, offset)
+		LIMIT 1
+	`, authorID, slug))
+
+	if err == sql.ErrNoRows {
+		return nil, entity.ErrBlogNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get blog by slug: %w", err)
+	}
+
+	return blog, nil
+}
+
+// GetAll retrieves all blogs with pagination, leaving out blogs by users
+// in a block with viewerID and by private accounts viewerID doesn't follow
+func (r *BlogRepository) GetAll(viewerID int64, limit, offset int) ([]*entity.Blog, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(blogSelect+`
+		AND b.author_id NOT IN (SELECT blocked_id FROM blocks WHERE blocker_id = $1)
+		AND b.author_id NOT IN (SELECT blocker_id FROM blocks WHERE blocked_id = $1)
+		AND (NOT u.private OR u.id = $1
+		     OR u.id IN (SELECT following_id FROM followers WHERE follower_id = $1 AND status = 'accepted'))
+		ORDER BY b.created_at DESC
+		LIMIT $2 OFFSET $3
+	`, viewerID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("get all blogs: %w", err)
 	}
 	defer rows.Close()
 
-	blogs := []*entity.Blog{}
-	for rows.Next() {
-		blog := &entity.Blog{Author: &entity.User{}}
-		err := rows.Scan(
-			&blog.ID, &blog.Title, &blog.Description, &blog.Body, &blog.AuthorID,
-			&blog.Author.ID, &blog.Author.Username, &blog.Author.Email, &blog.Author.DisplayName,
-			&blog.Author.Bio, &blog.Author.ProfileImage, &blog.Author.CreatedAt, &blog.Author.UpdatedAt,
-			&blog.LikesCount, &blog.CreatedAt, &blog.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("scan blog: %w", err)
-		}
-		blogs = append(blogs, blog)
+	return scanBlogs(rows)
+}
+
+// GetTop retrieves blogs with the most likes first, leaving out blogs by
+// users in a block with viewerID and by private accounts viewerID doesn't
+// follow
+func (r *BlogRepository) GetTop(viewerID int64, limit, offset int) ([]*entity.Blog, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(blogSelect+`
+		AND b.author_id NOT IN (SELECT blocked_id FROM blocks WHERE blocker_id = $1)
+		AND b.author_id NOT IN (SELECT blocker_id FROM blocks WHERE blocked_id = $1)
+		AND (NOT u.private OR u.id = $1
+		     OR u.id IN (SELECT following_id FROM followers WHERE follower_id = $1 AND status = 'accepted'))
+		ORDER BY (SELECT COUNT(*) FROM reactions WHERE blog_id = b.id AND kind = 'like') DESC, b.created_at DESC
+		LIMIT $2 OFFSET $3
+	`, viewerID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get top blogs: %w", err)
 	}
+	defer rows.Close()
 
-	return blogs, nil
+	return scanBlogs(rows)
 }
 
 // GetByAuthor retrieves blogs by a specific author
@@ -109,14 +193,8 @@ func (r *BlogRepository) GetByAuthor(authorID int64, limit, offset int) ([]*enti
 	r.db.mu.RLock()
 	defer r.db.mu.RUnlock()
 
-	rows, err := r.db.Client.Query(`
-		SELECT b.id, b.title, b.description, b.body, b.author_id,
-		       u.id, u.username, u.email, u.display_name, u.bio, u.profile_image, u.created_at, u.updated_at,
-		       (SELECT COUNT(*) FROM likes WHERE blog_id = b.id) as likes_count,
-		       b.created_at, b.updated_at
-		FROM blogs b
-		INNER JOIN users u ON b.author_id = u.id
-		WHERE b.author_id = $1
+	rows, err := r.db.Client.Query(blogSelect+`
+		AND b.author_id = $1
 		ORDER BY b.created_at DESC
 		LIMIT $2 OFFSET $3
 	`, authorID, limit, offset)
@@ -125,49 +203,106 @@ func (r *BlogRepository) GetByAuthor(authorID int64, limit, offset int) ([]*enti
 	}
 	defer rows.Close()
 
-	blogs := []*entity.Blog{}
-	for rows.Next() {
-		blog := &entity.Blog{Author: &entity.User{}}
-		err := rows.Scan(
-			&blog.ID, &blog.Title, &blog.Description, &blog.Body, &blog.AuthorID,
-			&blog.Author.ID, &blog.Author.Username, &blog.Author.Email, &blog.Author.DisplayName,
-			&blog.Author.Bio, &blog.Author.ProfileImage, &blog.Author.CreatedAt, &blog.Author.UpdatedAt,
-			&blog.LikesCount, &blog.CreatedAt, &blog.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("scan blog: %w", err)
-		}
-		blogs = append(blogs, blog)
+	return scanBlogs(rows)
+}
+
+// GetByTag retrieves the blogs of public accounts with a tag, newest first
+func (r *BlogRepository) GetByTag(tag string, limit, offset int) ([]*entity.Blog, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(blogSelect+`
+		AND b.tags @> ARRAY[$1::TEXT]
+		AND NOT u.private
+		ORDER BY b.created_at DESC
+		LIMIT $2 OFFSET $3
+	`, tag, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get blogs by tag: %w", err)
 	}
+	defer rows.Close()
 
-	return blogs, nil
+	return scanBlogs(rows)
 }
 
-// Update updates a blog post
+// GetByIDs retrieves the live blogs among ids, in the order of ids
+func (r *BlogRepository) GetByIDs(ids []int64) ([]*entity.Blog, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(blogSelect+`
+		AND b.id = ANY($1)
+		ORDER BY array_position($1, b.id)
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("get blogs by ids: %w", err)
+	}
+	defer rows.Close()
+
+	return scanBlogs(rows)
+}
+
+// GetFeed retrieves blogs by the authors a user follows and hasn't muted,
+// newest first, starting after cursor
+func (r *BlogRepository) GetFeed(userID int64, cursor *entity.Cursor, limit int) ([]*entity.Blog, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	query := blogSelect + `
+		AND b.author_id IN (SELECT following_id FROM followers WHERE follower_id = $1 AND status = 'accepted')
+		AND b.author_id NOT IN (SELECT muted_id FROM mutes WHERE muter_id = $1)`
+	args := []interface{}{userID, limit}
+	if cursor != nil {
+		query += `
+		AND (b.created_at, b.id) < ($3, $4)`
+		args = append(args, cursor.CreatedAt, cursor.ID)
+	}
+	query += `
+		ORDER BY b.created_at DESC, b.id DESC
+		LIMIT $2`
+
+	rows, err := r.db.Client.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get feed: %w", err)
+	}
+	defer rows.Close()
+
+	return scanBlogs(rows)
+}
+
+// Update updates a blog post if it is still at blog.Version, bumping the
+// version on success
 func (r *BlogRepository) Update(blog *entity.Blog) error {
 	r.db.mu.Lock()
 	defer r.db.mu.Unlock()
 
-	_, err := r.db.Client.Exec(`
+	err := r.db.Client.QueryRow(`
 		UPDATE blogs
-		SET title = $1, description = $2, body = $3, updated_at = $4
-		WHERE id = $5 AND author_id = $6
-	`, blog.Title, blog.Description, blog.Body, blog.UpdatedAt, blog.ID, blog.AuthorID)
+		SET title = $1, description = $2, body = $3, tags = $4, updated_at = $5, version = version + 1
+		WHERE id = $6 AND author_id = $7 AND version = $8 AND deleted_at IS NULL
+		RETURNING version
+	`, blog.Title, blog.Description, blog.Body, pq.Array(blog.Tags), blog.UpdatedAt,
+		blog.ID, blog.AuthorID, blog.Version).Scan(&blog.Version)
 
+	if err == sql.ErrNoRows {
+		return entity.ErrVersionConflict
+	}
 	if err != nil {
 		return fmt.Errorf("update blog: %w", err)
 	}
 	return nil
 }
 
-// Delete deletes a blog post
+// Delete moves a blog post to its author's trash. The row and its reactions
+// are kept until PurgeDeleted removes them.
 func (r *BlogRepository) Delete(id, authorID int64) error {
 	r.db.mu.Lock()
 	defer r.db.mu.Unlock()
 
 	result, err := r.db.Client.Exec(`
-		DELETE FROM blogs
-		WHERE id = $1 AND author_id = $2
+		UPDATE blogs
+		SET deleted_at = NOW()
+		WHERE id = $1 AND author_id = $2 AND deleted_at IS NULL
 	`, id, authorID)
 	if err != nil {
 		return fmt.Errorf("delete blog: %w", err)
@@ -184,91 +319,314 @@ func (r *BlogRepository) Delete(id, authorID int64) error {
 	return nil
 }
 
-// Like adds a like to a blog
-func (r *BlogRepository) Like(blogID, userID int64) error {
+// GetDeletedByAuthor retrieves an author's trashed blogs, most recently
+// deleted first
+func (r *BlogRepository) GetDeletedByAuthor(authorID int64, limit, offset int) ([]*entity.Blog, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(blogProjection+`
+		WHERE b.author_id = $1 AND b.deleted_at IS NOT NULL
+		ORDER BY b.deleted_at DESC
+		LIMIT $2 OFFSET $3
+	`, authorID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get deleted blogs: %w", err)
+	}
+	defer rows.Close()
+
+	return scanBlogs(rows)
+}
+
+// Restore moves a blog out of its author's trash
+func (r *BlogRepository) Restore(id, authorID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	result, err := r.db.Client.Exec(`
+		UPDATE blogs
+		SET deleted_at = NULL
+		WHERE id = $1 AND author_id = $2 AND deleted_at IS NOT NULL
+	`, id, authorID)
+	if err != nil {
+		return fmt.Errorf("restore blog: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entity.ErrBlogNotFound
+	}
+
+	return nil
+}
+
+// PurgeDeleted permanently removes blogs trashed before the given time,
+// along with everything that cascades from them
+func (r *BlogRepository) PurgeDeleted(before time.Time) (int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	result, err := r.db.Client.Exec(`
+		DELETE FROM blogs
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1
+	`, before)
+	if err != nil {
+		return 0, fmt.Errorf("purge deleted blogs: %w", err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("get rows affected: %w", err)
+	}
+	return purged, nil
+}
+
+// UpdateCommentSettings saves a blog's comment mode and moderation flag
+func (r *BlogRepository) UpdateCommentSettings(blog *entity.Blog) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	result, err := r.db.Client.Exec(`
+		UPDATE blogs
+		SET comment_mode = $1, moderate_comments = $2
+		WHERE id = $3 AND author_id = $4 AND deleted_at IS NULL
+	`, blog.CommentMode, blog.ModerateComments, blog.ID, blog.AuthorID)
+
+	if err != nil {
+		return fmt.Errorf("update comment settings: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return entity.ErrBlogNotFound
+	}
+	return nil
+}
+
+// SetPinnedComment pins a comment to the top of a blog's comments, or
+// unpins it when commentID is nil
+func (r *BlogRepository) SetPinnedComment(blogID int64, commentID *int64) error {
 	r.db.mu.Lock()
 	defer r.db.mu.Unlock()
 
 	_, err := r.db.Client.Exec(`
-		INSERT INTO likes (blog_id, user_id, created_at)
-		VALUES ($1, $2, NOW())
-		ON CONFLICT (blog_id, user_id) DO NOTHING
-	`, blogID, userID)
+		UPDATE blogs
+		SET pinned_comment_id = $1
+		WHERE id = $2
+	`, commentID, blogID)
 
 	if err != nil {
-		return fmt.Errorf("like blog: %w", err)
+		return fmt.Errorf("set pinned comment: %w", err)
 	}
 	return nil
 }
 
-// Unlike removes a like from a blog
-func (r *BlogRepository) Unlike(blogID, userID int64) error {
+// React adds a user's reaction of the given kind to a blog
+func (r *BlogRepository) React(blogID, userID int64, kind string) error {
 	r.db.mu.Lock()
 	defer r.db.mu.Unlock()
 
 	_, err := r.db.Client.Exec(`
-		DELETE FROM likes
-		WHERE blog_id = $1 AND user_id = $2
-	`, blogID, userID)
+		INSERT INTO reactions (blog_id, user_id, kind, created_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (blog_id, user_id, kind) DO NOTHING
+	`, blogID, userID, kind)
 
 	if err != nil {
-		return fmt.Errorf("unlike blog: %w", err)
+		return fmt.Errorf("react to blog: %w", err)
 	}
 	return nil
 }
 
-// GetLikes retrieves users who liked a blog
-func (r *BlogRepository) GetLikes(blogID int64, limit, offset int) ([]*entity.User, error) {
+// Unreact removes a user's reaction of the given kind from a blog
+func (r *BlogRepository) Unreact(blogID, userID int64, kind string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`
+		DELETE FROM reactions
+		WHERE blog_id = $1 AND user_id = $2 AND kind = $3
+	`, blogID, userID, kind)
+
+	if err != nil {
+		return fmt.Errorf("remove reaction: %w", err)
+	}
+	return nil
+}
+
+// GetReactions retrieves a blog's reactions with their users, newest first,
+// leaving out users in a block with viewerID; an empty kind returns
+// reactions of every kind
+func (r *BlogRepository) GetReactions(blogID, viewerID int64, kind string, limit, offset int) ([]*entity.Reaction, error) {
 	r.db.mu.RLock()
 	defer r.db.mu.RUnlock()
 
 	rows, err := r.db.Client.Query(`
-		SELECT u.id, u.username, u.email, u.display_name, u.bio, u.profile_image, u.created_at, u.updated_at
-		FROM users u
-		INNER JOIN likes l ON u.id = l.user_id
-		WHERE l.blog_id = $1
-		ORDER BY l.created_at DESC
-		LIMIT $2 OFFSET $3
-	`, blogID, limit, offset)
+		SELECT rc.blog_id, rc.kind, rc.created_at,
+		       u.id, u.username, u.email, u.display_name, u.bio, u.profile_image, u.private, u.created_at, u.updated_at
+		FROM reactions rc
+		INNER JOIN users u ON u.id = rc.user_id
+		WHERE rc.blog_id = $1 AND ($3 = '' OR rc.kind = $3)
+		  AND rc.user_id NOT IN (SELECT blocked_id FROM blocks WHERE blocker_id = $2)
+		  AND rc.user_id NOT IN (SELECT blocker_id FROM blocks WHERE blocked_id = $2)
+		ORDER BY rc.created_at DESC, rc.id DESC
+		LIMIT $4 OFFSET $5
+	`, blogID, viewerID, kind, limit, offset)
 
 	if err != nil {
-		return nil, fmt.Errorf("get blog likes: %w", err)
+		return nil, fmt.Errorf("get blog reactions: %w", err)
 	}
 	defer rows.Close()
 
-	users := []*entity.User{}
+	reactions := []*entity.Reaction{}
 	for rows.Next() {
-		user := &entity.User{}
+		reaction := &entity.Reaction{User: &entity.User{}}
 		err := rows.Scan(
-			&user.ID, &user.Username, &user.Email, &user.DisplayName,
-			&user.Bio, &user.ProfileImage, &user.CreatedAt, &user.UpdatedAt,
+			&reaction.BlogID, &reaction.Kind, &reaction.CreatedAt,
+			&reaction.User.ID, &reaction.User.Username, &reaction.User.Email, &reaction.User.DisplayName,
+			&reaction.User.Bio, &reaction.User.ProfileImage, &reaction.User.Private, &reaction.User.CreatedAt, &reaction.User.UpdatedAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("scan user: %w", err)
+			return nil, fmt.Errorf("scan reaction: %w", err)
 		}
-		users = append(users, user)
+		reaction.UserID = reaction.User.ID
+		reactions = append(reactions, reaction)
 	}
 
-	return users, nil
+	return reactions, nil
 }
 
-// IsLikedBy checks if a user has liked a blog
-func (r *BlogRepository) IsLikedBy(blogID, userID int64) (bool, error) {
+// HasReacted checks if a user has reacted to a blog with the given kind
+func (r *BlogRepository) HasReacted(blogID, userID int64, kind string) (bool, error) {
 	r.db.mu.RLock()
 	defer r.db.mu.RUnlock()
 
 	var exists bool
 	err := r.db.Client.QueryRow(`
 		SELECT EXISTS(
-			SELECT 1 FROM likes
-			WHERE blog_id = $1 AND user_id = $2
+			SELECT 1 FROM reactions
+			WHERE blog_id = $1 AND user_id = $2 AND kind = $3
 		)
-	`, blogID, userID).Scan(&exists)
+	`, blogID, userID, kind).Scan(&exists)
 
 	if err != nil {
-		return false, fmt.Errorf("check like: %w", err)
+		return false, fmt.Errorf("check reaction: %w", err)
 	}
 	return exists, nil
 }
 
+// RecordView counts a view of a blog in the bucket of the current hour,
+// unless viewer already viewed it this hour
+func (r *BlogRepository) RecordView(blogID int64, viewer string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`
+		WITH first_view AS (
+			INSERT INTO blog_viewers (blog_id, hour, viewer)
+			VALUES ($1, DATE_TRUNC('hour', NOW()), $2)
+			ON CONFLICT DO NOTHING
+			RETURNING blog_id, hour
+		)
+		INSERT INTO blog_views (blog_id, hour, views)
+		SELECT blog_id, hour, 1 FROM first_view
+		ON CONFLICT (blog_id, hour) DO UPDATE SET views = blog_views.views + 1
+	`, blogID, viewer)
+
+	if err != nil {
+		return fmt.Errorf("record blog view: %w", err)
+	}
+	return nil
+}
+
+// PurgeViews removes the view counts of hours before the given time, and
+// the viewers of past hours
+func (r *BlogRepository) PurgeViews(before time.Time) (int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	result, err := r.db.Client.Exec(`
+		DELETE FROM blog_views WHERE hour < $1
+	`, before)
+	if err != nil {
+		return 0, fmt.Errorf("purge blog views: %w", err)
+	}
+
+	// Viewers only matter within the hour they are counted in
+	if _, err := r.db.Client.Exec(`
+		DELETE FROM blog_viewers WHERE hour < DATE_TRUNC('hour', NOW())
+	`); err != nil {
+		return 0, fmt.Errorf("purge blog viewers: %w", err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("get rows affected: %w", err)
+	}
+	return purged, nil
+}
+
+// ComputeTrending sums the weighted likes, comments and views of each live
+// blog by a public account within the window, each decayed by half for
+// every half-life it is old
+func (r *BlogRepository) ComputeTrending(window entity.TrendingWindow, limit int) ([]*entity.TrendingBlog, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		WITH engagement AS (
+			SELECT blog_id, created_at AS at, $3::DOUBLE PRECISION AS weight
+			FROM reactions
+			WHERE kind = 'like' AND created_at >= NOW() - $1::DOUBLE PRECISION * INTERVAL '1 second'
+
+			UNION ALL
+
+			SELECT blog_id, created_at, $4::DOUBLE PRECISION
+			FROM comments
+			WHERE status = 'published' AND deleted_at IS NULL
+			  AND created_at >= NOW() - $1::DOUBLE PRECISION * INTERVAL '1 second'
+
+			UNION ALL
+
+			SELECT blog_id, hour, $5::DOUBLE PRECISION * views
+			FROM blog_views
+			WHERE hour >= NOW() - $1::DOUBLE PRECISION * INTERVAL '1 second'
+		)
+		SELECT e.blog_id,
+		       SUM(e.weight * POWER(0.5, GREATEST(EXTRACT(EPOCH FROM NOW() - e.at)::DOUBLE PRECISION, 0) / $2::DOUBLE PRECISION)) AS score
+		FROM engagement e
+		INNER JOIN blogs b ON b.id = e.blog_id
+		INNER JOIN users u ON u.id = b.author_id
+		WHERE b.deleted_at IS NULL AND NOT u.private
+		GROUP BY e.blog_id
+		ORDER BY score DESC, e.blog_id DESC
+		LIMIT $6
+	`, window.Period.Seconds(), window.HalfLife.Seconds(),
+		entity.TrendingLikeWeight, entity.TrendingCommentWeight, entity.TrendingViewWeight, limit)
+	if err != nil {
+		return nil, fmt.Errorf("compute trending blogs: %w", err)
+	}
+	defer rows.Close()
+
+	blogs := []*entity.TrendingBlog{}
+	for rows.Next() {
+		blog := &entity.TrendingBlog{}
+		if err := rows.Scan(&blog.BlogID, &blog.Score); err != nil {
+			return nil, fmt.Errorf("scan trending blog: %w", err)
+		}
+		blogs = append(blogs, blog)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate trending blogs: %w", err)
+	}
+	return blogs, nil
+}
+
+
 
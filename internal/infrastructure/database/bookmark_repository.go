@@ -0,0 +1,373 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// readingListColumns lists the columns read back by scanReadingList; the
+// items count leaves out trashed blogs
+const readingListColumns = `l.id, l.user_id, l.name, l.description, l.public,
+		       (SELECT COUNT(*) FROM reading_list_items i
+		        INNER JOIN blogs b ON b.id = i.blog_id AND b.deleted_at IS NULL
+		        WHERE i.list_id = l.id) as items_count,
+		       l.created_at, l.updated_at`
+
+// BookmarkRepository implements repository.BookmarkRepository for
+// PostgreSQL
+type BookmarkRepository struct {
+	db *PostgresDB
+}
+
+// NewBookmarkRepository creates a new bookmark repository
+func NewBookmarkRepository(db *PostgresDB) *BookmarkRepository {
+	return &BookmarkRepository{db: db}
+}
+
+func scanReadingList(row rowScanner) (*entity.ReadingList, error) {
+	list := &entity.ReadingList{}
+	err := row.Scan(
+		&list.ID, &list.UserID, &list.Name, &list.Description, &list.Public,
+		&list.ItemsCount, &list.CreatedAt, &list.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// AddBookmark bookmarks a blog for a user; bookmarking it again keeps the
+// original bookmark
+func (r *BookmarkRepository) AddBookmark(bookmark *entity.Bookmark) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	err := r.db.Client.QueryRow(`
+		INSERT INTO bookmarks (user_id, blog_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, blog_id) DO UPDATE SET user_id = EXCLUDED.user_id
+		RETURNING id, created_at
+	`, bookmark.UserID, bookmark.BlogID, bookmark.CreatedAt).Scan(&bookmark.ID, &bookmark.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("add bookmark: %w", err)
+	}
+	return nil
+}
+
+// RemoveBookmark removes a user's bookmark of a blog, if any
+func (r *BookmarkRepository) RemoveBookmark(userID, blogID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`
+		DELETE FROM bookmarks WHERE user_id = $1 AND blog_id = $2
+	`, userID, blogID)
+	if err != nil {
+		return fmt.Errorf("remove bookmark: %w", err)
+	}
+	return nil
+}
+
+// GetBookmarks retrieves a user's bookmarks of live blogs, newest first,
+// starting after cursor
+func (r *BookmarkRepository) GetBookmarks(userID int64, cursor *entity.Cursor, limit int) ([]*entity.Bookmark, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	query := `
+		SELECT bm.id, bm.user_id, bm.blog_id, bm.created_at
+		FROM bookmarks bm
+		INNER JOIN blogs b ON b.id = bm.blog_id AND b.deleted_at IS NULL
+		WHERE bm.user_id = $1`
+	args := []interface{}{userID, limit}
+	if cursor != nil {
+		query += `
+		AND (bm.created_at, bm.id) < ($3, $4)`
+		args = append(args, cursor.CreatedAt, cursor.ID)
+	}
+	query += `
+		ORDER BY bm.created_at DESC, bm.id DESC
+		LIMIT $2`
+
+	rows, err := r.db.Client.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get bookmarks: %w", err)
+	}
+	defer rows.Close()
+
+	bookmarks := []*entity.Bookmark{}
+	var blogIDs []int64
+	for rows.Next() {
+		bookmark := &entity.Bookmark{}
+		if err := rows.Scan(&bookmark.ID, &bookmark.UserID, &bookmark.BlogID, &bookmark.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan bookmark: %w", err)
+		}
+		bookmarks = append(bookmarks, bookmark)
+		blogIDs = append(blogIDs, bookmark.BlogID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate bookmarks: %w", err)
+	}
+
+	blogs, err := r.getBlogs(blogIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, bookmark := range bookmarks {
+		bookmark.Blog = blogs[bookmark.BlogID]
+	}
+	return bookmarks, nil
+}
+
+// GetBookmarkedIDs returns which of blogIDs a user bookmarked
+func (r *BookmarkRepository) GetBookmarkedIDs(userID int64, blogIDs []int64) (map[int64]bool, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		SELECT blog_id FROM bookmarks WHERE user_id = $1 AND blog_id = ANY($2)
+	`, userID, pq.Array(blogIDs))
+	if err != nil {
+		return nil, fmt.Errorf("get bookmarked blogs: %w", err)
+	}
+	defer rows.Close()
+
+	bookmarked := map[int64]bool{}
+	for rows.Next() {
+		var blogID int64
+		if err := rows.Scan(&blogID); err != nil {
+			return nil, fmt.Errorf("scan bookmarked blog: %w", err)
+		}
+		bookmarked[blogID] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate bookmarked blogs: %w", err)
+	}
+	return bookmarked, nil
+}
+
+// CreateList stores a new reading list
+func (r *BookmarkRepository) CreateList(list *entity.ReadingList) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	err := r.db.Client.QueryRow(`
+		INSERT INTO reading_lists (user_id, name, description, public, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, list.UserID, list.Name, list.Description, list.Public, list.CreatedAt, list.UpdatedAt).Scan(&list.ID)
+
+	if err != nil {
+		return fmt.Errorf("create reading list: %w", err)
+	}
+	return nil
+}
+
+// GetList retrieves a reading list by ID
+func (r *BookmarkRepository) GetList(id int64) (*entity.ReadingList, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	list, err := scanReadingList(r.db.Client.QueryRow(`
+		SELECT `+readingListColumns+`
+		FROM reading_lists l
+		WHERE l.id = $1
+	`, id))
+
+	if err == sql.ErrNoRows {
+		return nil, entity.ErrReadingListNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get reading list: %w", err)
+	}
+	return list, nil
+}
+
+// GetLists retrieves a user's reading lists, newest first, or only the
+// public ones
+func (r *BookmarkRepository) GetLists(userID int64, publicOnly bool) ([]*entity.ReadingList, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		SELECT `+readingListColumns+`
+		FROM reading_lists l
+		WHERE l.user_id = $1 AND (l.public OR NOT $2)
+		ORDER BY l.created_at DESC, l.id DESC
+	`, userID, publicOnly)
+	if err != nil {
+		return nil, fmt.Errorf("get reading lists: %w", err)
+	}
+	defer rows.Close()
+
+	lists := []*entity.ReadingList{}
+	for rows.Next() {
+		list, err := scanReadingList(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan reading list: %w", err)
+		}
+		lists = append(lists, list)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate reading lists: %w", err)
+	}
+	return lists, nil
+}
+
+// UpdateList saves a reading list's name, description and visibility
+func (r *BookmarkRepository) UpdateList(list *entity.ReadingList) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	result, err := r.db.Client.Exec(`
+		UPDATE reading_lists
+		SET name = $1, description = $2, public = $3, updated_at = $4
+		WHERE id = $5
+	`, list.Name, list.Description, list.Public, list.UpdatedAt, list.ID)
+	if err != nil {
+		return fmt.Errorf("update reading list: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update reading list: %w", err)
+	}
+	if rows == 0 {
+		return entity.ErrReadingListNotFound
+	}
+	return nil
+}
+
+// DeleteList deletes a reading list; its items go with it
+func (r *BookmarkRepository) DeleteList(id int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	result, err := r.db.Client.Exec(`DELETE FROM reading_lists WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete reading list: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete reading list: %w", err)
+	}
+	if rows == 0 {
+		return entity.ErrReadingListNotFound
+	}
+	return nil
+}
+
+// AddToList adds a blog to a reading list; adding it again keeps the
+// original item
+func (r *BookmarkRepository) AddToList(item *entity.ReadingListItem) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	err := r.db.Client.QueryRow(`
+		INSERT INTO reading_list_items (list_id, blog_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (list_id, blog_id) DO UPDATE SET list_id = EXCLUDED.list_id
+		RETURNING id, created_at
+	`, item.ListID, item.BlogID, item.CreatedAt).Scan(&item.ID, &item.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("add to reading list: %w", err)
+	}
+	return nil
+}
+
+// RemoveFromList removes a blog from a reading list, if it is there
+func (r *BookmarkRepository) RemoveFromList(listID, blogID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`
+		DELETE FROM reading_list_items WHERE list_id = $1 AND blog_id = $2
+	`, listID, blogID)
+	if err != nil {
+		return fmt.Errorf("remove from reading list: %w", err)
+	}
+	return nil
+}
+
+// GetListItems retrieves the items of a reading list with live blogs, most
+// recently added first, starting after cursor
+func (r *BookmarkRepository) GetListItems(listID int64, cursor *entity.Cursor, limit int) ([]*entity.ReadingListItem, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	query := `
+		SELECT i.id, i.list_id, i.blog_id, i.created_at
+		FROM reading_list_items i
+		INNER JOIN blogs b ON b.id = i.blog_id AND b.deleted_at IS NULL
+		WHERE i.list_id = $1`
+	args := []interface{}{listID, limit}
+	if cursor != nil {
+		query += `
+		AND (i.created_at, i.id) < ($3, $4)`
+		args = append(args, cursor.CreatedAt, cursor.ID)
+	}
+	query += `
+		ORDER BY i.created_at DESC, i.id DESC
+		LIMIT $2`
+
+	rows, err := r.db.Client.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get reading list items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []*entity.ReadingListItem{}
+	var blogIDs []int64
+	for rows.Next() {
+		item := &entity.ReadingListItem{}
+		if err := rows.Scan(&item.ID, &item.ListID, &item.BlogID, &item.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan reading list item: %w", err)
+		}
+		items = append(items, item)
+		blogIDs = append(blogIDs, item.BlogID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate reading list items: %w", err)
+	}
+
+	blogs, err := r.getBlogs(blogIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		item.Blog = blogs[item.BlogID]
+	}
+	return items, nil
+}
+
+// getBlogs loads the live blogs among ids by ID; the caller holds the lock
+func (r *BookmarkRepository) getBlogs(ids []int64) (map[int64]*entity.Blog, error) {
+	blogs := map[int64]*entity.Blog{}
+	if len(ids) == 0 {
+		return blogs, nil
+	}
+
+	rows, err := r.db.Client.Query(blogSelect+`
+		AND b.id = ANY($1)
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("get blogs by ids: %w", err)
+	}
+	defer rows.Close()
+
+	list, err := scanBlogs(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, blog := range list {
+		blogs[blog.ID] = blog
+	}
+	return blogs, nil
+}
@@ -0,0 +1,237 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// CommentRepository implements repository.CommentRepository for PostgreSQL
+type CommentRepository struct {
+	db *PostgresDB
+}
+
+// NewCommentRepository creates a new comment repository
+func NewCommentRepository(db *PostgresDB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+// commentColumns is the column list read back with scanComment; queries
+// alias comments as c and join users as u and blogs as b
+const commentColumns = `
+		c.id, c.blog_id, c.parent_id, c.author_id, c.body, c.depth, c.status,
+		c.id = COALESCE(b.pinned_comment_id, 0) as pinned,
+		c.created_at, c.updated_at, c.deleted_at,
+		u.id, u.username, u.email, u.display_name, u.bio, u.profile_image, u.private, u.created_at, u.updated_at`
+
+// commentJoins joins the tables commentColumns reads from
+const commentJoins = `
+		INNER JOIN users u ON c.author_id = u.id
+		INNER JOIN blogs b ON c.blog_id = b.id`
+
+// scanComment reads a single commentColumns row
+func scanComment(row rowScanner) (*entity.Comment, error) {
+	comment := &entity.Comment{Author: &entity.User{}}
+	var parentID sql.NullInt64
+	err := row.Scan(
+		&comment.ID, &comment.BlogID, &parentID, &comment.AuthorID, &comment.Body, &comment.Depth,
+		&comment.Status, &comment.Pinned, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt,
+		&comment.Author.ID, &comment.Author.Username, &comment.Author.Email, &comment.Author.DisplayName,
+		&comment.Author.Bio, &comment.Author.ProfileImage, &comment.Author.Private, &comment.Author.CreatedAt, &comment.Author.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		comment.ParentID = &parentID.Int64
+	}
+	return comment, nil
+}
+
+// scanComments reads all remaining commentColumns rows
+func scanComments(rows *sql.Rows) ([]*entity.Comment, error) {
+	comments := []*entity.Comment{}
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan comment: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate comments: %w", err)
+	}
+	return comments, nil
+}
+
+// Create creates a new comment
+func (r *CommentRepository) Create(comment *entity.Comment) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	err := r.db.Client.QueryRow(`
+		INSERT INTO comments (blog_id, parent_id, author_id, body, depth, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`, comment.BlogID, comment.ParentID, comment.AuthorID, comment.Body, comment.Depth,
+		comment.Status, comment.CreatedAt, comment.UpdatedAt).Scan(&comment.ID)
+
+	if err != nil {
+		return fmt.Errorf("create comment: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a comment by ID, including deleted comments
+func (r *CommentRepository) GetByID(id int64) (*entity.Comment, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	comment, err := scanComment(r.db.Client.QueryRow(`
+		SELECT `+commentColumns+`
+		FROM comments c`+commentJoins+`
+		WHERE c.id = $1
+	`, id))
+
+	if err == sql.ErrNoRows {
+		return nil, entity.ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get comment by id: %w", err)
+	}
+	return comment, nil
+}
+
+// GetThreads retrieves a page of a blog's published top-level comments,
+// pinned comment first and then oldest first, followed by all of their
+// published replies
+func (r *CommentRepository) GetThreads(blogID int64, limit, offset int) ([]*entity.Comment, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		WITH RECURSIVE thread AS (
+			(SELECT c.id
+			 FROM comments c
+			 INNER JOIN blogs b ON c.blog_id = b.id
+			 WHERE c.blog_id = $1 AND c.parent_id IS NULL AND c.status = 'published'
+			 ORDER BY c.id = COALESCE(b.pinned_comment_id, 0) DESC, c.created_at, c.id
+			 LIMIT $2 OFFSET $3)
+			UNION ALL
+			SELECT r.id
+			FROM comments r
+			INNER JOIN thread t ON r.parent_id = t.id
+			WHERE r.status = 'published'
+		)
+		SELECT `+commentColumns+`
+		FROM thread t
+		INNER JOIN comments c ON c.id = t.id`+commentJoins+`
+		ORDER BY c.id = COALESCE(b.pinned_comment_id, 0) DESC, c.created_at, c.id
+	`, blogID, limit, offset)
+
+	if err != nil {
+		return nil, fmt.Errorf("get comment threads: %w", err)
+	}
+	defer rows.Close()
+
+	return scanComments(rows)
+}
+
+// GetPendingForAuthor retrieves comments awaiting approval across all blogs
+// of an author, oldest first
+func (r *CommentRepository) GetPendingForAuthor(authorID int64, limit, offset int) ([]*entity.Comment, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		SELECT `+commentColumns+`
+		FROM comments c`+commentJoins+`
+		WHERE b.author_id = $1 AND b.deleted_at IS NULL
+		  AND c.status = 'pending' AND c.deleted_at IS NULL
+		ORDER BY c.created_at, c.id
+		LIMIT $2 OFFSET $3
+	`, authorID, limit, offset)
+
+	if err != nil {
+		return nil, fmt.Errorf("get pending comments: %w", err)
+	}
+	defer rows.Close()
+
+	return scanComments(rows)
+}
+
+// Approve publishes a pending comment
+func (r *CommentRepository) Approve(id int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	result, err := r.db.Client.Exec(`
+		UPDATE comments
+		SET status = 'published'
+		WHERE id = $1 AND status = 'pending' AND deleted_at IS NULL
+	`, id)
+
+	if err != nil {
+		return fmt.Errorf("approve comment: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return entity.ErrCommentNotPending
+	}
+	return nil
+}
+
+// Update updates a comment's body
+func (r *CommentRepository) Update(comment *entity.Comment) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	result, err := r.db.Client.Exec(`
+		UPDATE comments
+		SET body = $1, updated_at = $2
+		WHERE id = $3 AND deleted_at IS NULL
+	`, comment.Body, comment.UpdatedAt, comment.ID)
+
+	if err != nil {
+		return fmt.Errorf("update comment: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return entity.ErrCommentNotFound
+	}
+	return nil
+}
+
+// Delete marks a comment as deleted, keeping its replies in place
+func (r *CommentRepository) Delete(id int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	result, err := r.db.Client.Exec(`
+		UPDATE comments
+		SET deleted_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`, id)
+
+	if err != nil {
+		return fmt.Errorf("delete comment: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return entity.ErrCommentNotFound
+	}
+	return nil
+}
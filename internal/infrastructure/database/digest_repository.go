@@ -0,0 +1,210 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// DigestRepository implements repository.DigestRepository for PostgreSQL
+type DigestRepository struct {
+	db *PostgresDB
+}
+
+// NewDigestRepository creates a new digest repository
+func NewDigestRepository(db *PostgresDB) *DigestRepository {
+	return &DigestRepository{db: db}
+}
+
+// GetFrequency retrieves how often a user gets a digest
+func (r *DigestRepository) GetFrequency(userID int64) (string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var frequency string
+	err := r.db.Client.QueryRow(`
+		SELECT frequency FROM digest_settings WHERE user_id = $1
+	`, userID).Scan(&frequency)
+
+	if err == sql.ErrNoRows {
+		return entity.DigestOff, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("get digest frequency: %w", err)
+	}
+	return frequency, nil
+}
+
+// SetFrequency saves how often a user gets a digest; turning digests off
+// removes the setting
+func (r *DigestRepository) SetFrequency(userID int64, frequency string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var err error
+	if frequency == entity.DigestOff {
+		_, err = r.db.Client.Exec(`DELETE FROM digest_settings WHERE user_id = $1`, userID)
+	} else {
+		_, err = r.db.Client.Exec(`
+			INSERT INTO digest_settings (user_id, frequency, updated_at)
+			VALUES ($1, $2, CURRENT_TIMESTAMP)
+			ON CONFLICT (user_id) DO UPDATE SET frequency = EXCLUDED.frequency, updated_at = EXCLUDED.updated_at
+		`, userID, frequency)
+	}
+
+	if err != nil {
+		return fmt.Errorf("set digest frequency: %w", err)
+	}
+	return nil
+}
+
+// ClaimPeriod records a digest period for the users due one in a single
+// statement, so concurrent schedulers never claim a user twice
+func (r *DigestRepository) ClaimPeriod(frequency string, periodStart time.Time) ([]int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	rows, err := r.db.Client.Query(`
+		INSERT INTO digest_runs (user_id, frequency, period_start)
+		SELECT user_id, frequency, $2
+		FROM digest_settings
+		WHERE frequency = $1
+		ON CONFLICT DO NOTHING
+		RETURNING user_id
+	`, frequency, periodStart)
+
+	if err != nil {
+		return nil, fmt.Errorf("claim digest period: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan digest user: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate digest users: %w", err)
+	}
+	return ids, nil
+}
+
+// ReleasePeriod deletes a user's claim on a digest period
+func (r *DigestRepository) ReleasePeriod(userID int64, frequency string, periodStart time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`
+		DELETE FROM digest_runs
+		WHERE user_id = $1 AND frequency = $2 AND period_start = $3
+	`, userID, frequency, periodStart)
+
+	if err != nil {
+		return fmt.Errorf("release digest period: %w", err)
+	}
+	return nil
+}
+
+// GetNewBlogs retrieves the blogs followed authors created within a period
+func (r *DigestRepository) GetNewBlogs(userID int64, since, until time.Time, limit int) ([]*entity.Blog, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(blogSelect+`
+		AND b.author_id IN (SELECT following_id FROM followers WHERE follower_id = $1 AND status = 'accepted')
+		AND b.author_id NOT IN (SELECT muted_id FROM mutes WHERE muter_id = $1)
+		AND b.created_at >= $2 AND b.created_at < $3
+		ORDER BY b.created_at DESC, b.id DESC
+		LIMIT $4
+	`, userID, since, until, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("get digest blogs: %w", err)
+	}
+	defer rows.Close()
+
+	return scanBlogs(rows)
+}
+
+// GetTopBlogs ranks blogs by the likes followed users gave them within a
+// period. Blogs of private authors are only included when the user
+// follows them.
+func (r *DigestRepository) GetTopBlogs(userID int64, since, until time.Time, limit int) ([]*entity.Blog, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		WITH liked AS (
+			SELECT rc.blog_id, COUNT(*) AS likes
+			FROM reactions rc
+			INNER JOIN followers f ON f.following_id = rc.user_id AND f.follower_id = $1 AND f.status = 'accepted'
+			WHERE rc.kind = 'like' AND rc.created_at >= $2 AND rc.created_at < $3
+			GROUP BY rc.blog_id
+		)`+blogSelect+`
+		AND b.id IN (SELECT blog_id FROM liked)
+		AND b.author_id != $1
+		AND (NOT u.private OR b.author_id IN (SELECT following_id FROM followers WHERE follower_id = $1 AND status = 'accepted'))
+		AND b.author_id NOT IN (SELECT muted_id FROM mutes WHERE muter_id = $1)
+		AND b.author_id NOT IN (
+			SELECT blocked_id FROM blocks WHERE blocker_id = $1
+			UNION
+			SELECT blocker_id FROM blocks WHERE blocked_id = $1
+		)
+		ORDER BY (SELECT likes FROM liked WHERE liked.blog_id = b.id) DESC, b.id DESC
+		LIMIT $4
+	`, userID, since, until, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("get digest top blogs: %w", err)
+	}
+	defer rows.Close()
+
+	return scanBlogs(rows)
+}
+
+// GetNewFollowers retrieves the users who started following a user within
+// a period
+func (r *DigestRepository) GetNewFollowers(userID int64, since, until time.Time, limit int) ([]*entity.User, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		SELECT u.id, u.username, u.email, u.display_name, u.bio, u.profile_image, u.private, u.created_at, u.updated_at
+		FROM users u
+		INNER JOIN followers f ON u.id = f.follower_id
+		WHERE f.following_id = $1 AND f.status = 'accepted' AND f.created_at >= $2 AND f.created_at < $3
+		ORDER BY f.created_at DESC
+		LIMIT $4
+	`, userID, since, until, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("get digest followers: %w", err)
+	}
+	defer rows.Close()
+
+	return scanUsers(rows)
+}
+
+// CountNewFollowers counts the users who started following a user within a
+// period
+func (r *DigestRepository) CountNewFollowers(userID int64, since, until time.Time) (int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var count int
+	err := r.db.Client.QueryRow(`
+		SELECT COUNT(*)
+		FROM followers
+		WHERE following_id = $1 AND status = 'accepted' AND created_at >= $2 AND created_at < $3
+	`, userID, since, until).Scan(&count)
+
+	if err != nil {
+		return 0, fmt.Errorf("count digest followers: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,225 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// FederationRepository implements repository.FederationRepository for
+// PostgreSQL
+type FederationRepository struct {
+	db *PostgresDB
+}
+
+// NewFederationRepository creates a new federation repository
+func NewFederationRepository(db *PostgresDB) *FederationRepository {
+	return &FederationRepository{db: db}
+}
+
+// GetKey retrieves a user's actor key pair
+func (r *FederationRepository) GetKey(userID int64) (*entity.ActorKey, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	key := &entity.ActorKey{}
+	err := r.db.Client.QueryRow(`
+		SELECT user_id, public_key_pem, private_key_pem, created_at
+		FROM actor_keys
+		WHERE user_id = $1
+	`, userID).Scan(&key.UserID, &key.PublicKeyPEM, &key.PrivateKeyPEM, &key.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, entity.ErrActorKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get actor key: %w", err)
+	}
+	return key, nil
+}
+
+// CreateKey stores a user's actor key pair unless they already have one
+func (r *FederationRepository) CreateKey(key *entity.ActorKey) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`
+		INSERT INTO actor_keys (user_id, public_key_pem, private_key_pem, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO NOTHING
+	`, key.UserID, key.PublicKeyPEM, key.PrivateKeyPEM, key.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("create actor key: %w", err)
+	}
+	return nil
+}
+
+// GetRemoteActor retrieves a cached remote actor by ID
+func (r *FederationRepository) GetRemoteActor(id string) (*entity.RemoteActor, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	actor := &entity.RemoteActor{}
+	err := r.db.Client.QueryRow(`
+		SELECT id, inbox, shared_inbox, public_key_id, public_key_pem, fetched_at
+		FROM remote_actors
+		WHERE id = $1
+	`, id).Scan(&actor.ID, &actor.Inbox, &actor.SharedInbox, &actor.PublicKeyID, &actor.PublicKeyPEM, &actor.FetchedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, entity.ErrRemoteActorNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get remote actor: %w", err)
+	}
+	return actor, nil
+}
+
+// SaveRemoteActor creates or refreshes a cached remote actor
+func (r *FederationRepository) SaveRemoteActor(actor *entity.RemoteActor) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`
+		INSERT INTO remote_actors (id, inbox, shared_inbox, public_key_id, public_key_pem, fetched_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE
+		SET inbox = EXCLUDED.inbox, shared_inbox = EXCLUDED.shared_inbox,
+		    public_key_id = EXCLUDED.public_key_id, public_key_pem = EXCLUDED.public_key_pem,
+		    fetched_at = EXCLUDED.fetched_at
+	`, actor.ID, actor.Inbox, actor.SharedInbox, actor.PublicKeyID, actor.PublicKeyPEM, actor.FetchedAt)
+
+	if err != nil {
+		return fmt.Errorf("save remote actor: %w", err)
+	}
+	return nil
+}
+
+// AddFollower records a remote actor following a user, updating the inbox
+// of an existing follow
+func (r *FederationRepository) AddFollower(follower *entity.RemoteFollower) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`
+		INSERT INTO remote_followers (user_id, actor_id, inbox, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, actor_id) DO UPDATE SET inbox = EXCLUDED.inbox
+	`, follower.UserID, follower.ActorID, follower.Inbox, follower.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("add remote follower: %w", err)
+	}
+	return nil
+}
+
+// RemoveFollower removes a remote actor's follow of a user
+func (r *FederationRepository) RemoveFollower(userID int64, actorID string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`
+		DELETE FROM remote_followers WHERE user_id = $1 AND actor_id = $2
+	`, userID, actorID)
+
+	if err != nil {
+		return fmt.Errorf("remove remote follower: %w", err)
+	}
+	return nil
+}
+
+// GetFollowerInboxes retrieves the distinct inboxes of a user's remote
+// followers
+func (r *FederationRepository) GetFollowerInboxes(userID int64) ([]string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		SELECT DISTINCT inbox FROM remote_followers WHERE user_id = $1
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get remote follower inboxes: %w", err)
+	}
+	defer rows.Close()
+
+	inboxes := []string{}
+	for rows.Next() {
+		var inbox string
+		if err := rows.Scan(&inbox); err != nil {
+			return nil, fmt.Errorf("scan remote follower inbox: %w", err)
+		}
+		inboxes = append(inboxes, inbox)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate remote follower inboxes: %w", err)
+	}
+
+	return inboxes, nil
+}
+
+// CountFollowers counts a user's remote followers
+func (r *FederationRepository) CountFollowers(userID int64) (int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var count int
+	err := r.db.Client.QueryRow(`
+		SELECT COUNT(*) FROM remote_followers WHERE user_id = $1
+	`, userID).Scan(&count)
+
+	if err != nil {
+		return 0, fmt.Errorf("count remote followers: %w", err)
+	}
+	return count, nil
+}
+
+// AddLike records a remote actor liking a blog; liking twice keeps the
+// first like
+func (r *FederationRepository) AddLike(blogID int64, actorID, activityID string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`
+		INSERT INTO remote_likes (blog_id, actor_id, activity_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (blog_id, actor_id) DO NOTHING
+	`, blogID, actorID, activityID)
+
+	if err != nil {
+		return fmt.Errorf("add remote like: %w", err)
+	}
+	return nil
+}
+
+// RemoveLike removes the like a remote actor made with an activity
+func (r *FederationRepository) RemoveLike(actorID, activityID string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`
+		DELETE FROM remote_likes WHERE actor_id = $1 AND activity_id = $2
+	`, actorID, activityID)
+
+	if err != nil {
+		return fmt.Errorf("remove remote like: %w", err)
+	}
+	return nil
+}
+
+// CountLikes counts the remote likes of a blog
+func (r *FederationRepository) CountLikes(blogID int64) (int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var count int
+	err := r.db.Client.QueryRow(`
+		SELECT COUNT(*) FROM remote_likes WHERE blog_id = $1
+	`, blogID).Scan(&count)
+
+	if err != nil {
+		return 0, fmt.Errorf("count remote likes: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,179 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"github.com/lib/pq"
+)
+
+// jobColumns lists the columns read back by scanJob
+const jobColumns = `id, kind, user_id, payload, status, attempts, max_attempts, result, last_error, run_at, created_at, updated_at`
+
+// JobRepository implements the job repository interface
+type JobRepository struct {
+	db *PostgresDB
+}
+
+// NewJobRepository creates a new job repository
+func NewJobRepository(db *PostgresDB) *JobRepository {
+	return &JobRepository{db: db}
+}
+
+func scanJob(row rowScanner) (*entity.Job, error) {
+	job := &entity.Job{}
+	var userID sql.NullInt64
+	var payload []byte
+	err := row.Scan(
+		&job.ID, &job.Kind, &userID, &payload, &job.Status, &job.Attempts, &job.MaxAttempts,
+		&job.Result, &job.LastError, &job.RunAt, &job.CreatedAt, &job.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if userID.Valid {
+		job.UserID = &userID.Int64
+	}
+	job.Payload = payload
+	return job, nil
+}
+
+// Enqueue stores a new job, due immediately
+func (r *JobRepository) Enqueue(job *entity.Job) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var userID sql.NullInt64
+	if job.UserID != nil {
+		userID = sql.NullInt64{Int64: *job.UserID, Valid: true}
+	}
+
+	err := r.db.Client.QueryRow(`
+		INSERT INTO jobs (kind, user_id, payload, status, max_attempts)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, run_at, created_at, updated_at
+	`, job.Kind, userID, []byte(job.Payload), job.Status, job.MaxAttempts).Scan(
+		&job.ID, &job.RunAt, &job.CreatedAt, &job.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("enqueue job: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a job by ID
+func (r *JobRepository) GetByID(id int64) (*entity.Job, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	job, err := scanJob(r.db.Client.QueryRow(`
+		SELECT `+jobColumns+`
+		FROM jobs
+		WHERE id = $1
+	`, id))
+
+	if err == sql.ErrNoRows {
+		return nil, entity.ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get job by id: %w", err)
+	}
+
+	return job, nil
+}
+
+// Claim leases due jobs to the caller. SKIP LOCKED lets several API
+// instances poll the same queue without handing out a job twice.
+func (r *JobRepository) Claim(kinds []string, limit int, lease time.Duration) ([]*entity.Job, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	rows, err := r.db.Client.Query(`
+		UPDATE jobs
+		SET status = 'running', attempts = attempts + 1, updated_at = NOW(),
+		    locked_until = NOW() + $3 * INTERVAL '1 second'
+		WHERE id IN (
+			SELECT id FROM jobs
+			WHERE kind = ANY($1)
+			  AND ((status = 'queued' AND run_at <= NOW())
+			    OR (status = 'running' AND locked_until < NOW()))
+			ORDER BY run_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+jobColumns+`
+	`, pq.Array(kinds), limit, int64(lease/time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("claim jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []*entity.Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// Complete marks a job as done with its result
+func (r *JobRepository) Complete(id int64, result string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`
+		UPDATE jobs
+		SET status = 'done', result = $1, last_error = '', locked_until = NULL, updated_at = NOW()
+		WHERE id = $2
+	`, result, id)
+
+	if err != nil {
+		return fmt.Errorf("complete job: %w", err)
+	}
+	return nil
+}
+
+// Retry puts a failed job back in the queue to run after delay
+func (r *JobRepository) Retry(id int64, lastError string, delay time.Duration) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`
+		UPDATE jobs
+		SET status = 'queued', last_error = $1, locked_until = NULL, updated_at = NOW(),
+		    run_at = NOW() + $2 * INTERVAL '1 second'
+		WHERE id = $3
+	`, lastError, int64(delay/time.Second), id)
+
+	if err != nil {
+		return fmt.Errorf("retry job: %w", err)
+	}
+	return nil
+}
+
+// Fail marks a job as permanently failed
+func (r *JobRepository) Fail(id int64, lastError string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`
+		UPDATE jobs
+		SET status = 'failed', last_error = $1, locked_until = NULL, updated_at = NOW()
+		WHERE id = $2
+	`, lastError, id)
+
+	if err != nil {
+		return fmt.Errorf("fail job: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"github.com/lib/pq"
+)
+
+// MentionRepository implements repository.MentionRepository for PostgreSQL
+type MentionRepository struct {
+	db *PostgresDB
+}
+
+// NewMentionRepository creates a new mention repository
+func NewMentionRepository(db *PostgresDB) *MentionRepository {
+	return &MentionRepository{db: db}
+}
+
+// Replace sets the users mentioned by a blog, or by one of its comments when
+// commentID is set, and returns the IDs of newly mentioned users
+func (r *MentionRepository) Replace(blogID int64, commentID *int64, authorID int64, userIDs []int64) ([]int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	tx, err := r.db.Client.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		DELETE FROM mentions
+		WHERE blog_id = $1 AND COALESCE(comment_id, 0) = COALESCE($2, 0)
+		  AND user_id <> ALL($3)
+	`, blogID, commentID, pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("delete stale mentions: %w", err)
+	}
+
+	rows, err := tx.Query(`
+		INSERT INTO mentions (user_id, blog_id, comment_id, author_id, created_at)
+		SELECT user_id, $1, $2, $3, NOW() FROM UNNEST($4::INTEGER[]) AS user_id
+		ON CONFLICT (blog_id, (COALESCE(comment_id, 0)), user_id) DO NOTHING
+		RETURNING user_id
+	`, blogID, commentID, authorID, pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("insert mentions: %w", err)
+	}
+
+	added := []int64{}
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan mention: %w", err)
+		}
+		added = append(added, userID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate mentions: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit mentions: %w", err)
+	}
+	return added, nil
+}
+
+// GetForBlog retrieves the mentions in a blog's body
+func (r *MentionRepository) GetForBlog(blogID int64) ([]*entity.Mention, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		SELECT m.id, m.user_id, u.username, m.blog_id, m.comment_id, m.author_id, m.created_at
+		FROM mentions m
+		INNER JOIN users u ON m.user_id = u.id
+		WHERE m.blog_id = $1 AND m.comment_id IS NULL
+	`, blogID)
+	if err != nil {
+		return nil, fmt.Errorf("get blog mentions: %w", err)
+	}
+	defer rows.Close()
+
+	return scanMentions(rows)
+}
+
+// GetForComments retrieves the mentions in the given comments
+func (r *MentionRepository) GetForComments(commentIDs []int64) ([]*entity.Mention, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		SELECT m.id, m.user_id, u.username, m.blog_id, m.comment_id, m.author_id, m.created_at
+		FROM mentions m
+		INNER JOIN users u ON m.user_id = u.id
+		WHERE m.comment_id = ANY($1)
+	`, pq.Array(commentIDs))
+	if err != nil {
+		return nil, fmt.Errorf("get comment mentions: %w", err)
+	}
+	defer rows.Close()
+
+	return scanMentions(rows)
+}
+
+// GetByUser retrieves mentions of a user in live blogs and published
+// comments, newest first, leaving out authors in a block with the user and
+// blogs by private accounts the user doesn't follow
+func (r *MentionRepository) GetByUser(userID int64, limit, offset int) ([]*entity.Mention, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		SELECT m.id, m.user_id, mu.username, m.blog_id, m.comment_id, m.author_id, m.created_at,
+		       b.id, b.title, b.slug, b.created_at,
+		       a.id, a.username, a.email, a.display_name, a.bio, a.profile_image, a.private, a.created_at, a.updated_at
+		FROM mentions m
+		INNER JOIN users mu ON m.user_id = mu.id
+		INNER JOIN users a ON m.author_id = a.id
+		INNER JOIN blogs b ON m.blog_id = b.id
+		INNER JOIN users ba ON b.author_id = ba.id
+		LEFT JOIN comments c ON m.comment_id = c.id
+		WHERE m.user_id = $1 AND b.deleted_at IS NULL
+		  AND (m.comment_id IS NULL OR (c.status = 'published' AND c.deleted_at IS NULL))
+		  AND m.author_id NOT IN (SELECT blocked_id FROM blocks WHERE blocker_id = $1)
+		  AND m.author_id NOT IN (SELECT blocker_id FROM blocks WHERE blocked_id = $1)
+		  AND (NOT ba.private OR ba.id = $1 OR ba.id IN (SELECT following_id FROM followers WHERE follower_id = $1 AND status = 'accepted'))
+		ORDER BY m.created_at DESC, m.id DESC
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get user mentions: %w", err)
+	}
+	defer rows.Close()
+
+	mentions := []*entity.Mention{}
+	for rows.Next() {
+		mention := &entity.Mention{Blog: &entity.Blog{}, Author: &entity.User{}}
+		var commentID sql.NullInt64
+		err := rows.Scan(
+			&mention.ID, &mention.UserID, &mention.Username, &mention.BlogID, &commentID, &mention.AuthorID, &mention.CreatedAt,
+			&mention.Blog.ID, &mention.Blog.Title, &mention.Blog.Slug, &mention.Blog.CreatedAt,
+			&mention.Author.ID, &mention.Author.Username, &mention.Author.Email, &mention.Author.DisplayName,
+			&mention.Author.Bio, &mention.Author.ProfileImage, &mention.Author.Private, &mention.Author.CreatedAt, &mention.Author.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan mention: %w", err)
+		}
+		if commentID.Valid {
+			mention.CommentID = &commentID.Int64
+		}
+		mentions = append(mentions, mention)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate mentions: %w", err)
+	}
+
+	return mentions, nil
+}
+
+// scanMentions reads mention rows without blog or author details
+func scanMentions(rows *sql.Rows) ([]*entity.Mention, error) {
+	mentions := []*entity.Mention{}
+	for rows.Next() {
+		mention := &entity.Mention{}
+		var commentID sql.NullInt64
+		err := rows.Scan(
+			&mention.ID, &mention.UserID, &mention.Username, &mention.BlogID, &commentID, &mention.AuthorID, &mention.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan mention: %w", err)
+		}
+		if commentID.Valid {
+			mention.CommentID = &commentID.Int64
+		}
+		mentions = append(mentions, mention)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate mentions: %w", err)
+	}
+	return mentions, nil
+}
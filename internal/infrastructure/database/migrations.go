@@ -0,0 +1,51 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// MigrateLikes copies likes from before reactions existed into the default
+// like reaction, then renames the likes table to likes_legacy so the copy
+// runs once and the old rows stay around for a rollback. It returns how
+// many likes were copied, and 0 when there is no likes table.
+func (db *PostgresDB) MigrateLikes() (int64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	tx, err := db.Client.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var likes sql.NullString
+	if err := tx.QueryRow(`SELECT to_regclass('likes')::TEXT`).Scan(&likes); err != nil {
+		return 0, fmt.Errorf("check for likes table: %w", err)
+	}
+	if !likes.Valid {
+		return 0, nil
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO reactions (blog_id, user_id, kind, created_at)
+		SELECT blog_id, user_id, 'like', created_at FROM likes
+		ON CONFLICT (blog_id, user_id, kind) DO NOTHING
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("copy likes to reactions: %w", err)
+	}
+	copied, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("get rows affected: %w", err)
+	}
+
+	if _, err := tx.Exec(`ALTER TABLE likes RENAME TO likes_legacy`); err != nil {
+		return 0, fmt.Errorf("rename likes table: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit likes migration: %w", err)
+	}
+	return copied, nil
+}
@@ -0,0 +1,206 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"github.com/lib/pq"
+)
+
+// subscriptionColumns lists the columns read back by scanSubscription
+const subscriptionColumns = `id, author_id, email, status, created_at, confirmed_at`
+
+// NewsletterRepository implements repository.NewsletterRepository for
+// PostgreSQL
+type NewsletterRepository struct {
+	db *PostgresDB
+}
+
+// NewNewsletterRepository creates a new newsletter repository
+func NewNewsletterRepository(db *PostgresDB) *NewsletterRepository {
+	return &NewsletterRepository{db: db}
+}
+
+func scanSubscription(row rowScanner) (*entity.Subscription, error) {
+	subscription := &entity.Subscription{}
+	var confirmedAt sql.NullTime
+	err := row.Scan(
+		&subscription.ID, &subscription.AuthorID, &subscription.Email, &subscription.Status,
+		&subscription.CreatedAt, &confirmedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if confirmedAt.Valid {
+		subscription.ConfirmedAt = &confirmedAt.Time
+	}
+	return subscription, nil
+}
+
+// Subscribe stores a subscription, keeping an existing one of the same
+// address to the same author as it is
+func (r *NewsletterRepository) Subscribe(subscription *entity.Subscription) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	// The no-op update makes RETURNING yield the existing row on conflict
+	stored, err := scanSubscription(r.db.Client.QueryRow(`
+		INSERT INTO email_subscriptions (author_id, email, status, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (author_id, email) DO UPDATE SET email = EXCLUDED.email
+		RETURNING `+subscriptionColumns+`
+	`, subscription.AuthorID, subscription.Email, subscription.Status, subscription.CreatedAt))
+
+	if err != nil {
+		return fmt.Errorf("create subscription: %w", err)
+	}
+	*subscription = *stored
+	return nil
+}
+
+// GetByID retrieves a subscription by ID
+func (r *NewsletterRepository) GetByID(id int64) (*entity.Subscription, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	subscription, err := scanSubscription(r.db.Client.QueryRow(`
+		SELECT `+subscriptionColumns+`
+		FROM email_subscriptions
+		WHERE id = $1
+	`, id))
+
+	if err == sql.ErrNoRows {
+		return nil, entity.ErrSubscriptionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get subscription: %w", err)
+	}
+	return subscription, nil
+}
+
+// Confirm marks a subscription confirmed, keeping the time of the first
+// confirmation
+func (r *NewsletterRepository) Confirm(id int64) (*entity.Subscription, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	subscription, err := scanSubscription(r.db.Client.QueryRow(`
+		UPDATE email_subscriptions
+		SET status = $2, confirmed_at = COALESCE(confirmed_at, NOW())
+		WHERE id = $1
+		RETURNING `+subscriptionColumns+`
+	`, id, entity.SubscriptionConfirmed))
+
+	if err == sql.ErrNoRows {
+		return nil, entity.ErrSubscriptionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("confirm subscription: %w", err)
+	}
+	return subscription, nil
+}
+
+// Delete removes a subscription
+func (r *NewsletterRepository) Delete(id int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	result, err := r.db.Client.Exec(`DELETE FROM email_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete subscription: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete subscription: %w", err)
+	}
+	if rows == 0 {
+		return entity.ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+// ClaimDeliveries records a blog as sent to the author's confirmed
+// subscriptions that have no record of it yet, in a single statement, and
+// returns their IDs
+func (r *NewsletterRepository) ClaimDeliveries(authorID, blogID int64) ([]int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	rows, err := r.db.Client.Query(`
+		INSERT INTO newsletter_deliveries (subscription_id, blog_id)
+		SELECT s.id, $2
+		FROM email_subscriptions s
+		WHERE s.author_id = $1 AND s.status = $3
+		  AND NOT EXISTS (SELECT 1 FROM email_suppressions x WHERE x.email = s.email)
+		ON CONFLICT DO NOTHING
+		RETURNING subscription_id
+	`, authorID, blogID, entity.SubscriptionConfirmed)
+	if err != nil {
+		return nil, fmt.Errorf("claim newsletter deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan subscription: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate subscriptions: %w", err)
+	}
+
+	return ids, nil
+}
+
+// ReleaseDeliveries deletes the records of a blog sent to subscriptionIDs
+func (r *NewsletterRepository) ReleaseDeliveries(blogID int64, subscriptionIDs []int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`
+		DELETE FROM newsletter_deliveries
+		WHERE blog_id = $1 AND subscription_id = ANY($2)
+	`, blogID, pq.Array(subscriptionIDs))
+
+	if err != nil {
+		return fmt.Errorf("release newsletter deliveries: %w", err)
+	}
+	return nil
+}
+
+// Suppress adds an address to the suppression list, keeping the reason it
+// was first suppressed for
+func (r *NewsletterRepository) Suppress(email, reason string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`
+		INSERT INTO email_suppressions (email, reason)
+		VALUES ($1, $2)
+		ON CONFLICT (email) DO NOTHING
+	`, email, reason)
+	if err != nil {
+		return fmt.Errorf("suppress email: %w", err)
+	}
+	return nil
+}
+
+// IsSuppressed checks if an address is on the suppression list
+func (r *NewsletterRepository) IsSuppressed(email string) (bool, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var suppressed bool
+	err := r.db.Client.QueryRow(`
+		SELECT EXISTS(SELECT 1 FROM email_suppressions WHERE email = $1)
+	`, email).Scan(&suppressed)
+	if err != nil {
+		return false, fmt.Errorf("check suppression: %w", err)
+	}
+	return suppressed, nil
+}
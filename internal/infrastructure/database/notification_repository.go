@@ -0,0 +1,260 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"github.com/lib/pq"
+)
+
+// NotificationRepository implements repository.NotificationRepository for PostgreSQL
+type NotificationRepository struct {
+	db *PostgresDB
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *PostgresDB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// hiddenActors filters out notifications caused by users the recipient ($1)
+// muted or is in a block with
+const hiddenActors = `
+		  AND actor_id NOT IN (SELECT muted_id FROM mutes WHERE muter_id = $1)
+		  AND actor_id NOT IN (SELECT blocked_id FROM blocks WHERE blocker_id = $1)
+		  AND actor_id NOT IN (SELECT blocker_id FROM blocks WHERE blocked_id = $1)`
+
+// Create creates a new notification, skipping duplicates of unread ones
+func (r *NotificationRepository) Create(notification *entity.Notification) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	err := r.db.Client.QueryRow(`
+		INSERT INTO notifications (user_id, type, actor_id, blog_id, comment_id, group_key, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, group_key, actor_id) WHERE read_at IS NULL DO NOTHING
+		RETURNING id
+	`, notification.UserID, notification.Type, notification.ActorID, notification.BlogID,
+		notification.CommentID, notification.GroupKey(), notification.CreatedAt).Scan(&notification.ID)
+
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("create notification: %w", err)
+	}
+	return nil
+}
+
+// RecordSend records a notification as sent unless the same actor's
+// notification in the same group was already sent after since
+func (r *NotificationRepository) RecordSend(notification *entity.Notification, since time.Time) (bool, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var recorded bool
+	err := r.db.Client.QueryRow(`
+		INSERT INTO notification_sends (user_id, group_key, actor_id, sent_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, group_key, actor_id) DO UPDATE SET sent_at = EXCLUDED.sent_at
+		WHERE notification_sends.sent_at <= $5
+		RETURNING TRUE
+	`, notification.UserID, notification.GroupKey(), notification.ActorID,
+		notification.CreatedAt, since).Scan(&recorded)
+
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("record notification send: %w", err)
+	}
+	return true, nil
+}
+
+// GetGroups retrieves a user's notifications grouped by group key and read
+// state, newest first, with the most recent actors of each group
+func (r *NotificationRepository) GetGroups(userID int64, limit, offset int) ([]*entity.NotificationGroup, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		SELECT latest_id, type, blog_id, comment_id, actor_ids, actors_count, NOT unread, latest
+		FROM (
+			SELECT MAX(id) AS latest_id, MIN(type) AS type, MAX(blog_id) AS blog_id,
+			       MAX(comment_id) AS comment_id,
+			       ARRAY_AGG(actor_id ORDER BY created_at DESC, id DESC) AS actor_ids,
+			       COUNT(DISTINCT actor_id) AS actors_count,
+			       read_at IS NULL AS unread, MAX(created_at) AS latest
+			FROM notifications
+			WHERE user_id = $1`+hiddenActors+`
+			GROUP BY group_key, read_at IS NULL
+		) g
+		ORDER BY latest DESC, latest_id DESC
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get notifications: %w", err)
+	}
+	defer rows.Close()
+
+	groups := []*entity.NotificationGroup{}
+	groupActors := [][]int64{}
+	actorIDs := []int64{}
+	for rows.Next() {
+		group := &entity.NotificationGroup{}
+		var blogID, commentID sql.NullInt64
+		var ids []int64
+		err := rows.Scan(&group.ID, &group.Type, &blogID, &commentID, pq.Array(&ids),
+			&group.ActorsCount, &group.Read, &group.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan notification group: %w", err)
+		}
+		if blogID.Valid {
+			group.BlogID = &blogID.Int64
+		}
+		if commentID.Valid {
+			group.CommentID = &commentID.Int64
+		}
+
+		recent := recentActors(ids)
+		groups = append(groups, group)
+		groupActors = append(groupActors, recent)
+		actorIDs = append(actorIDs, recent...)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate notification groups: %w", err)
+	}
+
+	actors, err := r.getUsers(actorIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i, group := range groups {
+		group.Actors = []*entity.User{}
+		for _, id := range groupActors[i] {
+			if actor, ok := actors[id]; ok {
+				group.Actors = append(group.Actors, actor)
+			}
+		}
+		group.Summarize()
+	}
+
+	return groups, nil
+}
+
+// recentActors returns the first distinct actor IDs, up to MaxGroupActors
+func recentActors(ids []int64) []int64 {
+	recent := []int64{}
+	seen := map[int64]bool{}
+	for _, id := range ids {
+		if len(recent) == entity.MaxGroupActors {
+			break
+		}
+		if !seen[id] {
+			seen[id] = true
+			recent = append(recent, id)
+		}
+	}
+	return recent
+}
+
+// getUsers loads the users with the given IDs, keyed by ID
+func (r *NotificationRepository) getUsers(ids []int64) (map[int64]*entity.User, error) {
+	users := map[int64]*entity.User{}
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	rows, err := r.db.Client.Query(`
+		SELECT id, username, email, display_name, bio, profile_image, private, created_at, updated_at
+		FROM users
+		WHERE id = ANY($1)
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("get notification actors: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		user := &entity.User{}
+		err := rows.Scan(
+			&user.ID, &user.Username, &user.Email, &user.DisplayName,
+			&user.Bio, &user.ProfileImage, &user.Private, &user.CreatedAt, &user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan notification actor: %w", err)
+		}
+		users[user.ID] = user
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate notification actors: %w", err)
+	}
+
+	return users, nil
+}
+
+// CountUnread counts a user's groups of unread notifications
+func (r *NotificationRepository) CountUnread(userID int64) (int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var count int
+	err := r.db.Client.QueryRow(`
+		SELECT COUNT(DISTINCT group_key)
+		FROM notifications
+		WHERE user_id = $1 AND read_at IS NULL`+hiddenActors, userID).Scan(&count)
+
+	if err != nil {
+		return 0, fmt.Errorf("count unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+// MarkGroupRead marks a notification and the unread notifications grouped
+// with it read
+func (r *NotificationRepository) MarkGroupRead(userID, id int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var groupKey string
+	err := r.db.Client.QueryRow(`
+		SELECT group_key FROM notifications WHERE id = $1 AND user_id = $2
+	`, id, userID).Scan(&groupKey)
+
+	if err == sql.ErrNoRows {
+		return entity.ErrNotificationNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("get notification: %w", err)
+	}
+
+	_, err = r.db.Client.Exec(`
+		UPDATE notifications
+		SET read_at = NOW()
+		WHERE user_id = $1 AND group_key = $2 AND read_at IS NULL
+	`, userID, groupKey)
+
+	if err != nil {
+		return fmt.Errorf("mark notifications read: %w", err)
+	}
+	return nil
+}
+
+// MarkAllRead marks all of a user's notifications read
+func (r *NotificationRepository) MarkAllRead(userID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`
+		UPDATE notifications
+		SET read_at = NOW()
+		WHERE user_id = $1 AND read_at IS NULL
+	`, userID)
+
+	if err != nil {
+		return fmt.Errorf("mark all notifications read: %w", err)
+	}
+	return nil
+}
@@ -63,6 +63,7 @@ func (db *PostgresDB) InitTables() error {
 			display_name VARCHAR(100) NOT NULL,
 			bio TEXT,
 			profile_image VARCHAR(500),
+			private BOOLEAN NOT NULL DEFAULT FALSE,
 			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 		)
@@ -71,6 +72,14 @@ func (db *PostgresDB) InitTables() error {
 		return fmt.Errorf("create users table: %w", err)
 	}
 
+	// Add columns introduced after the initial users schema
+	_, err = db.Client.Exec(`
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS private BOOLEAN NOT NULL DEFAULT FALSE;
+	`)
+	if err != nil {
+		return fmt.Errorf("migrate users table: %w", err)
+	}
+
 	// Create blogs table
 	_, err = db.Client.Exec(`
 		CREATE TABLE IF NOT EXISTS blogs (
@@ -78,21 +87,46 @@ func (db *PostgresDB) InitTables() error {
 			title VARCHAR(200) NOT NULL,
 			description TEXT,
 			body TEXT NOT NULL,
+			slug VARCHAR(200) NOT NULL DEFAULT '',
+			tags TEXT[] NOT NULL DEFAULT '{}',
 			author_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			version INTEGER NOT NULL DEFAULT 1,
+			comment_mode VARCHAR(20) NOT NULL DEFAULT 'open',
+			moderate_comments BOOLEAN NOT NULL DEFAULT FALSE,
+			pinned_comment_id INTEGER,
 			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP
 		)
 	`)
 	if err != nil {
 		return fmt.Errorf("create blogs table: %w", err)
 	}
 
+	// Add columns introduced after the initial blogs schema
+	_, err = db.Client.Exec(`
+		ALTER TABLE blogs ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+		ALTER TABLE blogs ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+		ALTER TABLE blogs ADD COLUMN IF NOT EXISTS slug VARCHAR(200) NOT NULL DEFAULT '';
+		ALTER TABLE blogs ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+		ALTER TABLE blogs ADD COLUMN IF NOT EXISTS comment_mode VARCHAR(20) NOT NULL DEFAULT 'open';
+		ALTER TABLE blogs ADD COLUMN IF NOT EXISTS moderate_comments BOOLEAN NOT NULL DEFAULT FALSE;
+		ALTER TABLE blogs ADD COLUMN IF NOT EXISTS pinned_comment_id INTEGER;
+		UPDATE blogs
+		SET slug = COALESCE(NULLIF(TRIM(BOTH '-' FROM LOWER(REGEXP_REPLACE(title, '[^[:alnum:]]+', '-', 'g'))), ''), 'post')
+		WHERE slug = '';
+	`)
+	if err != nil {
+		return fmt.Errorf("migrate blogs table: %w", err)
+	}
+
 	// Create followers table
 	_, err = db.Client.Exec(`
 		CREATE TABLE IF NOT EXISTS followers (
 			id SERIAL PRIMARY KEY,
 			follower_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
 			following_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			status VARCHAR(20) NOT NULL DEFAULT 'accepted',
 			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			UNIQUE(follower_id, following_id),
 			CHECK (follower_id != following_id)
@@ -102,28 +136,438 @@ func (db *PostgresDB) InitTables() error {
 		return fmt.Errorf("create followers table: %w", err)
 	}
 
-	// Create likes table
+	// Add columns introduced after the initial followers schema
+	_, err = db.Client.Exec(`
+		ALTER TABLE followers ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'accepted';
+	`)
+	if err != nil {
+		return fmt.Errorf("migrate followers table: %w", err)
+	}
+
+	// Create blocks and mutes tables
+	_, err = db.Client.Exec(`
+		CREATE TABLE IF NOT EXISTS blocks (
+			id SERIAL PRIMARY KEY,
+			blocker_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			blocked_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(blocker_id, blocked_id),
+			CHECK (blocker_id != blocked_id)
+		);
+		CREATE TABLE IF NOT EXISTS mutes (
+			id SERIAL PRIMARY KEY,
+			muter_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			muted_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(muter_id, muted_id),
+			CHECK (muter_id != muted_id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create blocks and mutes tables: %w", err)
+	}
+
+	// Create reactions table
+	_, err = db.Client.Exec(`
+		CREATE TABLE IF NOT EXISTS reactions (
+			id SERIAL PRIMARY KEY,
+			blog_id INTEGER NOT NULL REFERENCES blogs(id) ON DELETE CASCADE,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			kind VARCHAR(32) NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(blog_id, user_id, kind)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create reactions table: %w", err)
+	}
+
+	// Likes from before reactions existed are moved by an explicit
+	// migration, never on startup
+	var legacyLikes sql.NullString
+	if err := db.Client.QueryRow(`SELECT to_regclass('likes')::TEXT`).Scan(&legacyLikes); err != nil {
+		return fmt.Errorf("check for likes table: %w", err)
+	}
+	if legacyLikes.Valid {
+		log.Println("⚠️  The likes table has not been migrated to reactions; run `go run ./cmd/migrate likes`")
+	}
+
+	// Create comments table
+	_, err = db.Client.Exec(`
+		CREATE TABLE IF NOT EXISTS comments (
+			id SERIAL PRIMARY KEY,
+			blog_id INTEGER NOT NULL REFERENCES blogs(id) ON DELETE CASCADE,
+			parent_id INTEGER REFERENCES comments(id) ON DELETE CASCADE,
+			author_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			body TEXT NOT NULL,
+			depth INTEGER NOT NULL DEFAULT 0,
+			status VARCHAR(20) NOT NULL DEFAULT 'published',
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create comments table: %w", err)
+	}
+
+	// Add columns introduced after the initial comments schema
+	_, err = db.Client.Exec(`
+		ALTER TABLE comments ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published';
+	`)
+	if err != nil {
+		return fmt.Errorf("migrate comments table: %w", err)
+	}
+
+	// Create mentions table
+	_, err = db.Client.Exec(`
+		CREATE TABLE IF NOT EXISTS mentions (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			blog_id INTEGER NOT NULL REFERENCES blogs(id) ON DELETE CASCADE,
+			comment_id INTEGER REFERENCES comments(id) ON DELETE CASCADE,
+			author_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_mentions_source ON mentions(blog_id, COALESCE(comment_id, 0), user_id);
+	`)
+	if err != nil {
+		return fmt.Errorf("create mentions table: %w", err)
+	}
+
+	// Create notifications table
+	_, err = db.Client.Exec(`
+		CREATE TABLE IF NOT EXISTS notifications (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			type VARCHAR(32) NOT NULL,
+			actor_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			blog_id INTEGER REFERENCES blogs(id) ON DELETE CASCADE,
+			comment_id INTEGER REFERENCES comments(id) ON DELETE CASCADE,
+			group_key VARCHAR(100) NOT NULL DEFAULT '',
+			read_at TIMESTAMP,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create notifications table: %w", err)
+	}
+
+	// Add columns introduced after the initial notifications schema, and
+	// drop unread duplicates so they can be prevented by a unique index
+	_, err = db.Client.Exec(`
+		ALTER TABLE notifications ADD COLUMN IF NOT EXISTS group_key VARCHAR(100) NOT NULL DEFAULT '';
+		UPDATE notifications
+		SET group_key = type || ':' || COALESCE(blog_id, 0) || ':' || COALESCE(comment_id, 0)
+		WHERE group_key = '';
+		DELETE FROM notifications a
+		USING notifications b
+		WHERE a.read_at IS NULL AND b.read_at IS NULL
+		  AND a.user_id = b.user_id AND a.group_key = b.group_key AND a.actor_id = b.actor_id
+		  AND a.id < b.id;
+	`)
+	if err != nil {
+		return fmt.Errorf("migrate notifications table: %w", err)
+	}
+
+	// Create notification sends table, remembering when notifications not
+	// stored in-app were last sent so repeats are not sent again
+	_, err = db.Client.Exec(`
+		CREATE TABLE IF NOT EXISTS notification_sends (
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			group_key VARCHAR(100) NOT NULL,
+			actor_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			sent_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, group_key, actor_id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create notification sends table: %w", err)
+	}
+
+	// Create blog views tables, counting views per blog per hour and
+	// remembering who viewed each blog this hour so they count once, and
+	// the trending table used when Redis is unavailable
+	_, err = db.Client.Exec(`
+		CREATE TABLE IF NOT EXISTS blog_views (
+			blog_id INTEGER NOT NULL REFERENCES blogs(id) ON DELETE CASCADE,
+			hour TIMESTAMP NOT NULL,
+			views INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (blog_id, hour)
+		);
+		CREATE TABLE IF NOT EXISTS blog_viewers (
+			blog_id INTEGER NOT NULL REFERENCES blogs(id) ON DELETE CASCADE,
+			hour TIMESTAMP NOT NULL,
+			viewer VARCHAR(64) NOT NULL,
+			PRIMARY KEY (blog_id, hour, viewer)
+		);
+		CREATE TABLE IF NOT EXISTS trending_blogs (
+			window_name VARCHAR(10) NOT NULL,
+			blog_id INTEGER NOT NULL REFERENCES blogs(id) ON DELETE CASCADE,
+			score DOUBLE PRECISION NOT NULL,
+			PRIMARY KEY (window_name, blog_id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create trending tables: %w", err)
+	}
+
+	// Create ActivityPub federation tables: local actors' signing keys,
+	// cached remote actors, and remote follows and likes
+	_, err = db.Client.Exec(`
+		CREATE TABLE IF NOT EXISTS actor_keys (
+			user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+			public_key_pem TEXT NOT NULL,
+			private_key_pem TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE TABLE IF NOT EXISTS remote_actors (
+			id TEXT PRIMARY KEY,
+			inbox TEXT NOT NULL,
+			shared_inbox TEXT NOT NULL DEFAULT '',
+			public_key_id TEXT NOT NULL,
+			public_key_pem TEXT NOT NULL,
+			fetched_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE TABLE IF NOT EXISTS remote_followers (
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			actor_id TEXT NOT NULL,
+			inbox TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, actor_id)
+		);
+		CREATE TABLE IF NOT EXISTS remote_likes (
+			blog_id INTEGER NOT NULL REFERENCES blogs(id) ON DELETE CASCADE,
+			actor_id TEXT NOT NULL,
+			activity_id TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (blog_id, actor_id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create federation tables: %w", err)
+	}
+
+	// Create Webmention tables: responses received for blogs, and the URLs
+	// blogs link to, which are sent Webmentions
+	_, err = db.Client.Exec(`
+		CREATE TABLE IF NOT EXISTS webmentions (
+			id SERIAL PRIMARY KEY,
+			blog_id INTEGER NOT NULL REFERENCES blogs(id) ON DELETE CASCADE,
+			source TEXT NOT NULL,
+			target TEXT NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'pending',
+			title TEXT NOT NULL DEFAULT '',
+			verified_at TIMESTAMP,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (blog_id, source)
+		);
+		CREATE TABLE IF NOT EXISTS webmention_targets (
+			blog_id INTEGER NOT NULL REFERENCES blogs(id) ON DELETE CASCADE,
+			target TEXT NOT NULL,
+			PRIMARY KEY (blog_id, target)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create webmention tables: %w", err)
+	}
+
+	// Create access tokens table for the scoped tokens of third-party
+	// clients
+	_, err = db.Client.Exec(`
+		CREATE TABLE IF NOT EXISTS access_tokens (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			name VARCHAR(100) NOT NULL,
+			scopes TEXT[] NOT NULL DEFAULT '{}',
+			token_hash CHAR(64) NOT NULL UNIQUE,
+			last_used_at TIMESTAMP,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create access tokens table: %w", err)
+	}
+
+	// Create newsletter tables: email subscriptions to authors, the
+	// addresses that bounced or complained, which are never mailed again,
+	// and the blogs sent to each subscription, so each is sent once
+	_, err = db.Client.Exec(`
+		CREATE TABLE IF NOT EXISTS email_subscriptions (
+			id SERIAL PRIMARY KEY,
+			author_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			email VARCHAR(255) NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'pending',
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			confirmed_at TIMESTAMP,
+			UNIQUE (author_id, email)
+		);
+		CREATE TABLE IF NOT EXISTS email_suppressions (
+			email VARCHAR(255) PRIMARY KEY,
+			reason VARCHAR(20) NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE TABLE IF NOT EXISTS newsletter_deliveries (
+			subscription_id INTEGER NOT NULL REFERENCES email_subscriptions(id) ON DELETE CASCADE,
+			blog_id INTEGER NOT NULL REFERENCES blogs(id) ON DELETE CASCADE,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (subscription_id, blog_id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create newsletter tables: %w", err)
+	}
+
+	// Create digest tables: how often users get a digest, and the periods
+	// digests were sent for, so each is sent once
+	_, err = db.Client.Exec(`
+		CREATE TABLE IF NOT EXISTS digest_settings (
+			user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+			frequency VARCHAR(10) NOT NULL,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE TABLE IF NOT EXISTS digest_runs (
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			frequency VARCHAR(10) NOT NULL,
+			period_start TIMESTAMP NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, frequency, period_start)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create digest tables: %w", err)
+	}
+
+	// Create notification preferences table, holding the channels users
+	// turned on or off; the others keep their defaults
 	_, err = db.Client.Exec(`
-		CREATE TABLE IF NOT EXISTS likes (
+		CREATE TABLE IF NOT EXISTS notification_preferences (
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			type VARCHAR(20) NOT NULL,
+			channel VARCHAR(20) NOT NULL,
+			enabled BOOLEAN NOT NULL,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, type, channel)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create notification preferences table: %w", err)
+	}
+
+	// Create push tables: the Web Push subscriptions of users' devices and
+	// the single VAPID key pushes are sent with
+	_, err = db.Client.Exec(`
+		CREATE TABLE IF NOT EXISTS push_subscriptions (
 			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			endpoint TEXT UNIQUE NOT NULL,
+			p256dh VARCHAR(100) NOT NULL,
+			auth VARCHAR(50) NOT NULL,
+			user_agent VARCHAR(255) NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE TABLE IF NOT EXISTS vapid_keys (
+			id SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+			public_key VARCHAR(100) NOT NULL,
+			private_key VARCHAR(50) NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create push tables: %w", err)
+	}
+
+	// Create bookmark tables: the blogs users saved for later and the
+	// reading lists they keep
+	_, err = db.Client.Exec(`
+		CREATE TABLE IF NOT EXISTS bookmarks (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
 			blog_id INTEGER NOT NULL REFERENCES blogs(id) ON DELETE CASCADE,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(user_id, blog_id)
+		);
+		CREATE TABLE IF NOT EXISTS reading_lists (
+			id SERIAL PRIMARY KEY,
 			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			name VARCHAR(100) NOT NULL,
+			description VARCHAR(500) NOT NULL DEFAULT '',
+			public BOOLEAN NOT NULL DEFAULT FALSE,
 			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-			UNIQUE(blog_id, user_id)
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE TABLE IF NOT EXISTS reading_list_items (
+			id SERIAL PRIMARY KEY,
+			list_id INTEGER NOT NULL REFERENCES reading_lists(id) ON DELETE CASCADE,
+			blog_id INTEGER NOT NULL REFERENCES blogs(id) ON DELETE CASCADE,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(list_id, blog_id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create bookmark tables: %w", err)
+	}
+
+	// Create jobs table for the background job queue
+	_, err = db.Client.Exec(`
+		CREATE TABLE IF NOT EXISTS jobs (
+			id SERIAL PRIMARY KEY,
+			kind VARCHAR(50) NOT NULL,
+			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			payload JSONB NOT NULL DEFAULT '{}',
+			status VARCHAR(20) NOT NULL DEFAULT 'queued',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			max_attempts INTEGER NOT NULL DEFAULT 5,
+			result TEXT NOT NULL DEFAULT '',
+			last_error TEXT NOT NULL DEFAULT '',
+			run_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			locked_until TIMESTAMP,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 		)
 	`)
 	if err != nil {
-		return fmt.Errorf("create likes table: %w", err)
+		return fmt.Errorf("create jobs table: %w", err)
 	}
 
 	// Create indexes for better performance
 	_, err = db.Client.Exec(`
 		CREATE INDEX IF NOT EXISTS idx_blogs_author ON blogs(author_id);
 		CREATE INDEX IF NOT EXISTS idx_blogs_created ON blogs(created_at DESC);
+		CREATE INDEX IF NOT EXISTS idx_blogs_deleted ON blogs(deleted_at) WHERE deleted_at IS NOT NULL;
+		CREATE INDEX IF NOT EXISTS idx_blogs_author_slug ON blogs(author_id, slug);
+		CREATE INDEX IF NOT EXISTS idx_blogs_tags ON blogs USING GIN(tags);
 		CREATE INDEX IF NOT EXISTS idx_followers_follower ON followers(follower_id);
 		CREATE INDEX IF NOT EXISTS idx_followers_following ON followers(following_id);
-		CREATE INDEX IF NOT EXISTS idx_likes_blog ON likes(blog_id);
-		CREATE INDEX IF NOT EXISTS idx_likes_user ON likes(user_id);
+		CREATE INDEX IF NOT EXISTS idx_followers_pending ON followers(following_id, created_at DESC) WHERE status = 'pending';
+		CREATE INDEX IF NOT EXISTS idx_blocks_blocked ON blocks(blocked_id);
+		CREATE INDEX IF NOT EXISTS idx_mutes_muted ON mutes(muted_id);
+		CREATE INDEX IF NOT EXISTS idx_reactions_blog ON reactions(blog_id, kind);
+		CREATE INDEX IF NOT EXISTS idx_reactions_user ON reactions(user_id);
+		CREATE INDEX IF NOT EXISTS idx_reactions_created ON reactions(created_at) WHERE kind = 'like';
+		CREATE INDEX IF NOT EXISTS idx_comments_created ON comments(created_at);
+		CREATE INDEX IF NOT EXISTS idx_blog_views_hour ON blog_views(hour);
+		CREATE INDEX IF NOT EXISTS idx_trending_blogs_score ON trending_blogs(window_name, score DESC);
+		CREATE INDEX IF NOT EXISTS idx_comments_blog ON comments(blog_id, created_at) WHERE parent_id IS NULL;
+		CREATE INDEX IF NOT EXISTS idx_comments_parent ON comments(parent_id);
+		CREATE INDEX IF NOT EXISTS idx_comments_pending ON comments(blog_id) WHERE status = 'pending';
+		CREATE INDEX IF NOT EXISTS idx_mentions_user ON mentions(user_id, created_at DESC);
+		CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications(user_id, created_at DESC);
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_unread ON notifications(user_id, group_key, actor_id) WHERE read_at IS NULL;
+		CREATE INDEX IF NOT EXISTS idx_remote_likes_actor ON remote_likes(actor_id, activity_id);
+		CREATE INDEX IF NOT EXISTS idx_webmentions_blog ON webmentions(blog_id, created_at DESC) WHERE status = 'verified';
+		CREATE INDEX IF NOT EXISTS idx_webmentions_source_host ON webmentions(lower(split_part(source, '/', 3)), updated_at);
+		CREATE INDEX IF NOT EXISTS idx_access_tokens_user ON access_tokens(user_id, created_at DESC);
+		CREATE INDEX IF NOT EXISTS idx_email_subscriptions_author ON email_subscriptions(author_id) WHERE status = 'confirmed';
+		CREATE INDEX IF NOT EXISTS idx_digest_settings_frequency ON digest_settings(frequency);
+		CREATE INDEX IF NOT EXISTS idx_push_subscriptions_user ON push_subscriptions(user_id, created_at DESC);
+		CREATE INDEX IF NOT EXISTS idx_bookmarks_user ON bookmarks(user_id, created_at DESC, id DESC);
+		CREATE INDEX IF NOT EXISTS idx_bookmarks_blog ON bookmarks(blog_id);
+		CREATE INDEX IF NOT EXISTS idx_reading_lists_user ON reading_lists(user_id, created_at DESC);
+		CREATE INDEX IF NOT EXISTS idx_reading_list_items_list ON reading_list_items(list_id, created_at DESC, id DESC);
+		CREATE INDEX IF NOT EXISTS idx_reading_list_items_blog ON reading_list_items(blog_id);
+		CREATE INDEX IF NOT EXISTS idx_jobs_due ON jobs(status, run_at);
 	`)
 	if err != nil {
 		return fmt.Errorf("create indexes: %w", err)
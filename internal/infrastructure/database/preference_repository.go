@@ -0,0 +1,82 @@
+package database
+
+import (
+	"fmt"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// PreferenceRepository implements repository.PreferenceRepository for
+// PostgreSQL
+type PreferenceRepository struct {
+	db *PostgresDB
+}
+
+// NewPreferenceRepository creates a new preference repository
+func NewPreferenceRepository(db *PostgresDB) *PreferenceRepository {
+	return &PreferenceRepository{db: db}
+}
+
+// GetPreferences retrieves the channels a user set
+func (r *PreferenceRepository) GetPreferences(userID int64) (entity.NotificationPreferences, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		SELECT type, channel, enabled
+		FROM notification_preferences
+		WHERE user_id = $1
+	`, userID)
+
+	if err != nil {
+		return nil, fmt.Errorf("get notification preferences: %w", err)
+	}
+	defer rows.Close()
+
+	prefs := entity.NotificationPreferences{}
+	for rows.Next() {
+		var prefType, channel string
+		var enabled bool
+		if err := rows.Scan(&prefType, &channel, &enabled); err != nil {
+			return nil, fmt.Errorf("scan notification preference: %w", err)
+		}
+		if prefs[prefType] == nil {
+			prefs[prefType] = map[string]bool{}
+		}
+		prefs[prefType][channel] = enabled
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate notification preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// SavePreferences upserts the channels set in prefs in one transaction
+func (r *PreferenceRepository) SavePreferences(userID int64, prefs entity.NotificationPreferences) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	tx, err := r.db.Client.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for prefType, channels := range prefs {
+		for channel, enabled := range channels {
+			_, err := tx.Exec(`
+				INSERT INTO notification_preferences (user_id, type, channel, enabled, updated_at)
+				VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+				ON CONFLICT (user_id, type, channel) DO UPDATE SET enabled = EXCLUDED.enabled, updated_at = EXCLUDED.updated_at
+			`, userID, prefType, channel, enabled)
+			if err != nil {
+				return fmt.Errorf("save notification preference: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit notification preferences: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// pushSubscriptionColumns lists the columns read back by
+// scanPushSubscription
+const pushSubscriptionColumns = `id, user_id, endpoint, p256dh, auth, user_agent, created_at`
+
+// PushRepository implements repository.PushRepository for PostgreSQL
+type PushRepository struct {
+	db *PostgresDB
+}
+
+// NewPushRepository creates a new push repository
+func NewPushRepository(db *PostgresDB) *PushRepository {
+	return &PushRepository{db: db}
+}
+
+func scanPushSubscription(row rowScanner) (*entity.PushSubscription, error) {
+	subscription := &entity.PushSubscription{}
+	err := row.Scan(
+		&subscription.ID, &subscription.UserID, &subscription.Endpoint, &subscription.P256dh,
+		&subscription.Auth, &subscription.UserAgent, &subscription.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// Subscribe stores a push subscription, replacing the one with the same
+// endpoint
+func (r *PushRepository) Subscribe(subscription *entity.PushSubscription) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	err := r.db.Client.QueryRow(`
+		INSERT INTO push_subscriptions (user_id, endpoint, p256dh, auth, user_agent, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (endpoint) DO UPDATE SET
+			user_id = EXCLUDED.user_id, p256dh = EXCLUDED.p256dh, auth = EXCLUDED.auth,
+			user_agent = EXCLUDED.user_agent, created_at = EXCLUDED.created_at
+		RETURNING id
+	`, subscription.UserID, subscription.Endpoint, subscription.P256dh, subscription.Auth,
+		subscription.UserAgent, subscription.CreatedAt).Scan(&subscription.ID)
+
+	if err != nil {
+		return fmt.Errorf("save push subscription: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a push subscription by ID
+func (r *PushRepository) GetByID(id int64) (*entity.PushSubscription, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	subscription, err := scanPushSubscription(r.db.Client.QueryRow(`
+		SELECT `+pushSubscriptionColumns+`
+		FROM push_subscriptions
+		WHERE id = $1
+	`, id))
+
+	if err == sql.ErrNoRows {
+		return nil, entity.ErrPushSubscriptionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get push subscription: %w", err)
+	}
+	return subscription, nil
+}
+
+// GetByUser retrieves a user's push subscriptions, newest first
+func (r *PushRepository) GetByUser(userID int64) ([]*entity.PushSubscription, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		SELECT `+pushSubscriptionColumns+`
+		FROM push_subscriptions
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get push subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := []*entity.PushSubscription{}
+	for rows.Next() {
+		subscription, err := scanPushSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan push subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate push subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// Unsubscribe deletes a user's push subscription by its endpoint
+func (r *PushRepository) Unsubscribe(userID int64, endpoint string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	result, err := r.db.Client.Exec(`
+		DELETE FROM push_subscriptions WHERE user_id = $1 AND endpoint = $2
+	`, userID, endpoint)
+	if err != nil {
+		return fmt.Errorf("delete push subscription: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete push subscription: %w", err)
+	}
+	if rows == 0 {
+		return entity.ErrPushSubscriptionNotFound
+	}
+	return nil
+}
+
+// Delete deletes a push subscription the push service dropped
+func (r *PushRepository) Delete(id int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`DELETE FROM push_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete push subscription: %w", err)
+	}
+	return nil
+}
+
+// GetVAPIDKey retrieves the VAPID key
+func (r *PushRepository) GetVAPIDKey() (*entity.VAPIDKey, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	key := &entity.VAPIDKey{}
+	err := r.db.Client.QueryRow(`
+		SELECT public_key, private_key, created_at
+		FROM vapid_keys
+		WHERE id = 1
+	`).Scan(&key.PublicKey, &key.PrivateKey, &key.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, entity.ErrVAPIDKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get vapid key: %w", err)
+	}
+	return key, nil
+}
+
+// CreateVAPIDKey stores the VAPID key unless there already is one
+func (r *PushRepository) CreateVAPIDKey(key *entity.VAPIDKey) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`
+		INSERT INTO vapid_keys (id, public_key, private_key, created_at)
+		VALUES (1, $1, $2, $3)
+		ON CONFLICT (id) DO NOTHING
+	`, key.PublicKey, key.PrivateKey, key.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("create vapid key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"fmt"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// RecommendationRepository implements repository.RecommendationRepository
+// for PostgreSQL
+type RecommendationRepository struct {
+	db *PostgresDB
+}
+
+// NewRecommendationRepository creates a new recommendation repository
+func NewRecommendationRepository(db *PostgresDB) *RecommendationRepository {
+	return &RecommendationRepository{db: db}
+}
+
+// ComputeRecommendations scores candidates from three sources and sums each
+// candidate's scores: every followed user who follows them counts 3, every
+// blog of theirs the user liked counts 2, and the most followed authors get
+// a small score growing with their follower count, so users without
+// follows or likes still get suggestions. A candidate's reason is its
+// strongest source.
+func (r *RecommendationRepository) ComputeRecommendations(userID int64, limit int) ([]*entity.Recommendation, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		WITH candidates AS (
+			SELECT f2.following_id AS user_id, $3::TEXT AS reason, 3.0 * COUNT(DISTINCT f1.following_id) AS score
+			FROM followers f1
+			INNER JOIN followers f2 ON f2.follower_id = f1.following_id AND f2.status = 'accepted'
+			WHERE f1.follower_id = $1 AND f1.status = 'accepted'
+			GROUP BY f2.following_id
+
+			UNION ALL
+
+			SELECT b.author_id, $4::TEXT, 2.0 * COUNT(*)
+			FROM reactions rc
+			INNER JOIN blogs b ON b.id = rc.blog_id
+			WHERE rc.user_id = $1 AND rc.kind = 'like' AND b.deleted_at IS NULL
+			GROUP BY b.author_id
+
+			UNION ALL
+
+			SELECT user_id, $5::TEXT, score FROM (
+				SELECT following_id AS user_id, LN(1 + COUNT(*)) AS score
+				FROM followers
+				WHERE status = 'accepted'
+				GROUP BY following_id
+				ORDER BY COUNT(*) DESC
+				LIMIT 100
+			) popular
+		)
+		SELECT u.id, u.username, u.email, u.display_name, u.bio, u.profile_image, u.private, u.created_at, u.updated_at,
+		       (ARRAY_AGG(c.reason ORDER BY c.score DESC))[1], SUM(c.score)
+		FROM candidates c
+		INNER JOIN users u ON u.id = c.user_id
+		WHERE c.user_id <> $1
+		  AND c.user_id NOT IN (SELECT following_id FROM followers WHERE follower_id = $1)
+		  AND c.user_id NOT IN (SELECT muted_id FROM mutes WHERE muter_id = $1)
+		  AND c.user_id NOT IN (SELECT blocked_id FROM blocks WHERE blocker_id = $1)
+		  AND c.user_id NOT IN (SELECT blocker_id FROM blocks WHERE blocked_id = $1)
+		GROUP BY u.id
+		ORDER BY SUM(c.score) DESC, u.id
+		LIMIT $2
+	`, userID, limit, entity.RecommendedByFriends, entity.RecommendedByLikes, entity.RecommendedPopular)
+	if err != nil {
+		return nil, fmt.Errorf("compute recommendations: %w", err)
+	}
+	defer rows.Close()
+
+	recommendations := []*entity.Recommendation{}
+	for rows.Next() {
+		recommendation := &entity.Recommendation{User: &entity.User{}}
+		user := recommendation.User
+		err := rows.Scan(
+			&user.ID, &user.Username, &user.Email, &user.DisplayName,
+			&user.Bio, &user.ProfileImage, &user.Private, &user.CreatedAt, &user.UpdatedAt,
+			&recommendation.Reason, &recommendation.Score,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan recommendation: %w", err)
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate recommendations: %w", err)
+	}
+	return recommendations, nil
+}
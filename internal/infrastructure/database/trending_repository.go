@@ -0,0 +1,88 @@
+package database
+
+import (
+	"fmt"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"github.com/lib/pq"
+)
+
+// TrendingRepository implements repository.TrendingRepository for
+// PostgreSQL, materializing trending blogs in the trending_blogs table.
+// It is used when Redis is unavailable.
+type TrendingRepository struct {
+	db *PostgresDB
+}
+
+// NewTrendingRepository creates a new trending repository
+func NewTrendingRepository(db *PostgresDB) *TrendingRepository {
+	return &TrendingRepository{db: db}
+}
+
+// StoreTrending replaces the trending blogs of a window
+func (r *TrendingRepository) StoreTrending(window string, blogs []*entity.TrendingBlog) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	ids := make([]int64, len(blogs))
+	scores := make([]float64, len(blogs))
+	for i, blog := range blogs {
+		ids[i] = blog.BlogID
+		scores[i] = blog.Score
+	}
+
+	tx, err := r.db.Client.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM trending_blogs WHERE window_name = $1`, window)
+	if err != nil {
+		return fmt.Errorf("clear trending blogs: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO trending_blogs (window_name, blog_id, score)
+		SELECT $1, blog_id, score
+		FROM UNNEST($2::INTEGER[], $3::DOUBLE PRECISION[]) AS t(blog_id, score)
+	`, window, pq.Array(ids), pq.Array(scores))
+	if err != nil {
+		return fmt.Errorf("insert trending blogs: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit trending blogs: %w", err)
+	}
+	return nil
+}
+
+// GetTrending retrieves a page of the IDs of a window's trending blogs
+func (r *TrendingRepository) GetTrending(window string, limit, offset int) ([]int64, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		SELECT blog_id FROM trending_blogs
+		WHERE window_name = $1
+		ORDER BY score DESC, blog_id DESC
+		LIMIT $2 OFFSET $3
+	`, window, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get trending blogs: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan trending blog: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate trending blogs: %w", err)
+	}
+	return ids, nil
+}
@@ -42,12 +42,12 @@ func (r *UserRepository) GetByID(id int64) (*entity.User, error) {
 
 	user := &entity.User{}
 	err := r.db.Client.QueryRow(`
-		SELECT id, username, email, display_name, bio, profile_image, created_at, updated_at
+		SELECT id, username, email, display_name, bio, profile_image, private, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`, id).Scan(
 		&user.ID, &user.Username, &user.Email, &user.DisplayName,
-		&user.Bio, &user.ProfileImage, &user.CreatedAt, &user.UpdatedAt,
+		&user.Bio, &user.ProfileImage, &user.Private, &user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -67,12 +67,12 @@ func (r *UserRepository) GetByUsername(username string) (*entity.User, error) {
 
 	user := &entity.User{}
 	err := r.db.Client.QueryRow(`
-		SELECT id, username, email, display_name, bio, profile_image, created_at, updated_at
+		SELECT id, username, email, display_name, bio, profile_image, private, created_at, updated_at
 		FROM users
 		WHERE username = $1
 	`, username).Scan(
 		&user.ID, &user.Username, &user.Email, &user.DisplayName,
-		&user.Bio, &user.ProfileImage, &user.CreatedAt, &user.UpdatedAt,
+		&user.Bio, &user.ProfileImage, &user.Private, &user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -92,9 +92,9 @@ func (r *UserRepository) Update(user *entity.User) error {
 
 	_, err := r.db.Client.Exec(`
 		UPDATE users
-		SET display_name = $1, bio = $2, profile_image = $3, updated_at = $4
-		WHERE id = $5
-	`, user.DisplayName, user.Bio, user.ProfileImage, user.UpdatedAt, user.ID)
+		SET display_name = $1, bio = $2, profile_image = $3, private = $4, updated_at = $5
+		WHERE id = $6
+	`, user.DisplayName, user.Bio, user.ProfileImage, user.Private, user.UpdatedAt, user.ID)
 
 	if err != nil {
 		return fmt.Errorf("update user: %w", err)
@@ -102,24 +102,28 @@ func (r *UserRepository) Update(user *entity.User) error {
 	return nil
 }
 
-// Follow creates a follow relationship
-func (r *UserRepository) Follow(followerID, followingID int64) error {
+// Follow creates a follow relationship with the given status, returning
+// the stored status
+func (r *UserRepository) Follow(followerID, followingID int64, status string) (string, error) {
 	r.db.mu.Lock()
 	defer r.db.mu.Unlock()
 
-	_, err := r.db.Client.Exec(`
-		INSERT INTO followers (follower_id, following_id, created_at)
-		VALUES ($1, $2, NOW())
-		ON CONFLICT (follower_id, following_id) DO NOTHING
-	`, followerID, followingID)
+	// The no-op update makes RETURNING report existing relationships too
+	var stored string
+	err := r.db.Client.QueryRow(`
+		INSERT INTO followers (follower_id, following_id, status, created_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (follower_id, following_id) DO UPDATE SET status = followers.status
+		RETURNING status
+	`, followerID, followingID, status).Scan(&stored)
 
 	if err != nil {
-		return fmt.Errorf("follow user: %w", err)
+		return "", fmt.Errorf("follow user: %w", err)
 	}
-	return nil
+	return stored, nil
 }
 
-// Unfollow removes a follow relationship
+// Unfollow removes a follow relationship or a pending follow request
 func (r *UserRepository) Unfollow(followerID, followingID int64) error {
 	r.db.mu.Lock()
 	defer r.db.mu.Unlock()
@@ -135,19 +139,22 @@ func (r *UserRepository) Unfollow(followerID, followingID int64) error {
 	return nil
 }
 
-// GetFollowers retrieves users following the given user
-func (r *UserRepository) GetFollowers(userID int64, limit, offset int) ([]*entity.User, error) {
+// GetFollowers retrieves users following the given user, leaving out users
+// in a block with viewerID
+func (r *UserRepository) GetFollowers(userID, viewerID int64, limit, offset int) ([]*entity.User, error) {
 	r.db.mu.RLock()
 	defer r.db.mu.RUnlock()
 
 	rows, err := r.db.Client.Query(`
-		SELECT u.id, u.username, u.email, u.display_name, u.bio, u.profile_image, u.created_at, u.updated_at
+		SELECT u.id, u.username, u.email, u.display_name, u.bio, u.profile_image, u.private, u.created_at, u.updated_at
 		FROM users u
 		INNER JOIN followers f ON u.id = f.follower_id
-		WHERE f.following_id = $1
+		WHERE f.following_id = $1 AND f.status = 'accepted'
+		  AND u.id NOT IN (SELECT blocked_id FROM blocks WHERE blocker_id = $2)
+		  AND u.id NOT IN (SELECT blocker_id FROM blocks WHERE blocked_id = $2)
 		ORDER BY f.created_at DESC
-		LIMIT $2 OFFSET $3
-	`, userID, limit, offset)
+		LIMIT $3 OFFSET $4
+	`, userID, viewerID, limit, offset)
 
 	if err != nil {
 		return nil, fmt.Errorf("get followers: %w", err)
@@ -159,7 +166,7 @@ func (r *UserRepository) GetFollowers(userID int64, limit, offset int) ([]*entit
 		user := &entity.User{}
 		err := rows.Scan(
 			&user.ID, &user.Username, &user.Email, &user.DisplayName,
-			&user.Bio, &user.ProfileImage, &user.CreatedAt, &user.UpdatedAt,
+			&user.Bio, &user.ProfileImage, &user.Private, &user.CreatedAt, &user.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan follower: %w", err)
@@ -170,19 +177,22 @@ func (r *UserRepository) GetFollowers(userID int64, limit, offset int) ([]*entit
 	return users, nil
 }
 
-// GetFollowing retrieves users that the given user is following
-func (r *UserRepository) GetFollowing(userID int64, limit, offset int) ([]*entity.User, error) {
+// GetFollowing retrieves users that the given user is following, leaving
+// out users in a block with viewerID
+func (r *UserRepository) GetFollowing(userID, viewerID int64, limit, offset int) ([]*entity.User, error) {
 	r.db.mu.RLock()
 	defer r.db.mu.RUnlock()
 
 	rows, err := r.db.Client.Query(`
-		SELECT u.id, u.username, u.email, u.display_name, u.bio, u.profile_image, u.created_at, u.updated_at
+		SELECT u.id, u.username, u.email, u.display_name, u.bio, u.profile_image, u.private, u.created_at, u.updated_at
 		FROM users u
 		INNER JOIN followers f ON u.id = f.following_id
-		WHERE f.follower_id = $1
+		WHERE f.follower_id = $1 AND f.status = 'accepted'
+		  AND u.id NOT IN (SELECT blocked_id FROM blocks WHERE blocker_id = $2)
+		  AND u.id NOT IN (SELECT blocker_id FROM blocks WHERE blocked_id = $2)
 		ORDER BY f.created_at DESC
-		LIMIT $2 OFFSET $3
-	`, userID, limit, offset)
+		LIMIT $3 OFFSET $4
+	`, userID, viewerID, limit, offset)
 
 	if err != nil {
 		return nil, fmt.Errorf("get following: %w", err)
@@ -194,7 +204,7 @@ func (r *UserRepository) GetFollowing(userID int64, limit, offset int) ([]*entit
 		user := &entity.User{}
 		err := rows.Scan(
 			&user.ID, &user.Username, &user.Email, &user.DisplayName,
-			&user.Bio, &user.ProfileImage, &user.CreatedAt, &user.UpdatedAt,
+			&user.Bio, &user.ProfileImage, &user.Private, &user.CreatedAt, &user.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan following: %w", err)
@@ -205,6 +215,36 @@ func (r *UserRepository) GetFollowing(userID int64, limit, offset int) ([]*entit
 	return users, nil
 }
 
+// GetFollowerIDs retrieves the IDs of the users following the given user,
+// leaving out those who muted them
+func (r *UserRepository) GetFollowerIDs(userID int64) ([]int64, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		SELECT follower_id FROM followers
+		WHERE following_id = $1 AND status = 'accepted'
+		  AND follower_id NOT IN (SELECT muter_id FROM mutes WHERE muted_id = $1)
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get follower ids: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan follower id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate follower ids: %w", err)
+	}
+	return ids, nil
+}
+
 // IsFollowing checks if one user is following another
 func (r *UserRepository) IsFollowing(followerID, followingID int64) (bool, error) {
 	r.db.mu.RLock()
@@ -214,7 +254,7 @@ func (r *UserRepository) IsFollowing(followerID, followingID int64) (bool, error
 	err := r.db.Client.QueryRow(`
 		SELECT EXISTS(
 			SELECT 1 FROM followers
-			WHERE follower_id = $1 AND following_id = $2
+			WHERE follower_id = $1 AND following_id = $2 AND status = 'accepted'
 		)
 	`, followerID, followingID).Scan(&exists)
 
@@ -224,6 +264,126 @@ func (r *UserRepository) IsFollowing(followerID, followingID int64) (bool, error
 	return exists, nil
 }
 
+// GetFollowRequests retrieves the pending follow requests of the given user,
+// newest first
+func (r *UserRepository) GetFollowRequests(userID int64, limit, offset int) ([]*entity.FollowRequest, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		SELECT u.id, u.username, u.email, u.display_name, u.bio, u.profile_image, u.private, u.created_at, u.updated_at,
+		       f.created_at
+		FROM users u
+		INNER JOIN followers f ON u.id = f.follower_id
+		WHERE f.following_id = $1 AND f.status = 'pending'
+		ORDER BY f.created_at DESC
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get follow requests: %w", err)
+	}
+	defer rows.Close()
+
+	requests := []*entity.FollowRequest{}
+	for rows.Next() {
+		request := &entity.FollowRequest{Follower: &entity.User{}}
+		user := request.Follower
+		err := rows.Scan(
+			&user.ID, &user.Username, &user.Email, &user.DisplayName,
+			&user.Bio, &user.ProfileImage, &user.Private, &user.CreatedAt, &user.UpdatedAt,
+			&request.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan follow request: %w", err)
+		}
+		requests = append(requests, request)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate follow requests: %w", err)
+	}
+	return requests, nil
+}
+
+// AcceptFollowRequest turns a pending follow request into a follow
+func (r *UserRepository) AcceptFollowRequest(followerID, followingID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	result, err := r.db.Client.Exec(`
+		UPDATE followers
+		SET status = 'accepted'
+		WHERE follower_id = $1 AND following_id = $2 AND status = 'pending'
+	`, followerID, followingID)
+
+	if err != nil {
+		return fmt.Errorf("accept follow request: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return entity.ErrFollowRequestNotFound
+	}
+	return nil
+}
+
+// RejectFollowRequest removes a pending follow request
+func (r *UserRepository) RejectFollowRequest(followerID, followingID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	result, err := r.db.Client.Exec(`
+		DELETE FROM followers
+		WHERE follower_id = $1 AND following_id = $2 AND status = 'pending'
+	`, followerID, followingID)
+
+	if err != nil {
+		return fmt.Errorf("reject follow request: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return entity.ErrFollowRequestNotFound
+	}
+	return nil
+}
+
+// AcceptAllFollowRequests accepts every pending follow request of the given
+// user, returning the IDs of the new followers
+func (r *UserRepository) AcceptAllFollowRequests(userID int64) ([]int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	rows, err := r.db.Client.Query(`
+		UPDATE followers
+		SET status = 'accepted'
+		WHERE following_id = $1 AND status = 'pending'
+		RETURNING follower_id
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("accept follow requests: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan follower id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate follower ids: %w", err)
+	}
+	return ids, nil
+}
+
 // GetStats retrieves statistics for a user
 func (r *UserRepository) GetStats(userID int64) (*entity.UserStats, error) {
 	r.db.mu.RLock()
@@ -233,7 +393,7 @@ func (r *UserRepository) GetStats(userID int64) (*entity.UserStats, error) {
 
 	// Get followers count
 	err := r.db.Client.QueryRow(`
-		SELECT COUNT(*) FROM followers WHERE following_id = $1
+		SELECT COUNT(*) FROM followers WHERE following_id = $1 AND status = 'accepted'
 	`, userID).Scan(&stats.FollowersCount)
 	if err != nil {
 		return nil, fmt.Errorf("get followers count: %w", err)
@@ -241,7 +401,7 @@ func (r *UserRepository) GetStats(userID int64) (*entity.UserStats, error) {
 
 	// Get following count
 	err = r.db.Client.QueryRow(`
-		SELECT COUNT(*) FROM followers WHERE follower_id = $1
+		SELECT COUNT(*) FROM followers WHERE follower_id = $1 AND status = 'accepted'
 	`, userID).Scan(&stats.FollowingCount)
 	if err != nil {
 		return nil, fmt.Errorf("get following count: %w", err)
@@ -249,7 +409,7 @@ func (r *UserRepository) GetStats(userID int64) (*entity.UserStats, error) {
 
 	// Get blogs count
 	err = r.db.Client.QueryRow(`
-		SELECT COUNT(*) FROM blogs WHERE author_id = $1
+		SELECT COUNT(*) FROM blogs WHERE author_id = $1 AND deleted_at IS NULL
 	`, userID).Scan(&stats.BlogsCount)
 	if err != nil {
 		return nil, fmt.Errorf("get blogs count: %w", err)
@@ -258,4 +418,222 @@ func (r *UserRepository) GetStats(userID int64) (*entity.UserStats, error) {
 	return stats, nil
 }
 
+// Block makes blocker and blocked invisible to each other and removes the
+// follow relationships between them
+func (r *UserRepository) Block(blockerID, blockedID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	tx, err := r.db.Client.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO blocks (blocker_id, blocked_id, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (blocker_id, blocked_id) DO NOTHING
+	`, blockerID, blockedID)
+	if err != nil {
+		return fmt.Errorf("block user: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM followers
+		WHERE (follower_id = $1 AND following_id = $2)
+		   OR (follower_id = $2 AND following_id = $1)
+	`, blockerID, blockedID)
+	if err != nil {
+		return fmt.Errorf("remove follows of blocked user: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit block: %w", err)
+	}
+	return nil
+}
+
+// Unblock removes a block
+func (r *UserRepository) Unblock(blockerID, blockedID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`
+		DELETE FROM blocks
+		WHERE blocker_id = $1 AND blocked_id = $2
+	`, blockerID, blockedID)
+
+	if err != nil {
+		return fmt.Errorf("unblock user: %w", err)
+	}
+	return nil
+}
+
+// GetBlocked retrieves the users the given user has blocked
+func (r *UserRepository) GetBlocked(userID int64, limit, offset int) ([]*entity.User, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		SELECT u.id, u.username, u.email, u.display_name, u.bio, u.profile_image, u.private, u.created_at, u.updated_at
+		FROM users u
+		INNER JOIN blocks k ON u.id = k.blocked_id
+		WHERE k.blocker_id = $1
+		ORDER BY k.created_at DESC
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get blocked users: %w", err)
+	}
+	defer rows.Close()
+
+	return scanUsers(rows)
+}
+
+// GetBlockIDs retrieves the IDs of the users who blocked or were blocked by
+// the given user
+func (r *UserRepository) GetBlockIDs(userID int64) ([]int64, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		SELECT blocked_id FROM blocks WHERE blocker_id = $1
+		UNION
+		SELECT blocker_id FROM blocks WHERE blocked_id = $1
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get block ids: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan block id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate block ids: %w", err)
+	}
+	return ids, nil
+}
+
+// IsBlocked checks if either user has blocked the other
+func (r *UserRepository) IsBlocked(userID, otherID int64) (bool, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var exists bool
+	err := r.db.Client.QueryRow(`
+		SELECT EXISTS(
+			SELECT 1 FROM blocks
+			WHERE (blocker_id = $1 AND blocked_id = $2)
+			   OR (blocker_id = $2 AND blocked_id = $1)
+		)
+	`, userID, otherID).Scan(&exists)
+
+	if err != nil {
+		return false, fmt.Errorf("check block: %w", err)
+	}
+	return exists, nil
+}
+
+// Mute hides the muted user's content from the muter's feed and
+// notifications
+func (r *UserRepository) Mute(muterID, mutedID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`
+		INSERT INTO mutes (muter_id, muted_id, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (muter_id, muted_id) DO NOTHING
+	`, muterID, mutedID)
+
+	if err != nil {
+		return fmt.Errorf("mute user: %w", err)
+	}
+	return nil
+}
+
+// Unmute removes a mute
+func (r *UserRepository) Unmute(muterID, mutedID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`
+		DELETE FROM mutes
+		WHERE muter_id = $1 AND muted_id = $2
+	`, muterID, mutedID)
+
+	if err != nil {
+		return fmt.Errorf("unmute user: %w", err)
+	}
+	return nil
+}
+
+// GetMuted retrieves the users the given user has muted
+func (r *UserRepository) GetMuted(userID int64, limit, offset int) ([]*entity.User, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		SELECT u.id, u.username, u.email, u.display_name, u.bio, u.profile_image, u.private, u.created_at, u.updated_at
+		FROM users u
+		INNER JOIN mutes m ON u.id = m.muted_id
+		WHERE m.muter_id = $1
+		ORDER BY m.created_at DESC
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get muted users: %w", err)
+	}
+	defer rows.Close()
+
+	return scanUsers(rows)
+}
+
+// IsMuted checks if one user has muted another
+func (r *UserRepository) IsMuted(muterID, mutedID int64) (bool, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var exists bool
+	err := r.db.Client.QueryRow(`
+		SELECT EXISTS(
+			SELECT 1 FROM mutes
+			WHERE muter_id = $1 AND muted_id = $2
+		)
+	`, muterID, mutedID).Scan(&exists)
+
+	if err != nil {
+		return false, fmt.Errorf("check mute: %w", err)
+	}
+	return exists, nil
+}
+
+// scanUsers reads the users selected by a query listing user columns
+func scanUsers(rows *sql.Rows) ([]*entity.User, error) {
+	users := []*entity.User{}
+	for rows.Next() {
+		user := &entity.User{}
+		err := rows.Scan(
+			&user.ID, &user.Username, &user.Email, &user.DisplayName,
+			&user.Bio, &user.ProfileImage, &user.Private, &user.CreatedAt, &user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate users: %w", err)
+	}
+	return users, nil
+}
+
+
 
@@ -0,0 +1,233 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// webmentionColumns lists the columns read back by scanWebmention
+const webmentionColumns = `id, blog_id, source, target, status, title, verified_at, created_at, updated_at`
+
+// WebmentionRepository implements repository.WebmentionRepository for
+// PostgreSQL
+type WebmentionRepository struct {
+	db *PostgresDB
+}
+
+// NewWebmentionRepository creates a new Webmention repository
+func NewWebmentionRepository(db *PostgresDB) *WebmentionRepository {
+	return &WebmentionRepository{db: db}
+}
+
+func scanWebmention(row rowScanner) (*entity.Webmention, error) {
+	webmention := &entity.Webmention{}
+	var verifiedAt sql.NullTime
+	err := row.Scan(
+		&webmention.ID, &webmention.BlogID, &webmention.Source, &webmention.Target, &webmention.Status,
+		&webmention.Title, &verifiedAt, &webmention.CreatedAt, &webmention.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if verifiedAt.Valid {
+		webmention.VerifiedAt = &verifiedAt.Time
+	}
+	return webmention, nil
+}
+
+// Save stores a pending Webmention, or updates an earlier one from the same
+// source to the same blog so it is checked again. A verified Webmention
+// stays listed while it is checked.
+func (r *WebmentionRepository) Save(webmention *entity.Webmention) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	saved, err := scanWebmention(r.db.Client.QueryRow(`
+		INSERT INTO webmentions (blog_id, source, target, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (blog_id, source) DO UPDATE
+		SET target = EXCLUDED.target, updated_at = EXCLUDED.updated_at,
+		    status = CASE WHEN webmentions.status = 'verified' THEN 'verified' ELSE EXCLUDED.status END
+		RETURNING `+webmentionColumns+`
+	`, webmention.BlogID, webmention.Source, webmention.Target, webmention.Status,
+		webmention.CreatedAt, webmention.UpdatedAt))
+
+	if err != nil {
+		return fmt.Errorf("save webmention: %w", err)
+	}
+	*webmention = *saved
+	return nil
+}
+
+// GetByID retrieves a Webmention by ID
+func (r *WebmentionRepository) GetByID(id int64) (*entity.Webmention, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	webmention, err := scanWebmention(r.db.Client.QueryRow(`
+		SELECT `+webmentionColumns+`
+		FROM webmentions
+		WHERE id = $1
+	`, id))
+
+	if err == sql.ErrNoRows {
+		return nil, entity.ErrWebmentionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get webmention: %w", err)
+	}
+	return webmention, nil
+}
+
+// MarkVerified records that a Webmention's source links to its target
+func (r *WebmentionRepository) MarkVerified(id int64, title string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	result, err := r.db.Client.Exec(`
+		UPDATE webmentions
+		SET status = 'verified', title = $2, verified_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`, id, title)
+	if err != nil {
+		return fmt.Errorf("verify webmention: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("verify webmention: %w", err)
+	}
+	if rows == 0 {
+		return entity.ErrWebmentionNotFound
+	}
+	return nil
+}
+
+// CountFromHost counts the Webmentions received or received again since a
+// time from sources on host
+func (r *WebmentionRepository) CountFromHost(host string, since time.Time) (int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var count int
+	err := r.db.Client.QueryRow(`
+		SELECT COUNT(*) FROM webmentions
+		WHERE lower(split_part(source, '/', 3)) = $1 AND updated_at > $2
+	`, strings.ToLower(host), since).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("count webmentions from host: %w", err)
+	}
+	return count, nil
+}
+
+// Delete removes a Webmention
+func (r *WebmentionRepository) Delete(id int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.Client.Exec(`DELETE FROM webmentions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete webmention: %w", err)
+	}
+	return nil
+}
+
+// GetVerified retrieves a page of a blog's verified Webmentions, newest
+// first
+func (r *WebmentionRepository) GetVerified(blogID int64, limit, offset int) ([]*entity.Webmention, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		SELECT `+webmentionColumns+`
+		FROM webmentions
+		WHERE blog_id = $1 AND status = 'verified'
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`, blogID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get webmentions: %w", err)
+	}
+	defer rows.Close()
+
+	webmentions := []*entity.Webmention{}
+	for rows.Next() {
+		webmention, err := scanWebmention(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan webmention: %w", err)
+		}
+		webmentions = append(webmentions, webmention)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webmentions: %w", err)
+	}
+
+	return webmentions, nil
+}
+
+// GetTargets retrieves the URLs a blog linked to when it was last sent
+func (r *WebmentionRepository) GetTargets(blogID int64) ([]string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows, err := r.db.Client.Query(`
+		SELECT target FROM webmention_targets WHERE blog_id = $1 ORDER BY target
+	`, blogID)
+	if err != nil {
+		return nil, fmt.Errorf("get webmention targets: %w", err)
+	}
+	defer rows.Close()
+
+	targets := []string{}
+	for rows.Next() {
+		var target string
+		if err := rows.Scan(&target); err != nil {
+			return nil, fmt.Errorf("scan webmention target: %w", err)
+		}
+		targets = append(targets, target)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webmention targets: %w", err)
+	}
+
+	return targets, nil
+}
+
+// SetTargets replaces the URLs a blog links to
+func (r *WebmentionRepository) SetTargets(blogID int64, targets []string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	tx, err := r.db.Client.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		DELETE FROM webmention_targets WHERE blog_id = $1 AND target <> ALL($2::TEXT[])
+	`, blogID, pq.Array(targets))
+	if err != nil {
+		return fmt.Errorf("delete stale webmention targets: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO webmention_targets (blog_id, target)
+		SELECT $1, target FROM UNNEST($2::TEXT[]) AS target
+		ON CONFLICT (blog_id, target) DO NOTHING
+	`, blogID, pq.Array(targets))
+	if err != nil {
+		return fmt.Errorf("insert webmention targets: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit webmention targets: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package federation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/pkg/activitypub"
+	"AbdelrahmanDwedar/blogo/pkg/httpsig"
+	"AbdelrahmanDwedar/blogo/pkg/safehttp"
+)
+
+const (
+	// requestTimeout bounds every request to a remote server
+	requestTimeout = 10 * time.Second
+
+	// maxDocumentSize is the largest actor document read from a remote
+	// server
+	maxDocumentSize = 1 << 20
+
+	// userAgent identifies blogo to remote servers
+	userAgent = "blogo (+https://github.com/AbdelrahmanDwedar/blogo)"
+)
+
+// Client implements service.FederationClient over HTTP
+type Client struct {
+	http *http.Client
+}
+
+// NewClient creates a new federation client. Actor and inbox URLs come
+// from unauthenticated requests, so it only reaches public addresses
+// unless allowPrivate is set for local testing.
+func NewClient(allowPrivate bool) *Client {
+	return &Client{http: safehttp.NewClient(safehttp.Options{
+		Timeout:      requestTimeout,
+		AllowPrivate: allowPrivate,
+	})}
+}
+
+// FetchActor retrieves a remote actor by its IRI. Key IDs may be passed
+// as well, since their fragment is dropped.
+func (c *Client) FetchActor(iri string) (*entity.RemoteActor, error) {
+	u, err := url.Parse(iri)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, entity.ErrRemoteActorNotFound
+	}
+	u.Fragment = ""
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("build actor request: %w", err)
+	}
+	req.Header.Set("Accept", activitypub.ContentType+", "+activitypub.LDContentType)
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch actor: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return nil, entity.ErrRemoteActorNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("fetch actor: %s", resp.Status)
+	}
+
+	var actor activitypub.Actor
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxDocumentSize)).Decode(&actor); err != nil {
+		return nil, fmt.Errorf("decode actor: %w", err)
+	}
+	if actor.ID == "" || actor.Inbox == "" || actor.PublicKey == nil {
+		return nil, entity.ErrRemoteActorNotFound
+	}
+
+	return &entity.RemoteActor{
+		ID:           actor.ID,
+		Inbox:        actor.Inbox,
+		SharedInbox:  actor.SharedInbox(),
+		PublicKeyID:  actor.PublicKey.ID,
+		PublicKeyPEM: actor.PublicKey.PublicKeyPem,
+		FetchedAt:    time.Now(),
+	}, nil
+}
+
+// Deliver posts a signed activity to a remote inbox. Client errors other
+// than timeouts and rate limiting mean the server will never accept the
+// activity and are reported as ErrDeliveryRejected.
+func (c *Client) Deliver(inbox string, activity []byte, keyID, privateKeyPEM string) error {
+	key, err := httpsig.ParsePrivateKey(privateKeyPEM)
+	if err != nil {
+		return fmt.Errorf("parse actor key: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, inbox, bytes.NewReader(activity))
+	if err != nil {
+		return fmt.Errorf("%w: %v", entity.ErrDeliveryRejected, err)
+	}
+	req.Header.Set("Content-Type", activitypub.ContentType)
+	req.Header.Set("User-Agent", userAgent)
+	if err := httpsig.Sign(req, activity, keyID, key); err != nil {
+		return err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("deliver activity: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxDocumentSize))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", entity.ErrDeliveryRejected, resp.Status)
+	default:
+		return fmt.Errorf("deliver activity: %s", resp.Status)
+	}
+}
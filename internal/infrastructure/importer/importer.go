@@ -0,0 +1,106 @@
+package importer
+
+import (
+	"errors"
+	"path"
+	"strings"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// Supported import formats
+const (
+	FormatWXR      = "wxr"
+	FormatMarkdown = "markdown"
+	FormatJSON     = "json"
+)
+
+// DetectFormat guesses the import format from a file name, returning an
+// empty string when it cannot tell
+func DetectFormat(name string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".xml", ".wxr":
+		return FormatWXR
+	case ".md", ".markdown", ".zip":
+		return FormatMarkdown
+	case ".json":
+		return FormatJSON
+	}
+	return ""
+}
+
+// Parse reads posts from a single uploaded file. Markdown input may be one
+// .md file or a .zip archive of them.
+func Parse(format, name string, data []byte) ([]*entity.ImportItem, error) {
+	switch format {
+	case FormatWXR:
+		return ParseWXR(data)
+	case FormatMarkdown:
+		if strings.EqualFold(path.Ext(name), ".zip") {
+			return ParseMarkdownZip(data)
+		}
+		return []*entity.ImportItem{ParseMarkdown(name, data)}, nil
+	case FormatJSON:
+		return ParseJSON(data)
+	}
+	return nil, entity.ErrUnknownImportFormat
+}
+
+// Parser implements service.ImportParser
+type Parser struct{}
+
+// NewParser creates a new import parser
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// Parse reads posts from an uploaded file, detecting its format from its
+// name when none is given
+func (p *Parser) Parse(format, name string, data []byte) ([]*entity.ImportItem, error) {
+	if format == "" {
+		format = DetectFormat(name)
+	}
+	return Parse(format, name, data)
+}
+
+// newBlog builds an unsaved blog from imported fields. The author is left
+// unset for the caller to fill in.
+func newBlog(title, description, body, slug string, tags []string, createdAt, updatedAt time.Time) *entity.Blog {
+	blog := entity.NewBlog(strings.TrimSpace(title), strings.TrimSpace(description), strings.TrimSpace(body), 0)
+	blog.SetSlug(slug)
+	blog.SetTags(tags)
+
+	if !createdAt.IsZero() {
+		blog.CreatedAt = createdAt
+		blog.UpdatedAt = createdAt
+	}
+	if !updatedAt.IsZero() && updatedAt.After(blog.CreatedAt) {
+		blog.UpdatedAt = updatedAt
+	}
+	return blog
+}
+
+// dateLayouts are the timestamp formats accepted in front-matter and JSON
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// parseDate parses a timestamp in any of dateLayouts; values without a
+// zone are taken as UTC
+func parseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("unrecognized date " + value)
+}
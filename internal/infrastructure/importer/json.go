@@ -0,0 +1,72 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// jsonPost is one post in a JSON import, using the same field names as the
+// blog API
+type jsonPost struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Body        string   `json:"body"`
+	Slug        string   `json:"slug"`
+	Tags        []string `json:"tags"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+}
+
+// ParseJSON reads posts from either a JSON array or an object with a
+// "posts" array
+func ParseJSON(data []byte) ([]*entity.ImportItem, error) {
+	var raw []json.RawMessage
+
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var wrapper struct {
+			Posts []json.RawMessage `json:"posts"`
+		}
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return nil, fmt.Errorf("parse json: %w", err)
+		}
+		raw = wrapper.Posts
+	} else if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse json: %w", err)
+	}
+
+	items := make([]*entity.ImportItem, 0, len(raw))
+	for i, message := range raw {
+		item := &entity.ImportItem{Source: fmt.Sprintf("post %d", i+1)}
+		items = append(items, item)
+
+		var post jsonPost
+		if err := json.Unmarshal(message, &post); err != nil {
+			item.Err = fmt.Errorf("invalid post: %w", err)
+			continue
+		}
+
+		var createdAt, updatedAt time.Time
+		var err error
+		if post.CreatedAt != "" {
+			if createdAt, err = parseDate(post.CreatedAt); err != nil {
+				item.Err = err
+				continue
+			}
+		}
+		if post.UpdatedAt != "" {
+			if updatedAt, err = parseDate(post.UpdatedAt); err != nil {
+				item.Err = err
+				continue
+			}
+		}
+
+		item.Blog = newBlog(post.Title, post.Description, post.Body, post.Slug, post.Tags, createdAt, updatedAt)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,374 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+const (
+	// maxZipEntries caps how many entries a ZIP archive may hold
+	maxZipEntries = 2000
+
+	// maxZipFileSize caps the uncompressed size of a Markdown file in a
+	// ZIP archive
+	maxZipFileSize = 2 << 20
+
+	// maxZipTotalSize caps the uncompressed size of all Markdown files in a
+	// ZIP archive, which may be far larger than the upload itself
+	maxZipTotalSize = 64 << 20
+)
+
+// datePrefix matches Jekyll-style "2024-01-31-" file name prefixes
+var datePrefix = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})-`)
+
+// ParseMarkdown reads a single Markdown file with optional YAML
+// front-matter. Missing slugs and dates fall back to the file name.
+func ParseMarkdown(name string, data []byte) *entity.ImportItem {
+	item := &entity.ImportItem{Source: name}
+
+	meta, body, err := splitFrontMatter(data)
+	if err != nil {
+		item.Err = err
+		return item
+	}
+
+	if draft := meta.scalar("draft"); draft == "true" || draft == "yes" {
+		item.Err = errors.New("skipped draft post")
+		return item
+	}
+
+	title := meta.scalar("title")
+	if title == "" {
+		title, body = takeHeading(body)
+	}
+
+	base := strings.TrimSuffix(path.Base(name), path.Ext(name))
+	slug := meta.scalar("slug")
+	var fileDate string
+	if m := datePrefix.FindStringSubmatch(base); m != nil {
+		fileDate = m[1]
+		base = strings.TrimPrefix(base, m[0])
+	}
+	if slug == "" {
+		slug = base
+	}
+
+	createdAt, err := meta.date(fileDate, "date", "published", "created")
+	if err != nil {
+		item.Err = err
+		return item
+	}
+	updatedAt, err := meta.date("", "updated", "lastmod", "modified")
+	if err != nil {
+		item.Err = err
+		return item
+	}
+
+	tags := append(meta.list("tags"), meta.list("categories")...)
+	description := meta.first("description", "summary", "excerpt")
+
+	item.Blog = newBlog(title, description, body, slug, tags, createdAt, updatedAt)
+	return item
+}
+
+// ParseMarkdownZip reads every Markdown file in a ZIP archive. Archives
+// with more than maxZipEntries entries, or whose Markdown files decompress
+// to more than maxZipTotalSize, are refused with ErrImportTooLarge; files
+// over maxZipFileSize fail on their own.
+func ParseMarkdownZip(data []byte) ([]*entity.ImportItem, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("open zip: %w", err)
+	}
+	if len(archive.File) > maxZipEntries {
+		return nil, fmt.Errorf("%w: more than %d entries", entity.ErrImportTooLarge, maxZipEntries)
+	}
+
+	files := []*zip.File{}
+	for _, f := range archive.File {
+		if !f.FileInfo().IsDir() && isMarkdown(f.Name) && !strings.HasPrefix(f.Name, "__MACOSX/") {
+			files = append(files, f)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	items := []*entity.ImportItem{}
+	var total int64
+	for _, f := range files {
+		content, err := readZipFile(f)
+		total += int64(len(content))
+		if total > maxZipTotalSize {
+			return nil, fmt.Errorf("%w: more than %d bytes uncompressed", entity.ErrImportTooLarge, maxZipTotalSize)
+		}
+		if err != nil {
+			items = append(items, &entity.ImportItem{Source: f.Name, Err: err})
+			continue
+		}
+		items = append(items, ParseMarkdown(f.Name, content))
+	}
+	return items, nil
+}
+
+// ParseMarkdownDir reads every Markdown file below the root of fsys
+func ParseMarkdownDir(fsys fs.FS) ([]*entity.ImportItem, error) {
+	items := []*entity.ImportItem{}
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isMarkdown(name) {
+			return nil
+		}
+
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			items = append(items, &entity.ImportItem{Source: name, Err: err})
+			return nil
+		}
+		items = append(items, ParseMarkdown(name, content))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk markdown directory: %w", err)
+	}
+	return items, nil
+}
+
+func isMarkdown(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".md" || ext == ".markdown"
+}
+
+// readZipFile reads a file from a ZIP archive, up to maxZipFileSize. The
+// sizes the archive declares are not trusted.
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(io.LimitReader(rc, maxZipFileSize+1))
+	if err != nil {
+		return content, fmt.Errorf("read %s: %w", f.Name, err)
+	}
+	if len(content) > maxZipFileSize {
+		return content, fmt.Errorf("%s is larger than %d bytes uncompressed", f.Name, maxZipFileSize)
+	}
+	return content, nil
+}
+
+// takeHeading uses a leading "# Heading" line as the title when the
+// front-matter has none
+func takeHeading(body string) (title, rest string) {
+	trimmed := strings.TrimLeft(body, "\n")
+	if !strings.HasPrefix(trimmed, "# ") {
+		return "", body
+	}
+	line, rest, _ := strings.Cut(trimmed, "\n")
+	return strings.TrimSpace(strings.TrimPrefix(line, "# ")), rest
+}
+
+// frontMatter holds front-matter values; each key maps to a scalar string
+// or a []string list
+type frontMatter map[string]interface{}
+
+func (m frontMatter) scalar(key string) string {
+	if v, ok := m[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+// first returns the first non-empty scalar among keys
+func (m frontMatter) first(keys ...string) string {
+	for _, key := range keys {
+		if v := m.scalar(key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// list returns a list value; scalars are treated as comma-separated lists
+func (m frontMatter) list(key string) []string {
+	switch v := m[key].(type) {
+	case []string:
+		return v
+	case string:
+		if v == "" {
+			return nil
+		}
+		return strings.Split(v, ",")
+	}
+	return nil
+}
+
+// date parses the first present key as a date, using fallback when none
+// is set
+func (m frontMatter) date(fallback string, keys ...string) (time.Time, error) {
+	value := m.first(keys...)
+	if value == "" {
+		value = fallback
+	}
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return parseDate(value)
+}
+
+// splitFrontMatter separates a "---" delimited YAML header from the body.
+// Only the subset of YAML used by static site generators is understood:
+// scalars, quoted strings, inline [a, b] lists, "- item" block lists and
+// "|" / ">" block scalars.
+func splitFrontMatter(data []byte) (frontMatter, string, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.TrimPrefix(text, "\ufeff")
+
+	meta := frontMatter{}
+	if !strings.HasPrefix(text, "---\n") {
+		return meta, text, nil
+	}
+
+	lines := strings.Split(strings.TrimPrefix(text, "---\n"), "\n")
+	end := -1
+	for i, line := range lines {
+		if line == "---" || line == "..." {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return nil, "", errors.New("unterminated front-matter")
+	}
+
+	header := lines[:end]
+	for i := 0; i < len(header); i++ {
+		line := header[i]
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if indented(line) {
+			return nil, "", fmt.Errorf("front-matter line %d: unexpected indentation", i+2)
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, "", fmt.Errorf("front-matter line %d: expected key: value", i+2)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch {
+		case value == "":
+			// A block list's items may sit at the key's own indentation,
+			// as go-yaml and Hugo write them
+			list := []string{}
+			for i+1 < len(header) && (indented(header[i+1]) || isListItem(header[i+1])) {
+				i++
+				item := strings.TrimSpace(header[i])
+				if !isListItem(item) {
+					return nil, "", fmt.Errorf("front-matter key %q: expected list item", key)
+				}
+				list = append(list, unquote(strings.TrimSpace(strings.TrimPrefix(item, "-"))))
+			}
+			if len(list) > 0 {
+				meta[key] = list
+			} else {
+				meta[key] = ""
+			}
+		case strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">"):
+			// Blank lines belong to a block scalar as long as indented
+			// lines follow them
+			var block []string
+			for j := i + 1; j < len(header) && (indented(header[j]) || strings.TrimSpace(header[j]) == ""); j++ {
+				if strings.TrimSpace(header[j]) != "" {
+					block = append(block, header[i+1:j+1]...)
+					i = j
+				}
+			}
+			meta[key] = blockScalar(block, value[0] == '>')
+		case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+			list := []string{}
+			for _, v := range strings.Split(value[1:len(value)-1], ",") {
+				if v = unquote(strings.TrimSpace(v)); v != "" {
+					list = append(list, v)
+				}
+			}
+			meta[key] = list
+			skipIndented(header, &i)
+		default:
+			meta[key] = unquote(value)
+			skipIndented(header, &i)
+		}
+	}
+
+	return meta, strings.Join(lines[end+1:], "\n"), nil
+}
+
+// indented checks if a front-matter line starts with whitespace
+func indented(line string) bool {
+	return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+}
+
+// isListItem checks if a trimmed front-matter line is a block list item
+func isListItem(line string) bool {
+	return strings.HasPrefix(line, "- ") || line == "-"
+}
+
+// skipIndented moves i past the indented lines that continue a scalar
+func skipIndented(header []string, i *int) {
+	for *i+1 < len(header) && indented(header[*i+1]) {
+		*i++
+	}
+}
+
+// blockScalar joins the lines of a "|" block, keeping line breaks, or of a
+// ">" block, folding lines into spaces and blank lines into line breaks
+func blockScalar(lines []string, folded bool) string {
+	var b strings.Builder
+	blank := false
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case i == 0:
+		case !folded || line == "":
+			b.WriteByte('\n')
+		case !blank:
+			b.WriteByte(' ')
+		}
+		b.WriteString(line)
+		blank = line == ""
+	}
+	return b.String()
+}
+
+// unquote strips YAML quoting from a scalar, or a trailing comment from an
+// unquoted one
+func unquote(value string) string {
+	switch {
+	case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+		if s, err := strconv.Unquote(value); err == nil {
+			return s
+		}
+		return value[1 : len(value)-1]
+	case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return value
+}
@@ -0,0 +1,126 @@
+package importer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitFrontMatter(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantMeta frontMatter
+		wantBody string
+		wantErr  bool
+	}{
+		{
+			name:     "no front-matter",
+			input:    "# Title\n\nBody",
+			wantMeta: frontMatter{},
+			wantBody: "# Title\n\nBody",
+		},
+		{
+			name:     "plain scalars and crlf",
+			input:    "---\r\ntitle: Hello world\r\ndate: 2023-04-05 # published\r\n---\r\nBody",
+			wantMeta: frontMatter{"title": "Hello world", "date": "2023-04-05"},
+			wantBody: "Body",
+		},
+		{
+			name:  "quoted values",
+			input: "---\ntitle: \"Go: a \\\"love\\\" story\"\nslug: 'it''s-here'\nsummary: \"# not a comment\"\n---\n",
+			wantMeta: frontMatter{
+				"title":   `Go: a "love" story`,
+				"slug":    "it's-here",
+				"summary": "# not a comment",
+			},
+			wantBody: "",
+		},
+		{
+			name:     "inline list",
+			input:    "---\ntags: [go, \"web dev\", 'api', ]\n---\nBody",
+			wantMeta: frontMatter{"tags": []string{"go", "web dev", "api"}},
+			wantBody: "Body",
+		},
+		{
+			name:     "indented block list",
+			input:    "---\ntags:\n  - go\n  - \"web\"\ntitle: Post\n---\nBody",
+			wantMeta: frontMatter{"tags": []string{"go", "web"}, "title": "Post"},
+			wantBody: "Body",
+		},
+		{
+			name:     "block list at the key's indentation",
+			input:    "---\ntags:\n- go\n- web\ntitle: Post\n---\nBody",
+			wantMeta: frontMatter{"tags": []string{"go", "web"}, "title": "Post"},
+			wantBody: "Body",
+		},
+		{
+			name:     "empty value",
+			input:    "---\ndescription:\ntitle: Post\n---\n",
+			wantMeta: frontMatter{"description": "", "title": "Post"},
+		},
+		{
+			name:     "literal block with a blank line",
+			input:    "---\ndescription: |\n  Para one.\n\n  Para two.\ntitle: Post\n---\nBody",
+			wantMeta: frontMatter{"description": "Para one.\n\nPara two.", "title": "Post"},
+			wantBody: "Body",
+		},
+		{
+			name:     "folded block with a blank line",
+			input:    "---\ndescription: >\n  Line one\n  line two.\n\n  Para two.\n\ntitle: Post\n---\n",
+			wantMeta: frontMatter{"description": "Line one line two.\nPara two.", "title": "Post"},
+		},
+		{
+			name:     "draft",
+			input:    "---\ntitle: Soon\ndraft: true\n---\nBody",
+			wantMeta: frontMatter{"title": "Soon", "draft": "true"},
+			wantBody: "Body",
+		},
+		{
+			name:     "keys are lowercased and comments skipped",
+			input:    "---\n# generated\nTitle: Post\n\n---\nBody",
+			wantMeta: frontMatter{"title": "Post"},
+			wantBody: "Body",
+		},
+		{
+			name:    "unterminated",
+			input:   "---\ntitle: Post\nBody",
+			wantErr: true,
+		},
+		{
+			name:    "line without a key",
+			input:   "---\ntitle: Post\njust text\n---\n",
+			wantErr: true,
+		},
+		{
+			name:    "unexpected indentation",
+			input:   "---\n  title: Post\n---\n",
+			wantErr: true,
+		},
+		{
+			name:    "mapping under a key",
+			input:   "---\nauthor:\n  name: Alice\n---\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta, body, err := splitFrontMatter([]byte(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("splitFrontMatter succeeded with %v, want an error", meta)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("splitFrontMatter: %v", err)
+			}
+			if !reflect.DeepEqual(meta, tt.wantMeta) {
+				t.Errorf("meta = %#v, want %#v", meta, tt.wantMeta)
+			}
+			if body != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// wxrDocument is the subset of a WordPress eXtended RSS export we read
+type wxrDocument struct {
+	Channel struct {
+		Items []wxrItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type wxrItem struct {
+	Title       string        `xml:"title"`
+	PubDate     string        `xml:"pubDate"`
+	Description string        `xml:"description"`
+	Categories  []wxrCategory `xml:"category"`
+
+	// Namespaced fields (content:, excerpt:, wp:) are matched by local name
+	// because the wp namespace URI changes between WXR versions
+	Fields []wxrField `xml:",any"`
+}
+
+type wxrCategory struct {
+	Domain string `xml:"domain,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type wxrField struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+// field returns the first namespaced field with the given local name whose
+// namespace URI contains ns
+func (it *wxrItem) field(ns, local string) string {
+	for _, f := range it.Fields {
+		if f.XMLName.Local == local && strings.Contains(f.XMLName.Space, ns) {
+			return strings.TrimSpace(f.Value)
+		}
+	}
+	return ""
+}
+
+// ParseWXR reads the posts of a WordPress WXR export. Pages, attachments
+// and other non-post items are ignored; unpublished posts are reported as
+// failed items.
+func ParseWXR(data []byte) ([]*entity.ImportItem, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+
+	var doc wxrDocument
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("parse wxr: %w", err)
+	}
+
+	items := []*entity.ImportItem{}
+	for i := range doc.Channel.Items {
+		it := &doc.Channel.Items[i]
+
+		postType := it.field("wordpress.org/export", "post_type")
+		if postType != "" && postType != "post" {
+			continue
+		}
+
+		source := fmt.Sprintf("item %d", i+1)
+		if id := it.field("wordpress.org/export", "post_id"); id != "" {
+			source = "post " + id
+		}
+
+		if status := it.field("wordpress.org/export", "status"); status != "" && status != "publish" {
+			items = append(items, &entity.ImportItem{
+				Source: source,
+				Err:    fmt.Errorf("skipped %s post %q", status, it.Title),
+			})
+			continue
+		}
+
+		description := it.field("/excerpt/", "encoded")
+		if description == "" {
+			description = it.Description
+		}
+
+		tags := []string{}
+		for _, c := range it.Categories {
+			if c.Domain == "post_tag" || c.Domain == "category" {
+				tags = append(tags, c.Value)
+			}
+		}
+
+		createdAt := parseWPDate(it.field("wordpress.org/export", "post_date_gmt"))
+		if createdAt.IsZero() && it.PubDate != "" {
+			createdAt, _ = parseDate(it.PubDate)
+		}
+		updatedAt := parseWPDate(it.field("wordpress.org/export", "post_modified_gmt"))
+
+		items = append(items, &entity.ImportItem{
+			Source: source,
+			Blog: newBlog(it.Title, description, it.field("/content/", "encoded"),
+				it.field("wordpress.org/export", "post_name"), tags, createdAt, updatedAt),
+		})
+	}
+
+	return items, nil
+}
+
+// parseWPDate parses WordPress' GMT timestamps, which use a zero date for
+// posts that were never published
+func parseWPDate(value string) time.Time {
+	if value == "" || strings.HasPrefix(value, "0000-00-00") {
+		return time.Time{}
+	}
+	t, err := time.Parse("2006-01-02 15:04:05", value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
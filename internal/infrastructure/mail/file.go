@@ -0,0 +1,50 @@
+package mail
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// FileMailer writes every message as an .eml file into a directory instead
+// of sending it, for local development
+type FileMailer struct {
+	dir  string
+	from *mail.Address
+}
+
+// NewFileMailer creates a mailer writing messages from from into dir
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("parse sender address: %w", err)
+	}
+	return &FileMailer{dir: dir, from: sender}, nil
+}
+
+// Send writes a message into the directory, creating it if needed
+func (m *FileMailer) Send(message *entity.EmailMessage) error {
+	now := time.Now()
+	data, err := encode(m.from, message, now)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("create mail directory: %w", err)
+	}
+
+	random := make([]byte, 4)
+	rand.Read(random)
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405.000000000"), hex.EncodeToString(random))
+	if err := os.WriteFile(filepath.Join(m.dir, name), data, 0o644); err != nil {
+		return fmt.Errorf("write mail: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// encode renders a message as an RFC 5322 email from the given sender,
+// with its text and HTML bodies as alternatives
+func encode(from *mail.Address, message *entity.EmailMessage, now time.Time) ([]byte, error) {
+	headers := map[string]string{
+		"From":         from.String(),
+		"To":           message.To,
+		"Subject":      mime.QEncoding.Encode("utf-8", message.Subject),
+		"Date":         now.Format(time.RFC1123Z),
+		"Message-ID":   messageID(from),
+		"MIME-Version": "1.0",
+	}
+	for name, value := range message.Headers {
+		headers[textproto.CanonicalMIMEHeaderKey(name)] = value
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	headers["Content-Type"] = "multipart/alternative; boundary=" + parts.Boundary()
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", message.Text},
+		{"text/html; charset=utf-8", message.HTML},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create message part: %w", err)
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, fmt.Errorf("write message part: %w", err)
+		}
+		if err := qp.Close(); err != nil {
+			return nil, fmt.Errorf("write message part: %w", err)
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, fmt.Errorf("finish message: %w", err)
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var out bytes.Buffer
+	for _, name := range names {
+		// Line breaks in a value would start new headers
+		if strings.ContainsAny(headers[name], "\r\n") {
+			return nil, fmt.Errorf("header %s contains a line break", name)
+		}
+		fmt.Fprintf(&out, "%s: %s\r\n", name, headers[name])
+	}
+	out.WriteString("\r\n")
+	out.Write(body.Bytes())
+	return out.Bytes(), nil
+}
+
+// messageID returns a new unique Message-ID in the sender's domain
+func messageID(from *mail.Address) string {
+	random := make([]byte, 12)
+	rand.Read(random)
+
+	domain := "localhost"
+	if i := strings.LastIndexByte(from.Address, '@'); i >= 0 {
+		domain = from.Address[i+1:]
+	}
+	return "<" + hex.EncodeToString(random) + "@" + domain + ">"
+}
@@ -0,0 +1,182 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// Renderer renders newsletter, digest and notification emails from the
+// embedded templates, each with a plain-text and an HTML body
+type Renderer struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// NewRenderer creates a renderer from the embedded templates
+func NewRenderer() *Renderer {
+	funcs := map[string]interface{}{
+		"name":   authorName,
+		"period": digestPeriod,
+		"more":   moreFollowers,
+	}
+	htmlFuncs := htmltemplate.FuncMap{"body": renderBody}
+	for name, fn := range funcs {
+		htmlFuncs[name] = fn
+	}
+
+	return &Renderer{
+		text: texttemplate.Must(texttemplate.New("").Funcs(funcs).ParseFS(templateFS, "templates/*.txt")),
+		html: htmltemplate.Must(htmltemplate.New("").Funcs(htmlFuncs).ParseFS(templateFS, "templates/*.html")),
+	}
+}
+
+// emailData is passed to every template
+type emailData struct {
+	Author         string
+	Blog           *entity.Blog
+	BlogURL        string
+	ConfirmURL     string
+	UnsubscribeURL string
+	Digest         *entity.Digest
+	Recipient      *entity.User
+	Notification   *entity.Notification
+	Comment        *entity.Comment
+	URL            string
+}
+
+// Confirmation renders the email asking to confirm a subscription
+func (r *Renderer) Confirmation(author *entity.User, confirmURL string) (*entity.EmailMessage, error) {
+	data := emailData{Author: authorName(author), ConfirmURL: confirmURL}
+	return r.render("confirm", "Confirm your subscription to "+data.Author, data)
+}
+
+// NewPost renders the email announcing a new blog, with a one-click
+// unsubscribe header (RFC 8058)
+func (r *Renderer) NewPost(author *entity.User, blog *entity.Blog, blogURL, unsubscribeURL string) (*entity.EmailMessage, error) {
+	data := emailData{
+		Author:         authorName(author),
+		Blog:           blog,
+		BlogURL:        blogURL,
+		UnsubscribeURL: unsubscribeURL,
+	}
+	message, err := r.render("post", blog.Title, data)
+	if err != nil {
+		return nil, err
+	}
+
+	message.Headers = map[string]string{
+		"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}
+	return message, nil
+}
+
+// Digest renders a digest email with a one-click unsubscribe header
+// (RFC 8058) that turns digests off
+func (r *Renderer) Digest(digest *entity.Digest, unsubscribeURL string) (*entity.EmailMessage, error) {
+	subject := "Your daily Blogo digest"
+	if digest.Frequency == entity.DigestWeekly {
+		subject = "Your weekly Blogo digest"
+	}
+
+	message, err := r.render("digest", subject, emailData{Digest: digest, UnsubscribeURL: unsubscribeURL})
+	if err != nil {
+		return nil, err
+	}
+
+	message.Headers = map[string]string{
+		"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}
+	return message, nil
+}
+
+// Notification renders the email for a notification, with a one-click
+// unsubscribe header (RFC 8058) that turns off emails of its type
+func (r *Renderer) Notification(recipient *entity.User, notification *entity.Notification, blog *entity.Blog,
+	comment *entity.Comment, url, unsubscribeURL string) (*entity.EmailMessage, error) {
+	data := emailData{
+		Recipient:      recipient,
+		Notification:   notification,
+		Blog:           blog,
+		Comment:        comment,
+		URL:            url,
+		UnsubscribeURL: unsubscribeURL,
+	}
+	message, err := r.render("notification", authorName(notification.Actor)+" "+notification.Action(), data)
+	if err != nil {
+		return nil, err
+	}
+
+	message.Headers = map[string]string{
+		"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}
+	return message, nil
+}
+
+// render executes the text and HTML templates of an email
+func (r *Renderer) render(name, subject string, data emailData) (*entity.EmailMessage, error) {
+	var text, body bytes.Buffer
+	if err := r.text.ExecuteTemplate(&text, name+".txt", data); err != nil {
+		return nil, fmt.Errorf("render %s email: %w", name, err)
+	}
+	if err := r.html.ExecuteTemplate(&body, name+".html", data); err != nil {
+		return nil, fmt.Errorf("render %s email: %w", name, err)
+	}
+
+	return &entity.EmailMessage{
+		Subject: subject,
+		Text:    text.String(),
+		HTML:    body.String(),
+	}, nil
+}
+
+// authorName is how a user is named in emails
+func authorName(author *entity.User) string {
+	if author.DisplayName != "" {
+		return author.DisplayName
+	}
+	return author.Username
+}
+
+// digestPeriod describes the period a digest covers
+func digestPeriod(digest *entity.Digest) string {
+	if digest.Frequency == entity.DigestWeekly {
+		last := digest.PeriodEnd.AddDate(0, 0, -1)
+		return "from " + digest.PeriodStart.Format("January 2") + " to " + last.Format("January 2")
+	}
+	return "on " + digest.PeriodStart.Format("Monday, January 2")
+}
+
+// moreFollowers counts the new followers a digest does not list by name
+func moreFollowers(digest *entity.Digest) int {
+	return digest.NewFollowersCount - len(digest.NewFollowers)
+}
+
+// renderBody turns a plain-text blog body into escaped HTML paragraphs
+func renderBody(blog *entity.Blog) htmltemplate.HTML {
+	var b strings.Builder
+	for _, paragraph := range strings.Split(strings.ReplaceAll(blog.Body, "\r\n", "\n"), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		lines := strings.Split(paragraph, "\n")
+		for i, line := range lines {
+			lines[i] = html.EscapeString(line)
+		}
+		b.WriteString("<p>" + strings.Join(lines, "<br>\n") + "</p>\n")
+	}
+	return htmltemplate.HTML(b.String())
+}
@@ -0,0 +1,65 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// SMTPMailer sends email through an SMTP server, upgrading the connection
+// with STARTTLS when the server offers it
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from *mail.Address
+}
+
+// NewSMTPMailer creates a mailer sending through the server at host:port
+// as from. username and password are only used when username is set.
+func NewSMTPMailer(host string, port int, username, password, from string) (*SMTPMailer, error) {
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("parse sender address: %w", err)
+	}
+
+	mailer := &SMTPMailer{
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		from: sender,
+	}
+	if username != "" {
+		mailer.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return mailer, nil
+}
+
+// Send delivers a message. Permanent (5xx) refusals are reported as
+// ErrRecipientRejected when they concern the mailbox and ErrMailRejected
+// otherwise.
+func (m *SMTPMailer) Send(message *entity.EmailMessage) error {
+	data, err := encode(m.from, message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	err = smtp.SendMail(m.addr, m.auth, m.from.Address, []string{message.To}, data)
+	var reply *textproto.Error
+	if errors.As(err, &reply) && reply.Code >= 500 {
+		switch reply.Code {
+		// Mailbox unavailable, user not local, mailbox name not allowed
+		case 550, 551, 553:
+			return fmt.Errorf("%w: %v", entity.ErrRecipientRejected, err)
+		}
+		return fmt.Errorf("%w: %v", entity.ErrMailRejected, err)
+	}
+	if err != nil {
+		return fmt.Errorf("send mail: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package media
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// DirStore keeps media files in a directory on disk
+type DirStore struct {
+	dir string
+}
+
+// NewDirStore creates a media store in dir
+func NewDirStore(dir string) *DirStore {
+	return &DirStore{dir: dir}
+}
+
+// Save writes a media file into the directory, creating it if needed
+func (s *DirStore) Save(file *entity.MediaFile) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("create media directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.dir, filepath.Base(file.Name)), file.Data, 0o644); err != nil {
+		return fmt.Errorf("write media file: %w", err)
+	}
+	return nil
+}
+
+// Open opens a media file in the directory
+func (s *DirStore) Open(name string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(s.dir, filepath.Base(name)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, entity.ErrMediaNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open media file: %w", err)
+	}
+	return f, nil
+}
@@ -0,0 +1,118 @@
+package push
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/pkg/safehttp"
+	"AbdelrahmanDwedar/blogo/pkg/webpush"
+)
+
+const (
+	// requestTimeout bounds every request to a push service
+	requestTimeout = 10 * time.Second
+
+	// messageTTL is how long a push service keeps a message for a device
+	// that is offline
+	messageTTL = 24 * time.Hour
+
+	// vapidLifetime is how long the VAPID token of a push is valid for
+	vapidLifetime = 12 * time.Hour
+
+	// maxResponseSize is the most of a push service's response read
+	maxResponseSize = 64 << 10
+
+	// userAgent identifies blogo to push services
+	userAgent = "blogo (+https://github.com/AbdelrahmanDwedar/blogo)"
+)
+
+// Client implements service.PushSender over HTTP
+type Client struct {
+	http    *http.Client
+	subject string
+}
+
+// NewClient creates a new push client. subject is the mailto: or https:
+// URL push services can reach this server's operator at. Endpoints are
+// given by users, so pushes are only sent over https to public addresses
+// unless allowPrivate is set for local testing.
+func NewClient(subject string, allowPrivate bool) *Client {
+	return &Client{
+		http: safehttp.NewClient(safehttp.Options{
+			Timeout:      requestTimeout,
+			AllowPrivate: allowPrivate,
+			HTTPSOnly:    true,
+		}),
+		subject: subject,
+	}
+}
+
+// Send encrypts a payload for a subscription and posts it to its push
+// service. Not found and gone mean the subscription expired; other client
+// errors than timeouts and rate limiting are reported as ErrPushRejected.
+func (c *Client) Send(subscription *entity.PushSubscription, payload []byte, vapidPrivateKey string) error {
+	key, err := webpush.ParsePrivateKey(vapidPrivateKey)
+	if err != nil {
+		return fmt.Errorf("parse vapid key: %w", err)
+	}
+
+	p256dh, err := webpush.DecodeKey(subscription.P256dh)
+	if err != nil {
+		return fmt.Errorf("%w: %v", entity.ErrPushRejected, err)
+	}
+	auth, err := webpush.DecodeKey(subscription.Auth)
+	if err != nil {
+		return fmt.Errorf("%w: %v", entity.ErrPushRejected, err)
+	}
+	body, err := webpush.Encrypt(payload, p256dh, auth)
+	if errors.Is(err, webpush.ErrInvalidKey) || errors.Is(err, webpush.ErrPayloadTooLarge) {
+		return fmt.Errorf("%w: %v", entity.ErrPushRejected, err)
+	}
+	if err != nil {
+		return fmt.Errorf("encrypt push: %w", err)
+	}
+
+	authorization, err := webpush.VAPIDHeader(subscription.Endpoint, c.subject, key, time.Now().Add(vapidLifetime))
+	if err != nil {
+		return fmt.Errorf("%w: %v", entity.ErrPushRejected, err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, subscription.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", entity.ErrPushRejected, err)
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(int(messageTTL.Seconds())))
+	req.Header.Set("Urgency", "normal")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.http.Do(req)
+	if errors.Is(err, safehttp.ErrForbiddenAddress) || errors.Is(err, safehttp.ErrInsecureURL) {
+		return fmt.Errorf("%w: %v", entity.ErrPushRejected, err)
+	}
+	if err != nil {
+		return fmt.Errorf("send push: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseSize))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return fmt.Errorf("%w: %s", entity.ErrPushSubscriptionGone, resp.Status)
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", entity.ErrPushRejected, resp.Status)
+	default:
+		return fmt.Errorf("send push: %s", resp.Status)
+	}
+}
@@ -0,0 +1,129 @@
+package realtime
+
+import (
+	"sync"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+const (
+	// eventBacklog is how many recent events are kept per user for
+	// clients resuming with Last-Event-ID
+	eventBacklog = 100
+
+	// subscriberBuffer is how many live events a subscriber may fall
+	// behind before its stream is closed
+	subscriberBuffer = 64
+)
+
+// subscriber is one open stream of a user's events
+type subscriber struct {
+	ch chan *entity.Event
+
+	// replayed holds the IDs of backlog events already delivered, so the
+	// same events arriving live are skipped
+	replayed map[string]bool
+
+	// holding is set while the subscriber's backlog is read; live events
+	// are kept in held until it is replayed
+	holding bool
+	held    []*entity.Event
+}
+
+// hub fans events out to the subscribers connected to this process
+type hub struct {
+	mu          sync.Mutex
+	subscribers map[int64]map[*subscriber]bool
+}
+
+func newHub() *hub {
+	return &hub{subscribers: map[int64]map[*subscriber]bool{}}
+}
+
+// add registers a new subscriber for a user. The caller must hold h.mu.
+func (h *hub) add(userID int64) *subscriber {
+	s := &subscriber{
+		ch:       make(chan *entity.Event, eventBacklog+subscriberBuffer),
+		replayed: map[string]bool{},
+	}
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = map[*subscriber]bool{}
+	}
+	h.subscribers[userID][s] = true
+	return s
+}
+
+// replay delivers backlog events to a new subscriber. The caller must hold
+// h.mu.
+func (h *hub) replay(s *subscriber, events []*entity.Event) {
+	for _, event := range events {
+		s.replayed[event.ID] = true
+		s.ch <- event
+	}
+}
+
+// release replays the backlog read for a holding subscriber, then the
+// live events held back meanwhile that were not in it, and lets live
+// events through. The caller must hold h.mu.
+func (h *hub) release(userID int64, s *subscriber, backlog []*entity.Event) {
+	if !h.subscribers[userID][s] {
+		// Dropped for falling behind while holding
+		return
+	}
+
+	h.replay(s, backlog)
+	for _, event := range s.held {
+		if !s.replayed[event.ID] {
+			s.ch <- event
+		}
+	}
+	s.holding = false
+	s.held = nil
+}
+
+// remove unregisters a subscriber and closes its channel
+func (h *hub) remove(userID int64, s *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.drop(userID, s)
+}
+
+// drop unregisters a subscriber if it still is registered. The caller must
+// hold h.mu.
+func (h *hub) drop(userID int64, s *subscriber) {
+	if !h.subscribers[userID][s] {
+		return
+	}
+	delete(h.subscribers[userID], s)
+	if len(h.subscribers[userID]) == 0 {
+		delete(h.subscribers, userID)
+	}
+	close(s.ch)
+}
+
+// deliver sends an event to a user's subscribers. Subscribers that are too
+// far behind are dropped; their clients reconnect and resume from the
+// backlog.
+func (h *hub) deliver(userID int64, event *entity.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for s := range h.subscribers[userID] {
+		if s.replayed[event.ID] {
+			continue
+		}
+		if s.holding {
+			if len(s.held) < subscriberBuffer {
+				s.held = append(s.held, event)
+			} else {
+				h.drop(userID, s)
+			}
+			continue
+		}
+		select {
+		case s.ch <- event:
+		default:
+			h.drop(userID, s)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package realtime
+
+import (
+	"strconv"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+)
+
+// MemoryBroker implements service.EventBroker within a single process. It
+// is used when Redis is unavailable, so events only reach streams opened
+// on the same API instance.
+type MemoryBroker struct {
+	hub     *hub
+	seq     int64
+	backlog map[int64][]*entity.Event
+}
+
+// NewMemoryBroker creates a new in-process event broker
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		hub:     newHub(),
+		backlog: map[int64][]*entity.Event{},
+	}
+}
+
+// Publish sends an event to a user's streams and records it in the backlog
+func (b *MemoryBroker) Publish(userID int64, event *entity.Event) error {
+	b.hub.mu.Lock()
+	b.seq++
+	published := &entity.Event{ID: strconv.FormatInt(b.seq, 10), Type: event.Type, Data: event.Data}
+
+	backlog := append(b.backlog[userID], published)
+	if len(backlog) > eventBacklog {
+		backlog = backlog[len(backlog)-eventBacklog:]
+	}
+	b.backlog[userID] = backlog
+	b.hub.mu.Unlock()
+
+	b.hub.deliver(userID, published)
+	return nil
+}
+
+// Subscribe opens a stream of a user's events, replaying the backlog after
+// lastEventID. An ID no longer in the backlog replays all of it.
+func (b *MemoryBroker) Subscribe(userID int64, lastEventID string) (<-chan *entity.Event, func(), error) {
+	b.hub.mu.Lock()
+	defer b.hub.mu.Unlock()
+
+	s := b.hub.add(userID)
+	if lastEventID != "" {
+		backlog := b.backlog[userID]
+		for i, event := range backlog {
+			if event.ID == lastEventID {
+				backlog = backlog[i+1:]
+				break
+			}
+		}
+		b.hub.replay(s, backlog)
+	}
+
+	return s.ch, func() { b.hub.remove(userID, s) }, nil
+}
@@ -0,0 +1,148 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// eventsChannel is the pub/sub channel every API instance listens on
+	eventsChannel = "events"
+
+	// eventBacklogTTL is how long an idle user's backlog is kept
+	eventBacklogTTL = 24 * time.Hour
+)
+
+// message is an event as sent over Redis pub/sub
+type message struct {
+	UserID int64         `json:"user_id"`
+	Event  *entity.Event `json:"event"`
+}
+
+// RedisBroker implements service.EventBroker across API instances. Each
+// user's backlog is a capped Redis stream whose entry IDs are the event IDs,
+// and published events are broadcast over pub/sub to every instance, which
+// delivers them to its own subscribers.
+type RedisBroker struct {
+	client *redis.Client
+	ctx    context.Context
+	hub    *hub
+}
+
+// NewRedisBroker creates a new Redis-backed event broker; Run must be
+// started for events to be delivered
+func NewRedisBroker(client *redis.Client) *RedisBroker {
+	return &RedisBroker{
+		client: client,
+		ctx:    context.Background(),
+		hub:    newHub(),
+	}
+}
+
+func backlogKey(userID int64) string {
+	return fmt.Sprintf("events:%d", userID)
+}
+
+// Run delivers events broadcast by any instance to local subscribers until
+// ctx is cancelled
+func (b *RedisBroker) Run(ctx context.Context) {
+	pubsub := b.client.Subscribe(ctx, eventsChannel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			var m message
+			if err := json.Unmarshal([]byte(msg.Payload), &m); err != nil || m.Event == nil {
+				log.Printf("⚠️  Ignoring malformed event: %v\n", err)
+				continue
+			}
+			b.hub.deliver(m.UserID, m.Event)
+		}
+	}
+}
+
+// Publish appends an event to the user's backlog and broadcasts it
+func (b *RedisBroker) Publish(userID int64, event *entity.Event) error {
+	key := backlogKey(userID)
+	id, err := b.client.XAdd(b.ctx, &redis.XAddArgs{
+		Stream: key,
+		MaxLen: eventBacklog,
+		Approx: true,
+		Values: map[string]interface{}{"type": event.Type, "data": string(event.Data)},
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("append event: %w", err)
+	}
+	b.client.Expire(b.ctx, key, eventBacklogTTL)
+
+	payload, err := json.Marshal(message{
+		UserID: userID,
+		Event:  &entity.Event{ID: id, Type: event.Type, Data: event.Data},
+	})
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+	if err := b.client.Publish(b.ctx, eventsChannel, payload).Err(); err != nil {
+		return fmt.Errorf("publish event: %w", err)
+	}
+	return nil
+}
+
+// Subscribe opens a stream of a user's events, replaying the backlog after
+// lastEventID
+func (b *RedisBroker) Subscribe(userID int64, lastEventID string) (<-chan *entity.Event, func(), error) {
+	// Register before reading the backlog, holding back events broadcast
+	// meanwhile, so none are lost and the hub is not locked during the read
+	resuming := lastEventID != ""
+	b.hub.mu.Lock()
+	s := b.hub.add(userID)
+	s.holding = resuming
+	b.hub.mu.Unlock()
+
+	if resuming {
+		backlog := b.readBacklog(userID, lastEventID)
+
+		b.hub.mu.Lock()
+		b.hub.release(userID, s, backlog)
+		b.hub.mu.Unlock()
+	}
+
+	return s.ch, func() { b.hub.remove(userID, s) }, nil
+}
+
+// readBacklog reads up to eventBacklog of a user's events after
+// lastEventID
+func (b *RedisBroker) readBacklog(userID int64, lastEventID string) []*entity.Event {
+	entries, err := b.client.XRangeN(b.ctx, backlogKey(userID), lastEventID, "+", eventBacklog+1).Result()
+	if err != nil {
+		// Not a stream ID, e.g. one issued by the in-process broker
+		log.Printf("⚠️  Cannot resume events of user %d after %q: %v\n", userID, lastEventID, err)
+	}
+
+	var backlog []*entity.Event
+	for _, entry := range entries {
+		if entry.ID == lastEventID {
+			continue
+		}
+		eventType, _ := entry.Values["type"].(string)
+		data, _ := entry.Values["data"].(string)
+		backlog = append(backlog, &entity.Event{ID: entry.ID, Type: eventType, Data: json.RawMessage(data)})
+	}
+	if len(backlog) > eventBacklog {
+		backlog = backlog[len(backlog)-eventBacklog:]
+	}
+	return backlog
+}
@@ -0,0 +1,191 @@
+package sitegen
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/service"
+	"AbdelrahmanDwedar/blogo/pkg/feed"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// Renderer renders an author's blogs as a static HTML site with an index,
+// one page per post, tag pages and an Atom feed. All links are relative so
+// the output can be served from any directory.
+type Renderer struct {
+	list  *template.Template
+	post  *template.Template
+	tags  *template.Template
+	style []byte
+}
+
+// NewRenderer creates a renderer from the embedded templates
+func NewRenderer() *Renderer {
+	funcs := template.FuncMap{
+		"postPath": postPath,
+		"tagPath":  tagPath,
+		"body":     renderBody,
+		"date":     func(t time.Time) string { return t.Format("January 2, 2006") },
+		"iso":      func(t time.Time) string { return t.UTC().Format(time.RFC3339) },
+	}
+	parse := func(page string) *template.Template {
+		return template.Must(template.New(page).Funcs(funcs).ParseFS(templateFS,
+			"templates/layout.html", "templates/"+page))
+	}
+
+	style, err := templateFS.ReadFile("templates/style.css")
+	if err != nil {
+		panic(err)
+	}
+
+	return &Renderer{
+		list:  parse("list.html"),
+		post:  parse("post.html"),
+		tags:  parse("tags.html"),
+		style: style,
+	}
+}
+
+// tagCount is a tag and the number of posts carrying it
+type tagCount struct {
+	Name  string
+	Count int
+}
+
+// pageData is passed to every template. Root is the relative path from
+// the page back to the site root.
+type pageData struct {
+	Site  *entity.Site
+	Root  string
+	Blogs []*entity.Blog
+	Blog  *entity.Blog
+	Tag   string
+	Tags  []tagCount
+}
+
+// Render writes the whole site to sink
+func (r *Renderer) Render(site *entity.Site, sink service.FileSink) error {
+	if err := r.page(sink, r.list, "index.html", pageData{Site: site, Blogs: site.Blogs}); err != nil {
+		return err
+	}
+
+	byTag := map[string][]*entity.Blog{}
+	for _, blog := range site.Blogs {
+		if err := r.page(sink, r.post, postPath(blog), pageData{Site: site, Root: "../", Blog: blog}); err != nil {
+			return err
+		}
+		for _, tag := range blog.Tags {
+			byTag[tag] = append(byTag[tag], blog)
+		}
+	}
+
+	tags := make([]tagCount, 0, len(byTag))
+	for tag, blogs := range byTag {
+		tags = append(tags, tagCount{Name: tag, Count: len(blogs)})
+		data := pageData{Site: site, Root: "../", Blogs: blogs, Tag: tag}
+		if err := r.page(sink, r.list, tagPath(tag), data); err != nil {
+			return err
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	if err := r.page(sink, r.tags, "tags.html", pageData{Site: site, Tags: tags}); err != nil {
+		return err
+	}
+
+	atom, err := siteFeed(site).Atom()
+	if err != nil {
+		return fmt.Errorf("render feed: %w", err)
+	}
+	if err := sink.WriteFile("feed.atom", atom); err != nil {
+		return err
+	}
+
+	return sink.WriteFile("style.css", r.style)
+}
+
+// page executes a page template and writes the result to sink
+func (r *Renderer) page(sink service.FileSink, tmpl *template.Template, name string, data pageData) error {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "layout", data); err != nil {
+		return fmt.Errorf("render %s: %w", name, err)
+	}
+	return sink.WriteFile(name, buf.Bytes())
+}
+
+// siteFeed builds the Atom feed of a site. Without a base URL, links stay
+// relative to the feed and IDs fall back to URNs.
+func siteFeed(site *entity.Site) *feed.Feed {
+	author := &feed.Person{Name: site.Author.DisplayName}
+	f := &feed.Feed{
+		ID:          fmt.Sprintf("urn:blogo:user:%d", site.Author.ID),
+		Title:       site.Author.DisplayName,
+		Description: site.Author.Bio,
+		Link:        site.BaseURL + "/index.html",
+		Self:        site.BaseURL + "/feed.atom",
+		Updated:     site.GeneratedAt,
+		Author:      author,
+	}
+	if site.BaseURL == "" {
+		f.Link, f.Self = "index.html", "feed.atom"
+	}
+
+	for _, blog := range site.Blogs {
+		link := postPath(blog)
+		if site.BaseURL != "" {
+			link = site.BaseURL + "/" + link
+		}
+		f.Items = append(f.Items, &feed.Item{
+			ID:         fmt.Sprintf("urn:blogo:blog:%d", blog.ID),
+			Title:      blog.Title,
+			Link:       link,
+			Summary:    blog.Description,
+			Content:    string(renderBody(blog)),
+			Author:     author,
+			Published:  blog.CreatedAt,
+			Updated:    blog.UpdatedAt,
+			Categories: blog.Tags,
+		})
+	}
+	if len(site.Blogs) > 0 {
+		f.Updated = site.Blogs[0].UpdatedAt
+	}
+	return f
+}
+
+// postPath is the site-relative path of a blog's page. The ID keeps paths
+// unique even when slugs repeat.
+func postPath(blog *entity.Blog) string {
+	return fmt.Sprintf("posts/%d-%s.html", blog.ID, blog.Slug)
+}
+
+// tagPath is the site-relative path of a tag's page. The tag index lives
+// outside tags/ so that no tag's page can replace it.
+func tagPath(tag string) string {
+	return "tags/" + tag + ".html"
+}
+
+// renderBody turns a plain-text blog body into escaped HTML paragraphs
+func renderBody(blog *entity.Blog) template.HTML {
+	var b strings.Builder
+	for _, paragraph := range strings.Split(strings.ReplaceAll(blog.Body, "\r\n", "\n"), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		lines := strings.Split(paragraph, "\n")
+		for i, line := range lines {
+			lines[i] = html.EscapeString(line)
+		}
+		b.WriteString("<p>" + strings.Join(lines, "<br>\n") + "</p>\n")
+	}
+	return template.HTML(b.String())
+}
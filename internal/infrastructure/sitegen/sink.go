@@ -0,0 +1,96 @@
+package sitegen
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/service"
+)
+
+// DirSink writes site files below a directory on disk
+type DirSink struct {
+	root string
+}
+
+// NewDirSink creates a sink writing below root, creating it if needed
+func NewDirSink(root string) (*DirSink, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("create output directory: %w", err)
+	}
+	return &DirSink{root: root}, nil
+}
+
+// WriteFile writes a file relative to the sink's root
+func (s *DirSink) WriteFile(name string, data []byte) error {
+	path := filepath.Join(s.root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create directory for %s: %w", name, err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// ZipSink writes site files into a ZIP archive
+type ZipSink struct {
+	out io.WriteCloser
+	zip *zip.Writer
+}
+
+// NewZipSink creates a sink writing a ZIP archive to out. Close finishes
+// the archive and closes out.
+func NewZipSink(out io.WriteCloser) *ZipSink {
+	return &ZipSink{out: out, zip: zip.NewWriter(out)}
+}
+
+// WriteFile adds a file to the archive
+func (s *ZipSink) WriteFile(name string, data []byte) error {
+	w, err := s.zip.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("add %s to archive: %w", name, err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// Close finishes the archive
+func (s *ZipSink) Close() error {
+	if err := s.zip.Close(); err != nil {
+		s.out.Close()
+		return fmt.Errorf("finish archive: %w", err)
+	}
+	return s.out.Close()
+}
+
+// ZipStore keeps site archives as ZIP files in a directory
+type ZipStore struct {
+	dir string
+}
+
+// NewZipStore creates an archive store in dir
+func NewZipStore(dir string) *ZipStore {
+	return &ZipStore{dir: dir}
+}
+
+// Create starts a new archive file
+func (s *ZipStore) Create(name string) (service.ArchiveWriter, error) {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create archive directory: %w", err)
+	}
+	f, err := os.Create(filepath.Join(s.dir, filepath.Base(name)))
+	if err != nil {
+		return nil, fmt.Errorf("create archive: %w", err)
+	}
+	return NewZipSink(f), nil
+}
+
+// Open opens an archive file for reading
+func (s *ZipStore) Open(name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(s.dir, filepath.Base(name)))
+}
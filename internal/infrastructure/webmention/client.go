@@ -0,0 +1,223 @@
+package webmention
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/pkg/safehttp"
+)
+
+const (
+	// requestTimeout bounds every request to another site
+	requestTimeout = 10 * time.Second
+
+	// maxPageSize is the largest page read when discovering an endpoint or
+	// verifying a source
+	maxPageSize = 1 << 20
+
+	// userAgent identifies blogo to other sites
+	userAgent = "blogo (+https://github.com/AbdelrahmanDwedar/blogo)"
+)
+
+// Client implements service.WebmentionClient over HTTP
+type Client struct {
+	http *http.Client
+}
+
+// NewClient creates a new Webmention client. Sources, targets and
+// endpoints come from anyone, so it only reaches public addresses unless
+// allowPrivate is set for local testing.
+func NewClient(allowPrivate bool) *Client {
+	return &Client{http: safehttp.NewClient(safehttp.Options{
+		Timeout:      requestTimeout,
+		AllowPrivate: allowPrivate,
+	})}
+}
+
+// get fetches a page, returning the response with its body read up to
+// maxPageSize
+func (c *Client) get(rawURL, accept string) (*http.Response, []byte, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, body, nil
+}
+
+// DiscoverEndpoint finds the Webmention endpoint a page advertises, in a
+// Link header or else in a <link> or <a> element with rel="webmention".
+// Relative endpoints are resolved against the page's URL after redirects.
+func (c *Client) DiscoverEndpoint(target string) (string, error) {
+	resp, body, err := c.get(target, "text/html, */*;q=0.8")
+	if err != nil {
+		return "", fmt.Errorf("fetch webmention target: %w", err)
+	}
+	switch {
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusRequestTimeout ||
+		resp.StatusCode == http.StatusTooManyRequests:
+		return "", fmt.Errorf("fetch webmention target: %s", resp.Status)
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return "", nil
+	}
+
+	base := resp.Request.URL
+	for _, value := range resp.Header.Values("Link") {
+		if endpoint := linkEndpoint(value, base); endpoint != "" {
+			return endpoint, nil
+		}
+	}
+
+	if mediaType(resp) == "text/html" || mediaType(resp) == "application/xhtml+xml" {
+		return htmlEndpoint(string(body), base), nil
+	}
+	return "", nil
+}
+
+// linkEndpoint returns the first URL in a Link header value with
+// rel="webmention", resolved against base
+func linkEndpoint(value string, base *url.URL) string {
+	for value != "" {
+		start := strings.IndexByte(value, '<')
+		end := strings.IndexByte(value, '>')
+		if start < 0 || end < start {
+			return ""
+		}
+		ref := value[start+1 : end]
+		value = value[end+1:]
+
+		// The link's parameters run up to the next link
+		params := value
+		if next := strings.IndexByte(value, '<'); next >= 0 {
+			params = value[:next]
+		}
+		for _, param := range strings.Split(params, ";") {
+			name, rel, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(name), "rel") {
+				continue
+			}
+			if hasRel(strings.Trim(strings.TrimSpace(rel), `"`), "webmention") {
+				return resolve(base, ref)
+			}
+		}
+	}
+	return ""
+}
+
+// Send posts a Webmention to an endpoint. Client errors other than
+// timeouts and rate limiting mean the endpoint will never accept it and
+// are reported as ErrWebmentionRejected.
+func (c *Client) Send(endpoint, source, target string) error {
+	form := url.Values{"source": {source}, "target": {target}}
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("%w: %v", entity.ErrWebmentionRejected, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("send webmention: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxPageSize))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", entity.ErrWebmentionRejected, resp.Status)
+	default:
+		return fmt.Errorf("send webmention: %s", resp.Status)
+	}
+}
+
+// FetchSource retrieves the source of a Webmention and checks whether it
+// links to target. HTML sources must link to it from an element; JSON
+// sources must hold it as a value; any other source must contain it.
+func (c *Client) FetchSource(source, target string) (*entity.WebmentionSource, error) {
+	resp, body, err := c.get(source, "text/html, application/json;q=0.9, */*;q=0.8")
+	if err != nil {
+		return nil, fmt.Errorf("fetch webmention source: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return nil, entity.ErrWebmentionSourceGone
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests:
+		// A source we may not read cannot be shown to link to the target
+		return &entity.WebmentionSource{}, nil
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return nil, fmt.Errorf("fetch webmention source: %s", resp.Status)
+	}
+
+	switch mediaType := mediaType(resp); {
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		page := string(body)
+		return &entity.WebmentionSource{
+			LinksTarget: htmlLinksTo(page, resp.Request.URL, target),
+			Title:       htmlTitle(page),
+		}, nil
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		var document interface{}
+		if err := json.Unmarshal(body, &document); err != nil {
+			return &entity.WebmentionSource{}, nil
+		}
+		return &entity.WebmentionSource{LinksTarget: jsonHolds(document, target)}, nil
+	default:
+		return &entity.WebmentionSource{LinksTarget: strings.Contains(string(body), target)}, nil
+	}
+}
+
+// jsonHolds checks if a decoded JSON document holds a string containing
+// target anywhere within it
+func jsonHolds(document interface{}, target string) bool {
+	switch value := document.(type) {
+	case string:
+		return strings.Contains(value, target)
+	case []interface{}:
+		for _, item := range value {
+			if jsonHolds(item, target) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for _, item := range value {
+			if jsonHolds(item, target) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mediaType returns the lowercased media type of a response
+func mediaType(resp *http.Response) string {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+	return mediaType
+}
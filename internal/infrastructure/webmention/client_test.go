@@ -0,0 +1,280 @@
+package webmention
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/pkg/safehttp"
+)
+
+// page is a response a test server gives
+type page struct {
+	status      int
+	contentType string
+	link        string
+	body        string
+}
+
+// serve starts a server answering every path with p, and /moved with a
+// redirect to /posts/1
+func serve(t *testing.T, p page) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/moved" {
+			http.Redirect(w, r, "/posts/1", http.StatusMovedPermanently)
+			return
+		}
+		if p.contentType != "" {
+			w.Header().Set("Content-Type", p.contentType)
+		}
+		if p.link != "" {
+			w.Header().Set("Link", p.link)
+		}
+		if p.status != 0 {
+			w.WriteHeader(p.status)
+		}
+		w.Write([]byte(p.body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDiscoverEndpoint(t *testing.T) {
+	tests := []struct {
+		name string
+		page page
+		path string
+		// want is the endpoint expected, relative to the server's URL
+		want string
+	}{
+		{
+			name: "absolute link header",
+			page: page{link: `<https://hooks.example/webmention>; rel="webmention"`},
+			want: "https://hooks.example/webmention",
+		},
+		{
+			name: "relative link header",
+			page: page{link: `</webmention>; rel=webmention`},
+			want: "/webmention",
+		},
+		{
+			name: "link header among others",
+			page: page{link: `</feed>; rel="alternate", </wm>; rel="nofollow webmention"`},
+			want: "/wm",
+		},
+		{
+			name: "link header before html",
+			page: page{
+				contentType: "text/html",
+				link:        `</from-header>; rel="webmention"`,
+				body:        `<link rel="webmention" href="/from-html">`,
+			},
+			want: "/from-header",
+		},
+		{
+			name: "link element",
+			page: page{contentType: "text/html; charset=utf-8", body: `<head><link href="/endpoint?a=1&amp;b=2" rel="webmention"></head>`},
+			want: "/endpoint?a=1&b=2",
+		},
+		{
+			name: "anchor element",
+			page: page{contentType: "text/html", body: `<a rel='webmention' href='endpoint'>mention</a>`},
+			want: "/posts/endpoint",
+		},
+		{
+			name: "empty href is the page",
+			page: page{contentType: "text/html", body: `<link rel="webmention" href="">`},
+			want: "/posts/1",
+		},
+		{
+			name: "first element wins",
+			page: page{contentType: "text/html", body: `<a rel="webmention" href="/first"></a><link rel="webmention" href="/second">`},
+			want: "/first",
+		},
+		{
+			name: "commented out element",
+			page: page{contentType: "text/html", body: `<!-- <link rel="webmention" href="/old"> --><link rel="webmention" href="/new">`},
+			want: "/new",
+		},
+		{
+			name: "relative to the page after redirects",
+			page: page{contentType: "text/html", body: `<link rel="webmention" href="endpoint">`},
+			path: "/moved",
+			want: "/posts/endpoint",
+		},
+		{
+			name: "other rel",
+			page: page{contentType: "text/html", body: `<link rel="pingback" href="/xmlrpc">`},
+		},
+		{
+			name: "not html",
+			page: page{contentType: "text/plain", body: `<link rel="webmention" href="/endpoint">`},
+		},
+		{
+			name: "missing page",
+			page: page{status: http.StatusNotFound, contentType: "text/html", body: `<link rel="webmention" href="/endpoint">`},
+		},
+	}
+
+	client := NewClient(true)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := serve(t, tt.page)
+			path := tt.path
+			if path == "" {
+				path = "/posts/1"
+			}
+
+			got, err := client.DiscoverEndpoint(server.URL + path)
+			if err != nil {
+				t.Fatalf("DiscoverEndpoint: %v", err)
+			}
+			want := tt.want
+			if want != "" && want[0] == '/' {
+				want = server.URL + want
+			}
+			if got != want {
+				t.Errorf("endpoint = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestDiscoverEndpointServerError(t *testing.T) {
+	server := serve(t, page{status: http.StatusServiceUnavailable})
+
+	if _, err := NewClient(true).DiscoverEndpoint(server.URL + "/posts/1"); err == nil {
+		t.Fatal("DiscoverEndpoint succeeded on a server error, want an error to retry")
+	}
+}
+
+func TestFetchSourceLinksTarget(t *testing.T) {
+	const target = "https://blogo.example/blogs/7"
+
+	tests := []struct {
+		name string
+		page page
+		want bool
+	}{
+		{
+			name: "anchor",
+			page: page{contentType: "text/html", body: `<p>Read <a href="https://blogo.example/blogs/7">this</a></p>`},
+			want: true,
+		},
+		{
+			name: "anchor with fragment",
+			page: page{contentType: "text/html", body: `<a href="https://blogo.example/blogs/7#comments">this</a>`},
+			want: true,
+		},
+		{
+			name: "escaped href",
+			page: page{contentType: "text/html", body: `<a href="https&#58;//blogo.example/blogs/7">this</a>`},
+			want: true,
+		},
+		{
+			name: "image source",
+			page: page{contentType: "text/html", body: `<img src="https://blogo.example/blogs/7">`},
+			want: true,
+		},
+		{
+			name: "quote citation",
+			page: page{contentType: "text/html", body: `<blockquote cite="https://blogo.example/blogs/7">…</blockquote>`},
+			want: true,
+		},
+		{
+			name: "only in text",
+			page: page{contentType: "text/html", body: `<p>https://blogo.example/blogs/7</p>`},
+		},
+		{
+			name: "only in a comment",
+			page: page{contentType: "text/html", body: `<!-- <a href="https://blogo.example/blogs/7">this</a> -->`},
+		},
+		{
+			name: "longer url",
+			page: page{contentType: "text/html", body: `<a href="https://blogo.example/blogs/70">this</a>`},
+		},
+		{
+			name: "json value",
+			page: page{contentType: "application/activity+json", body: `{"object":{"inReplyTo":"https://blogo.example/blogs/7"}}`},
+			want: true,
+		},
+		{
+			name: "json key only",
+			page: page{contentType: "application/json", body: `{"https://blogo.example/blogs/7":1}`},
+		},
+		{
+			name: "plain text",
+			page: page{contentType: "text/plain", body: `see https://blogo.example/blogs/7`},
+			want: true,
+		},
+		{
+			name: "forbidden",
+			page: page{status: http.StatusForbidden, contentType: "text/html", body: `<a href="https://blogo.example/blogs/7">this</a>`},
+		},
+	}
+
+	client := NewClient(true)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := serve(t, tt.page)
+
+			source, err := client.FetchSource(server.URL+"/posts/1", target)
+			if err != nil {
+				t.Fatalf("FetchSource: %v", err)
+			}
+			if source.LinksTarget != tt.want {
+				t.Errorf("LinksTarget = %v, want %v", source.LinksTarget, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchSourceRelativeLink(t *testing.T) {
+	server := serve(t, page{contentType: "text/html", body: `<title> A   reply </title><a href="/posts/2#top">earlier</a>`})
+
+	source, err := NewClient(true).FetchSource(server.URL+"/moved", server.URL+"/posts/2")
+	if err != nil {
+		t.Fatalf("FetchSource: %v", err)
+	}
+	if !source.LinksTarget {
+		t.Error("relative link to the target was not found")
+	}
+	if source.Title != "A reply" {
+		t.Errorf("Title = %q, want %q", source.Title, "A reply")
+	}
+}
+
+func TestFetchSourceGone(t *testing.T) {
+	server := serve(t, page{status: http.StatusGone})
+
+	_, err := NewClient(true).FetchSource(server.URL+"/posts/1", "https://blogo.example/blogs/7")
+	if !errors.Is(err, entity.ErrWebmentionSourceGone) {
+		t.Fatalf("FetchSource error = %v, want %v", err, entity.ErrWebmentionSourceGone)
+	}
+}
+
+func TestClientRefusesPrivateAddresses(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient(false)
+
+	if _, err := client.DiscoverEndpoint(server.URL); !errors.Is(err, safehttp.ErrForbiddenAddress) {
+		t.Errorf("DiscoverEndpoint error = %v, want %v", err, safehttp.ErrForbiddenAddress)
+	}
+	if _, err := client.FetchSource(server.URL, "https://blogo.example/blogs/7"); !errors.Is(err, safehttp.ErrForbiddenAddress) {
+		t.Errorf("FetchSource error = %v, want %v", err, safehttp.ErrForbiddenAddress)
+	}
+	if err := client.Send(server.URL, "https://blogo.example/blogs/7", server.URL); !errors.Is(err, safehttp.ErrForbiddenAddress) {
+		t.Errorf("Send error = %v, want %v", err, safehttp.ErrForbiddenAddress)
+	}
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		t.Errorf("loopback server got %d requests, want none", n)
+	}
+}
@@ -0,0 +1,129 @@
+package webmention
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	// commentPattern matches HTML comments, which are skipped when pages
+	// are scanned
+	commentPattern = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+	// tagPattern matches the start tag of an element, capturing its name
+	// and attributes
+	tagPattern = regexp.MustCompile(`(?is)<([a-z][a-z0-9]*)\b([^>]*)>`)
+
+	// attrPattern matches one attribute of a start tag, quoted or not
+	attrPattern = regexp.MustCompile(`(?is)([a-z_:][-a-z0-9_:.]*)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'=<>` + "`" + `]+))`)
+
+	// titlePattern matches the title of a page
+	titlePattern = regexp.MustCompile(`(?is)<title\b[^>]*>(.*?)</title>`)
+)
+
+// maxTitleLength bounds the title kept for a Webmention
+const maxTitleLength = 200
+
+// element is a start tag found in a page
+type element struct {
+	name  string
+	attrs map[string]string
+}
+
+// elements returns the start tags of page in document order, with their
+// attribute names lowercased and values unescaped
+func elements(page string) []element {
+	page = commentPattern.ReplaceAllString(page, "")
+
+	var found []element
+	for _, match := range tagPattern.FindAllStringSubmatch(page, -1) {
+		attrs := map[string]string{}
+		for _, attr := range attrPattern.FindAllStringSubmatch(match[2], -1) {
+			name := strings.ToLower(attr[1])
+			if _, ok := attrs[name]; ok {
+				continue
+			}
+			attrs[name] = html.UnescapeString(attr[2] + attr[3] + attr[4])
+		}
+		found = append(found, element{name: strings.ToLower(match[1]), attrs: attrs})
+	}
+	return found
+}
+
+// hasRel checks if a space-separated rel value includes rel
+func hasRel(value, rel string) bool {
+	for _, field := range strings.Fields(strings.ToLower(value)) {
+		if field == rel {
+			return true
+		}
+	}
+	return false
+}
+
+// htmlEndpoint returns the href of the first <link> or <a> element of page
+// with rel="webmention", resolved against base
+func htmlEndpoint(page string, base *url.URL) string {
+	for _, el := range elements(page) {
+		if el.name != "link" && el.name != "a" {
+			continue
+		}
+		href, ok := el.attrs["href"]
+		if !ok || !hasRel(el.attrs["rel"], "webmention") {
+			continue
+		}
+		if endpoint := resolve(base, href); endpoint != "" {
+			return endpoint
+		}
+	}
+	return ""
+}
+
+// htmlLinksTo checks if any element of page links to target through an
+// href, src or cite attribute, resolved against base
+func htmlLinksTo(page string, base *url.URL, target string) bool {
+	for _, el := range elements(page) {
+		for _, attr := range []string{"href", "src", "cite"} {
+			if value, ok := el.attrs[attr]; ok && sameURL(resolve(base, value), target) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// htmlTitle returns the title of page, with whitespace collapsed
+func htmlTitle(page string) string {
+	match := titlePattern.FindStringSubmatch(page)
+	if match == nil {
+		return ""
+	}
+
+	title := strings.Join(strings.Fields(html.UnescapeString(match[1])), " ")
+	if runes := []rune(title); len(runes) > maxTitleLength {
+		title = string(runes[:maxTitleLength-1]) + "…"
+	}
+	return title
+}
+
+// resolve resolves ref against base, returning an empty string for refs
+// that are not URLs
+func resolve(base *url.URL, ref string) string {
+	u, err := url.Parse(strings.TrimSpace(ref))
+	if err != nil {
+		return ""
+	}
+	return base.ResolveReference(u).String()
+}
+
+// sameURL compares two URLs ignoring their fragments
+func sameURL(a, b string) bool {
+	if i := strings.IndexByte(a, '#'); i >= 0 {
+		a = a[:i]
+	}
+	if i := strings.IndexByte(b, '#'); i >= 0 {
+		b = b[:i]
+	}
+	return a != "" && a == b
+}
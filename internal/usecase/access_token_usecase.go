@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"log"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+)
+
+// AccessTokenUseCase manages the scoped access tokens users create for
+// third-party clients, such as Micropub editors, and authenticates the
+// requests those clients make with them
+type AccessTokenUseCase struct {
+	tokenRepo repository.AccessTokenRepository
+}
+
+// NewAccessTokenUseCase creates a new access token use case
+func NewAccessTokenUseCase(tokenRepo repository.AccessTokenRepository) *AccessTokenUseCase {
+	return &AccessTokenUseCase{tokenRepo: tokenRepo}
+}
+
+// CreateToken creates an access token for a user with the given scopes and
+// returns it with its secret, which cannot be retrieved again
+func (uc *AccessTokenUseCase) CreateToken(userID int64, name string, scopes []string) (*entity.AccessToken, string, error) {
+	token, secret, err := entity.NewAccessToken(userID, name, scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := uc.tokenRepo.Create(token); err != nil {
+		return nil, "", err
+	}
+	return token, secret, nil
+}
+
+// GetTokens retrieves a user's access tokens
+func (uc *AccessTokenUseCase) GetTokens(userID int64) ([]*entity.AccessToken, error) {
+	return uc.tokenRepo.GetByUser(userID)
+}
+
+// RevokeToken deletes one of a user's access tokens
+func (uc *AccessTokenUseCase) RevokeToken(id, userID int64) error {
+	return uc.tokenRepo.Delete(id, userID)
+}
+
+// Authenticate returns the access token with the given secret, recording
+// its use
+func (uc *AccessTokenUseCase) Authenticate(secret string) (*entity.AccessToken, error) {
+	if secret == "" {
+		return nil, entity.ErrAccessTokenNotFound
+	}
+
+	token, err := uc.tokenRepo.GetByHash(entity.HashAccessToken(secret))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.tokenRepo.Touch(token.ID); err != nil {
+		log.Printf("⚠️  Failed to record use of access token %d: %v\n", token.ID, err)
+	}
+	return token, nil
+}
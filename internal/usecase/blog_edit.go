@@ -0,0 +1,43 @@
+package usecase
+
+import "AbdelrahmanDwedar/blogo/internal/domain/entity"
+
+// blogFields holds the fields of a blog publishing clients can change
+type blogFields struct {
+	title       string
+	description string
+	body        string
+	tags        []string
+}
+
+// editBlog applies edit to the fields of a blog the user owns and saves
+// them. An edit racing with another one is applied again on top of the
+// other edit.
+func editBlog(blogUC *BlogUseCase, blogID, userID int64, edit func(*blogFields) error) (*entity.Blog, error) {
+	blog, err := blogUC.GetBlogByID(blogID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !blog.IsOwnedBy(userID) {
+		return nil, entity.ErrNotBlogOwner
+	}
+
+	for attempt := 0; ; attempt++ {
+		fields := &blogFields{
+			title:       blog.Title,
+			description: blog.Description,
+			body:        blog.Body,
+			tags:        append([]string{}, blog.Tags...),
+		}
+		if err := edit(fields); err != nil {
+			return nil, err
+		}
+
+		updated, err := blogUC.UpdateBlog(blogID, fields.title, fields.description, fields.body, fields.tags, userID, blog.Version)
+		if err == entity.ErrVersionConflict && attempt == 0 {
+			blog = updated
+			continue
+		}
+		return updated, err
+	}
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// blogPathPattern matches the paths a blog is reachable at, capturing its ID
+var blogPathPattern = regexp.MustCompile(`^/(?:api/b|ap/blogs)/([0-9]+)/?$`)
+
+// blogURL returns the public URL of a blog on the server at baseURL
+func blogURL(baseURL string, blogID int64) string {
+	return fmt.Sprintf("%s/api/b/%d", baseURL, blogID)
+}
+
+// blogIDFromURL returns the ID of the blog raw points to on the server at
+// baseURL, or 0 when it points elsewhere
+func blogIDFromURL(baseURL, raw string) int64 {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return 0
+	}
+	u, err := url.Parse(raw)
+	if err != nil || !strings.EqualFold(u.Host, base.Host) {
+		return 0
+	}
+
+	match := blogPathPattern.FindStringSubmatch(strings.TrimPrefix(u.Path, strings.TrimRight(base.Path, "/")))
+	if match == nil {
+		return 0
+	}
+	id, _ := strconv.ParseInt(match[1], 10, 64)
+	return id
+}
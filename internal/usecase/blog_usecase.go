@@ -1,6 +1,10 @@
 package usecase
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
 	"time"
 
 	"AbdelrahmanDwedar/blogo/internal/domain/entity"
@@ -9,22 +13,55 @@ import (
 
 // BlogUseCase handles blog-related business logic
 type BlogUseCase struct {
-	blogRepo  repository.BlogRepository
-	cacheRepo repository.CacheRepository
+	blogRepo          repository.BlogRepository
+	userRepo          repository.UserRepository
+	cacheRepo         repository.CacheRepository
+	reactionKinds     []string
+	listeners         []BlogListener
+	reactionListeners []ReactionListener
 }
 
-// NewBlogUseCase creates a new blog use case
-func NewBlogUseCase(blogRepo repository.BlogRepository, cacheRepo repository.CacheRepository) *BlogUseCase {
+// NewBlogUseCase creates a new blog use case that accepts the given
+// reaction kinds in addition to the default like
+func NewBlogUseCase(blogRepo repository.BlogRepository, userRepo repository.UserRepository,
+	cacheRepo repository.CacheRepository, reactionKinds []string) *BlogUseCase {
 	return &BlogUseCase{
-		blogRepo:  blogRepo,
-		cacheRepo: cacheRepo,
+		blogRepo:      blogRepo,
+		userRepo:      userRepo,
+		cacheRepo:     cacheRepo,
+		reactionKinds: entity.NormalizeReactionKinds(reactionKinds),
+	}
+}
+
+// Subscribe registers a listener for blog changes
+func (uc *BlogUseCase) Subscribe(listener BlogListener) {
+	uc.listeners = append(uc.listeners, listener)
+}
+
+// SubscribeReactions registers a listener for added reactions
+func (uc *BlogUseCase) SubscribeReactions(listener ReactionListener) {
+	uc.reactionListeners = append(uc.reactionListeners, listener)
+}
+
+// blogSaved tells the listeners about a saved blog
+func (uc *BlogUseCase) blogSaved(blog *entity.Blog, created bool) {
+	for _, listener := range uc.listeners {
+		listener.BlogSaved(blog, created)
+	}
+}
+
+// blogDeleted tells the listeners about a trashed blog
+func (uc *BlogUseCase) blogDeleted(blog *entity.Blog) {
+	for _, listener := range uc.listeners {
+		listener.BlogDeleted(blog)
 	}
 }
 
 // CreateBlog creates a new blog post
-func (uc *BlogUseCase) CreateBlog(title, description, body string, authorID int64) (*entity.Blog, error) {
+func (uc *BlogUseCase) CreateBlog(title, description, body string, tags []string, authorID int64) (*entity.Blog, error) {
 	// Create blog entity
 	blog := entity.NewBlog(title, description, body, authorID)
+	blog.SetTags(tags)
 
 	// Validate
 	if err := blog.Validate(); err != nil {
@@ -41,11 +78,74 @@ func (uc *BlogUseCase) CreateBlog(title, description, body string, authorID int6
 		uc.cacheRepo.DeletePattern("blogs:*")
 	}
 
+	uc.blogSaved(blog, true)
+
+	return blog, nil
+}
+
+// ImportBlog saves a blog built elsewhere, keeping its slug and dates, and
+// tells the listeners about it like a newly created blog
+func (uc *BlogUseCase) ImportBlog(blog *entity.Blog) error {
+	if err := blog.Validate(); err != nil {
+		return err
+	}
+
+	if err := uc.blogRepo.Create(blog); err != nil {
+		return err
+	}
+
+	// Invalidate blog list cache
+	if uc.cacheRepo != nil {
+		uc.cacheRepo.DeletePattern("blogs:*")
+	}
+
+	uc.blogSaved(blog, true)
+
+	return nil
+}
+
+// GetBlogByID retrieves a blog by ID with caching, as seen by viewerID;
+// blogs by users in a block with the viewer, and by private accounts the
+// viewer doesn't follow, are not found
+func (uc *BlogUseCase) GetBlogByID(id, viewerID int64) (*entity.Blog, error) {
+	blog, err := uc.getBlog(id)
+	if err != nil {
+		return nil, err
+	}
+
+	visible, err := canSeeAuthor(uc.userRepo, viewerID, blog.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, entity.ErrBlogNotFound
+	}
+
 	return blog, nil
 }
 
-// GetBlogByID retrieves a blog by ID with caching
-func (uc *BlogUseCase) GetBlogByID(id int64) (*entity.Blog, error) {
+// RecordView counts a view of a blog for trending, logging failures.
+// Each viewer, or each address for anonymous viewers, counts once an hour;
+// authors viewing their own blogs are not counted.
+func (uc *BlogUseCase) RecordView(blog *entity.Blog, viewerID int64, clientIP string) {
+	if blog.IsOwnedBy(viewerID) {
+		return
+	}
+
+	// Addresses are only kept hashed
+	viewer := fmt.Sprintf("user:%d", viewerID)
+	if viewerID == 0 {
+		sum := sha256.Sum256([]byte(clientIP))
+		viewer = "ip:" + hex.EncodeToString(sum[:16])
+	}
+
+	if err := uc.blogRepo.RecordView(blog.ID, viewer); err != nil {
+		log.Printf("⚠️  Failed to record view of blog %d: %v\n", blog.ID, err)
+	}
+}
+
+// getBlog retrieves a blog by ID with caching
+func (uc *BlogUseCase) getBlog(id int64) (*entity.Blog, error) {
 	// Try cache first
 	if uc.cacheRepo != nil {
 		if blog, err := uc.cacheRepo.GetBlog(id); err == nil && blog != nil {
@@ -67,9 +167,18 @@ func (uc *BlogUseCase) GetBlogByID(id int64) (*entity.Blog, error) {
 	return blog, nil
 }
 
-// GetAllBlogs retrieves all blogs with pagination
-func (uc *BlogUseCase) GetAllBlogs(limit, offset int) ([]*entity.Blog, error) {
-	return uc.blogRepo.GetAll(limit, offset)
+// GetAllBlogs retrieves all blogs with pagination, newest first or, sorted
+// by top, most liked first. Blogs by users in a block with viewerID and by
+// private accounts the viewer doesn't follow are left out.
+func (uc *BlogUseCase) GetAllBlogs(viewerID int64, sort string, limit, offset int) ([]*entity.Blog, error) {
+	switch sort {
+	case "", entity.SortLatest:
+		return uc.blogRepo.GetAll(viewerID, limit, offset)
+	case entity.SortTop:
+		return uc.blogRepo.GetTop(viewerID, limit, offset)
+	default:
+		return nil, entity.ErrInvalidSort
+	}
 }
 
 // GetBlogsByAuthor retrieves blogs by a specific author
@@ -77,8 +186,11 @@ func (uc *BlogUseCase) GetBlogsByAuthor(authorID int64, limit, offset int) ([]*e
 	return uc.blogRepo.GetByAuthor(authorID, limit, offset)
 }
 
-// UpdateBlog updates a blog post
-func (uc *BlogUseCase) UpdateBlog(id int64, title, description, body string, userID int64) (*entity.Blog, error) {
+// UpdateBlog updates a blog post; nil tags leave the existing tags alone.
+// version is the blog version the edit was based on; if the blog has moved
+// on since, ErrVersionConflict is returned together with the current blog
+// so the caller can report its version.
+func (uc *BlogUseCase) UpdateBlog(id int64, title, description, body string, tags []string, userID, version int64) (*entity.Blog, error) {
 	// Get existing blog
 	blog, err := uc.blogRepo.GetByID(id)
 	if err != nil {
@@ -90,8 +202,16 @@ func (uc *BlogUseCase) UpdateBlog(id int64, title, description, body string, use
 		return nil, entity.ErrNotBlogOwner
 	}
 
+	// Reject edits based on a stale version
+	if blog.Version != version {
+		return blog, entity.ErrVersionConflict
+	}
+
 	// Update
 	blog.Update(title, description, body)
+	if tags != nil {
+		blog.SetTags(tags)
+	}
 
 	// Validate
 	if err := blog.Validate(); err != nil {
@@ -100,6 +220,14 @@ func (uc *BlogUseCase) UpdateBlog(id int64, title, description, body string, use
 
 	// Save
 	if err := uc.blogRepo.Update(blog); err != nil {
+		if err == entity.ErrVersionConflict {
+			// Someone saved between our read and write
+			current, getErr := uc.blogRepo.GetByID(id)
+			if getErr != nil {
+				return nil, getErr
+			}
+			return current, err
+		}
 		return nil, err
 	}
 
@@ -109,10 +237,12 @@ func (uc *BlogUseCase) UpdateBlog(id int64, title, description, body string, use
 		uc.cacheRepo.DeletePattern("blogs:*")
 	}
 
+	uc.blogSaved(blog, false)
+
 	return blog, nil
 }
 
-// DeleteBlog deletes a blog post
+// DeleteBlog moves a blog post to the owner's trash
 func (uc *BlogUseCase) DeleteBlog(id, userID int64) error {
 	// Get blog to check ownership
 	blog, err := uc.blogRepo.GetByID(id)
@@ -136,12 +266,87 @@ func (uc *BlogUseCase) DeleteBlog(id, userID int64) error {
 		uc.cacheRepo.DeletePattern("blogs:*")
 	}
 
+	uc.blogDeleted(blog)
+
 	return nil
 }
 
-// LikeBlog adds a like to a blog
-func (uc *BlogUseCase) LikeBlog(blogID, userID int64) error {
-	if err := uc.blogRepo.Like(blogID, userID); err != nil {
+// GetTrash retrieves the blogs in a user's trash
+func (uc *BlogUseCase) GetTrash(userID int64, limit, offset int) ([]*entity.Blog, error) {
+	return uc.blogRepo.GetDeletedByAuthor(userID, limit, offset)
+}
+
+// RestoreBlog moves a blog post out of the owner's trash
+func (uc *BlogUseCase) RestoreBlog(id, userID int64) (*entity.Blog, error) {
+	if err := uc.blogRepo.Restore(id, userID); err != nil {
+		return nil, err
+	}
+
+	// Invalidate list caches
+	if uc.cacheRepo != nil {
+		uc.cacheRepo.DeletePattern("blogs:*")
+	}
+
+	blog, err := uc.blogRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.blogSaved(blog, false)
+
+	return blog, nil
+}
+
+// PurgeTrash permanently removes blogs that have been in the trash for
+// longer than retention
+func (uc *BlogUseCase) PurgeTrash(retention time.Duration) (int64, error) {
+	return uc.blogRepo.PurgeDeleted(time.Now().Add(-retention))
+}
+
+// ReactionKinds returns the reaction kinds users can react with
+func (uc *BlogUseCase) ReactionKinds() []string {
+	return uc.reactionKinds
+}
+
+// isReactionKind checks if kind is one of the configured reaction kinds
+func (uc *BlogUseCase) isReactionKind(kind string) bool {
+	for _, k := range uc.reactionKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// ReactToBlog adds a user's reaction of the given kind to a blog
+func (uc *BlogUseCase) ReactToBlog(blogID, userID int64, kind string) error {
+	if !uc.isReactionKind(kind) {
+		return entity.ErrInvalidReaction
+	}
+
+	// Trashed blogs cannot be reacted to
+	blog, err := uc.blogRepo.GetByID(blogID)
+	if err != nil {
+		return err
+	}
+
+	blocked, err := uc.userRepo.IsBlocked(userID, blog.AuthorID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return entity.ErrUserBlocked
+	}
+
+	visible, err := canSeeAuthor(uc.userRepo, userID, blog.AuthorID)
+	if err != nil {
+		return err
+	}
+	if !visible {
+		return entity.ErrBlogNotFound
+	}
+
+	if err := uc.blogRepo.React(blogID, userID, kind); err != nil {
 		return err
 	}
 
@@ -150,12 +355,20 @@ func (uc *BlogUseCase) LikeBlog(blogID, userID int64) error {
 		uc.cacheRepo.DeleteBlog(blogID)
 	}
 
+	for _, listener := range uc.reactionListeners {
+		listener.BlogReacted(blog, userID, kind)
+	}
+
 	return nil
 }
 
-// UnlikeBlog removes a like from a blog
-func (uc *BlogUseCase) UnlikeBlog(blogID, userID int64) error {
-	if err := uc.blogRepo.Unlike(blogID, userID); err != nil {
+// RemoveReaction removes a user's reaction of the given kind from a blog
+func (uc *BlogUseCase) RemoveReaction(blogID, userID int64, kind string) error {
+	if !uc.isReactionKind(kind) {
+		return entity.ErrInvalidReaction
+	}
+
+	if err := uc.blogRepo.Unreact(blogID, userID, kind); err != nil {
 		return err
 	}
 
@@ -167,9 +380,34 @@ func (uc *BlogUseCase) UnlikeBlog(blogID, userID int64) error {
 	return nil
 }
 
-// GetBlogLikes retrieves users who liked a blog
-func (uc *BlogUseCase) GetBlogLikes(blogID int64, limit, offset int) ([]*entity.User, error) {
-	return uc.blogRepo.GetLikes(blogID, limit, offset)
+// GetBlogReactions retrieves a blog's reactions of the given kind, or of
+// every kind when kind is empty, leaving out reactions by users in a block
+// with viewerID. Reactions to blogs the viewer cannot see are not found.
+func (uc *BlogUseCase) GetBlogReactions(blogID, viewerID int64, kind string, limit, offset int) ([]*entity.Reaction, error) {
+	if kind != "" && !uc.isReactionKind(kind) {
+		return nil, entity.ErrInvalidReaction
+	}
+
+	if _, err := uc.GetBlogByID(blogID, viewerID); err != nil {
+		return nil, err
+	}
+
+	return uc.blogRepo.GetReactions(blogID, viewerID, kind, limit, offset)
+}
+
+// GetBlogLikes retrieves users who liked a blog, leaving out users in a
+// block with viewerID
+func (uc *BlogUseCase) GetBlogLikes(blogID, viewerID int64, limit, offset int) ([]*entity.User, error) {
+	reactions, err := uc.GetBlogReactions(blogID, viewerID, entity.DefaultReaction, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*entity.User, 0, len(reactions))
+	for _, reaction := range reactions {
+		users = append(users, reaction.User)
+	}
+	return users, nil
 }
 
 
@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+)
+
+// BookmarkUseCase manages the blogs users bookmark and their reading
+// lists, and tells viewers which blogs they bookmarked. Blogs by users in
+// a block with the viewer, and by private accounts the viewer doesn't
+// follow, are left out of both.
+type BookmarkUseCase struct {
+	bookmarkRepo repository.BookmarkRepository
+	blogRepo     repository.BlogRepository
+	userRepo     repository.UserRepository
+}
+
+// NewBookmarkUseCase creates a new bookmark use case
+func NewBookmarkUseCase(bookmarkRepo repository.BookmarkRepository, blogRepo repository.BlogRepository,
+	userRepo repository.UserRepository) *BookmarkUseCase {
+	return &BookmarkUseCase{
+		bookmarkRepo: bookmarkRepo,
+		blogRepo:     blogRepo,
+		userRepo:     userRepo,
+	}
+}
+
+// Bookmark saves a blog the user can see for later
+func (uc *BookmarkUseCase) Bookmark(userID, blogID int64) (*entity.Bookmark, error) {
+	if err := uc.checkBlog(userID, blogID); err != nil {
+		return nil, err
+	}
+
+	bookmark := entity.NewBookmark(userID, blogID)
+	if err := uc.bookmarkRepo.AddBookmark(bookmark); err != nil {
+		return nil, err
+	}
+	return bookmark, nil
+}
+
+// Unbookmark removes a user's bookmark of a blog
+func (uc *BookmarkUseCase) Unbookmark(userID, blogID int64) error {
+	return uc.bookmarkRepo.RemoveBookmark(userID, blogID)
+}
+
+// GetBookmarks retrieves a page of a user's bookmarks starting after
+// cursor, along with the cursor of the next page, which is nil on the
+// last page
+func (uc *BookmarkUseCase) GetBookmarks(userID int64, cursor *entity.Cursor, limit int) ([]*entity.Bookmark, *entity.Cursor, error) {
+	bookmarks, err := uc.bookmarkRepo.GetBookmarks(userID, cursor, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The next page starts after the last bookmark read, even when it is
+	// left out below
+	var next *entity.Cursor
+	if len(bookmarks) == limit {
+		last := bookmarks[len(bookmarks)-1]
+		next = entity.NewCursor(last.CreatedAt, last.ID)
+	}
+
+	blogs := make([]*entity.Blog, 0, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		if bookmark.Blog != nil {
+			blogs = append(blogs, bookmark.Blog)
+		}
+	}
+	hidden, err := uc.hiddenAuthors(userID, blogs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	visible := make([]*entity.Bookmark, 0, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		if bookmark.Blog == nil || hidden[bookmark.Blog.AuthorID] {
+			continue
+		}
+		bookmarked := true
+		bookmark.Blog.IsBookmarked = &bookmarked
+		visible = append(visible, bookmark)
+	}
+	return visible, next, nil
+}
+
+// MarkBookmarked sets IsBookmarked on blogs for an authenticated viewer;
+// blogs shown to anonymous viewers are left alone
+func (uc *BookmarkUseCase) MarkBookmarked(viewerID int64, blogs ...*entity.Blog) error {
+	if viewerID == 0 || len(blogs) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(blogs))
+	for i, blog := range blogs {
+		ids[i] = blog.ID
+	}
+	bookmarked, err := uc.bookmarkRepo.GetBookmarkedIDs(viewerID, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, blog := range blogs {
+		isBookmarked := bookmarked[blog.ID]
+		blog.IsBookmarked = &isBookmarked
+	}
+	return nil
+}
+
+// CreateList creates a reading list for a user
+func (uc *BookmarkUseCase) CreateList(userID int64, name, description string, public bool) (*entity.ReadingList, error) {
+	list, err := entity.NewReadingList(userID, name, description, public)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.bookmarkRepo.CreateList(list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetLists retrieves the reading lists of ownerID as seen by viewerID:
+// all of them for the owner and the public ones for anyone who can see
+// the owner
+func (uc *BookmarkUseCase) GetLists(ownerID, viewerID int64) ([]*entity.ReadingList, error) {
+	if ownerID == viewerID {
+		return uc.bookmarkRepo.GetLists(ownerID, false)
+	}
+
+	visible, err := canSeeAuthor(uc.userRepo, viewerID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, entity.ErrUserNotFound
+	}
+	return uc.bookmarkRepo.GetLists(ownerID, true)
+}
+
+// GetList retrieves a reading list and a page of its items starting after
+// cursor, as seen by viewerID, along with the cursor of the next page.
+// Private lists are only found by their owner.
+func (uc *BookmarkUseCase) GetList(id, viewerID int64, cursor *entity.Cursor, limit int) (*entity.ReadingList, []*entity.ReadingListItem, *entity.Cursor, error) {
+	list, err := uc.bookmarkRepo.GetList(id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !list.IsOwnedBy(viewerID) {
+		visible := false
+		if list.Public {
+			visible, err = canSeeAuthor(uc.userRepo, viewerID, list.UserID)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		if !visible {
+			return nil, nil, nil, entity.ErrReadingListNotFound
+		}
+	}
+
+	items, err := uc.bookmarkRepo.GetListItems(list.ID, cursor, limit)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var next *entity.Cursor
+	if len(items) == limit {
+		last := items[len(items)-1]
+		next = entity.NewCursor(last.CreatedAt, last.ID)
+	}
+
+	blogs := make([]*entity.Blog, 0, len(items))
+	for _, item := range items {
+		if item.Blog != nil {
+			blogs = append(blogs, item.Blog)
+		}
+	}
+	hidden, err := uc.hiddenAuthors(viewerID, blogs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	visible := make([]*entity.ReadingListItem, 0, len(items))
+	shown := make([]*entity.Blog, 0, len(items))
+	for _, item := range items {
+		if item.Blog == nil || hidden[item.Blog.AuthorID] {
+			continue
+		}
+		visible = append(visible, item)
+		shown = append(shown, item.Blog)
+	}
+	if err := uc.MarkBookmarked(viewerID, shown...); err != nil {
+		return nil, nil, nil, err
+	}
+	return list, visible, next, nil
+}
+
+// UpdateList renames one of a user's reading lists, changes its
+// description and makes it public or private
+func (uc *BookmarkUseCase) UpdateList(id, userID int64, name, description string, public bool) (*entity.ReadingList, error) {
+	list, err := uc.ownList(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := list.Update(name, description, public); err != nil {
+		return nil, err
+	}
+	if err := uc.bookmarkRepo.UpdateList(list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// DeleteList deletes one of a user's reading lists
+func (uc *BookmarkUseCase) DeleteList(id, userID int64) error {
+	if _, err := uc.ownList(id, userID); err != nil {
+		return err
+	}
+	return uc.bookmarkRepo.DeleteList(id)
+}
+
+// AddToList adds a blog the user can see to one of their reading lists
+func (uc *BookmarkUseCase) AddToList(listID, userID, blogID int64) (*entity.ReadingListItem, error) {
+	if _, err := uc.ownList(listID, userID); err != nil {
+		return nil, err
+	}
+	if err := uc.checkBlog(userID, blogID); err != nil {
+		return nil, err
+	}
+
+	item := entity.NewReadingListItem(listID, blogID)
+	if err := uc.bookmarkRepo.AddToList(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// RemoveFromList removes a blog from one of a user's reading lists
+func (uc *BookmarkUseCase) RemoveFromList(listID, userID, blogID int64) error {
+	if _, err := uc.ownList(listID, userID); err != nil {
+		return err
+	}
+	return uc.bookmarkRepo.RemoveFromList(listID, blogID)
+}
+
+// ownList retrieves a reading list of userID; other users' lists are not
+// found
+func (uc *BookmarkUseCase) ownList(id, userID int64) (*entity.ReadingList, error) {
+	list, err := uc.bookmarkRepo.GetList(id)
+	if err != nil {
+		return nil, err
+	}
+	if !list.IsOwnedBy(userID) {
+		return nil, entity.ErrReadingListNotFound
+	}
+	return list, nil
+}
+
+// checkBlog checks that a blog is live and visible to userID
+func (uc *BookmarkUseCase) checkBlog(userID, blogID int64) error {
+	blog, err := uc.blogRepo.GetByID(blogID)
+	if err != nil {
+		return err
+	}
+
+	visible, err := canSeeAuthor(uc.userRepo, userID, blog.AuthorID)
+	if err != nil {
+		return err
+	}
+	if !visible {
+		return entity.ErrBlogNotFound
+	}
+	return nil
+}
+
+// hiddenAuthors returns the authors of blogs hidden from viewerID
+func (uc *BookmarkUseCase) hiddenAuthors(viewerID int64, blogs []*entity.Blog) (map[int64]bool, error) {
+	hidden, err := blockedUsers(uc.userRepo, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := hidePrivateAuthors(uc.userRepo, viewerID, blogs, hidden); err != nil {
+		return nil, err
+	}
+	return hidden, nil
+}
@@ -0,0 +1,366 @@
+package usecase
+
+import (
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+)
+
+// CommentUseCase handles comment-related business logic
+type CommentUseCase struct {
+	commentRepo repository.CommentRepository
+	blogRepo    repository.BlogRepository
+	userRepo    repository.UserRepository
+	cacheRepo   repository.CacheRepository
+	maxDepth    int
+	listeners   []CommentListener
+}
+
+// NewCommentUseCase creates a new comment use case. Replies may be nested
+// up to maxDepth levels below a top-level comment.
+func NewCommentUseCase(commentRepo repository.CommentRepository, blogRepo repository.BlogRepository,
+	userRepo repository.UserRepository, cacheRepo repository.CacheRepository, maxDepth int) *CommentUseCase {
+	return &CommentUseCase{
+		commentRepo: commentRepo,
+		blogRepo:    blogRepo,
+		userRepo:    userRepo,
+		cacheRepo:   cacheRepo,
+		maxDepth:    maxDepth,
+	}
+}
+
+// Subscribe registers a listener for published comments
+func (uc *CommentUseCase) Subscribe(listener CommentListener) {
+	uc.listeners = append(uc.listeners, listener)
+}
+
+// commentPublished tells the listeners about a visible comment
+func (uc *CommentUseCase) commentPublished(comment *entity.Comment, created bool) {
+	if !comment.IsPublished() {
+		return
+	}
+	for _, listener := range uc.listeners {
+		listener.CommentPublished(comment, created)
+	}
+}
+
+// CreateComment adds a comment to a blog, as a reply when parentID is set.
+// On moderated blogs, comments from users who don't follow the author are
+// held for approval.
+func (uc *CommentUseCase) CreateComment(blogID, authorID int64, parentID *int64, body string) (*entity.Comment, error) {
+	// Trashed blogs cannot be commented on
+	blog, err := uc.blogRepo.GetByID(blogID)
+	if err != nil {
+		return nil, err
+	}
+	if !blog.AcceptsComments() {
+		return nil, entity.ErrCommentsClosed
+	}
+
+	blocked, err := uc.userRepo.IsBlocked(authorID, blog.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, entity.ErrUserBlocked
+	}
+
+	visible, err := canSeeAuthor(uc.userRepo, authorID, blog.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, entity.ErrBlogNotFound
+	}
+
+	comment := entity.NewComment(blogID, authorID, body)
+	if parentID != nil {
+		parent, err := uc.commentRepo.GetByID(*parentID)
+		if err == entity.ErrCommentNotFound {
+			return nil, entity.ErrParentCommentInvalid
+		}
+		if err != nil {
+			return nil, err
+		}
+		if parent.BlogID != blogID || !parent.IsPublished() {
+			return nil, entity.ErrParentCommentInvalid
+		}
+		if parent.Depth >= uc.maxDepth {
+			return nil, entity.ErrCommentTooDeep
+		}
+		blocked, err := uc.userRepo.IsBlocked(authorID, parent.AuthorID)
+		if err != nil {
+			return nil, err
+		}
+		if blocked {
+			return nil, entity.ErrUserBlocked
+		}
+		comment.ReplyTo(parent)
+	}
+
+	if err := comment.Validate(); err != nil {
+		return nil, err
+	}
+
+	if blog.ModerateComments && !blog.IsOwnedBy(authorID) {
+		following, err := uc.userRepo.IsFollowing(authorID, blog.AuthorID)
+		if err != nil {
+			return nil, err
+		}
+		if !following {
+			comment.Status = entity.CommentPending
+		}
+	}
+
+	if err := uc.commentRepo.Create(comment); err != nil {
+		return nil, err
+	}
+
+	uc.invalidateBlog(blogID)
+
+	uc.commentPublished(comment, true)
+
+	return uc.commentRepo.GetByID(comment.ID)
+}
+
+// GetComments retrieves a page of a blog's published comment threads as
+// seen by viewerID, without comments by users in a block with the viewer;
+// blogs with comments disabled have none, and blogs the viewer cannot see
+// are not found
+func (uc *CommentUseCase) GetComments(blogID, viewerID int64, limit, offset int) ([]*entity.Comment, error) {
+	blog, err := uc.blogRepo.GetByID(blogID)
+	if err != nil {
+		return nil, err
+	}
+	if blog.CommentMode == entity.CommentsDisabled {
+		return []*entity.Comment{}, nil
+	}
+
+	visible, err := canSeeAuthor(uc.userRepo, viewerID, blog.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, entity.ErrBlogNotFound
+	}
+
+	comments, err := uc.commentRepo.GetThreads(blogID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	hidden, err := blockedUsers(uc.userRepo, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	return visibleComments(entity.BuildCommentThreads(comments), hidden), nil
+}
+
+// UpdateComment edits a comment's body
+func (uc *CommentUseCase) UpdateComment(blogID, commentID, userID int64, body string) (*entity.Comment, error) {
+	blog, err := uc.blogRepo.GetByID(blogID)
+	if err != nil {
+		return nil, err
+	}
+	if !blog.AcceptsComments() {
+		return nil, entity.ErrCommentsClosed
+	}
+
+	comment, err := uc.getLiveComment(blogID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check ownership
+	if !comment.IsOwnedBy(userID) {
+		return nil, entity.ErrNotCommentAuthor
+	}
+
+	comment.Update(body)
+	if err := comment.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := uc.commentRepo.Update(comment); err != nil {
+		return nil, err
+	}
+
+	uc.commentPublished(comment, false)
+
+	return comment, nil
+}
+
+// DeleteComment deletes a comment; replies to it stay in the thread.
+// Comments can be deleted by their author and by the blog's owner.
+func (uc *CommentUseCase) DeleteComment(blogID, commentID, userID int64) error {
+	blog, err := uc.blogRepo.GetByID(blogID)
+	if err != nil {
+		return err
+	}
+
+	comment, err := uc.getLiveComment(blogID, commentID)
+	if err != nil {
+		return err
+	}
+
+	// Check ownership
+	if !comment.IsOwnedBy(userID) && !blog.IsOwnedBy(userID) {
+		return entity.ErrNotCommentAuthor
+	}
+
+	if err := uc.commentRepo.Delete(commentID); err != nil {
+		return err
+	}
+
+	if comment.Pinned {
+		if err := uc.blogRepo.SetPinnedComment(blogID, nil); err != nil {
+			return err
+		}
+	}
+
+	uc.invalidateBlog(blogID)
+
+	return nil
+}
+
+// UpdateCommentSettings changes how a blog accepts comments
+func (uc *CommentUseCase) UpdateCommentSettings(blogID, userID int64, mode string, moderate bool) (*entity.Blog, error) {
+	blog, err := uc.getOwnedBlog(blogID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := blog.SetCommentSettings(mode, moderate); err != nil {
+		return nil, err
+	}
+
+	if err := uc.blogRepo.UpdateCommentSettings(blog); err != nil {
+		return nil, err
+	}
+
+	// Invalidate caches
+	if uc.cacheRepo != nil {
+		uc.cacheRepo.DeleteBlog(blogID)
+		uc.cacheRepo.DeletePattern("blogs:*")
+	}
+
+	return blog, nil
+}
+
+// PinComment pins a published top-level comment to the top of the blog's
+// comments, replacing any previously pinned comment
+func (uc *CommentUseCase) PinComment(blogID, commentID, userID int64) error {
+	if _, err := uc.getOwnedBlog(blogID, userID); err != nil {
+		return err
+	}
+
+	comment, err := uc.getLiveComment(blogID, commentID)
+	if err != nil {
+		return err
+	}
+	if !comment.IsPublished() {
+		return entity.ErrCommentNotFound
+	}
+	if comment.ParentID != nil {
+		return entity.ErrCannotPinReply
+	}
+
+	if err := uc.blogRepo.SetPinnedComment(blogID, &commentID); err != nil {
+		return err
+	}
+
+	uc.invalidateBlog(blogID)
+
+	return nil
+}
+
+// UnpinComment removes the pinned comment of a blog
+func (uc *CommentUseCase) UnpinComment(blogID, userID int64) error {
+	if _, err := uc.getOwnedBlog(blogID, userID); err != nil {
+		return err
+	}
+
+	if err := uc.blogRepo.SetPinnedComment(blogID, nil); err != nil {
+		return err
+	}
+
+	uc.invalidateBlog(blogID)
+
+	return nil
+}
+
+// GetPendingComments retrieves the comments awaiting the user's approval
+// across all of their blogs
+func (uc *CommentUseCase) GetPendingComments(userID int64, limit, offset int) ([]*entity.Comment, error) {
+	return uc.commentRepo.GetPendingForAuthor(userID, limit, offset)
+}
+
+// ApproveComment publishes a comment held for approval
+func (uc *CommentUseCase) ApproveComment(blogID, commentID, userID int64) (*entity.Comment, error) {
+	if _, err := uc.getOwnedBlog(blogID, userID); err != nil {
+		return nil, err
+	}
+
+	comment, err := uc.getLiveComment(blogID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.commentRepo.Approve(commentID); err != nil {
+		return nil, err
+	}
+	comment.Status = entity.CommentPublished
+
+	uc.invalidateBlog(blogID)
+
+	uc.commentPublished(comment, true)
+
+	return comment, nil
+}
+
+// RejectComment discards a comment held for approval
+func (uc *CommentUseCase) RejectComment(blogID, commentID, userID int64) error {
+	if _, err := uc.getOwnedBlog(blogID, userID); err != nil {
+		return err
+	}
+
+	comment, err := uc.getLiveComment(blogID, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.Status != entity.CommentPending {
+		return entity.ErrCommentNotPending
+	}
+
+	return uc.commentRepo.Delete(commentID)
+}
+
+// getOwnedBlog retrieves a blog and checks that the user owns it
+func (uc *CommentUseCase) getOwnedBlog(blogID, userID int64) (*entity.Blog, error) {
+	blog, err := uc.blogRepo.GetByID(blogID)
+	if err != nil {
+		return nil, err
+	}
+	if !blog.IsOwnedBy(userID) {
+		return nil, entity.ErrNotBlogOwner
+	}
+	return blog, nil
+}
+
+// getLiveComment retrieves a comment that is not deleted and belongs to the blog
+func (uc *CommentUseCase) getLiveComment(blogID, commentID int64) (*entity.Comment, error) {
+	comment, err := uc.commentRepo.GetByID(commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.BlogID != blogID || comment.IsDeleted() {
+		return nil, entity.ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+// invalidateBlog drops the cached blog so its comment count is refreshed
+func (uc *CommentUseCase) invalidateBlog(blogID int64) {
+	if uc.cacheRepo != nil {
+		uc.cacheRepo.DeleteBlog(blogID)
+	}
+}
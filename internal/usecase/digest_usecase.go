@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+	"AbdelrahmanDwedar/blogo/internal/domain/service"
+	"AbdelrahmanDwedar/blogo/pkg/auth"
+)
+
+// digestLinkPurpose is the purpose of the signed tokens in links that turn
+// digests off
+const digestLinkPurpose = "digest-unsubscribe"
+
+// Limits on what a digest lists
+const (
+	digestMaxNewBlogs  = 10
+	digestMaxTopBlogs  = 5
+	digestMaxFollowers = 10
+)
+
+// digestMaxAttempts is how often sending a digest is tried
+const digestMaxAttempts = 5
+
+// digestPayload is the payload of send_digest jobs
+type digestPayload struct {
+	UserID      int64     `json:"user_id"`
+	Frequency   string    `json:"frequency"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+}
+
+// DigestUseCase sends users who opt in a daily or weekly email summing up
+// the new blogs of the authors they follow, the blogs liked most by the
+// people they follow and their new followers. Each period is claimed once
+// per user before its digest is queued, so a digest is never sent twice,
+// however often or on however many instances the scheduler runs.
+type DigestUseCase struct {
+	digestRepo     repository.DigestRepository
+	userRepo       repository.UserRepository
+	newsletterRepo repository.NewsletterRepository
+	jobRepo        repository.JobRepository
+	preferenceUC   *PreferenceUseCase
+	mailer         service.Mailer
+	renderer       service.DigestRenderer
+	baseURL        string
+}
+
+// NewDigestUseCase creates a new digest use case. Digests are only sent
+// while the digest email preference is on, and not to addresses suppressed
+// for newsletters. baseURL is the public URL of this server, which the
+// links in digests are built on.
+func NewDigestUseCase(digestRepo repository.DigestRepository, userRepo repository.UserRepository,
+	newsletterRepo repository.NewsletterRepository, jobRepo repository.JobRepository,
+	preferenceUC *PreferenceUseCase, mailer service.Mailer, renderer service.DigestRenderer,
+	baseURL string) *DigestUseCase {
+	return &DigestUseCase{
+		digestRepo:     digestRepo,
+		userRepo:       userRepo,
+		newsletterRepo: newsletterRepo,
+		jobRepo:        jobRepo,
+		preferenceUC:   preferenceUC,
+		mailer:         mailer,
+		renderer:       renderer,
+		baseURL:        strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// GetSettings returns how often a user gets a digest
+func (uc *DigestUseCase) GetSettings(userID int64) (*entity.DigestSettings, error) {
+	frequency, err := uc.digestRepo.GetFrequency(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.DigestSettings{Frequency: frequency}, nil
+}
+
+// UpdateSettings sets how often a user gets a digest. Users who opt in get
+// the digest of the last complete period at the next scheduler run.
+func (uc *DigestUseCase) UpdateSettings(userID int64, frequency string) (*entity.DigestSettings, error) {
+	if err := entity.ValidateDigestFrequency(frequency); err != nil {
+		return nil, err
+	}
+	if err := uc.digestRepo.SetFrequency(userID, frequency); err != nil {
+		return nil, err
+	}
+	return &entity.DigestSettings{Frequency: frequency}, nil
+}
+
+// Unsubscribe turns digests off for the user an unsubscribe link was sent
+// to. Following the link again succeeds too.
+func (uc *DigestUseCase) Unsubscribe(token string) error {
+	userID, err := auth.VerifyLinkToken(digestLinkPurpose, token)
+	if err != nil {
+		return err
+	}
+	return uc.digestRepo.SetFrequency(userID, entity.DigestOff)
+}
+
+// ScheduleDigests claims the last complete period of each frequency for
+// the users who get digests at it and have not been claimed for it yet,
+// queues a send_digest job for each, and returns how many were queued.
+// Claims whose job cannot be queued are released for the next run.
+func (uc *DigestUseCase) ScheduleDigests() (int, error) {
+	now := time.Now()
+	queued := 0
+	for _, frequency := range entity.DigestFrequencies {
+		periodStart, periodEnd := entity.DigestPeriod(frequency, now)
+		userIDs, err := uc.digestRepo.ClaimPeriod(frequency, periodStart)
+		if err != nil {
+			return queued, err
+		}
+
+		for _, userID := range userIDs {
+			job, err := entity.NewJob(entity.JobSendDigest, userID, digestPayload{
+				UserID:      userID,
+				Frequency:   frequency,
+				PeriodStart: periodStart,
+				PeriodEnd:   periodEnd,
+			}, digestMaxAttempts)
+			if err == nil {
+				err = uc.jobRepo.Enqueue(job)
+			}
+			if err != nil {
+				log.Printf("⚠️  Failed to queue digest of user %d: %v\n", userID, err)
+
+				// Give the period back so the next run queues it instead
+				// of the user silently missing this digest
+				if err := uc.digestRepo.ReleasePeriod(userID, frequency, periodStart); err != nil {
+					log.Printf("⚠️  Failed to release digest period of user %d: %v\n", userID, err)
+				}
+				continue
+			}
+			queued++
+		}
+	}
+	return queued, nil
+}
+
+// RunSendDigestJob gathers and emails the digest of a send_digest job.
+// Digests with nothing in them are not sent.
+func (uc *DigestUseCase) RunSendDigestJob(job *entity.Job) (string, error) {
+	var payload digestPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return "", entity.ErrJobNotRetryable
+	}
+
+	user, err := uc.userRepo.GetByID(payload.UserID)
+	if errors.Is(err, entity.ErrUserNotFound) {
+		return "user deleted", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// The user may have changed their mind since the job was queued
+	frequency, err := uc.digestRepo.GetFrequency(user.ID)
+	if err != nil {
+		return "", err
+	}
+	if frequency != payload.Frequency {
+		return "digest frequency changed", nil
+	}
+	enabled, err := uc.preferenceUC.Enabled(user.ID, entity.PreferenceDigest, entity.ChannelEmail)
+	if err != nil {
+		return "", err
+	}
+	if !enabled {
+		return "digest emails turned off", nil
+	}
+
+	suppressed, err := uc.newsletterRepo.IsSuppressed(strings.ToLower(user.Email))
+	if err != nil {
+		return "", err
+	}
+	if suppressed {
+		return "address suppressed", nil
+	}
+
+	digest, err := uc.gather(user, payload)
+	if err != nil {
+		return "", err
+	}
+	if digest.IsEmpty() {
+		return "nothing new", nil
+	}
+
+	token := auth.SignLinkToken(digestLinkPurpose, user.ID, 0)
+	message, err := uc.renderer.Digest(digest, uc.baseURL+"/digest/unsubscribe?token="+url.QueryEscape(token))
+	if err != nil {
+		return "", err
+	}
+	message.To = user.Email
+	return sendMail(uc.mailer, uc.newsletterRepo, message)
+}
+
+// gather collects what happened in a digest's period
+func (uc *DigestUseCase) gather(user *entity.User, payload digestPayload) (*entity.Digest, error) {
+	periodStart, periodEnd := payload.PeriodStart, payload.PeriodEnd
+	digest := &entity.Digest{
+		User:        user,
+		Frequency:   payload.Frequency,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+	}
+
+	newBlogs, err := uc.digestRepo.GetNewBlogs(user.ID, periodStart, periodEnd, digestMaxNewBlogs)
+	if err != nil {
+		return nil, err
+	}
+	topBlogs, err := uc.digestRepo.GetTopBlogs(user.ID, periodStart, periodEnd, digestMaxTopBlogs)
+	if err != nil {
+		return nil, err
+	}
+	digest.NewBlogs = uc.entries(newBlogs)
+	digest.TopBlogs = uc.entries(topBlogs)
+
+	digest.NewFollowers, err = uc.digestRepo.GetNewFollowers(user.ID, periodStart, periodEnd, digestMaxFollowers)
+	if err != nil {
+		return nil, err
+	}
+	digest.NewFollowersCount, err = uc.digestRepo.CountNewFollowers(user.ID, periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+	return digest, nil
+}
+
+// entries pairs blogs with their URLs
+func (uc *DigestUseCase) entries(blogs []*entity.Blog) []*entity.DigestEntry {
+	entries := make([]*entity.DigestEntry, 0, len(blogs))
+	for _, blog := range blogs {
+		entries = append(entries, &entity.DigestEntry{Blog: blog, URL: blogURL(uc.baseURL, blog.ID)})
+	}
+	return entries
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+	"AbdelrahmanDwedar/blogo/internal/domain/service"
+)
+
+const (
+	// exportBatchSize is how many blogs are loaded per query during export
+	exportBatchSize = 100
+
+	// exportMaxAttempts is how often a failing export job is tried
+	exportMaxAttempts = 3
+)
+
+// exportPayload is the payload of an export_site job
+type exportPayload struct {
+	UserID int64 `json:"user_id"`
+}
+
+// ExportUseCase handles exporting a user's blogs as a static site
+type ExportUseCase struct {
+	blogRepo repository.BlogRepository
+	userRepo repository.UserRepository
+	jobRepo  repository.JobRepository
+	renderer service.SiteRenderer
+	archives service.ArchiveStore
+}
+
+// NewExportUseCase creates a new export use case
+func NewExportUseCase(blogRepo repository.BlogRepository, userRepo repository.UserRepository,
+	jobRepo repository.JobRepository, renderer service.SiteRenderer, archives service.ArchiveStore) *ExportUseCase {
+	return &ExportUseCase{
+		blogRepo: blogRepo,
+		userRepo: userRepo,
+		jobRepo:  jobRepo,
+		renderer: renderer,
+		archives: archives,
+	}
+}
+
+// ExportSite renders all of a user's published blogs into sink. baseURL is
+// where the site will be hosted; leave it empty for relative links only.
+func (uc *ExportUseCase) ExportSite(userID int64, baseURL string, sink service.FileSink) error {
+	author, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	site := &entity.Site{
+		Author:      author,
+		Blogs:       []*entity.Blog{},
+		BaseURL:     baseURL,
+		GeneratedAt: time.Now(),
+	}
+
+	for offset := 0; ; offset += exportBatchSize {
+		blogs, err := uc.blogRepo.GetByAuthor(userID, exportBatchSize, offset)
+		if err != nil {
+			return err
+		}
+		site.Blogs = append(site.Blogs, blogs...)
+		if len(blogs) < exportBatchSize {
+			break
+		}
+	}
+
+	return uc.renderer.Render(site, sink)
+}
+
+// RequestExport queues a ZIP export of the user's blogs
+func (uc *ExportUseCase) RequestExport(userID int64) (*entity.Job, error) {
+	job, err := entity.NewJob(entity.JobExportSite, userID, exportPayload{UserID: userID}, exportMaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.jobRepo.Enqueue(job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// GetExport retrieves an export job requested by the user
+func (uc *ExportUseCase) GetExport(userID, jobID int64) (*entity.Job, error) {
+	job, err := uc.jobRepo.GetByID(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Don't reveal other users' jobs
+	if job.Kind != entity.JobExportSite || !job.IsOwnedBy(userID) {
+		return nil, entity.ErrJobNotFound
+	}
+	return job, nil
+}
+
+// OpenExport opens the archive produced by a finished export job
+func (uc *ExportUseCase) OpenExport(userID, jobID int64) (io.ReadCloser, error) {
+	job, err := uc.GetExport(userID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != entity.JobStatusDone {
+		return nil, entity.ErrJobNotDone
+	}
+	return uc.archives.Open(job.Result)
+}
+
+// RunExportJob renders an export_site job into a new archive and returns
+// the archive name
+func (uc *ExportUseCase) RunExportJob(job *entity.Job) (string, error) {
+	var payload exportPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return "", entity.ErrJobNotRetryable
+	}
+
+	name := fmt.Sprintf("export-%d.zip", job.ID)
+	archive, err := uc.archives.Create(name)
+	if err != nil {
+		return "", err
+	}
+
+	if err := uc.ExportSite(payload.UserID, "", archive); err != nil {
+		archive.Close()
+		return "", err
+	}
+	if err := archive.Close(); err != nil {
+		return "", err
+	}
+	return name, nil
+}
@@ -0,0 +1,662 @@
+package usecase
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+	"AbdelrahmanDwedar/blogo/internal/domain/service"
+	"AbdelrahmanDwedar/blogo/pkg/activitypub"
+	"AbdelrahmanDwedar/blogo/pkg/httpsig"
+)
+
+const (
+	// outboxPageSize is how many activities an outbox page holds
+	outboxPageSize = 20
+
+	// remoteActorTTL is how long a fetched remote actor is trusted before
+	// it is fetched again
+	remoteActorTTL = 24 * time.Hour
+
+	// deliveryMaxAttempts is how often delivering an activity to an inbox
+	// is tried
+	deliveryMaxAttempts = 8
+)
+
+// deliveryPayload is the payload of a deliver_activity job
+type deliveryPayload struct {
+	UserID   int64           `json:"user_id"`
+	Inbox    string          `json:"inbox"`
+	Activity json.RawMessage `json:"activity"`
+}
+
+// FederationUseCase exposes public users as ActivityPub actors: it serves
+// their actors, outboxes and articles, handles activities remote servers
+// post to their inboxes, and delivers their new, edited and deleted blogs
+// to remote followers through the job queue
+type FederationUseCase struct {
+	federationRepo repository.FederationRepository
+	userRepo       repository.UserRepository
+	blogRepo       repository.BlogRepository
+	jobRepo        repository.JobRepository
+	client         service.FederationClient
+	baseURL        string
+	host           string
+}
+
+// NewFederationUseCase creates a new federation use case. baseURL is the
+// public URL of this server, which all actor and object IRIs are built on.
+func NewFederationUseCase(federationRepo repository.FederationRepository, userRepo repository.UserRepository,
+	blogRepo repository.BlogRepository, jobRepo repository.JobRepository, client service.FederationClient,
+	baseURL string) *FederationUseCase {
+	baseURL = strings.TrimRight(baseURL, "/")
+	var host string
+	if u, err := url.Parse(baseURL); err == nil {
+		host = u.Host
+	}
+
+	return &FederationUseCase{
+		federationRepo: federationRepo,
+		userRepo:       userRepo,
+		blogRepo:       blogRepo,
+		jobRepo:        jobRepo,
+		client:         client,
+		baseURL:        baseURL,
+		host:           host,
+	}
+}
+
+func (uc *FederationUseCase) actorIRI(username string) string {
+	return uc.baseURL + "/ap/users/" + url.PathEscape(username)
+}
+
+func (uc *FederationUseCase) keyID(username string) string {
+	return uc.actorIRI(username) + "#main-key"
+}
+
+func (uc *FederationUseCase) articleIRI(blogID int64) string {
+	return fmt.Sprintf("%s/ap/blogs/%d", uc.baseURL, blogID)
+}
+
+// federatedUser retrieves a user by username. Private accounts are not
+// federated, so they are reported as not found.
+func (uc *FederationUseCase) federatedUser(username string) (*entity.User, error) {
+	user, err := uc.userRepo.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if user.Private {
+		return nil, entity.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// key retrieves a user's actor key pair, generating it on first use
+func (uc *FederationUseCase) key(userID int64) (*entity.ActorKey, error) {
+	key, err := uc.federationRepo.GetKey(userID)
+	if !errors.Is(err, entity.ErrActorKeyNotFound) {
+		return key, err
+	}
+
+	privatePEM, publicPEM, err := httpsig.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	// Another request may have generated a key meanwhile; whichever was
+	// stored first wins
+	err = uc.federationRepo.CreateKey(&entity.ActorKey{
+		UserID:        userID,
+		PublicKeyPEM:  publicPEM,
+		PrivateKeyPEM: privatePEM,
+		CreatedAt:     time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uc.federationRepo.GetKey(userID)
+}
+
+// WebFinger resolves an acct: resource naming a local user to their actor
+func (uc *FederationUseCase) WebFinger(resource string) (*activitypub.WebFinger, error) {
+	account := strings.TrimPrefix(resource, "acct:")
+	username, host, ok := strings.Cut(strings.TrimPrefix(account, "@"), "@")
+	if !ok || !strings.EqualFold(host, uc.host) {
+		return nil, entity.ErrUserNotFound
+	}
+
+	user, err := uc.federatedUser(username)
+	if err != nil {
+		return nil, err
+	}
+
+	actor := uc.actorIRI(user.Username)
+	return &activitypub.WebFinger{
+		Subject: "acct:" + user.Username + "@" + uc.host,
+		Aliases: []string{actor},
+		Links: []activitypub.Link{
+			{Rel: "self", Type: activitypub.ContentType, Href: actor},
+		},
+	}, nil
+}
+
+// GetActor retrieves the actor of a user
+func (uc *FederationUseCase) GetActor(username string) (*activitypub.Actor, error) {
+	user, err := uc.federatedUser(username)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := uc.key(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	iri := uc.actorIRI(user.Username)
+	actor := &activitypub.Actor{
+		Context:           activitypub.Context,
+		ID:                iri,
+		Type:              activitypub.TypePerson,
+		PreferredUsername: user.Username,
+		Name:              user.DisplayName,
+		Summary:           html.EscapeString(user.Bio),
+		URL:               fmt.Sprintf("%s/api/u/%d", uc.baseURL, user.ID),
+		Inbox:             iri + "/inbox",
+		Outbox:            iri + "/outbox",
+		Followers:         iri + "/followers",
+		PublicKey: &activitypub.PublicKey{
+			ID:           uc.keyID(user.Username),
+			Owner:        iri,
+			PublicKeyPem: key.PublicKeyPEM,
+		},
+		Published: &user.CreatedAt,
+	}
+	if user.ProfileImage != "" {
+		actor.Icon = &activitypub.Image{Type: "Image", URL: user.ProfileImage}
+	}
+	return actor, nil
+}
+
+// GetOutbox retrieves the outbox collection of a user; its items are
+// served in pages by GetOutboxPage
+func (uc *FederationUseCase) GetOutbox(username string) (*activitypub.OrderedCollection, error) {
+	user, err := uc.federatedUser(username)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := uc.userRepo.GetStats(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	outbox := uc.actorIRI(user.Username) + "/outbox"
+	return &activitypub.OrderedCollection{
+		Context:    activitypub.Context,
+		ID:         outbox,
+		Type:       activitypub.TypeOrderedCollection,
+		TotalItems: stats.BlogsCount,
+		First:      outbox + "?page=1",
+	}, nil
+}
+
+// GetOutboxPage retrieves a page, starting at 1, of the Create activities
+// of a user's blogs, newest first
+func (uc *FederationUseCase) GetOutboxPage(username string, page int) (*activitypub.OrderedCollectionPage, error) {
+	user, err := uc.federatedUser(username)
+	if err != nil {
+		return nil, err
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	blogs, err := uc.blogRepo.GetByAuthor(user.ID, outboxPageSize, (page-1)*outboxPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	outbox := uc.actorIRI(user.Username) + "/outbox"
+	collection := &activitypub.OrderedCollectionPage{
+		Context: activitypub.Context,
+		ID:      fmt.Sprintf("%s?page=%d", outbox, page),
+		Type:    activitypub.TypeOrderedCollectionPage,
+		PartOf:  outbox,
+		Items:   make([]interface{}, 0, len(blogs)),
+	}
+	if len(blogs) == outboxPageSize {
+		collection.Next = fmt.Sprintf("%s?page=%d", outbox, page+1)
+	}
+	if page > 1 {
+		collection.Prev = fmt.Sprintf("%s?page=%d", outbox, page-1)
+	}
+
+	for _, blog := range blogs {
+		activity, err := uc.blogActivity(activitypub.TypeCreate, user, uc.article(blog, user, nil))
+		if err != nil {
+			return nil, err
+		}
+		collection.Items = append(collection.Items, activity)
+	}
+	return collection, nil
+}
+
+// GetFollowers retrieves the followers collection of a user. Only its size
+// is published; followers themselves are not listed.
+func (uc *FederationUseCase) GetFollowers(username string) (*activitypub.OrderedCollection, error) {
+	user, err := uc.federatedUser(username)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := uc.userRepo.GetStats(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := uc.federationRepo.CountFollowers(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &activitypub.OrderedCollection{
+		Context:    activitypub.Context,
+		ID:         uc.actorIRI(user.Username) + "/followers",
+		Type:       activitypub.TypeOrderedCollection,
+		TotalItems: stats.FollowersCount + remote,
+	}, nil
+}
+
+// GetArticle retrieves the Article object of a blog by a public author,
+// with its likes from local and remote users
+func (uc *FederationUseCase) GetArticle(blogID int64) (*activitypub.Object, error) {
+	blog, err := uc.blogRepo.GetByID(blogID)
+	if err != nil {
+		return nil, err
+	}
+
+	author, err := uc.userRepo.GetByID(blog.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	if author.Private {
+		return nil, entity.ErrBlogNotFound
+	}
+
+	remoteLikes, err := uc.federationRepo.CountLikes(blog.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	article := uc.article(blog, author, &activitypub.OrderedCollection{
+		ID:         uc.articleIRI(blog.ID) + "/likes",
+		Type:       activitypub.TypeOrderedCollection,
+		TotalItems: blog.LikesCount + remoteLikes,
+	})
+	article.Context = activitypub.Context
+	return article, nil
+}
+
+// article builds the Article object of a blog
+func (uc *FederationUseCase) article(blog *entity.Blog, author *entity.User, likes *activitypub.OrderedCollection) *activitypub.Object {
+	created, updated := blog.CreatedAt, blog.UpdatedAt
+	object := &activitypub.Object{
+		ID:           uc.articleIRI(blog.ID),
+		Type:         activitypub.TypeArticle,
+		AttributedTo: uc.actorIRI(author.Username),
+		Name:         blog.Title,
+		Summary:      html.EscapeString(blog.Description),
+		Content:      articleContent(blog.Body),
+		URL:          fmt.Sprintf("%s/api/b/%d", uc.baseURL, blog.ID),
+		Likes:        likes,
+		To:           []string{activitypub.Public},
+		Cc:           []string{uc.actorIRI(author.Username) + "/followers"},
+		Published:    &created,
+	}
+	if updated.After(created) {
+		object.Updated = &updated
+	}
+	for _, tag := range blog.Tags {
+		object.Tag = append(object.Tag, activitypub.Tag{Type: "Hashtag", Name: "#" + tag})
+	}
+	return object
+}
+
+// articleContent renders a blog body as HTML paragraphs, one per block of
+// text separated by blank lines
+func articleContent(body string) string {
+	var b strings.Builder
+	for _, paragraph := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>"))
+		b.WriteString("</p>")
+	}
+	return b.String()
+}
+
+// blogActivity wraps an Article or Tombstone in an activity by its author.
+// A blog is created once, but every update and deletion needs an ID of its
+// own.
+func (uc *FederationUseCase) blogActivity(kind string, author *entity.User, object *activitypub.Object) (*activitypub.Activity, error) {
+	id := object.ID + "#" + strings.ToLower(kind)
+	if kind != activitypub.TypeCreate {
+		id += "-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+
+	activity, err := activitypub.NewActivity(id, kind, uc.actorIRI(author.Username), object)
+	if err != nil {
+		return nil, err
+	}
+	activity.To = object.To
+	activity.Cc = object.Cc
+	activity.Published = object.Published
+	return activity, nil
+}
+
+// remoteActor retrieves the remote actor owning a key, fetching it when it
+// is not cached, was fetched too long ago or refresh is set
+func (uc *FederationUseCase) remoteActor(keyID string, refresh bool) (*entity.RemoteActor, error) {
+	actorID, _, _ := strings.Cut(keyID, "#")
+
+	actor, err := uc.federationRepo.GetRemoteActor(actorID)
+	if err != nil && !errors.Is(err, entity.ErrRemoteActorNotFound) {
+		return nil, err
+	}
+	if err == nil && !refresh && time.Since(actor.FetchedAt) < remoteActorTTL && actorServedBy(actor, actorID, keyID) {
+		return actor, nil
+	}
+
+	actor, err = uc.client.FetchActor(actorID)
+	if err != nil {
+		return nil, err
+	}
+	if !actorServedBy(actor, actorID, keyID) {
+		return nil, entity.ErrInvalidSignature
+	}
+
+	if err := uc.federationRepo.SaveRemoteActor(actor); err != nil {
+		return nil, err
+	}
+	return actor, nil
+}
+
+// actorServedBy checks that an actor fetched from iri is the actor at iri
+// and owns keyID. Its ID must be iri and its key and inboxes must be on the
+// same server, or any server could serve a document claiming to be someone
+// else and post activities or take deliveries in their name.
+func actorServedBy(actor *entity.RemoteActor, iri, keyID string) bool {
+	if actor.ID != iri || actor.PublicKeyID != keyID {
+		return false
+	}
+
+	origin := urlOrigin(iri)
+	if origin == "" || urlOrigin(keyID) != origin || urlOrigin(actor.Inbox) != origin {
+		return false
+	}
+	return actor.SharedInbox == "" || urlOrigin(actor.SharedInbox) == origin
+}
+
+// urlOrigin returns the scheme and host of an absolute URL, or "" when raw
+// is not one
+func urlOrigin(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return ""
+	}
+	return strings.ToLower(u.Scheme) + "://" + strings.ToLower(u.Host)
+}
+
+// PublicKey retrieves the public key a remote actor signs requests with,
+// for verifying their signatures
+func (uc *FederationUseCase) PublicKey(keyID string) (*rsa.PublicKey, error) {
+	return uc.publicKey(keyID, false)
+}
+
+// RefreshPublicKey fetches a remote actor's public key again, for
+// verifying signatures made after the actor rotated its key
+func (uc *FederationUseCase) RefreshPublicKey(keyID string) (*rsa.PublicKey, error) {
+	return uc.publicKey(keyID, true)
+}
+
+func (uc *FederationUseCase) publicKey(keyID string, refresh bool) (*rsa.PublicKey, error) {
+	actor, err := uc.remoteActor(keyID, refresh)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := httpsig.ParsePublicKey(actor.PublicKeyPEM)
+	if err != nil {
+		return nil, entity.ErrInvalidSignature
+	}
+	return key, nil
+}
+
+// HandleInbox processes an activity posted to a user's inbox with a valid
+// signature by keyID. The activity must be by the key's owner. Follows are
+// accepted right away, likes of the user's blogs are recorded, both can be
+// undone, and any other activity is ignored.
+func (uc *FederationUseCase) HandleInbox(username, keyID string, body []byte) error {
+	user, err := uc.federatedUser(username)
+	if err != nil {
+		return err
+	}
+
+	var activity activitypub.Activity
+	if err := json.Unmarshal(body, &activity); err != nil || activity.Type == "" || activity.Actor == "" {
+		return entity.ErrInvalidActivity
+	}
+
+	sender, err := uc.remoteActor(keyID, false)
+	if err != nil {
+		return err
+	}
+	if activity.Actor != sender.ID {
+		return entity.ErrInvalidSignature
+	}
+
+	switch activity.Type {
+	case activitypub.TypeFollow:
+		return uc.handleFollow(user, sender, &activity, body)
+	case activitypub.TypeLike:
+		return uc.handleLike(user, sender, &activity)
+	case activitypub.TypeUndo:
+		return uc.handleUndo(user, sender, &activity)
+	}
+	return nil
+}
+
+// handleFollow records a remote follower and sends them an Accept
+func (uc *FederationUseCase) handleFollow(user *entity.User, sender *entity.RemoteActor, follow *activitypub.Activity, body []byte) error {
+	actor := uc.actorIRI(user.Username)
+	if follow.ObjectID() != actor {
+		return entity.ErrInvalidActivity
+	}
+
+	err := uc.federationRepo.AddFollower(&entity.RemoteFollower{
+		UserID:    user.ID,
+		ActorID:   sender.ID,
+		Inbox:     sender.DeliveryInbox(),
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	accept, err := activitypub.NewActivity(
+		fmt.Sprintf("%s#accept-%d", actor, time.Now().UnixNano()),
+		activitypub.TypeAccept, actor, json.RawMessage(body))
+	if err != nil {
+		return err
+	}
+	accept.To = []string{sender.ID}
+	return uc.enqueue(user.ID, sender.Inbox, accept)
+}
+
+// handleLike records a remote like of one of the user's blogs
+func (uc *FederationUseCase) handleLike(user *entity.User, sender *entity.RemoteActor, like *activitypub.Activity) error {
+	if like.ID == "" {
+		return entity.ErrInvalidActivity
+	}
+
+	id, err := strconv.ParseInt(strings.TrimPrefix(like.ObjectID(), uc.baseURL+"/ap/blogs/"), 10, 64)
+	if err != nil {
+		return entity.ErrInvalidActivity
+	}
+	blog, err := uc.blogRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if blog.AuthorID != user.ID {
+		return entity.ErrInvalidActivity
+	}
+
+	return uc.federationRepo.AddLike(blog.ID, sender.ID, like.ID)
+}
+
+// handleUndo reverts a remote follow or like. Undone activities given only
+// by IRI can only be likes, since follows are recorded by actor.
+func (uc *FederationUseCase) handleUndo(user *entity.User, sender *entity.RemoteActor, undo *activitypub.Activity) error {
+	undone := undo.Embedded()
+	if undone == nil {
+		if id := undo.ObjectID(); id != "" {
+			return uc.federationRepo.RemoveLike(sender.ID, id)
+		}
+		return entity.ErrInvalidActivity
+	}
+	if undone.Actor != "" && undone.Actor != sender.ID {
+		return entity.ErrInvalidSignature
+	}
+
+	switch undone.Type {
+	case activitypub.TypeFollow:
+		return uc.federationRepo.RemoveFollower(user.ID, sender.ID)
+	case activitypub.TypeLike:
+		return uc.federationRepo.RemoveLike(sender.ID, undone.ID)
+	}
+	return nil
+}
+
+// BlogSaved delivers a Create for a new blog, or an Update for an edited
+// or restored one, to the author's remote followers
+func (uc *FederationUseCase) BlogSaved(blog *entity.Blog, created bool) {
+	author, err := uc.userRepo.GetByID(blog.AuthorID)
+	if err != nil {
+		log.Printf("⚠️  Failed to load author of blog %d for federation: %v\n", blog.ID, err)
+		return
+	}
+	if author.Private {
+		return
+	}
+
+	kind := activitypub.TypeUpdate
+	if created {
+		kind = activitypub.TypeCreate
+	}
+	uc.deliver(author, kind, uc.article(blog, author, nil))
+}
+
+// BlogDeleted delivers a Delete of a trashed blog to the author's remote
+// followers
+func (uc *FederationUseCase) BlogDeleted(blog *entity.Blog) {
+	author, err := uc.userRepo.GetByID(blog.AuthorID)
+	if err != nil {
+		log.Printf("⚠️  Failed to load author of blog %d for federation: %v\n", blog.ID, err)
+		return
+	}
+
+	now := time.Now()
+	uc.deliver(author, activitypub.TypeDelete, &activitypub.Object{
+		ID:         uc.articleIRI(blog.ID),
+		Type:       activitypub.TypeTombstone,
+		FormerType: activitypub.TypeArticle,
+		To:         []string{activitypub.Public},
+		Cc:         []string{uc.actorIRI(author.Username) + "/followers"},
+		Deleted:    &now,
+	})
+}
+
+// deliver queues an activity on object for every inbox of the author's
+// remote followers, logging failures
+func (uc *FederationUseCase) deliver(author *entity.User, kind string, object *activitypub.Object) {
+	inboxes, err := uc.federationRepo.GetFollowerInboxes(author.ID)
+	if err != nil {
+		log.Printf("⚠️  Failed to get remote followers of user %d: %v\n", author.ID, err)
+		return
+	}
+	if len(inboxes) == 0 {
+		return
+	}
+
+	activity, err := uc.blogActivity(kind, author, object)
+	if err != nil {
+		log.Printf("⚠️  Failed to build %s of %s: %v\n", kind, object.ID, err)
+		return
+	}
+
+	for _, inbox := range inboxes {
+		if err := uc.enqueue(author.ID, inbox, activity); err != nil {
+			log.Printf("⚠️  Failed to queue %s of %s for %s: %v\n", kind, object.ID, inbox, err)
+		}
+	}
+}
+
+// enqueue queues the delivery of an activity by a user to an inbox
+func (uc *FederationUseCase) enqueue(userID int64, inbox string, activity *activitypub.Activity) error {
+	data, err := json.Marshal(activity)
+	if err != nil {
+		return err
+	}
+
+	job, err := entity.NewJob(entity.JobDeliverActivity, userID, deliveryPayload{
+		UserID:   userID,
+		Inbox:    inbox,
+		Activity: data,
+	}, deliveryMaxAttempts)
+	if err != nil {
+		return err
+	}
+	return uc.jobRepo.Enqueue(job)
+}
+
+// RunDeliveryJob delivers the activity of a deliver_activity job, signed
+// with its sender's key, and returns the inbox it was delivered to
+func (uc *FederationUseCase) RunDeliveryJob(job *entity.Job) (string, error) {
+	var payload deliveryPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return "", entity.ErrJobNotRetryable
+	}
+
+	user, err := uc.userRepo.GetByID(payload.UserID)
+	if errors.Is(err, entity.ErrUserNotFound) {
+		return "", entity.ErrJobNotRetryable
+	}
+	if err != nil {
+		return "", err
+	}
+
+	key, err := uc.key(user.ID)
+	if err != nil {
+		return "", err
+	}
+
+	err = uc.client.Deliver(payload.Inbox, payload.Activity, uc.keyID(user.Username), key.PrivateKeyPEM)
+	if errors.Is(err, entity.ErrDeliveryRejected) {
+		return "", fmt.Errorf("%w: %v", entity.ErrJobNotRetryable, err)
+	}
+	if err != nil {
+		return "", err
+	}
+	return payload.Inbox, nil
+}
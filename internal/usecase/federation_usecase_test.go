@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+	"AbdelrahmanDwedar/blogo/internal/infrastructure/federation"
+	"AbdelrahmanDwedar/blogo/pkg/activitypub"
+	"AbdelrahmanDwedar/blogo/pkg/httpsig"
+	"AbdelrahmanDwedar/blogo/pkg/safehttp"
+)
+
+// inboxFederationRepo keeps remote actors in memory; other methods are not
+// used by the inbox tests
+type inboxFederationRepo struct {
+	repository.FederationRepository
+	actors map[string]*entity.RemoteActor
+}
+
+func (r *inboxFederationRepo) GetRemoteActor(id string) (*entity.RemoteActor, error) {
+	actor, ok := r.actors[id]
+	if !ok {
+		return nil, entity.ErrRemoteActorNotFound
+	}
+	return actor, nil
+}
+
+func (r *inboxFederationRepo) SaveRemoteActor(actor *entity.RemoteActor) error {
+	r.actors[actor.ID] = actor
+	return nil
+}
+
+// inboxUserRepo knows the single local user activities are posted to
+type inboxUserRepo struct {
+	repository.UserRepository
+}
+
+func (r *inboxUserRepo) GetByUsername(username string) (*entity.User, error) {
+	if username != "bob" {
+		return nil, entity.ErrUserNotFound
+	}
+	return &entity.User{ID: 1, Username: "bob"}, nil
+}
+
+// remoteServer serves actor documents built by actorFor for any path, and
+// counts the requests it gets
+type remoteServer struct {
+	*httptest.Server
+	requests int32
+}
+
+func newRemoteServer(t *testing.T, actorFor func(base, path string) *activitypub.Actor) *remoteServer {
+	t.Helper()
+	s := &remoteServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&s.requests, 1)
+		w.Header().Set("Content-Type", activitypub.ContentType)
+		json.NewEncoder(w).Encode(actorFor(s.URL, r.URL.Path))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// honestActor returns the actor at base+path, with its key and inbox on
+// the same server
+func honestActor(t *testing.T) func(base, path string) *activitypub.Actor {
+	t.Helper()
+	_, publicPEM, err := httpsig.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	return func(base, path string) *activitypub.Actor {
+		id := base + path
+		return &activitypub.Actor{
+			ID:        id,
+			Type:      "Person",
+			Inbox:     id + "/inbox",
+			Endpoints: &activitypub.Endpoints{SharedInbox: base + "/inbox"},
+			PublicKey: &activitypub.PublicKey{ID: id + "#main-key", Owner: id, PublicKeyPem: publicPEM},
+		}
+	}
+}
+
+func newInboxUseCase(client *federation.Client) (*FederationUseCase, *inboxFederationRepo) {
+	repo := &inboxFederationRepo{actors: map[string]*entity.RemoteActor{}}
+	uc := NewFederationUseCase(repo, &inboxUserRepo{}, nil, nil, client, "https://blogo.example")
+	return uc, repo
+}
+
+func activityBody(t *testing.T, activityType, actor string) []byte {
+	t.Helper()
+	body, err := json.Marshal(map[string]string{
+		"id":     actor + "/activities/1",
+		"type":   activityType,
+		"actor":  actor,
+		"object": "https://blogo.example/users/bob",
+	})
+	if err != nil {
+		t.Fatalf("marshal activity: %v", err)
+	}
+	return body
+}
+
+func TestHandleInboxAcceptsActorFromItsServer(t *testing.T) {
+	remote := newRemoteServer(t, honestActor(t))
+	uc, repo := newInboxUseCase(federation.NewClient(true))
+
+	actorID := remote.URL + "/users/alice"
+	err := uc.HandleInbox("bob", actorID+"#main-key", activityBody(t, "Announce", actorID))
+	if err != nil {
+		t.Fatalf("HandleInbox: %v", err)
+	}
+	if _, ok := repo.actors[actorID]; !ok {
+		t.Errorf("actor %s was not cached", actorID)
+	}
+}
+
+func TestHandleInboxRejectsImpersonation(t *testing.T) {
+	honest := honestActor(t)
+
+	tests := []struct {
+		name string
+		// forge changes the actor document the remote server serves
+		forge func(actor *activitypub.Actor)
+		// activityActor is the actor the activity claims, when not the
+		// key's owner
+		activityActor string
+	}{
+		{
+			name: "document claims another actor",
+			forge: func(actor *activitypub.Actor) {
+				actor.ID = "https://victim.example/users/alice"
+			},
+		},
+		{
+			name: "key of another actor",
+			forge: func(actor *activitypub.Actor) {
+				actor.PublicKey.ID = "https://victim.example/users/alice#main-key"
+			},
+		},
+		{
+			name: "inbox on another server",
+			forge: func(actor *activitypub.Actor) {
+				actor.Inbox = "https://victim.example/users/alice/inbox"
+			},
+		},
+		{
+			name: "shared inbox on another server",
+			forge: func(actor *activitypub.Actor) {
+				actor.Endpoints.SharedInbox = "https://victim.example/inbox"
+			},
+		},
+		{
+			name:          "activity by someone else than the key owner",
+			activityActor: "https://victim.example/users/alice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote := newRemoteServer(t, func(base, path string) *activitypub.Actor {
+				actor := honest(base, path)
+				if tt.forge != nil {
+					tt.forge(actor)
+				}
+				return actor
+			})
+			uc, repo := newInboxUseCase(federation.NewClient(true))
+
+			actorID := remote.URL + "/users/mallory"
+			activityActor := actorID
+			if tt.activityActor != "" {
+				activityActor = tt.activityActor
+			}
+
+			err := uc.HandleInbox("bob", actorID+"#main-key", activityBody(t, "Follow", activityActor))
+			if !errors.Is(err, entity.ErrInvalidSignature) {
+				t.Fatalf("HandleInbox error = %v, want %v", err, entity.ErrInvalidSignature)
+			}
+			if tt.forge != nil && len(repo.actors) != 0 {
+				t.Errorf("forged actor was cached: %v", repo.actors)
+			}
+		})
+	}
+}
+
+func TestHandleInboxRefusesPrivateAddresses(t *testing.T) {
+	remote := newRemoteServer(t, honestActor(t))
+	uc, _ := newInboxUseCase(federation.NewClient(false))
+
+	actorID := remote.URL + "/users/alice"
+	err := uc.HandleInbox("bob", actorID+"#main-key", activityBody(t, "Follow", actorID))
+	if !errors.Is(err, safehttp.ErrForbiddenAddress) {
+		t.Fatalf("HandleInbox error = %v, want %v", err, safehttp.ErrForbiddenAddress)
+	}
+	if n := atomic.LoadInt32(&remote.requests); n != 0 {
+		t.Errorf("loopback server got %d requests, want none", n)
+	}
+}
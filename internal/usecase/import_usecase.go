@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+	"AbdelrahmanDwedar/blogo/internal/domain/service"
+)
+
+// ImportUseCase handles importing posts from other blogging platforms
+type ImportUseCase struct {
+	blogRepo repository.BlogRepository
+	blogUC   *BlogUseCase
+	parser   service.ImportParser
+}
+
+// NewImportUseCase creates a new import use case. Blogs are saved through
+// blogUC so that its listeners hear about them.
+func NewImportUseCase(blogRepo repository.BlogRepository, blogUC *BlogUseCase, parser service.ImportParser) *ImportUseCase {
+	return &ImportUseCase{
+		blogRepo: blogRepo,
+		blogUC:   blogUC,
+		parser:   parser,
+	}
+}
+
+// ImportFile parses an uploaded file and imports its posts as blogs
+// written by authorID. An empty format is detected from the file's name.
+func (uc *ImportUseCase) ImportFile(authorID int64, format, name string, data []byte, dryRun bool) (*entity.ImportReport, error) {
+	items, err := uc.parser.Parse(format, name, data)
+	if err != nil {
+		return nil, err
+	}
+	return uc.ImportBlogs(authorID, items, dryRun), nil
+}
+
+// ImportBlogs saves parsed posts as blogs written by authorID. Each item
+// succeeds or fails on its own; failures are listed in the report. In a
+// dry run every post is validated and checked for slug clashes but nothing
+// is saved.
+func (uc *ImportUseCase) ImportBlogs(authorID int64, items []*entity.ImportItem, dryRun bool) *entity.ImportReport {
+	report := &entity.ImportReport{
+		DryRun: dryRun,
+		Total:  len(items),
+		Items:  make([]*entity.ImportItemResult, 0, len(items)),
+	}
+	slugs := map[string]bool{}
+
+	for _, item := range items {
+		result := &entity.ImportItemResult{Source: item.Source}
+		report.Items = append(report.Items, result)
+
+		if err := uc.importItem(authorID, item, slugs, dryRun, result); err != nil {
+			result.Status = entity.ImportStatusFailed
+			result.Error = err.Error()
+			report.Failed++
+			continue
+		}
+		report.Imported++
+	}
+
+	return report
+}
+
+// importItem validates and, unless dryRun, saves a single post
+func (uc *ImportUseCase) importItem(authorID int64, item *entity.ImportItem, slugs map[string]bool, dryRun bool, result *entity.ImportItemResult) error {
+	if item.Err != nil {
+		return item.Err
+	}
+
+	blog := item.Blog
+	blog.AuthorID = authorID
+	result.Title = blog.Title
+	result.Slug = blog.Slug
+
+	if err := blog.Validate(); err != nil {
+		return err
+	}
+
+	// Slugs must be unique within the import and among existing blogs
+	if slugs[blog.Slug] {
+		return entity.ErrDuplicateSlug
+	}
+	if _, err := uc.blogRepo.GetBySlug(authorID, blog.Slug); err == nil {
+		return entity.ErrDuplicateSlug
+	} else if err != entity.ErrBlogNotFound {
+		return err
+	}
+	slugs[blog.Slug] = true
+
+	if dryRun {
+		result.Status = entity.ImportStatusValid
+		return nil
+	}
+
+	if err := uc.blogUC.ImportBlog(blog); err != nil {
+		return err
+	}
+	result.BlogID = blog.ID
+	result.Status = entity.ImportStatusImported
+	return nil
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import "AbdelrahmanDwedar/blogo/internal/domain/entity"
+
+// BlogListener is told about blog changes after they are saved, so features
+// like mentions can react without the blog use case knowing about them.
+// Listeners run synchronously and handle their own errors.
+type BlogListener interface {
+	// BlogSaved is called after a blog is created, updated or restored
+	// from the trash
+	BlogSaved(blog *entity.Blog, created bool)
+
+	// BlogDeleted is called after a blog is moved to the trash
+	BlogDeleted(blog *entity.Blog)
+}
+
+// RelationshipListener is told about follows and mutes after they change
+type RelationshipListener interface {
+	// UserFollowed is called after followerID starts following followingID
+	// without needing approval
+	UserFollowed(followerID, followingID int64)
+
+	// FollowRequested is called after followerID asks to follow the private
+	// account followingID
+	FollowRequested(followerID, followingID int64)
+
+	// FollowAccepted is called after followingID accepts the follow request
+	// of followerID
+	FollowAccepted(followerID, followingID int64)
+
+	// UserUnfollowed is called after followerID stops following followingID
+	// or withdraws a follow request, including when either blocks the other
+	UserUnfollowed(followerID, followingID int64)
+
+	// UserMuted is called after muterID mutes mutedID
+	UserMuted(muterID, mutedID int64)
+
+	// UserUnmuted is called after muterID unmutes mutedID
+	UserUnmuted(muterID, mutedID int64)
+}
+
+// ProfileListener is told about profile changes after they are saved
+type ProfileListener interface {
+	// ProfileUpdated is called after a user edits their profile, including
+	// making their account private or public
+	ProfileUpdated(user *entity.User)
+}
+
+// ReactionListener is told about reactions after they are added
+type ReactionListener interface {
+	// BlogReacted is called after userID reacts to blog with kind
+	BlogReacted(blog *entity.Blog, userID int64, kind string)
+}
+
+// NotificationListener sends notifications on the channel it subscribed
+// to
+type NotificationListener interface {
+	// NotificationCreated is called for each notification sent on the
+	// listener's channel. Only notifications also sent in-app are stored
+	// and have an ID.
+	NotificationCreated(notification *entity.Notification)
+}
+
+// CommentListener is told about comments once they are visible
+type CommentListener interface {
+	// CommentPublished is called after a comment is published or edited;
+	// created is true the first time the comment becomes visible
+	CommentPublished(comment *entity.Comment, created bool)
+}
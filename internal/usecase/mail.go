@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+	"AbdelrahmanDwedar/blogo/internal/domain/service"
+)
+
+// sendMail sends an email from a job. Addresses the mail server refuses
+// are suppressed; messages it refuses for other reasons are not retried.
+func sendMail(mailer service.Mailer, newsletterRepo repository.NewsletterRepository, message *entity.EmailMessage) (string, error) {
+	err := mailer.Send(message)
+	if errors.Is(err, entity.ErrRecipientRejected) {
+		if err := newsletterRepo.Suppress(message.To, entity.BounceHard); err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("%w: %v", entity.ErrJobNotRetryable, err)
+	}
+	if errors.Is(err, entity.ErrMailRejected) {
+		return "", fmt.Errorf("%w: %v", entity.ErrJobNotRetryable, err)
+	}
+	if err != nil {
+		return "", err
+	}
+	return "sent", nil
+}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"log"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+)
+
+// MentionUseCase records @mentions in blogs and comments and notifies the
+// mentioned users
+type MentionUseCase struct {
+	mentionRepo    repository.MentionRepository
+	userRepo       repository.UserRepository
+	blogRepo       repository.BlogRepository
+	notificationUC *NotificationUseCase
+}
+
+// NewMentionUseCase creates a new mention use case
+func NewMentionUseCase(mentionRepo repository.MentionRepository, userRepo repository.UserRepository,
+	blogRepo repository.BlogRepository, notificationUC *NotificationUseCase) *MentionUseCase {
+	return &MentionUseCase{
+		mentionRepo:    mentionRepo,
+		userRepo:       userRepo,
+		blogRepo:       blogRepo,
+		notificationUC: notificationUC,
+	}
+}
+
+// BlogSaved records the mentions in a saved blog's body
+func (uc *MentionUseCase) BlogSaved(blog *entity.Blog, created bool) {
+	if err := uc.record(blog.ID, blog.AuthorID, nil, blog.AuthorID, blog.Body); err != nil {
+		log.Printf("⚠️  Failed to record mentions in blog %d: %v\n", blog.ID, err)
+	}
+}
+
+// BlogDeleted keeps the mentions of a trashed blog; they are hidden while
+// it is in the trash and restored with it
+func (uc *MentionUseCase) BlogDeleted(blog *entity.Blog) {}
+
+// CommentPublished records the mentions in a published comment
+func (uc *MentionUseCase) CommentPublished(comment *entity.Comment, created bool) {
+	blog, err := uc.blogRepo.GetByID(comment.BlogID)
+	if err != nil {
+		log.Printf("⚠️  Failed to record mentions in comment %d: %v\n", comment.ID, err)
+		return
+	}
+	if err := uc.record(blog.ID, blog.AuthorID, &comment.ID, comment.AuthorID, comment.Body); err != nil {
+		log.Printf("⚠️  Failed to record mentions in comment %d: %v\n", comment.ID, err)
+	}
+}
+
+// record resolves the users mentioned in text, stores them as the mentions
+// of the blog or comment, and notifies users who weren't mentioned before.
+// Users who may not see the blog's author are left out, so that mentions
+// don't reveal a private account's posts.
+func (uc *MentionUseCase) record(blogID, blogAuthorID int64, commentID *int64, authorID int64, text string) error {
+	userIDs := []int64{}
+	for _, username := range entity.ParseMentions(text) {
+		if len(userIDs) == entity.MaxMentions {
+			break
+		}
+
+		user, err := uc.userRepo.GetByUsername(username)
+		if err == entity.ErrUserNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		// Mentioning yourself is not worth a record
+		if user.ID == authorID {
+			continue
+		}
+		visible, err := canSeeAuthor(uc.userRepo, user.ID, blogAuthorID)
+		if err != nil {
+			return err
+		}
+		if visible {
+			userIDs = append(userIDs, user.ID)
+		}
+	}
+
+	added, err := uc.mentionRepo.Replace(blogID, commentID, authorID, userIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range added {
+		notification := entity.NewNotification(userID, entity.NotificationMention, authorID)
+		notification.BlogID = &blogID
+		notification.CommentID = commentID
+		if err := uc.notificationUC.Notify(notification); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetMentions retrieves the posts and comments that mention a user
+func (uc *MentionUseCase) GetMentions(userID int64, limit, offset int) ([]*entity.Mention, error) {
+	return uc.mentionRepo.GetByUser(userID, limit, offset)
+}
+
+// RenderBlog fills in the blog's BodyHTML with its mentions linked
+func (uc *MentionUseCase) RenderBlog(blog *entity.Blog) error {
+	mentions, err := uc.mentionRepo.GetForBlog(blog.ID)
+	if err != nil {
+		return err
+	}
+	blog.BodyHTML = entity.RenderMentions(blog.Body, mentions)
+	return nil
+}
+
+// RenderComments fills in BodyHTML for comments and all of their replies
+func (uc *MentionUseCase) RenderComments(comments []*entity.Comment) error {
+	ids := []int64{}
+	collectCommentIDs(comments, &ids)
+
+	mentions, err := uc.mentionRepo.GetForComments(ids)
+	if err != nil {
+		return err
+	}
+
+	byComment := map[int64][]*entity.Mention{}
+	for _, mention := range mentions {
+		byComment[*mention.CommentID] = append(byComment[*mention.CommentID], mention)
+	}
+	renderComments(comments, byComment)
+	return nil
+}
+
+func collectCommentIDs(comments []*entity.Comment, ids *[]int64) {
+	for _, comment := range comments {
+		*ids = append(*ids, comment.ID)
+		collectCommentIDs(comment.Replies, ids)
+	}
+}
+
+func renderComments(comments []*entity.Comment, mentions map[int64][]*entity.Mention) {
+	for _, comment := range comments {
+		comment.BodyHTML = entity.RenderMentions(comment.Body, mentions[comment.ID])
+		renderComments(comment.Replies, mentions)
+	}
+}
@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+	"AbdelrahmanDwedar/blogo/internal/domain/service"
+)
+
+// maxRecentPosts bounds how many posts getRecentPosts returns
+const maxRecentPosts = 100
+
+// MetaWeblogPost is a post as sent by a MetaWeblog client. Nil fields are
+// left unchanged by edits.
+type MetaWeblogPost struct {
+	Title   *string
+	Excerpt *string
+	Body    *string
+	Tags    []string
+}
+
+// MetaWeblogUseCase publishes blogs from desktop blogging tools speaking
+// the MetaWeblog API. Each user is a weblog whose ID is their user ID, and
+// clients log in with the user's username and an access token as the
+// application password; every change needs the token scope of its kind.
+type MetaWeblogUseCase struct {
+	blogUC     *BlogUseCase
+	tokenUC    *AccessTokenUseCase
+	userRepo   repository.UserRepository
+	mediaStore service.MediaStore
+	baseURL    string
+}
+
+// NewMetaWeblogUseCase creates a new MetaWeblog use case. baseURL is the
+// public URL of this server, which blog and media URLs are built on.
+func NewMetaWeblogUseCase(blogUC *BlogUseCase, tokenUC *AccessTokenUseCase, userRepo repository.UserRepository,
+	mediaStore service.MediaStore, baseURL string) *MetaWeblogUseCase {
+	return &MetaWeblogUseCase{
+		blogUC:     blogUC,
+		tokenUC:    tokenUC,
+		userRepo:   userRepo,
+		mediaStore: mediaStore,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// BlogURL returns the URL of a blog
+func (uc *MetaWeblogUseCase) BlogURL(blogID int64) string {
+	return blogURL(uc.baseURL, blogID)
+}
+
+// WeblogURL returns the URL of a user's weblog, their profile
+func (uc *MetaWeblogUseCase) WeblogURL(userID int64) string {
+	return fmt.Sprintf("%s/api/u/%d", uc.baseURL, userID)
+}
+
+// Login returns the access token a client logs in with as the application
+// password of the user with the given username
+func (uc *MetaWeblogUseCase) Login(username, password string) (*entity.AccessToken, error) {
+	user, err := uc.userRepo.GetByUsername(username)
+	if errors.Is(err, entity.ErrUserNotFound) {
+		return nil, entity.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := uc.tokenUC.Authenticate(password)
+	if errors.Is(err, entity.ErrAccessTokenNotFound) {
+		return nil, entity.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if token.UserID != user.ID {
+		return nil, entity.ErrInvalidCredentials
+	}
+	return token, nil
+}
+
+// GetUser returns the user a token belongs to, whose weblog it can post to
+func (uc *MetaWeblogUseCase) GetUser(token *entity.AccessToken) (*entity.User, error) {
+	return uc.userRepo.GetByID(token.UserID)
+}
+
+// NewPost publishes a blog on the token owner's weblog. Posts without a
+// title are titled after the start of their body.
+func (uc *MetaWeblogUseCase) NewPost(token *entity.AccessToken, weblogID int64, post *MetaWeblogPost) (*entity.Blog, error) {
+	if !token.HasScope(entity.ScopeCreate) {
+		return nil, entity.ErrInsufficientScope
+	}
+	if weblogID != token.UserID {
+		return nil, entity.ErrNotBlogOwner
+	}
+
+	fields := &blogFields{tags: post.Tags}
+	post.applyTo(fields)
+	if fields.title == "" {
+		fields.title = noteTitle(fields.body)
+	}
+
+	return uc.blogUC.CreateBlog(fields.title, fields.description, fields.body, fields.tags, token.UserID)
+}
+
+// EditPost changes the fields of one of the token owner's blogs that the
+// post sets
+func (uc *MetaWeblogUseCase) EditPost(token *entity.AccessToken, blogID int64, post *MetaWeblogPost) (*entity.Blog, error) {
+	if !token.HasScope(entity.ScopeUpdate) {
+		return nil, entity.ErrInsufficientScope
+	}
+
+	return editBlog(uc.blogUC, blogID, token.UserID, func(fields *blogFields) error {
+		post.applyTo(fields)
+		if post.Tags != nil {
+			fields.tags = post.Tags
+		}
+		return nil
+	})
+}
+
+// applyTo copies the fields the post sets
+func (p *MetaWeblogPost) applyTo(fields *blogFields) {
+	if p.Title != nil {
+		fields.title = *p.Title
+	}
+	if p.Excerpt != nil {
+		fields.description = *p.Excerpt
+	}
+	if p.Body != nil {
+		fields.body = *p.Body
+	}
+}
+
+// GetPost returns one of the token owner's blogs
+func (uc *MetaWeblogUseCase) GetPost(token *entity.AccessToken, blogID int64) (*entity.Blog, error) {
+	blog, err := uc.blogUC.GetBlogByID(blogID, token.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !blog.IsOwnedBy(token.UserID) {
+		return nil, entity.ErrNotBlogOwner
+	}
+	return blog, nil
+}
+
+// GetRecentPosts returns the newest blogs of the token owner's weblog, at
+// most maxRecentPosts of them
+func (uc *MetaWeblogUseCase) GetRecentPosts(token *entity.AccessToken, weblogID int64, count int) ([]*entity.Blog, error) {
+	if weblogID != token.UserID {
+		return nil, entity.ErrNotBlogOwner
+	}
+	if count <= 0 || count > maxRecentPosts {
+		count = maxRecentPosts
+	}
+	return uc.blogUC.GetBlogsByAuthor(token.UserID, count, 0)
+}
+
+// DeletePost moves one of the token owner's blogs to the trash
+func (uc *MetaWeblogUseCase) DeletePost(token *entity.AccessToken, blogID int64) error {
+	if !token.HasScope(entity.ScopeDelete) {
+		return entity.ErrInsufficientScope
+	}
+	return uc.blogUC.DeleteBlog(blogID, token.UserID)
+}
+
+// NewMediaObject stores a file uploaded to the token owner's weblog and
+// returns its URL
+func (uc *MetaWeblogUseCase) NewMediaObject(token *entity.AccessToken, weblogID int64, name, contentType string, data []byte) (string, error) {
+	if !token.HasScope(entity.ScopeCreate) {
+		return "", entity.ErrInsufficientScope
+	}
+	if weblogID != token.UserID {
+		return "", entity.ErrNotBlogOwner
+	}
+
+	file, err := entity.NewMediaFile(token.UserID, name, contentType, data)
+	if err != nil {
+		return "", err
+	}
+	if err := uc.mediaStore.Save(file); err != nil {
+		return "", err
+	}
+	return uc.baseURL + "/media/" + url.PathEscape(file.Name), nil
+}
+
+// OpenMedia opens an uploaded media file, returning its content type
+func (uc *MetaWeblogUseCase) OpenMedia(name string) (string, io.ReadCloser, error) {
+	contentType, ok := entity.MediaContentType(name)
+	if !ok {
+		return "", nil, entity.ErrMediaNotFound
+	}
+
+	file, err := uc.mediaStore.Open(name)
+	if err != nil {
+		return "", nil, err
+	}
+	return contentType, file, nil
+}
@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"strings"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/pkg/micropub"
+)
+
+// noteTitleLength bounds the title derived from the content of a post
+// without a name
+const noteTitleLength = 60
+
+// MicropubUseCase publishes blogs from Micropub clients. Posts map onto
+// blogs as name → title, summary → description, content → body and
+// category → tags; posts without a name are notes, titled after the start
+// of their content. Every action needs an access token with its scope.
+type MicropubUseCase struct {
+	blogUC  *BlogUseCase
+	baseURL string
+}
+
+// NewMicropubUseCase creates a new Micropub use case. baseURL is the
+// public URL of this server, which blog URLs are built on.
+func NewMicropubUseCase(blogUC *BlogUseCase, baseURL string) *MicropubUseCase {
+	return &MicropubUseCase{
+		blogUC:  blogUC,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// BlogURL returns the URL of a blog, which Micropub clients refer to it by
+func (uc *MicropubUseCase) BlogURL(blogID int64) string {
+	return blogURL(uc.baseURL, blogID)
+}
+
+// Config describes what the endpoint supports
+func (uc *MicropubUseCase) Config() *micropub.Config {
+	return &micropub.Config{
+		SyndicateTo: []interface{}{},
+		PostTypes: []micropub.PostType{
+			{Type: "note", Name: "Note"},
+			{Type: "article", Name: "Article"},
+		},
+		Q: []string{"config", "source", "syndicate-to"},
+	}
+}
+
+// Source returns a blog the token's owner can see as a Micropub entry,
+// limited to the given properties unless none are given
+func (uc *MicropubUseCase) Source(token *entity.AccessToken, rawURL string, properties []string) (*micropub.Entry, error) {
+	blog, err := uc.blogUC.GetBlogByID(blogIDFromURL(uc.baseURL, rawURL), token.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]interface{}, len(blog.Tags))
+	for i, tag := range blog.Tags {
+		tags[i] = tag
+	}
+	all := micropub.Properties{
+		"name":      {blog.Title},
+		"content":   {blog.Body},
+		"category":  tags,
+		"published": {blog.CreatedAt.Format(time.RFC3339)},
+		"updated":   {blog.UpdatedAt.Format(time.RFC3339)},
+		"url":       {uc.BlogURL(blog.ID)},
+	}
+	if blog.Description != "" {
+		all["summary"] = []interface{}{blog.Description}
+	}
+
+	entry := &micropub.Entry{Type: []string{micropub.TypeEntry}, Properties: all}
+	if len(properties) > 0 {
+		entry.Properties = micropub.Properties{}
+		for _, name := range properties {
+			if values, ok := all[name]; ok {
+				entry.Properties[name] = values
+			}
+		}
+	}
+	return entry, nil
+}
+
+// Publish carries out a Micropub request for the token's owner. Creates,
+// updates and undeletes return the blog; deletes move it to the trash and
+// return nil. Each action needs the token scope of the same name, except
+// undelete, which needs the delete scope.
+func (uc *MicropubUseCase) Publish(token *entity.AccessToken, req *micropub.Request) (*entity.Blog, error) {
+	scope := req.Action
+	if scope == micropub.ActionUndelete {
+		scope = entity.ScopeDelete
+	}
+	if !token.HasScope(scope) {
+		return nil, entity.ErrInsufficientScope
+	}
+
+	if req.Action == micropub.ActionCreate {
+		return uc.create(token.UserID, req)
+	}
+
+	blogID := blogIDFromURL(uc.baseURL, req.URL)
+	if blogID == 0 {
+		return nil, entity.ErrBlogNotFound
+	}
+
+	switch req.Action {
+	case micropub.ActionUpdate:
+		return uc.update(token.UserID, blogID, req)
+	case micropub.ActionDelete:
+		return nil, uc.blogUC.DeleteBlog(blogID, token.UserID)
+	case micropub.ActionUndelete:
+		return uc.blogUC.RestoreBlog(blogID, token.UserID)
+	}
+	return nil, micropub.ErrInvalidRequest
+}
+
+// create publishes a new blog from an h-entry
+func (uc *MicropubUseCase) create(userID int64, req *micropub.Request) (*entity.Blog, error) {
+	if req.Type != micropub.TypeEntry {
+		return nil, micropub.ErrInvalidRequest
+	}
+
+	post := &blogFields{
+		title:       req.Properties.String("name"),
+		description: req.Properties.String("summary"),
+		body:        req.Properties.String("content"),
+		tags:        micropub.Strings(req.Properties["category"]),
+	}
+	if post.title == "" {
+		post.title = noteTitle(post.body)
+	}
+
+	return uc.blogUC.CreateBlog(post.title, post.description, post.body, post.tags, userID)
+}
+
+// update applies the replacements, additions and deletions of an update
+// request to a blog
+func (uc *MicropubUseCase) update(userID, blogID int64, req *micropub.Request) (*entity.Blog, error) {
+	return editBlog(uc.blogUC, blogID, userID, func(post *blogFields) error {
+		return applyMicropubUpdate(post, req)
+	})
+}
+
+// applyMicropubUpdate changes the post as an update request describes
+func applyMicropubUpdate(p *blogFields, req *micropub.Request) error {
+	for name, values := range req.Replace {
+		switch name {
+		case "name":
+			p.title = micropub.Properties{name: values}.String(name)
+		case "summary":
+			p.description = micropub.Properties{name: values}.String(name)
+		case "content":
+			p.body = micropub.Properties{name: values}.String(name)
+		case "category":
+			p.tags = micropub.Strings(values)
+		}
+	}
+
+	for name, values := range req.Add {
+		text := micropub.Properties{name: values}.String(name)
+		var err error
+		switch name {
+		case "name":
+			err = addSingle(&p.title, text)
+		case "summary":
+			err = addSingle(&p.description, text)
+		case "content":
+			err = addSingle(&p.body, text)
+		case "category":
+			p.tags = append(p.tags, micropub.Strings(values)...)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range req.DeleteAll {
+		switch name {
+		case "name":
+			p.title = noteTitle(p.body)
+		case "summary":
+			p.description = ""
+		case "content":
+			p.body = ""
+		case "category":
+			p.tags = []string{}
+		}
+	}
+
+	if values, ok := req.Delete["category"]; ok {
+		removed := map[string]bool{}
+		for _, tag := range entity.NormalizeTags(micropub.Strings(values)) {
+			removed[tag] = true
+		}
+		kept := []string{}
+		for _, tag := range entity.NormalizeTags(p.tags) {
+			if !removed[tag] {
+				kept = append(kept, tag)
+			}
+		}
+		p.tags = kept
+	}
+
+	return nil
+}
+
+// addSingle adds a value to a property a blog holds only one of, which
+// only works while the property is empty
+func addSingle(field *string, text string) error {
+	if *field != "" {
+		return micropub.ErrInvalidRequest
+	}
+	*field = text
+	return nil
+}
+
+// noteTitle titles a post without a name after the start of its content's
+// first line
+func noteTitle(content string) string {
+	line := strings.TrimSpace(content)
+	if i := strings.IndexByte(line, '\n'); i >= 0 {
+		line = strings.TrimSpace(line[:i])
+	}
+	line = strings.Join(strings.Fields(line), " ")
+
+	if runes := []rune(line); len(runes) > noteTitleLength {
+		line = strings.TrimSpace(string(runes[:noteTitleLength-1])) + "…"
+	}
+	if line == "" {
+		return "Note"
+	}
+	return line
+}
@@ -0,0 +1,305 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+	"AbdelrahmanDwedar/blogo/internal/domain/service"
+	"AbdelrahmanDwedar/blogo/pkg/auth"
+)
+
+// Purposes of the signed tokens in newsletter links
+const (
+	confirmLinkPurpose     = "newsletter-confirm"
+	unsubscribeLinkPurpose = "newsletter-unsubscribe"
+)
+
+// confirmLinkTTL is how long a confirmation link works
+const confirmLinkTTL = 7 * 24 * time.Hour
+
+// newsletterMaxAttempts is how often sending a newsletter email is tried
+const newsletterMaxAttempts = 5
+
+// newsletterPayload is the payload of send_confirmation, send_newsletters
+// and send_newsletter jobs, which each use the fields they need
+type newsletterPayload struct {
+	SubscriptionID int64 `json:"subscription_id,omitempty"`
+	BlogID         int64 `json:"blog_id,omitempty"`
+}
+
+// NewsletterUseCase lets readers without an account subscribe to authors
+// by email. Subscriptions are confirmed through a link sent to the address
+// (double opt-in); once confirmed, every new blog by the author is emailed
+// to the subscriber through the job queue, with a signed link to
+// unsubscribe. Addresses that bounce or complain are suppressed for good.
+type NewsletterUseCase struct {
+	newsletterRepo repository.NewsletterRepository
+	userRepo       repository.UserRepository
+	blogRepo       repository.BlogRepository
+	jobRepo        repository.JobRepository
+	mailer         service.Mailer
+	renderer       service.NewsletterRenderer
+	baseURL        string
+}
+
+// NewNewsletterUseCase creates a new newsletter use case. baseURL is the
+// public URL of this server, which the links in emails are built on.
+func NewNewsletterUseCase(newsletterRepo repository.NewsletterRepository, userRepo repository.UserRepository,
+	blogRepo repository.BlogRepository, jobRepo repository.JobRepository, mailer service.Mailer,
+	renderer service.NewsletterRenderer, baseURL string) *NewsletterUseCase {
+	return &NewsletterUseCase{
+		newsletterRepo: newsletterRepo,
+		userRepo:       userRepo,
+		blogRepo:       blogRepo,
+		jobRepo:        jobRepo,
+		mailer:         mailer,
+		renderer:       renderer,
+		baseURL:        strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Subscribe subscribes an email address to a public author and sends it a
+// confirmation link. Subscribing an address again resends the link while
+// the subscription is pending; confirmed and suppressed addresses are
+// left alone, without telling the caller, so the endpoint does not reveal
+// who subscribes.
+func (uc *NewsletterUseCase) Subscribe(authorID int64, email string) error {
+	subscription, err := entity.NewSubscription(authorID, email)
+	if err != nil {
+		return err
+	}
+
+	visible, err := canSeeAuthor(uc.userRepo, 0, authorID)
+	if err != nil {
+		return err
+	}
+	if !visible {
+		return entity.ErrUserNotFound
+	}
+
+	suppressed, err := uc.newsletterRepo.IsSuppressed(subscription.Email)
+	if err != nil {
+		return err
+	}
+	if suppressed {
+		return nil
+	}
+
+	if err := uc.newsletterRepo.Subscribe(subscription); err != nil {
+		return err
+	}
+	if subscription.IsConfirmed() {
+		return nil
+	}
+
+	return uc.enqueue(entity.JobSendConfirmation, authorID, newsletterPayload{SubscriptionID: subscription.ID})
+}
+
+// Confirm confirms the subscription a confirmation link was sent for
+func (uc *NewsletterUseCase) Confirm(token string) (*entity.Subscription, error) {
+	id, err := auth.VerifyLinkToken(confirmLinkPurpose, token)
+	if err != nil {
+		return nil, err
+	}
+	return uc.newsletterRepo.Confirm(id)
+}
+
+// Unsubscribe removes the subscription an unsubscribe link was sent for.
+// Following the link again succeeds too.
+func (uc *NewsletterUseCase) Unsubscribe(token string) error {
+	id, err := auth.VerifyLinkToken(unsubscribeLinkPurpose, token)
+	if err != nil {
+		return err
+	}
+
+	err = uc.newsletterRepo.Delete(id)
+	if errors.Is(err, entity.ErrSubscriptionNotFound) {
+		return nil
+	}
+	return err
+}
+
+// RecordBounce records that email to an address bounced. Hard bounces and
+// spam complaints suppress the address; soft bounces are temporary and
+// ignored.
+func (uc *NewsletterUseCase) RecordBounce(email, kind string) error {
+	email, err := entity.NormalizeEmail(email)
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case entity.BounceHard, entity.BounceComplaint:
+		return uc.newsletterRepo.Suppress(email, kind)
+	case entity.BounceSoft:
+		return nil
+	}
+	return entity.ErrInvalidBounce
+}
+
+// BlogSaved queues the newsletter for a new blog by a public author.
+// Edits and restores are not sent again.
+func (uc *NewsletterUseCase) BlogSaved(blog *entity.Blog, created bool) {
+	if !created {
+		return
+	}
+
+	author, err := uc.userRepo.GetByID(blog.AuthorID)
+	if err != nil {
+		log.Printf("⚠️  Failed to load author of blog %d for newsletter: %v\n", blog.ID, err)
+		return
+	}
+	if author.Private {
+		return
+	}
+
+	if err := uc.enqueue(entity.JobSendNewsletters, blog.AuthorID, newsletterPayload{BlogID: blog.ID}); err != nil {
+		log.Printf("⚠️  Failed to queue newsletter for blog %d: %v\n", blog.ID, err)
+	}
+}
+
+// BlogDeleted does nothing; newsletters already sent cannot be recalled
+func (uc *NewsletterUseCase) BlogDeleted(blog *entity.Blog) {}
+
+// RunSendNewslettersJob queues a send_newsletter job for each confirmed
+// subscriber of the author of the blog of a send_newsletters job
+func (uc *NewsletterUseCase) RunSendNewslettersJob(job *entity.Job) (string, error) {
+	var payload newsletterPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return "", entity.ErrJobNotRetryable
+	}
+
+	blog, err := uc.blogRepo.GetByID(payload.BlogID)
+	if errors.Is(err, entity.ErrBlogNotFound) {
+		return "blog deleted", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// Subscribers already queued by an earlier attempt are not claimed
+	// again, so a retry only queues the rest
+	ids, err := uc.newsletterRepo.ClaimDeliveries(blog.AuthorID, blog.ID)
+	if err != nil {
+		return "", err
+	}
+	for i, id := range ids {
+		err := uc.enqueue(entity.JobSendNewsletter, blog.AuthorID, newsletterPayload{
+			SubscriptionID: id,
+			BlogID:         blog.ID,
+		})
+		if err != nil {
+			if releaseErr := uc.newsletterRepo.ReleaseDeliveries(blog.ID, ids[i:]); releaseErr != nil {
+				log.Printf("⚠️  Failed to release newsletter deliveries of blog %d: %v\n", blog.ID, releaseErr)
+			}
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%d subscribers", len(ids)), nil
+}
+
+// RunSendConfirmationJob emails the confirmation link of the subscription
+// of a send_confirmation job
+func (uc *NewsletterUseCase) RunSendConfirmationJob(job *entity.Job) (string, error) {
+	var payload newsletterPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return "", entity.ErrJobNotRetryable
+	}
+
+	subscription, author, err := uc.recipient(payload.SubscriptionID)
+	if err != nil || subscription == nil {
+		return "skipped", err
+	}
+	if subscription.IsConfirmed() {
+		return "already confirmed", nil
+	}
+
+	token := auth.SignLinkToken(confirmLinkPurpose, subscription.ID, confirmLinkTTL)
+	message, err := uc.renderer.Confirmation(author, uc.linkURL("confirm", token))
+	if err != nil {
+		return "", err
+	}
+	return uc.send(subscription, message)
+}
+
+// RunSendNewsletterJob emails the blog of a send_newsletter job to its
+// subscriber
+func (uc *NewsletterUseCase) RunSendNewsletterJob(job *entity.Job) (string, error) {
+	var payload newsletterPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return "", entity.ErrJobNotRetryable
+	}
+
+	subscription, author, err := uc.recipient(payload.SubscriptionID)
+	if err != nil || subscription == nil {
+		return "skipped", err
+	}
+
+	blog, err := uc.blogRepo.GetByID(payload.BlogID)
+	if errors.Is(err, entity.ErrBlogNotFound) {
+		return "blog deleted", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	token := auth.SignLinkToken(unsubscribeLinkPurpose, subscription.ID, 0)
+	message, err := uc.renderer.NewPost(author, blog, blogURL(uc.baseURL, blog.ID), uc.linkURL("unsubscribe", token))
+	if err != nil {
+		return "", err
+	}
+	return uc.send(subscription, message)
+}
+
+// recipient loads a subscription and its author, returning no subscription
+// when it is gone or its address is suppressed
+func (uc *NewsletterUseCase) recipient(subscriptionID int64) (*entity.Subscription, *entity.User, error) {
+	subscription, err := uc.newsletterRepo.GetByID(subscriptionID)
+	if errors.Is(err, entity.ErrSubscriptionNotFound) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	suppressed, err := uc.newsletterRepo.IsSuppressed(subscription.Email)
+	if err != nil || suppressed {
+		return nil, nil, err
+	}
+
+	author, err := uc.userRepo.GetByID(subscription.AuthorID)
+	if errors.Is(err, entity.ErrUserNotFound) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return subscription, author, nil
+}
+
+// send mails a message to a subscriber
+func (uc *NewsletterUseCase) send(subscription *entity.Subscription, message *entity.EmailMessage) (string, error) {
+	message.To = subscription.Email
+	return sendMail(uc.mailer, uc.newsletterRepo, message)
+}
+
+// enqueue queues a newsletter job
+func (uc *NewsletterUseCase) enqueue(kind string, authorID int64, payload newsletterPayload) error {
+	job, err := entity.NewJob(kind, authorID, payload, newsletterMaxAttempts)
+	if err != nil {
+		return err
+	}
+	return uc.jobRepo.Enqueue(job)
+}
+
+// linkURL returns the URL of a newsletter link carrying a signed token
+func (uc *NewsletterUseCase) linkURL(action, token string) string {
+	return uc.baseURL + "/newsletter/" + action + "?token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+	"AbdelrahmanDwedar/blogo/internal/domain/service"
+	"AbdelrahmanDwedar/blogo/pkg/auth"
+)
+
+// notificationEmailMaxAttempts is how often sending a notification email
+// is tried
+const notificationEmailMaxAttempts = 5
+
+// NotificationEmailUseCase sends notifications by email. It listens on the
+// email channel of the notification use case and sends each email through
+// the job queue, with a signed link turning off emails of its type.
+type NotificationEmailUseCase struct {
+	userRepo       repository.UserRepository
+	blogRepo       repository.BlogRepository
+	commentRepo    repository.CommentRepository
+	newsletterRepo repository.NewsletterRepository
+	jobRepo        repository.JobRepository
+	preferenceUC   *PreferenceUseCase
+	mailer         service.Mailer
+	renderer       service.NotificationRenderer
+	baseURL        string
+}
+
+// NewNotificationEmailUseCase creates a new notification email use case.
+// Addresses suppressed for newsletters are not sent notifications either.
+// baseURL is the public URL of this server, which the links in emails are
+// built on.
+func NewNotificationEmailUseCase(userRepo repository.UserRepository, blogRepo repository.BlogRepository,
+	commentRepo repository.CommentRepository, newsletterRepo repository.NewsletterRepository,
+	jobRepo repository.JobRepository, preferenceUC *PreferenceUseCase, mailer service.Mailer,
+	renderer service.NotificationRenderer, baseURL string) *NotificationEmailUseCase {
+	return &NotificationEmailUseCase{
+		userRepo:       userRepo,
+		blogRepo:       blogRepo,
+		commentRepo:    commentRepo,
+		newsletterRepo: newsletterRepo,
+		jobRepo:        jobRepo,
+		preferenceUC:   preferenceUC,
+		mailer:         mailer,
+		renderer:       renderer,
+		baseURL:        strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// NotificationCreated queues the email for a notification
+func (uc *NotificationEmailUseCase) NotificationCreated(notification *entity.Notification) {
+	job, err := entity.NewJob(entity.JobSendNotification, notification.UserID, notification, notificationEmailMaxAttempts)
+	if err == nil {
+		err = uc.jobRepo.Enqueue(job)
+	}
+	if err != nil {
+		log.Printf("⚠️  Failed to queue %s email for user %d: %v\n", notification.Type, notification.UserID, err)
+	}
+}
+
+// RunSendJob emails the notification of a send_notification_email job
+// unless its recipient turned such emails off in the meantime
+func (uc *NotificationEmailUseCase) RunSendJob(job *entity.Job) (string, error) {
+	var notification entity.Notification
+	if err := json.Unmarshal(job.Payload, &notification); err != nil {
+		return "", entity.ErrJobNotRetryable
+	}
+
+	recipient, err := uc.userRepo.GetByID(notification.UserID)
+	if errors.Is(err, entity.ErrUserNotFound) {
+		return "user deleted", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	prefType := notification.PreferenceType()
+	enabled, err := uc.preferenceUC.Enabled(recipient.ID, prefType, entity.ChannelEmail)
+	if err != nil {
+		return "", err
+	}
+	if !enabled {
+		return "emails turned off", nil
+	}
+
+	suppressed, err := uc.newsletterRepo.IsSuppressed(strings.ToLower(recipient.Email))
+	if err != nil {
+		return "", err
+	}
+	if suppressed {
+		return "address suppressed", nil
+	}
+
+	notification.Actor, err = uc.userRepo.GetByID(notification.ActorID)
+	if errors.Is(err, entity.ErrUserNotFound) {
+		return "actor deleted", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// Follows link to the follower, everything else to the blog
+	link := fmt.Sprintf("%s/api/u/%d", uc.baseURL, notification.ActorID)
+	var blog *entity.Blog
+	if notification.BlogID != nil {
+		blog, err = uc.blogRepo.GetByID(*notification.BlogID)
+		if errors.Is(err, entity.ErrBlogNotFound) {
+			return "blog deleted", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		link = blogURL(uc.baseURL, blog.ID)
+	}
+
+	var comment *entity.Comment
+	if notification.CommentID != nil {
+		comment, err = uc.commentRepo.GetByID(*notification.CommentID)
+		if errors.Is(err, entity.ErrCommentNotFound) {
+			return "comment deleted", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if !comment.IsPublished() {
+			return "comment not published", nil
+		}
+	}
+
+	token := auth.SignLinkToken(notificationEmailPurpose(prefType), recipient.ID, 0)
+	unsubscribeURL := uc.baseURL + "/notifications/unsubscribe?type=" + url.QueryEscape(prefType) +
+		"&token=" + url.QueryEscape(token)
+
+	message, err := uc.renderer.Notification(recipient, &notification, blog, comment, link, unsubscribeURL)
+	if err != nil {
+		return "", err
+	}
+	message.To = recipient.Email
+	return sendMail(uc.mailer, uc.newsletterRepo, message)
+}
+
+// Unsubscribe turns off emails of a preference type for the user an
+// unsubscribe link was sent to
+func (uc *NotificationEmailUseCase) Unsubscribe(prefType, token string) error {
+	userID, err := auth.VerifyLinkToken(notificationEmailPurpose(prefType), token)
+	if err != nil {
+		return err
+	}
+
+	_, err = uc.preferenceUC.UpdatePreferences(userID, entity.NotificationPreferences{
+		prefType: {entity.ChannelEmail: false},
+	})
+	return err
+}
+
+// notificationEmailPurpose is the purpose of the signed tokens in links
+// turning off emails of a preference type
+func notificationEmailPurpose(prefType string) string {
+	return "notification-email:" + prefType
+}
@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"log"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+)
+
+// notificationChannels are the channels notifications are sent on, in
+// dispatch order
+var notificationChannels = []string{entity.ChannelInApp, entity.ChannelEmail, entity.ChannelPush}
+
+// notificationRepeatWindow is how long a notification that is not stored
+// in-app is not sent again, so undoing and redoing an action does not
+// email or push it twice
+const notificationRepeatWindow = 24 * time.Hour
+
+// NotificationUseCase produces notifications for follows, likes, comments
+// and mentions, sends them on the channels their recipients chose, and
+// serves the in-app ones grouped to their recipients
+type NotificationUseCase struct {
+	notificationRepo repository.NotificationRepository
+	userRepo         repository.UserRepository
+	blogRepo         repository.BlogRepository
+	commentRepo      repository.CommentRepository
+	preferenceUC     *PreferenceUseCase
+	listeners        map[string][]NotificationListener
+}
+
+// NewNotificationUseCase creates a new notification use case
+func NewNotificationUseCase(notificationRepo repository.NotificationRepository, userRepo repository.UserRepository,
+	blogRepo repository.BlogRepository, commentRepo repository.CommentRepository,
+	preferenceUC *PreferenceUseCase) *NotificationUseCase {
+	return &NotificationUseCase{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		blogRepo:         blogRepo,
+		commentRepo:      commentRepo,
+		preferenceUC:     preferenceUC,
+		listeners:        map[string][]NotificationListener{},
+	}
+}
+
+// Subscribe registers a listener sending notifications on a channel
+func (uc *NotificationUseCase) Subscribe(channel string, listener NotificationListener) {
+	uc.listeners[channel] = append(uc.listeners[channel], listener)
+}
+
+// Notify sends a notification on the channels its recipient turned on for
+// its type; in-app notifications are stored first. Repeats are sent once:
+// while the stored notification is unread or, when in-app is off, for
+// notificationRepeatWindow. Users are never notified about their own
+// actions, nor about those of users they muted or are in a block with.
+func (uc *NotificationUseCase) Notify(notification *entity.Notification) error {
+	if notification.UserID == notification.ActorID {
+		return nil
+	}
+
+	blocked, err := uc.userRepo.IsBlocked(notification.UserID, notification.ActorID)
+	if err != nil {
+		return err
+	}
+	muted, err := uc.userRepo.IsMuted(notification.UserID, notification.ActorID)
+	if err != nil {
+		return err
+	}
+	if blocked || muted {
+		return nil
+	}
+
+	prefs, err := uc.preferenceUC.GetPreferences(notification.UserID)
+	if err != nil {
+		return err
+	}
+	prefType := notification.PreferenceType()
+
+	if prefs.Enabled(prefType, entity.ChannelInApp) {
+		if err := uc.notificationRepo.Create(notification); err != nil {
+			return err
+		}
+
+		// Duplicates of unread notifications are not stored, nor sent
+		// again on other channels
+		if notification.ID == 0 {
+			return nil
+		}
+	} else if prefs.Enabled(prefType, entity.ChannelEmail) || prefs.Enabled(prefType, entity.ChannelPush) {
+		// Without a stored notification to deduplicate against, repeats
+		// are caught by a record of recent sends
+		first, err := uc.notificationRepo.RecordSend(notification, notification.CreatedAt.Add(-notificationRepeatWindow))
+		if err != nil {
+			return err
+		}
+		if !first {
+			return nil
+		}
+	}
+
+	for _, channel := range notificationChannels {
+		if !prefs.Enabled(prefType, channel) {
+			continue
+		}
+		for _, listener := range uc.listeners[channel] {
+			listener.NotificationCreated(notification)
+		}
+	}
+	return nil
+}
+
+// notify stores a notification produced by a listener, logging failures
+func (uc *NotificationUseCase) notify(notification *entity.Notification) {
+	if err := uc.Notify(notification); err != nil {
+		log.Printf("⚠️  Failed to notify user %d of %s: %v\n", notification.UserID, notification.Type, err)
+	}
+}
+
+// UserFollowed notifies a user about a new follower
+func (uc *NotificationUseCase) UserFollowed(followerID, followingID int64) {
+	uc.notify(entity.NewNotification(followingID, entity.NotificationFollow, followerID))
+}
+
+// FollowRequested notifies a private account about a follow request
+func (uc *NotificationUseCase) FollowRequested(followerID, followingID int64) {
+	uc.notify(entity.NewNotification(followingID, entity.NotificationFollowRequest, followerID))
+}
+
+// FollowAccepted notifies a user that their follow request was accepted
+func (uc *NotificationUseCase) FollowAccepted(followerID, followingID int64) {
+	uc.notify(entity.NewNotification(followerID, entity.NotificationFollowAccept, followingID))
+}
+
+// UserUnfollowed leaves the follow notification in place
+func (uc *NotificationUseCase) UserUnfollowed(followerID, followingID int64) {}
+
+// UserMuted needs nothing; notifications from muted users are hidden when
+// listed
+func (uc *NotificationUseCase) UserMuted(muterID, mutedID int64) {}
+
+// UserUnmuted needs nothing; see UserMuted
+func (uc *NotificationUseCase) UserUnmuted(muterID, mutedID int64) {}
+
+// BlogReacted notifies a blog's author when it is liked. Other reactions
+// only show up in the blog's counts.
+func (uc *NotificationUseCase) BlogReacted(blog *entity.Blog, userID int64, kind string) {
+	if kind != entity.DefaultReaction {
+		return
+	}
+
+	notification := entity.NewNotification(blog.AuthorID, entity.NotificationLike, userID)
+	notification.BlogID = &blog.ID
+	uc.notify(notification)
+}
+
+// CommentPublished notifies the blog's author about a new comment and, for
+// replies, the author of the parent comment
+func (uc *NotificationUseCase) CommentPublished(comment *entity.Comment, created bool) {
+	if !created {
+		return
+	}
+
+	blog, err := uc.blogRepo.GetByID(comment.BlogID)
+	if err != nil {
+		log.Printf("⚠️  Failed to load blog %d of comment %d: %v\n", comment.BlogID, comment.ID, err)
+		return
+	}
+
+	var parentAuthorID int64
+	if comment.ParentID != nil {
+		parent, err := uc.commentRepo.GetByID(*comment.ParentID)
+		if err != nil {
+			log.Printf("⚠️  Failed to load parent of comment %d: %v\n", comment.ID, err)
+			return
+		}
+		parentAuthorID = parent.AuthorID
+
+		reply := entity.NewNotification(parentAuthorID, entity.NotificationReply, comment.AuthorID)
+		reply.BlogID = &comment.BlogID
+		reply.CommentID = &comment.ID
+		uc.notify(reply)
+	}
+
+	// A blog author replied to is already notified of the reply
+	if blog.AuthorID != parentAuthorID {
+		notification := entity.NewNotification(blog.AuthorID, entity.NotificationComment, comment.AuthorID)
+		notification.BlogID = &comment.BlogID
+		notification.CommentID = &comment.ID
+		uc.notify(notification)
+	}
+}
+
+// GetNotifications retrieves a page of a user's grouped notifications and
+// the number of unread groups
+func (uc *NotificationUseCase) GetNotifications(userID int64, limit, offset int) ([]*entity.NotificationGroup, int, error) {
+	groups, err := uc.notificationRepo.GetGroups(userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	unread, err := uc.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return groups, unread, nil
+}
+
+// MarkRead marks a notification and the rest of its group read
+func (uc *NotificationUseCase) MarkRead(userID, id int64) error {
+	return uc.notificationRepo.MarkGroupRead(userID, id)
+}
+
+// MarkAllRead marks all of a user's notifications read
+func (uc *NotificationUseCase) MarkAllRead(userID int64) error {
+	return uc.notificationRepo.MarkAllRead(userID)
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+)
+
+// PreferenceUseCase manages the channels users get each type of
+// notification on. Every notification producer asks it before sending.
+type PreferenceUseCase struct {
+	preferenceRepo repository.PreferenceRepository
+}
+
+// NewPreferenceUseCase creates a new preference use case
+func NewPreferenceUseCase(preferenceRepo repository.PreferenceRepository) *PreferenceUseCase {
+	return &PreferenceUseCase{preferenceRepo: preferenceRepo}
+}
+
+// GetPreferences returns a user's preferences, with defaults for the
+// channels they never set
+func (uc *PreferenceUseCase) GetPreferences(userID int64) (entity.NotificationPreferences, error) {
+	stored, err := uc.preferenceRepo.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs := entity.DefaultNotificationPreferences()
+	prefs.Apply(stored)
+	return prefs, nil
+}
+
+// UpdatePreferences turns the channels set in changes on or off and
+// returns the resulting preferences
+func (uc *PreferenceUseCase) UpdatePreferences(userID int64, changes entity.NotificationPreferences) (entity.NotificationPreferences, error) {
+	if err := changes.Validate(); err != nil {
+		return nil, err
+	}
+	if err := uc.preferenceRepo.SavePreferences(userID, changes); err != nil {
+		return nil, err
+	}
+	return uc.GetPreferences(userID)
+}
+
+// Enabled checks if a user gets a preference type on a channel
+func (uc *PreferenceUseCase) Enabled(userID int64, prefType, channel string) (bool, error) {
+	prefs, err := uc.GetPreferences(userID)
+	if err != nil {
+		return false, err
+	}
+	return prefs.Enabled(prefType, channel), nil
+}
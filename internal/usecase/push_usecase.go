@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+	"AbdelrahmanDwedar/blogo/internal/domain/service"
+	"AbdelrahmanDwedar/blogo/pkg/webpush"
+)
+
+// pushMaxAttempts is how often sending a push is tried
+const pushMaxAttempts = 5
+
+// PushUseCase sends notifications as Web Push messages to the devices
+// users subscribed. It listens on the push channel of the notification use
+// case and sends one push per device through the job queue, dropping the
+// subscriptions push services report expired.
+type PushUseCase struct {
+	pushRepo     repository.PushRepository
+	userRepo     repository.UserRepository
+	blogRepo     repository.BlogRepository
+	jobRepo      repository.JobRepository
+	preferenceUC *PreferenceUseCase
+	sender       service.PushSender
+	baseURL      string
+}
+
+// NewPushUseCase creates a new push use case. baseURL is the public URL of
+// this server, which the links in pushes are built on.
+func NewPushUseCase(pushRepo repository.PushRepository, userRepo repository.UserRepository,
+	blogRepo repository.BlogRepository, jobRepo repository.JobRepository, preferenceUC *PreferenceUseCase,
+	sender service.PushSender, baseURL string) *PushUseCase {
+	return &PushUseCase{
+		pushRepo:     pushRepo,
+		userRepo:     userRepo,
+		blogRepo:     blogRepo,
+		jobRepo:      jobRepo,
+		preferenceUC: preferenceUC,
+		sender:       sender,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// pushPayload is the payload of a send_push job
+type pushPayload struct {
+	SubscriptionID int64                `json:"subscription_id"`
+	Notification   *entity.Notification `json:"notification"`
+}
+
+// PublicKey returns the VAPID public key browsers subscribe with
+func (uc *PushUseCase) PublicKey() (string, error) {
+	key, err := uc.vapidKey()
+	if err != nil {
+		return "", err
+	}
+	return key.PublicKey, nil
+}
+
+// vapidKey retrieves the VAPID key, generating it on first use
+func (uc *PushUseCase) vapidKey() (*entity.VAPIDKey, error) {
+	key, err := uc.pushRepo.GetVAPIDKey()
+	if !errors.Is(err, entity.ErrVAPIDKeyNotFound) {
+		return key, err
+	}
+
+	private, err := webpush.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	// Another request may have generated a key meanwhile; whichever was
+	// stored first wins
+	err = uc.pushRepo.CreateVAPIDKey(&entity.VAPIDKey{
+		PublicKey:  webpush.EncodePublicKey(&private.PublicKey),
+		PrivateKey: webpush.EncodePrivateKey(private),
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uc.pushRepo.GetVAPIDKey()
+}
+
+// Subscribe registers a device's push subscription for a user. A device
+// subscribing again replaces its subscription.
+func (uc *PushUseCase) Subscribe(userID int64, endpoint, p256dh, auth, userAgent string) (*entity.PushSubscription, error) {
+	subscription, err := entity.NewPushSubscription(userID, endpoint, p256dh, auth, userAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.pushRepo.Subscribe(subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// GetSubscriptions retrieves the push subscriptions of a user's devices
+func (uc *PushUseCase) GetSubscriptions(userID int64) ([]*entity.PushSubscription, error) {
+	return uc.pushRepo.GetByUser(userID)
+}
+
+// Unsubscribe removes one of a user's push subscriptions by its endpoint
+func (uc *PushUseCase) Unsubscribe(userID int64, endpoint string) error {
+	return uc.pushRepo.Unsubscribe(userID, strings.TrimSpace(endpoint))
+}
+
+// NotificationCreated queues a push of the notification to each of its
+// recipient's devices
+func (uc *PushUseCase) NotificationCreated(notification *entity.Notification) {
+	subscriptions, err := uc.pushRepo.GetByUser(notification.UserID)
+	if err != nil {
+		log.Printf("⚠️  Failed to get push subscriptions of user %d: %v\n", notification.UserID, err)
+		return
+	}
+
+	for _, subscription := range subscriptions {
+		job, err := entity.NewJob(entity.JobSendPush, notification.UserID, pushPayload{
+			SubscriptionID: subscription.ID,
+			Notification:   notification,
+		}, pushMaxAttempts)
+		if err == nil {
+			err = uc.jobRepo.Enqueue(job)
+		}
+		if err != nil {
+			log.Printf("⚠️  Failed to queue %s push for user %d: %v\n", notification.Type, notification.UserID, err)
+		}
+	}
+}
+
+// RunSendJob pushes the notification of a send_push job to its device,
+// unless its recipient turned such pushes off in the meantime, and returns
+// the push service it was sent to. Subscriptions the push service dropped
+// are deleted.
+func (uc *PushUseCase) RunSendJob(job *entity.Job) (string, error) {
+	var payload pushPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil || payload.Notification == nil {
+		return "", entity.ErrJobNotRetryable
+	}
+	notification := payload.Notification
+
+	subscription, err := uc.pushRepo.GetByID(payload.SubscriptionID)
+	if errors.Is(err, entity.ErrPushSubscriptionNotFound) {
+		return "subscription removed", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if subscription.UserID != notification.UserID {
+		return "subscription moved to another user", nil
+	}
+
+	enabled, err := uc.preferenceUC.Enabled(notification.UserID, notification.PreferenceType(), entity.ChannelPush)
+	if err != nil {
+		return "", err
+	}
+	if !enabled {
+		return "pushes turned off", nil
+	}
+
+	message, err := uc.message(notification)
+	if errors.Is(err, entity.ErrUserNotFound) {
+		return "actor deleted", nil
+	}
+	if errors.Is(err, entity.ErrBlogNotFound) {
+		return "blog deleted", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(message)
+	if err != nil {
+		return "", entity.ErrJobNotRetryable
+	}
+
+	key, err := uc.vapidKey()
+	if err != nil {
+		return "", err
+	}
+
+	err = uc.sender.Send(subscription, data, key.PrivateKey)
+	if errors.Is(err, entity.ErrPushSubscriptionGone) {
+		if err := uc.pushRepo.Delete(subscription.ID); err != nil {
+			return "", err
+		}
+		return "subscription expired", nil
+	}
+	if errors.Is(err, entity.ErrPushRejected) {
+		return "", fmt.Errorf("%w: %v", entity.ErrJobNotRetryable, err)
+	}
+	if err != nil {
+		return "", err
+	}
+	return pushServiceHost(subscription.Endpoint), nil
+}
+
+// message builds the push shown for a notification, e.g. "Alice" / "liked
+// your blog: Hello world". Follows link to the follower, everything else
+// to the blog.
+func (uc *PushUseCase) message(notification *entity.Notification) (*entity.PushMessage, error) {
+	actor, err := uc.userRepo.GetByID(notification.ActorID)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &entity.PushMessage{
+		Type:  notification.Type,
+		Title: actor.DisplayName,
+		Body:  notification.Action(),
+		URL:   fmt.Sprintf("%s/api/u/%d", uc.baseURL, actor.ID),
+	}
+	if message.Title == "" {
+		message.Title = actor.Username
+	}
+
+	if notification.BlogID != nil {
+		blog, err := uc.blogRepo.GetByID(*notification.BlogID)
+		if err != nil {
+			return nil, err
+		}
+		message.Body += ": " + blog.Title
+		message.URL = blogURL(uc.baseURL, blog.ID)
+	}
+	return message, nil
+}
+
+// pushServiceHost returns the host of a push endpoint, which is all job
+// results show of it since the endpoint identifies the device
+func pushServiceHost(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
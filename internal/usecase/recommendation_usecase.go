@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"log"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+)
+
+const (
+	// recommendationCount is how many recommendations are computed and
+	// stored per user
+	recommendationCount = 50
+
+	// recommendationActiveWindow is how long after last asking a user's
+	// recommendations keep being refreshed
+	recommendationActiveWindow = 7 * 24 * time.Hour
+)
+
+// RecommendationUseCase suggests users to follow. Recommendations are
+// computed when first asked for, stored in the cache and recomputed
+// periodically for users who keep asking; without a cache they are
+// computed on every request.
+type RecommendationUseCase struct {
+	recommendationRepo repository.RecommendationRepository
+	cache              repository.RecommendationCache
+	refreshInterval    time.Duration
+}
+
+// NewRecommendationUseCase creates a new recommendation use case;
+// refreshInterval is how often RefreshRecommendations runs
+func NewRecommendationUseCase(recommendationRepo repository.RecommendationRepository,
+	cache repository.RecommendationCache, refreshInterval time.Duration) *RecommendationUseCase {
+	return &RecommendationUseCase{
+		recommendationRepo: recommendationRepo,
+		cache:              cache,
+		refreshInterval:    refreshInterval,
+	}
+}
+
+// GetRecommendations retrieves up to limit users for a user to follow
+func (uc *RecommendationUseCase) GetRecommendations(userID int64, limit int) ([]*entity.Recommendation, error) {
+	recommendations, ok, err := uc.cache.GetRecommendations(userID)
+	if err != nil {
+		log.Printf("⚠️  Failed to read recommendations of user %d: %v\n", userID, err)
+		ok = false
+	}
+
+	if !ok {
+		recommendations, err = uc.compute(userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	return recommendations, nil
+}
+
+// RefreshRecommendations recomputes the stored recommendations of users who
+// asked for them recently and returns how many users were refreshed
+func (uc *RecommendationUseCase) RefreshRecommendations() (int, error) {
+	userIDs, err := uc.cache.GetActiveRecommendationUsers(time.Now().Add(-recommendationActiveWindow))
+	if err != nil {
+		return 0, err
+	}
+
+	refreshed := 0
+	for _, userID := range userIDs {
+		if _, err := uc.compute(userID); err != nil {
+			log.Printf("⚠️  Failed to refresh recommendations of user %d: %v\n", userID, err)
+			continue
+		}
+		refreshed++
+	}
+	return refreshed, nil
+}
+
+// compute computes and stores a user's recommendations. They are kept for
+// two refresh intervals so a slow refresh doesn't leave the user without.
+func (uc *RecommendationUseCase) compute(userID int64) ([]*entity.Recommendation, error) {
+	recommendations, err := uc.recommendationRepo.ComputeRecommendations(userID, recommendationCount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.cache.StoreRecommendations(userID, recommendations, 2*uc.refreshInterval); err != nil {
+		log.Printf("⚠️  Failed to store recommendations of user %d: %v\n", userID, err)
+	}
+	return recommendations, nil
+}
+
+// UserFollowed drops the follower's recommendations, which may suggest the
+// followed user
+func (uc *RecommendationUseCase) UserFollowed(followerID, followingID int64) {
+	uc.dropRecommendations(followerID)
+}
+
+// FollowRequested drops the follower's recommendations, which may suggest
+// the requested user
+func (uc *RecommendationUseCase) FollowRequested(followerID, followingID int64) {
+	uc.dropRecommendations(followerID)
+}
+
+// FollowAccepted needs nothing; the follower stopped being suggested the
+// user when requesting
+func (uc *RecommendationUseCase) FollowAccepted(followerID, followingID int64) {}
+
+// UserUnfollowed drops the recommendations of both users, as unfollows
+// also come from blocks, which hide the users from each other
+func (uc *RecommendationUseCase) UserUnfollowed(followerID, followingID int64) {
+	uc.dropRecommendations(followerID)
+	uc.dropRecommendations(followingID)
+}
+
+// UserMuted drops the muter's recommendations, which may suggest the muted
+// user
+func (uc *RecommendationUseCase) UserMuted(muterID, mutedID int64) {
+	uc.dropRecommendations(muterID)
+}
+
+// UserUnmuted needs nothing; the unmuted user comes back with the next
+// refresh
+func (uc *RecommendationUseCase) UserUnmuted(muterID, mutedID int64) {}
+
+func (uc *RecommendationUseCase) dropRecommendations(userID int64) {
+	if err := uc.cache.DeleteRecommendations(userID); err != nil {
+		log.Printf("⚠️  Failed to drop recommendations of user %d: %v\n", userID, err)
+	}
+}
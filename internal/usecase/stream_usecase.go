@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"log"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+	"AbdelrahmanDwedar/blogo/internal/domain/service"
+)
+
+// StreamUseCase pushes new notifications and new blogs by followed authors
+// to users' real-time streams
+type StreamUseCase struct {
+	broker   service.EventBroker
+	userRepo repository.UserRepository
+}
+
+// NewStreamUseCase creates a new stream use case
+func NewStreamUseCase(broker service.EventBroker, userRepo repository.UserRepository) *StreamUseCase {
+	return &StreamUseCase{
+		broker:   broker,
+		userRepo: userRepo,
+	}
+}
+
+// Subscribe opens a user's event stream, resuming after lastEventID when set
+func (uc *StreamUseCase) Subscribe(userID int64, lastEventID string) (<-chan *entity.Event, func(), error) {
+	return uc.broker.Subscribe(userID, lastEventID)
+}
+
+// NotificationCreated pushes a new notification to its recipient
+func (uc *StreamUseCase) NotificationCreated(notification *entity.Notification) {
+	event, err := entity.NewEvent(entity.EventNotification, notification)
+	if err != nil {
+		log.Printf("⚠️  Failed to create notification event: %v\n", err)
+		return
+	}
+	uc.publish(event, notification.UserID)
+}
+
+// BlogSaved pushes a newly created blog to the author's followers. The
+// fan-out runs in the background so that authors with many followers do
+// not wait for it when saving.
+func (uc *StreamUseCase) BlogSaved(blog *entity.Blog, created bool) {
+	if !created {
+		return
+	}
+
+	event, err := entity.NewEvent(entity.EventBlog, blog)
+	if err != nil {
+		log.Printf("⚠️  Failed to create blog event: %v\n", err)
+		return
+	}
+
+	authorID := blog.AuthorID
+	go func() {
+		followerIDs, err := uc.userRepo.GetFollowerIDs(authorID)
+		if err != nil {
+			log.Printf("⚠️  Failed to get followers of user %d: %v\n", authorID, err)
+			return
+		}
+		uc.publish(event, followerIDs...)
+	}()
+}
+
+// BlogDeleted pushes nothing; clients drop trashed blogs on their next fetch
+func (uc *StreamUseCase) BlogDeleted(blog *entity.Blog) {}
+
+func (uc *StreamUseCase) publish(event *entity.Event, userIDs ...int64) {
+	for _, userID := range userIDs {
+		if err := uc.broker.Publish(userID, event); err != nil {
+			log.Printf("⚠️  Failed to publish %s event to user %d: %v\n", event.Type, userID, err)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+	"AbdelrahmanDwedar/blogo/pkg/feed"
+)
+
+const (
+	// syndicationSize is how many of the newest blogs a feed holds
+	syndicationSize = 50
+
+	// syndicationCacheTTL bounds how long a rendered feed is served from
+	// the cache; blog changes drop feeds sooner
+	syndicationCacheTTL = 10 * time.Minute
+)
+
+// SyndicationUseCase renders RSS, Atom and JSON feeds of the newest blogs,
+// overall, by author and by tag. Rendered feeds are cached until a blog is
+// created, edited, trashed or restored, or an author edits their profile.
+// Blogs of private accounts are never syndicated.
+type SyndicationUseCase struct {
+	blogRepo repository.BlogRepository
+	userRepo repository.UserRepository
+	cache    repository.SyndicationCache
+	baseURL  string
+
+	// changedAt is when feeds last changed, for servers without a cache
+	mu        sync.Mutex
+	changedAt time.Time
+}
+
+// NewSyndicationUseCase creates a new syndication use case. baseURL is the
+// public URL of this server, which feed and item links are built on.
+func NewSyndicationUseCase(blogRepo repository.BlogRepository, userRepo repository.UserRepository,
+	cache repository.SyndicationCache, baseURL string) *SyndicationUseCase {
+	return &SyndicationUseCase{
+		blogRepo: blogRepo,
+		userRepo: userRepo,
+		cache:    cache,
+		baseURL:  strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// GetAllFeed renders the feed of the newest blogs by all public authors
+func (uc *SyndicationUseCase) GetAllFeed(format string) (*entity.RenderedFeed, error) {
+	return uc.render("all."+format, format, func() (*feed.Feed, error) {
+		blogs, err := uc.blogRepo.GetAll(0, syndicationSize, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return uc.feed(&feed.Feed{
+			ID:          "urn:blogo:feed:all",
+			Title:       "Blogo",
+			Description: "The newest blogs on Blogo",
+			Link:        uc.baseURL + "/api/b",
+			Self:        fmt.Sprintf("%s/feeds/all.%s", uc.baseURL, format),
+		}, blogs)
+	})
+}
+
+// GetAuthorFeed renders the feed of an author's newest blogs
+func (uc *SyndicationUseCase) GetAuthorFeed(authorID int64, format string) (*entity.RenderedFeed, error) {
+	return uc.render(fmt.Sprintf("u:%d.%s", authorID, format), format, func() (*feed.Feed, error) {
+		author, err := uc.userRepo.GetByID(authorID)
+		if err != nil {
+			return nil, err
+		}
+		if author.Private {
+			return nil, entity.ErrUserNotFound
+		}
+
+		blogs, err := uc.blogRepo.GetByAuthor(authorID, syndicationSize, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return uc.feed(&feed.Feed{
+			ID:          fmt.Sprintf("urn:blogo:user:%d", author.ID),
+			Title:       author.DisplayName,
+			Description: author.Bio,
+			Link:        fmt.Sprintf("%s/api/u/%d", uc.baseURL, author.ID),
+			Self:        fmt.Sprintf("%s/feeds/u/%d.%s", uc.baseURL, author.ID, format),
+			Author:      uc.person(author),
+		}, blogs)
+	})
+}
+
+// GetTagFeed renders the feed of the newest blogs with a tag
+func (uc *SyndicationUseCase) GetTagFeed(tag, format string) (*entity.RenderedFeed, error) {
+	tag = entity.Slugify(tag)
+	return uc.render(fmt.Sprintf("t:%s.%s", tag, format), format, func() (*feed.Feed, error) {
+		blogs, err := uc.blogRepo.GetByTag(tag, syndicationSize, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return uc.feed(&feed.Feed{
+			ID:          "urn:blogo:tag:" + tag,
+			Title:       "Blogo: #" + tag,
+			Description: "The newest blogs tagged " + tag + " on Blogo",
+			Link:        uc.baseURL + "/api/b",
+			Self:        fmt.Sprintf("%s/feeds/t/%s.%s", uc.baseURL, url.PathEscape(tag), format),
+		}, blogs)
+	})
+}
+
+// render serves a feed from the cache, or builds, renders and caches it
+func (uc *SyndicationUseCase) render(key, format string, build func() (*feed.Feed, error)) (*entity.RenderedFeed, error) {
+	contentType := feed.ContentType(format)
+	if contentType == "" {
+		return nil, feed.ErrUnknownFormat
+	}
+
+	if rendered, ok, err := uc.cache.GetFeed(key); err != nil {
+		log.Printf("⚠️  Failed to read feed %s from cache: %v\n", key, err)
+	} else if ok {
+		return rendered, nil
+	}
+
+	f, err := build()
+	if err != nil {
+		return nil, err
+	}
+
+	// Trashing a blog or hiding an author can leave only older items, but a
+	// feed's update time must never go back or readers skip the change
+	if changedAt := uc.lastChanged(); changedAt.After(f.Updated) {
+		f.Updated = changedAt
+	}
+
+	body, err := f.Render(format)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(body)
+	rendered := &entity.RenderedFeed{
+		Body:        body,
+		ContentType: contentType,
+		ETag:        `"` + hex.EncodeToString(sum[:16]) + `"`,
+		Updated:     f.LastUpdated(),
+	}
+
+	if err := uc.cache.StoreFeed(key, rendered, syndicationCacheTTL); err != nil {
+		log.Printf("⚠️  Failed to cache feed %s: %v\n", key, err)
+	}
+	return rendered, nil
+}
+
+// feed fills in the items of f from blogs, which the repository has
+// already limited to public authors. A feed is as new as its most recently
+// updated blog.
+func (uc *SyndicationUseCase) feed(f *feed.Feed, blogs []*entity.Blog) (*feed.Feed, error) {
+	for _, blog := range blogs {
+		author := f.Author
+		if blog.Author != nil {
+			author = uc.person(blog.Author)
+		}
+
+		f.Items = append(f.Items, &feed.Item{
+			ID:         fmt.Sprintf("urn:blogo:blog:%d", blog.ID),
+			Title:      blog.Title,
+			Link:       fmt.Sprintf("%s/api/b/%d", uc.baseURL, blog.ID),
+			Summary:    blog.Description,
+			Content:    articleContent(blog.Body),
+			Author:     author,
+			Published:  blog.CreatedAt,
+			Updated:    blog.UpdatedAt,
+			Categories: blog.Tags,
+		})
+	}
+
+	// Feeds without blogs still need an update time; the Unix epoch stands
+	// for an unknown one
+	if f.LastUpdated().IsZero() {
+		f.Updated = time.Unix(0, 0)
+	}
+	return f, nil
+}
+
+func (uc *SyndicationUseCase) person(user *entity.User) *feed.Person {
+	return &feed.Person{
+		Name: user.DisplayName,
+		URI:  fmt.Sprintf("%s/api/u/%d", uc.baseURL, user.ID),
+	}
+}
+
+// BlogSaved drops the rendered feeds so they include the new or edited blog
+func (uc *SyndicationUseCase) BlogSaved(blog *entity.Blog, created bool) {
+	uc.dropFeeds()
+}
+
+// BlogDeleted drops the rendered feeds so they leave out the trashed blog
+func (uc *SyndicationUseCase) BlogDeleted(blog *entity.Blog) {
+	uc.dropFeeds()
+}
+
+// ProfileUpdated drops the rendered feeds so they show the author's new
+// name and leave out or bring back the blogs of an author who made their
+// account private or public
+func (uc *SyndicationUseCase) ProfileUpdated(user *entity.User) {
+	uc.dropFeeds()
+}
+
+// dropFeeds records that feeds changed and drops the rendered ones
+func (uc *SyndicationUseCase) dropFeeds() {
+	now := time.Now()
+	uc.mu.Lock()
+	uc.changedAt = now
+	uc.mu.Unlock()
+
+	if err := uc.cache.SetFeedsChangedAt(now); err != nil {
+		log.Printf("⚠️  Failed to record feed change: %v\n", err)
+	}
+	if err := uc.cache.DeleteFeeds(); err != nil {
+		log.Printf("⚠️  Failed to drop cached feeds: %v\n", err)
+	}
+}
+
+// lastChanged returns when feeds last changed, as recorded by this server
+// or, across restarts and servers, in the cache
+func (uc *SyndicationUseCase) lastChanged() time.Time {
+	uc.mu.Lock()
+	changedAt := uc.changedAt
+	uc.mu.Unlock()
+
+	cached, ok, err := uc.cache.GetFeedsChangedAt()
+	if err != nil {
+		log.Printf("⚠️  Failed to read feed change time: %v\n", err)
+	} else if ok && cached.After(changedAt) {
+		changedAt = cached
+	}
+	return changedAt
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"log"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+)
+
+// timelineBuildSize is how many blogs are loaded when a home timeline is
+// rebuilt from the database
+const timelineBuildSize = 800
+
+// TimelineUseCase serves home timelines: the newest blogs by the authors a
+// user follows. Timelines are precomputed in the cache by fanning new blogs
+// out to followers, and read from the database whenever the cache cannot
+// answer.
+type TimelineUseCase struct {
+	blogRepo     repository.BlogRepository
+	userRepo     repository.UserRepository
+	timelineRepo repository.TimelineRepository
+}
+
+// NewTimelineUseCase creates a new timeline use case
+func NewTimelineUseCase(blogRepo repository.BlogRepository, userRepo repository.UserRepository,
+	timelineRepo repository.TimelineRepository) *TimelineUseCase {
+	return &TimelineUseCase{
+		blogRepo:     blogRepo,
+		userRepo:     userRepo,
+		timelineRepo: timelineRepo,
+	}
+}
+
+// GetFeed retrieves a page of a user's home timeline starting after cursor,
+// along with the cursor of the next page, which is nil on the last page
+func (uc *TimelineUseCase) GetFeed(userID int64, cursor *entity.Cursor, limit int) ([]*entity.Blog, *entity.Cursor, error) {
+	ids, ok, err := uc.timelineRepo.GetTimeline(userID, cursor, limit)
+	if err != nil {
+		log.Printf("⚠️  Failed to read timeline of user %d: %v\n", userID, err)
+		ok = false
+	}
+
+	var blogs []*entity.Blog
+	if ok {
+		blogs, err = uc.blogRepo.GetByIDs(ids)
+		if err != nil {
+			return nil, nil, err
+		}
+		// Blogs trashed since they were fanned out leave gaps in the page;
+		// the database answers those pages exactly
+		ok = len(blogs) == len(ids)
+	}
+
+	if !ok {
+		if cursor == nil {
+			blogs, err = uc.rebuild(userID, limit)
+		} else {
+			blogs, err = uc.blogRepo.GetFeed(userID, cursor, limit)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var next *entity.Cursor
+	if len(blogs) == limit {
+		last := blogs[len(blogs)-1]
+		next = entity.NewCursor(last.CreatedAt, last.ID)
+	}
+	return blogs, next, nil
+}
+
+// rebuild loads a user's timeline from the database, stores it in the cache
+// and returns its first page
+func (uc *TimelineUseCase) rebuild(userID int64, limit int) ([]*entity.Blog, error) {
+	blogs, err := uc.blogRepo.GetFeed(userID, nil, timelineBuildSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.timelineRepo.StoreTimeline(userID, blogs, len(blogs) < timelineBuildSize); err != nil {
+		log.Printf("⚠️  Failed to store timeline of user %d: %v\n", userID, err)
+	}
+
+	if len(blogs) > limit {
+		blogs = blogs[:limit]
+	}
+	return blogs, nil
+}
+
+// BlogSaved fans a new or restored blog out to the timelines of the
+// author's followers
+func (uc *TimelineUseCase) BlogSaved(blog *entity.Blog, created bool) {
+	// Re-read the blog so its creation time matches the database, which
+	// orders timelines rebuilt from it
+	stored, err := uc.blogRepo.GetByID(blog.ID)
+	if err != nil {
+		log.Printf("⚠️  Failed to load blog %d for timelines: %v\n", blog.ID, err)
+		return
+	}
+
+	followerIDs, err := uc.userRepo.GetFollowerIDs(stored.AuthorID)
+	if err != nil {
+		log.Printf("⚠️  Failed to get followers of user %d: %v\n", stored.AuthorID, err)
+		return
+	}
+
+	if err := uc.timelineRepo.AddToTimelines(followerIDs, stored); err != nil {
+		log.Printf("⚠️  Failed to add blog %d to timelines: %v\n", stored.ID, err)
+	}
+}
+
+// BlogDeleted removes a trashed blog from the timelines of the author's
+// followers
+func (uc *TimelineUseCase) BlogDeleted(blog *entity.Blog) {
+	followerIDs, err := uc.userRepo.GetFollowerIDs(blog.AuthorID)
+	if err != nil {
+		log.Printf("⚠️  Failed to get followers of user %d: %v\n", blog.AuthorID, err)
+		return
+	}
+
+	if err := uc.timelineRepo.RemoveFromTimelines(followerIDs, blog.ID); err != nil {
+		log.Printf("⚠️  Failed to remove blog %d from timelines: %v\n", blog.ID, err)
+	}
+}
+
+// UserFollowed drops the follower's timeline so it is rebuilt with the new
+// author's blogs
+func (uc *TimelineUseCase) UserFollowed(followerID, followingID int64) {
+	uc.dropTimeline(followerID)
+}
+
+// FollowRequested needs nothing; pending follows add nothing to timelines
+func (uc *TimelineUseCase) FollowRequested(followerID, followingID int64) {}
+
+// FollowAccepted drops the follower's timeline so it is rebuilt with the
+// private author's blogs
+func (uc *TimelineUseCase) FollowAccepted(followerID, followingID int64) {
+	uc.dropTimeline(followerID)
+}
+
+// UserUnfollowed drops the follower's timeline so it is rebuilt without the
+// unfollowed author's blogs
+func (uc *TimelineUseCase) UserUnfollowed(followerID, followingID int64) {
+	uc.dropTimeline(followerID)
+}
+
+// UserMuted drops the muter's timeline so it is rebuilt without the muted
+// author's blogs
+func (uc *TimelineUseCase) UserMuted(muterID, mutedID int64) {
+	uc.dropTimeline(muterID)
+}
+
+// UserUnmuted drops the muter's timeline so it is rebuilt with the
+// unmuted author's blogs
+func (uc *TimelineUseCase) UserUnmuted(muterID, mutedID int64) {
+	uc.dropTimeline(muterID)
+}
+
+func (uc *TimelineUseCase) dropTimeline(userID int64) {
+	if err := uc.timelineRepo.DeleteTimeline(userID); err != nil {
+		log.Printf("⚠️  Failed to drop timeline of user %d: %v\n", userID, err)
+	}
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+)
+
+// trendingSize is how many blogs are kept per trending window
+const trendingSize = 1000
+
+// TrendingUseCase ranks blogs by recent engagement. Rankings are
+// recomputed periodically by RefreshTrending and read from the trending
+// repository in between.
+type TrendingUseCase struct {
+	blogRepo     repository.BlogRepository
+	userRepo     repository.UserRepository
+	trendingRepo repository.TrendingRepository
+}
+
+// NewTrendingUseCase creates a new trending use case
+func NewTrendingUseCase(blogRepo repository.BlogRepository, userRepo repository.UserRepository,
+	trendingRepo repository.TrendingRepository) *TrendingUseCase {
+	return &TrendingUseCase{
+		blogRepo:     blogRepo,
+		userRepo:     userRepo,
+		trendingRepo: trendingRepo,
+	}
+}
+
+// GetTrending retrieves a page of a window's trending blogs as seen by
+// viewerID, leaving out blogs by users in a block with the viewer. Blogs
+// by private accounts never trend.
+func (uc *TrendingUseCase) GetTrending(windowName string, viewerID int64, limit, offset int) ([]*entity.Blog, error) {
+	window, err := entity.ParseTrendingWindow(windowName)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := uc.trendingRepo.GetTrending(window.Name, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	blogs, err := uc.blogRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	hidden, err := blockedUsers(uc.userRepo, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Trending blogs are computed without private authors; this only
+	// catches authors who went private since the last refresh
+	for _, blog := range blogs {
+		if blog.Author != nil && blog.Author.Private && blog.AuthorID != viewerID {
+			hidden[blog.AuthorID] = true
+		}
+	}
+	return visibleBlogs(blogs, hidden), nil
+}
+
+// RefreshTrending recomputes the trending blogs of every window and drops
+// view counts too old to matter to any of them
+func (uc *TrendingUseCase) RefreshTrending() error {
+	var longest time.Duration
+	for _, window := range entity.TrendingWindows {
+		blogs, err := uc.blogRepo.ComputeTrending(window, trendingSize)
+		if err != nil {
+			return err
+		}
+		if err := uc.trendingRepo.StoreTrending(window.Name, blogs); err != nil {
+			return err
+		}
+		if window.Period > longest {
+			longest = window.Period
+		}
+	}
+
+	_, err := uc.blogRepo.PurgeViews(time.Now().Add(-longest))
+	return err
+}
@@ -10,8 +10,10 @@ import (
 
 // UserUseCase handles user-related business logic
 type UserUseCase struct {
-	userRepo  repository.UserRepository
-	cacheRepo repository.CacheRepository
+	userRepo         repository.UserRepository
+	cacheRepo        repository.CacheRepository
+	listeners        []RelationshipListener
+	profileListeners []ProfileListener
 }
 
 // NewUserUseCase creates a new user use case
@@ -22,6 +24,16 @@ func NewUserUseCase(userRepo repository.UserRepository, cacheRepo repository.Cac
 	}
 }
 
+// Subscribe registers a listener for follow and mute changes
+func (uc *UserUseCase) Subscribe(listener RelationshipListener) {
+	uc.listeners = append(uc.listeners, listener)
+}
+
+// SubscribeProfiles registers a listener for profile changes
+func (uc *UserUseCase) SubscribeProfiles(listener ProfileListener) {
+	uc.profileListeners = append(uc.profileListeners, listener)
+}
+
 // CreateUser creates a new user and returns JWT token
 func (uc *UserUseCase) CreateUser(username, email, displayName string) (*entity.User, string, error) {
 	// Create user entity
@@ -74,8 +86,17 @@ func (uc *UserUseCase) GetUserByID(id int64) (*entity.User, error) {
 	return user, nil
 }
 
-// GetUserWithStats retrieves a user with statistics
-func (uc *UserUseCase) GetUserWithStats(id int64) (*entity.User, *entity.UserStats, error) {
+// GetUserWithStats retrieves a user with statistics as seen by viewerID;
+// users in a block with the viewer are not found
+func (uc *UserUseCase) GetUserWithStats(id, viewerID int64) (*entity.User, *entity.UserStats, error) {
+	blocked, err := isBlocked(uc.userRepo, viewerID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if blocked {
+		return nil, nil, entity.ErrUserNotFound
+	}
+
 	user, err := uc.GetUserByID(id)
 	if err != nil {
 		return nil, nil, err
@@ -89,8 +110,10 @@ func (uc *UserUseCase) GetUserWithStats(id int64) (*entity.User, *entity.UserSta
 	return user, stats, nil
 }
 
-// UpdateUser updates user profile
-func (uc *UserUseCase) UpdateUser(id int64, displayName, bio, profileImage string) (*entity.User, error) {
+// UpdateUser updates user profile; a nil private leaves the account's
+// privacy alone. Making a private account public accepts its pending
+// follow requests.
+func (uc *UserUseCase) UpdateUser(id int64, displayName, bio, profileImage string, private *bool) (*entity.User, error) {
 	// Get existing user
 	user, err := uc.userRepo.GetByID(id)
 	if err != nil {
@@ -99,6 +122,10 @@ func (uc *UserUseCase) UpdateUser(id int64, displayName, bio, profileImage strin
 
 	// Update
 	user.Update(displayName, bio, profileImage)
+	wasPrivate := user.Private
+	if private != nil {
+		user.Private = *private
+	}
 
 	// Save
 	if err := uc.userRepo.Update(user); err != nil {
@@ -110,17 +137,58 @@ func (uc *UserUseCase) UpdateUser(id int64, displayName, bio, profileImage strin
 		uc.cacheRepo.DeleteUser(id)
 	}
 
+	if wasPrivate && !user.Private {
+		followerIDs, err := uc.userRepo.AcceptAllFollowRequests(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, followerID := range followerIDs {
+			uc.followAccepted(followerID, id)
+		}
+	}
+
+	for _, listener := range uc.profileListeners {
+		listener.ProfileUpdated(user)
+	}
+
 	return user, nil
 }
 
-// FollowUser creates a follow relationship
-func (uc *UserUseCase) FollowUser(followerID, followingID int64) error {
+// FollowUser creates a follow relationship and returns its status: follows
+// of private accounts are pending until the followed user accepts them
+func (uc *UserUseCase) FollowUser(followerID, followingID int64) (string, error) {
 	if followerID == followingID {
-		return entity.ErrCannotFollowSelf
+		return "", entity.ErrCannotFollowSelf
 	}
 
-	if err := uc.userRepo.Follow(followerID, followingID); err != nil {
-		return err
+	blocked, err := uc.userRepo.IsBlocked(followerID, followingID)
+	if err != nil {
+		return "", err
+	}
+	if blocked {
+		return "", entity.ErrUserBlocked
+	}
+
+	following, err := uc.userRepo.GetByID(followingID)
+	if err != nil {
+		return "", err
+	}
+
+	status := entity.FollowAccepted
+	if following.Private {
+		status = entity.FollowPending
+	}
+
+	status, err = uc.userRepo.Follow(followerID, followingID, status)
+	if err != nil {
+		return "", err
+	}
+
+	if status == entity.FollowPending {
+		for _, listener := range uc.listeners {
+			listener.FollowRequested(followerID, followingID)
+		}
+		return status, nil
 	}
 
 	// Invalidate caches
@@ -129,9 +197,46 @@ func (uc *UserUseCase) FollowUser(followerID, followingID int64) error {
 		uc.cacheRepo.DeleteUser(followingID)
 	}
 
+	for _, listener := range uc.listeners {
+		listener.UserFollowed(followerID, followingID)
+	}
+
+	return status, nil
+}
+
+// GetFollowRequests retrieves the pending follow requests of a user
+func (uc *UserUseCase) GetFollowRequests(userID int64, limit, offset int) ([]*entity.FollowRequest, error) {
+	return uc.userRepo.GetFollowRequests(userID, limit, offset)
+}
+
+// AcceptFollowRequest lets followerID follow userID
+func (uc *UserUseCase) AcceptFollowRequest(userID, followerID int64) error {
+	if err := uc.userRepo.AcceptFollowRequest(followerID, userID); err != nil {
+		return err
+	}
+
+	uc.followAccepted(followerID, userID)
 	return nil
 }
 
+// RejectFollowRequest turns down the follow request of followerID
+func (uc *UserUseCase) RejectFollowRequest(userID, followerID int64) error {
+	return uc.userRepo.RejectFollowRequest(followerID, userID)
+}
+
+// followAccepted invalidates caches and tells the listeners about an
+// accepted follow request
+func (uc *UserUseCase) followAccepted(followerID, followingID int64) {
+	if uc.cacheRepo != nil {
+		uc.cacheRepo.DeleteUser(followerID)
+		uc.cacheRepo.DeleteUser(followingID)
+	}
+
+	for _, listener := range uc.listeners {
+		listener.FollowAccepted(followerID, followingID)
+	}
+}
+
 // UnfollowUser removes a follow relationship
 func (uc *UserUseCase) UnfollowUser(followerID, followingID int64) error {
 	if err := uc.userRepo.Unfollow(followerID, followingID); err != nil {
@@ -144,17 +249,103 @@ func (uc *UserUseCase) UnfollowUser(followerID, followingID int64) error {
 		uc.cacheRepo.DeleteUser(followingID)
 	}
 
+	for _, listener := range uc.listeners {
+		listener.UserUnfollowed(followerID, followingID)
+	}
+
+	return nil
+}
+
+// GetFollowers retrieves a user's followers, leaving out users in a block
+// with viewerID
+func (uc *UserUseCase) GetFollowers(userID, viewerID int64, limit, offset int) ([]*entity.User, error) {
+	return uc.userRepo.GetFollowers(userID, viewerID, limit, offset)
+}
+
+// GetFollowing retrieves users that a user is following, leaving out users
+// in a block with viewerID
+func (uc *UserUseCase) GetFollowing(userID, viewerID int64, limit, offset int) ([]*entity.User, error) {
+	return uc.userRepo.GetFollowing(userID, viewerID, limit, offset)
+}
+
+// BlockUser blocks a user: neither can see the other's blogs, profile or
+// comments, follow the other, or react to or comment on the other's blogs.
+// Existing follows between them are removed.
+func (uc *UserUseCase) BlockUser(blockerID, blockedID int64) error {
+	if blockerID == blockedID {
+		return entity.ErrCannotBlockSelf
+	}
+
+	if _, err := uc.userRepo.GetByID(blockedID); err != nil {
+		return err
+	}
+
+	if err := uc.userRepo.Block(blockerID, blockedID); err != nil {
+		return err
+	}
+
+	// Invalidate caches
+	if uc.cacheRepo != nil {
+		uc.cacheRepo.DeleteUser(blockerID)
+		uc.cacheRepo.DeleteUser(blockedID)
+	}
+
+	for _, listener := range uc.listeners {
+		listener.UserUnfollowed(blockerID, blockedID)
+		listener.UserUnfollowed(blockedID, blockerID)
+	}
+
 	return nil
 }
 
-// GetFollowers retrieves a user's followers
-func (uc *UserUseCase) GetFollowers(userID int64, limit, offset int) ([]*entity.User, error) {
-	return uc.userRepo.GetFollowers(userID, limit, offset)
+// UnblockUser removes a block; follows removed by it are not restored
+func (uc *UserUseCase) UnblockUser(blockerID, blockedID int64) error {
+	return uc.userRepo.Unblock(blockerID, blockedID)
+}
+
+// GetBlockedUsers retrieves the users a user has blocked
+func (uc *UserUseCase) GetBlockedUsers(userID int64, limit, offset int) ([]*entity.User, error) {
+	return uc.userRepo.GetBlocked(userID, limit, offset)
+}
+
+// MuteUser hides a user's blogs from the muter's feed and their actions
+// from the muter's notifications, without them knowing
+func (uc *UserUseCase) MuteUser(muterID, mutedID int64) error {
+	if muterID == mutedID {
+		return entity.ErrCannotMuteSelf
+	}
+
+	if _, err := uc.userRepo.GetByID(mutedID); err != nil {
+		return err
+	}
+
+	if err := uc.userRepo.Mute(muterID, mutedID); err != nil {
+		return err
+	}
+
+	for _, listener := range uc.listeners {
+		listener.UserMuted(muterID, mutedID)
+	}
+
+	return nil
+}
+
+// UnmuteUser removes a mute
+func (uc *UserUseCase) UnmuteUser(muterID, mutedID int64) error {
+	if err := uc.userRepo.Unmute(muterID, mutedID); err != nil {
+		return err
+	}
+
+	for _, listener := range uc.listeners {
+		listener.UserUnmuted(muterID, mutedID)
+	}
+
+	return nil
 }
 
-// GetFollowing retrieves users that a user is following
-func (uc *UserUseCase) GetFollowing(userID int64, limit, offset int) ([]*entity.User, error) {
-	return uc.userRepo.GetFollowing(userID, limit, offset)
+// GetMutedUsers retrieves the users a user has muted
+func (uc *UserUseCase) GetMutedUsers(userID int64, limit, offset int) ([]*entity.User, error) {
+	return uc.userRepo.GetMuted(userID, limit, offset)
 }
 
 
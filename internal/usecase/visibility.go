@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+)
+
+// blockedUsers returns the users hidden from viewerID because one of them
+// blocked the other. Anonymous viewers (ID 0) see everyone.
+func blockedUsers(userRepo repository.UserRepository, viewerID int64) (map[int64]bool, error) {
+	blocked := map[int64]bool{}
+	if viewerID == 0 {
+		return blocked, nil
+	}
+
+	ids, err := userRepo.GetBlockIDs(viewerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		blocked[id] = true
+	}
+	return blocked, nil
+}
+
+// isBlocked reports whether viewerID and userID are in a block; anonymous
+// viewers are never blocked
+func isBlocked(userRepo repository.UserRepository, viewerID, userID int64) (bool, error) {
+	if viewerID == 0 || viewerID == userID {
+		return false, nil
+	}
+	return userRepo.IsBlocked(viewerID, userID)
+}
+
+// canSeeAuthor reports whether viewerID may see the blogs of authorID: the
+// two are not in a block and, when the author's account is private, the
+// viewer is the author or one of their accepted followers
+func canSeeAuthor(userRepo repository.UserRepository, viewerID, authorID int64) (bool, error) {
+	if viewerID == authorID {
+		return true, nil
+	}
+
+	blocked, err := isBlocked(userRepo, viewerID, authorID)
+	if err != nil || blocked {
+		return false, err
+	}
+
+	author, err := userRepo.GetByID(authorID)
+	if err != nil {
+		return false, err
+	}
+	if !author.Private {
+		return true, nil
+	}
+	if viewerID == 0 {
+		return false, nil
+	}
+	return userRepo.IsFollowing(viewerID, authorID)
+}
+
+// hidePrivateAuthors adds to hidden the private authors of blogs that
+// viewerID is not an accepted follower of
+func hidePrivateAuthors(userRepo repository.UserRepository, viewerID int64, blogs []*entity.Blog, hidden map[int64]bool) error {
+	checked := map[int64]bool{}
+	for _, blog := range blogs {
+		author := blog.Author
+		if author == nil || !author.Private || author.ID == viewerID || checked[author.ID] {
+			continue
+		}
+		checked[author.ID] = true
+
+		following := false
+		if viewerID != 0 {
+			var err error
+			following, err = userRepo.IsFollowing(viewerID, author.ID)
+			if err != nil {
+				return err
+			}
+		}
+		if !following {
+			hidden[author.ID] = true
+		}
+	}
+	return nil
+}
+
+// visibleBlogs leaves out blogs by hidden authors
+func visibleBlogs(blogs []*entity.Blog, hidden map[int64]bool) []*entity.Blog {
+	visible := make([]*entity.Blog, 0, len(blogs))
+	for _, blog := range blogs {
+		if !hidden[blog.AuthorID] {
+			visible = append(visible, blog)
+		}
+	}
+	return visible
+}
+
+// visibleComments leaves out comments by hidden authors, along with the
+// replies to them
+func visibleComments(comments []*entity.Comment, hidden map[int64]bool) []*entity.Comment {
+	visible := make([]*entity.Comment, 0, len(comments))
+	for _, comment := range comments {
+		if hidden[comment.AuthorID] {
+			continue
+		}
+		comment.Replies = visibleComments(comment.Replies, hidden)
+		visible = append(visible, comment)
+	}
+	return visible
+}
@@ -0,0 +1,278 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+	"AbdelrahmanDwedar/blogo/internal/domain/service"
+)
+
+const (
+	// webmentionMaxAttempts is how often sending or verifying a Webmention
+	// is tried
+	webmentionMaxAttempts = 6
+
+	// webmentionHostLimit is how many Webmentions are accepted from the
+	// sources on one host within webmentionHostWindow, each of which makes
+	// the server fetch its source
+	webmentionHostLimit  = 20
+	webmentionHostWindow = time.Hour
+)
+
+// sendWebmentionPayload is the payload of a send_webmention job
+type sendWebmentionPayload struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// verifyWebmentionPayload is the payload of a verify_webmention job
+type verifyWebmentionPayload struct {
+	WebmentionID int64 `json:"webmention_id"`
+}
+
+// WebmentionUseCase lets blogs take part in the IndieWeb: it sends
+// Webmentions to the pages a blog links to whenever it is published,
+// edited or trashed, and accepts Webmentions from pages linking to blogs,
+// showing them as responses once their source is verified. Sending and
+// verifying both run on the job queue.
+type WebmentionUseCase struct {
+	webmentionRepo repository.WebmentionRepository
+	blogRepo       repository.BlogRepository
+	userRepo       repository.UserRepository
+	jobRepo        repository.JobRepository
+	client         service.WebmentionClient
+	baseURL        string
+}
+
+// NewWebmentionUseCase creates a new Webmention use case. baseURL is the
+// public URL of this server, which blog URLs are built on.
+func NewWebmentionUseCase(webmentionRepo repository.WebmentionRepository, blogRepo repository.BlogRepository,
+	userRepo repository.UserRepository, jobRepo repository.JobRepository, client service.WebmentionClient,
+	baseURL string) *WebmentionUseCase {
+	return &WebmentionUseCase{
+		webmentionRepo: webmentionRepo,
+		blogRepo:       blogRepo,
+		userRepo:       userRepo,
+		jobRepo:        jobRepo,
+		client:         client,
+		baseURL:        strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Receive accepts a Webmention claiming that source links to target, which
+// must be a blog by a public author. The Webmention is stored pending and
+// its source is verified in the background; a Webmention from a source
+// seen before is verified again, which is how sources report edits and
+// deletions. Each source host may only send webmentionHostLimit
+// Webmentions within webmentionHostWindow.
+func (uc *WebmentionUseCase) Receive(source, target string) (*entity.Webmention, error) {
+	source = strings.TrimSpace(source)
+	target = strings.TrimSpace(target)
+	if !entity.IsWebURL(source) || !entity.IsWebURL(target) || source == target {
+		return nil, entity.ErrInvalidWebmention
+	}
+
+	blogID := blogIDFromURL(uc.baseURL, target)
+	if blogID == 0 {
+		return nil, entity.ErrInvalidWebmention
+	}
+
+	blog, err := uc.blogRepo.GetByID(blogID)
+	if err != nil {
+		return nil, err
+	}
+	visible, err := canSeeAuthor(uc.userRepo, 0, blog.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, entity.ErrBlogNotFound
+	}
+
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, entity.ErrInvalidWebmention
+	}
+	received, err := uc.webmentionRepo.CountFromHost(u.Host, time.Now().Add(-webmentionHostWindow))
+	if err != nil {
+		return nil, err
+	}
+	if received >= webmentionHostLimit {
+		return nil, entity.ErrWebmentionRateLimited
+	}
+
+	webmention := entity.NewWebmention(blog.ID, source, target)
+	if err := uc.webmentionRepo.Save(webmention); err != nil {
+		return nil, err
+	}
+
+	job, err := entity.NewJob(entity.JobVerifyWebmention, 0, verifyWebmentionPayload{
+		WebmentionID: webmention.ID,
+	}, webmentionMaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.jobRepo.Enqueue(job); err != nil {
+		return nil, err
+	}
+
+	return webmention, nil
+}
+
+// GetWebmentions retrieves a page of the verified Webmentions of a blog the
+// viewer can see
+func (uc *WebmentionUseCase) GetWebmentions(blogID, viewerID int64, limit, offset int) ([]*entity.Webmention, error) {
+	blog, err := uc.blogRepo.GetByID(blogID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible, err := canSeeAuthor(uc.userRepo, viewerID, blog.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, entity.ErrBlogNotFound
+	}
+
+	return uc.webmentionRepo.GetVerified(blogID, limit, offset)
+}
+
+// RunVerifyJob fetches the source of the Webmention of a verify_webmention
+// job. Webmentions whose source links to the blog are verified; the rest,
+// including those whose source is gone, are deleted.
+func (uc *WebmentionUseCase) RunVerifyJob(job *entity.Job) (string, error) {
+	var payload verifyWebmentionPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return "", entity.ErrJobNotRetryable
+	}
+
+	webmention, err := uc.webmentionRepo.GetByID(payload.WebmentionID)
+	if errors.Is(err, entity.ErrWebmentionNotFound) {
+		return "", entity.ErrJobNotRetryable
+	}
+	if err != nil {
+		return "", err
+	}
+
+	source, err := uc.client.FetchSource(webmention.Source, webmention.Target)
+	if errors.Is(err, entity.ErrWebmentionSourceGone) {
+		return "deleted", uc.webmentionRepo.Delete(webmention.ID)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if !source.LinksTarget {
+		return "rejected", uc.webmentionRepo.Delete(webmention.ID)
+	}
+	return entity.WebmentionVerified, uc.webmentionRepo.MarkVerified(webmention.ID, source.Title)
+}
+
+// BlogSaved sends Webmentions for a new, edited or restored blog to every
+// URL it links to, and to those it linked to before an edit so they learn
+// about removed links. Blogs of private authors are not announced.
+func (uc *WebmentionUseCase) BlogSaved(blog *entity.Blog, created bool) {
+	author, err := uc.userRepo.GetByID(blog.AuthorID)
+	if err != nil {
+		log.Printf("⚠️  Failed to load author of blog %d for webmentions: %v\n", blog.ID, err)
+		return
+	}
+	if author.Private {
+		return
+	}
+
+	source := blogURL(uc.baseURL, blog.ID)
+	links := []string{}
+	for _, link := range entity.ExtractLinks(blog.Body) {
+		if link != source {
+			links = append(links, link)
+		}
+	}
+
+	previous, err := uc.webmentionRepo.GetTargets(blog.ID)
+	if err != nil {
+		log.Printf("⚠️  Failed to get webmention targets of blog %d: %v\n", blog.ID, err)
+		return
+	}
+	if err := uc.webmentionRepo.SetTargets(blog.ID, links); err != nil {
+		log.Printf("⚠️  Failed to store webmention targets of blog %d: %v\n", blog.ID, err)
+		return
+	}
+
+	targets := links
+	linked := map[string]bool{}
+	for _, link := range links {
+		linked[link] = true
+	}
+	for _, target := range previous {
+		if !linked[target] {
+			targets = append(targets, target)
+		}
+	}
+
+	uc.send(blog, targets)
+}
+
+// BlogDeleted sends Webmentions for a trashed blog to the URLs it linked
+// to, whose sites then find the blog gone
+func (uc *WebmentionUseCase) BlogDeleted(blog *entity.Blog) {
+	targets, err := uc.webmentionRepo.GetTargets(blog.ID)
+	if err != nil {
+		log.Printf("⚠️  Failed to get webmention targets of blog %d: %v\n", blog.ID, err)
+		return
+	}
+
+	uc.send(blog, targets)
+}
+
+// send queues a Webmention from a blog to each of targets, logging failures
+func (uc *WebmentionUseCase) send(blog *entity.Blog, targets []string) {
+	source := blogURL(uc.baseURL, blog.ID)
+	for _, target := range targets {
+		job, err := entity.NewJob(entity.JobSendWebmention, blog.AuthorID, sendWebmentionPayload{
+			Source: source,
+			Target: target,
+		}, webmentionMaxAttempts)
+		if err == nil {
+			err = uc.jobRepo.Enqueue(job)
+		}
+		if err != nil {
+			log.Printf("⚠️  Failed to queue webmention from blog %d to %s: %v\n", blog.ID, target, err)
+		}
+	}
+}
+
+// RunSendJob discovers the Webmention endpoint of the target of a
+// send_webmention job and sends the Webmention to it, returning the
+// endpoint. Targets without an endpoint are skipped.
+func (uc *WebmentionUseCase) RunSendJob(job *entity.Job) (string, error) {
+	var payload sendWebmentionPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return "", entity.ErrJobNotRetryable
+	}
+
+	endpoint, err := uc.client.DiscoverEndpoint(payload.Target)
+	if err != nil {
+		return "", err
+	}
+	if !entity.IsWebURL(endpoint) {
+		return "no endpoint", nil
+	}
+
+	err = uc.client.Send(endpoint, payload.Source, payload.Target)
+	if errors.Is(err, entity.ErrWebmentionRejected) {
+		return "", fmt.Errorf("%w: %v", entity.ErrJobNotRetryable, err)
+	}
+	if err != nil {
+		return "", err
+	}
+	return endpoint, nil
+}
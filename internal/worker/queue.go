@@ -0,0 +1,122 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"AbdelrahmanDwedar/blogo/internal/domain/entity"
+	"AbdelrahmanDwedar/blogo/internal/domain/repository"
+)
+
+const (
+	// claimBatch is how many jobs are claimed per poll
+	claimBatch = 10
+
+	// jobLease is how long a claimed job may run before another poller
+	// may assume its worker died and claim it again
+	jobLease = 15 * time.Minute
+
+	// Retry delays grow exponentially from retryBase up to retryMax
+	retryBase = 30 * time.Second
+	retryMax  = 6 * time.Hour
+)
+
+// JobHandler runs a claimed job and returns the result to store with it
+type JobHandler func(job *entity.Job) (string, error)
+
+// Queue dispatches jobs from the job repository to handlers by kind,
+// retrying failures with exponential backoff
+type Queue struct {
+	jobs     repository.JobRepository
+	handlers map[string]JobHandler
+}
+
+// NewQueue creates a new job queue runner
+func NewQueue(jobs repository.JobRepository) *Queue {
+	return &Queue{
+		jobs:     jobs,
+		handlers: map[string]JobHandler{},
+	}
+}
+
+// Handle registers the handler for a job kind. Jobs of kinds without a
+// handler are left in the queue.
+func (q *Queue) Handle(kind string, handler JobHandler) {
+	q.handlers[kind] = handler
+}
+
+// Run polls for due jobs every interval until ctx is cancelled
+func (q *Queue) Run(ctx context.Context, interval time.Duration) {
+	Every(ctx, "job-queue", interval, q.process)
+}
+
+// process claims and runs one batch of due jobs
+func (q *Queue) process() error {
+	if len(q.handlers) == 0 {
+		return nil
+	}
+
+	kinds := make([]string, 0, len(q.handlers))
+	for kind := range q.handlers {
+		kinds = append(kinds, kind)
+	}
+
+	jobs, err := q.jobs.Claim(kinds, claimBatch, jobLease)
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		q.run(job)
+	}
+	return nil
+}
+
+// run executes a single job and records the outcome
+func (q *Queue) run(job *entity.Job) {
+	result, err := q.call(job)
+	if err == nil {
+		if err := q.jobs.Complete(job.ID, result); err != nil {
+			log.Printf("⚠️  Failed to complete job %d: %v\n", job.ID, err)
+		}
+		return
+	}
+
+	if job.Attempts >= job.MaxAttempts || errors.Is(err, entity.ErrJobNotRetryable) {
+		log.Printf("❌ Job %d (%s) failed: %v\n", job.ID, job.Kind, err)
+		if err := q.jobs.Fail(job.ID, err.Error()); err != nil {
+			log.Printf("⚠️  Failed to mark job %d failed: %v\n", job.ID, err)
+		}
+		return
+	}
+
+	if err := q.jobs.Retry(job.ID, err.Error(), backoff(job.Attempts)); err != nil {
+		log.Printf("⚠️  Failed to reschedule job %d: %v\n", job.ID, err)
+	}
+}
+
+// call runs the job's handler, turning panics into errors so one bad job
+// cannot take the poller down
+func (q *Queue) call(job *entity.Job) (result string, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return q.handlers[job.Kind](job)
+}
+
+// backoff returns the delay before the next attempt after attempts tries
+func backoff(attempts int) time.Duration {
+	delay := retryBase
+	for i := 1; i < attempts && delay < retryMax; i++ {
+		delay *= 2
+	}
+	if delay > retryMax {
+		delay = retryMax
+	}
+	return delay
+}
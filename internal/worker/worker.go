@@ -0,0 +1,31 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Every runs job once immediately and then on every interval until ctx is
+// cancelled. Failures are logged and do not stop the schedule.
+func Every(ctx context.Context, name string, interval time.Duration, job func() error) {
+	run := func() {
+		if err := job(); err != nil {
+			log.Printf("⚠️  Job %s failed: %v\n", name, err)
+		}
+	}
+
+	run()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run()
+		}
+	}
+}
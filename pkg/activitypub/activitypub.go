@@ -0,0 +1,196 @@
+// Package activitypub holds the ActivityStreams vocabulary blogo speaks
+// with other ActivityPub servers, and WebFinger documents for discovering
+// actors
+package activitypub
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ContentType is the media type of ActivityPub documents
+const ContentType = "application/activity+json"
+
+// LDContentType is the JSON-LD media type some servers request instead
+const LDContentType = `application/ld+json; profile="https://www.w3.org/ns/activitystreams"`
+
+// Public addresses an object to everyone
+const Public = "https://www.w3.org/ns/activitystreams#Public"
+
+// Context is the JSON-LD context of documents served by blogo
+var Context = []string{
+	"https://www.w3.org/ns/activitystreams",
+	"https://w3id.org/security/v1",
+}
+
+// Activity types handled by blogo
+const (
+	TypeCreate = "Create"
+	TypeUpdate = "Update"
+	TypeDelete = "Delete"
+	TypeFollow = "Follow"
+	TypeAccept = "Accept"
+	TypeUndo   = "Undo"
+	TypeLike   = "Like"
+)
+
+// Object types used by blogo
+const (
+	TypePerson    = "Person"
+	TypeArticle   = "Article"
+	TypeTombstone = "Tombstone"
+
+	TypeOrderedCollection     = "OrderedCollection"
+	TypeOrderedCollectionPage = "OrderedCollectionPage"
+)
+
+// PublicKey is the key an actor signs its requests with
+type PublicKey struct {
+	ID           string `json:"id"`
+	Owner        string `json:"owner"`
+	PublicKeyPem string `json:"publicKeyPem"`
+}
+
+// Endpoints lists server-wide endpoints of an actor
+type Endpoints struct {
+	SharedInbox string `json:"sharedInbox,omitempty"`
+}
+
+// Actor is an account that can follow and be followed
+type Actor struct {
+	Context           interface{} `json:"@context,omitempty"`
+	ID                string      `json:"id"`
+	Type              string      `json:"type"`
+	PreferredUsername string      `json:"preferredUsername"`
+	Name              string      `json:"name,omitempty"`
+	Summary           string      `json:"summary,omitempty"`
+	URL               string      `json:"url,omitempty"`
+	Icon              *Image      `json:"icon,omitempty"`
+	Inbox             string      `json:"inbox"`
+	Outbox            string      `json:"outbox,omitempty"`
+	Followers         string      `json:"followers,omitempty"`
+	Following         string      `json:"following,omitempty"`
+	Endpoints         *Endpoints  `json:"endpoints,omitempty"`
+	PublicKey         *PublicKey  `json:"publicKey,omitempty"`
+	Published         *time.Time  `json:"published,omitempty"`
+}
+
+// SharedInbox returns the actor's shared inbox, or its own inbox when it
+// has none
+func (a *Actor) SharedInbox() string {
+	if a.Endpoints != nil && a.Endpoints.SharedInbox != "" {
+		return a.Endpoints.SharedInbox
+	}
+	return a.Inbox
+}
+
+// Image is an avatar or other picture
+type Image struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// Object is an Article published by an actor, or the Tombstone left when
+// it is deleted
+type Object struct {
+	Context      interface{}        `json:"@context,omitempty"`
+	ID           string             `json:"id"`
+	Type         string             `json:"type"`
+	AttributedTo string             `json:"attributedTo,omitempty"`
+	Name         string             `json:"name,omitempty"`
+	Summary      string             `json:"summary,omitempty"`
+	Content      string             `json:"content,omitempty"`
+	URL          string             `json:"url,omitempty"`
+	Tag          []Tag              `json:"tag,omitempty"`
+	Likes        *OrderedCollection `json:"likes,omitempty"`
+	To           []string           `json:"to,omitempty"`
+	Cc           []string           `json:"cc,omitempty"`
+	Published    *time.Time         `json:"published,omitempty"`
+	Updated      *time.Time         `json:"updated,omitempty"`
+	Deleted      *time.Time         `json:"deleted,omitempty"`
+	FormerType   string             `json:"formerType,omitempty"`
+}
+
+// Tag is a hashtag on an object
+type Tag struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// Activity is an action taken by an actor. Its object is kept raw since
+// it is either an IRI or an embedded object, depending on the activity.
+type Activity struct {
+	Context   interface{}     `json:"@context,omitempty"`
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Actor     string          `json:"actor"`
+	Object    json.RawMessage `json:"object"`
+	To        []string        `json:"to,omitempty"`
+	Cc        []string        `json:"cc,omitempty"`
+	Published *time.Time      `json:"published,omitempty"`
+}
+
+// NewActivity creates an activity by actor on object, which is marshalled
+// as its object
+func NewActivity(id, kind, actor string, object interface{}) (*Activity, error) {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	return &Activity{
+		Context: Context,
+		ID:      id,
+		Type:    kind,
+		Actor:   actor,
+		Object:  data,
+	}, nil
+}
+
+// ObjectID returns the ID of the activity's object, whether it is given as
+// an IRI or embedded
+func (a *Activity) ObjectID() string {
+	var id string
+	if err := json.Unmarshal(a.Object, &id); err == nil {
+		return id
+	}
+
+	var object struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(a.Object, &object); err == nil {
+		return object.ID
+	}
+	return ""
+}
+
+// Embedded decodes the activity's object when it is embedded, returning
+// nil when it is only an IRI
+func (a *Activity) Embedded() *Activity {
+	var object Activity
+	if err := json.Unmarshal(a.Object, &object); err != nil {
+		return nil
+	}
+	return &object
+}
+
+// OrderedCollection is a list of items, either inline or split into pages
+type OrderedCollection struct {
+	Context    interface{}   `json:"@context,omitempty"`
+	ID         string        `json:"id"`
+	Type       string        `json:"type"`
+	TotalItems int           `json:"totalItems"`
+	First      string        `json:"first,omitempty"`
+	Last       string        `json:"last,omitempty"`
+	Items      []interface{} `json:"orderedItems,omitempty"`
+}
+
+// OrderedCollectionPage is one page of an OrderedCollection
+type OrderedCollectionPage struct {
+	Context interface{}   `json:"@context,omitempty"`
+	ID      string        `json:"id"`
+	Type    string        `json:"type"`
+	PartOf  string        `json:"partOf"`
+	Next    string        `json:"next,omitempty"`
+	Prev    string        `json:"prev,omitempty"`
+	Items   []interface{} `json:"orderedItems"`
+}
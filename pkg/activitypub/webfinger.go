@@ -0,0 +1,29 @@
+package activitypub
+
+// WebFingerContentType is the media type of WebFinger documents
+const WebFingerContentType = "application/jrd+json"
+
+// WebFinger is a JSON Resource Descriptor answering a WebFinger query
+type WebFinger struct {
+	Subject string   `json:"subject"`
+	Aliases []string `json:"aliases,omitempty"`
+	Links   []Link   `json:"links"`
+}
+
+// Link is a link of a WebFinger document
+type Link struct {
+	Rel  string `json:"rel"`
+	Type string `json:"type,omitempty"`
+	Href string `json:"href,omitempty"`
+}
+
+// ActorLink returns the IRI of the ActivityPub actor the document
+// describes, or "" when it has none
+func (w *WebFinger) ActorLink() string {
+	for _, link := range w.Links {
+		if link.Rel == "self" && (link.Type == ContentType || link.Type == LDContentType) {
+			return link.Href
+		}
+	}
+	return ""
+}
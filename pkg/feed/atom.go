@@ -0,0 +1,108 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Author   *atomPerson `xml:"author,omitempty"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Published  string         `xml:"published,omitempty"`
+	Updated    string         `xml:"updated"`
+	Links      []atomLink     `xml:"link"`
+	Author     *atomPerson    `xml:"author,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Content    *atomText      `xml:"content,omitempty"`
+}
+
+// Atom renders the feed as an Atom 1.0 document
+func (f *Feed) Atom() ([]byte, error) {
+	doc := atomFeed{
+		ID:       f.ID,
+		Title:    f.Title,
+		Subtitle: f.Description,
+		Updated:  atomTime(f.LastUpdated()),
+		Author:   atomAuthor(f.Author),
+	}
+	if f.Link != "" {
+		doc.Links = append(doc.Links, atomLink{Rel: "alternate", Type: "text/html", Href: f.Link})
+	}
+	if f.Self != "" {
+		doc.Links = append(doc.Links, atomLink{Rel: "self", Type: "application/atom+xml", Href: f.Self})
+	}
+
+	for _, item := range f.Items {
+		entry := atomEntry{
+			ID:      item.ID,
+			Title:   item.Title,
+			Updated: atomTime(item.Updated),
+			Author:  atomAuthor(item.Author),
+		}
+		if !item.Published.IsZero() {
+			entry.Published = atomTime(item.Published)
+		}
+		if item.Link != "" {
+			entry.Links = []atomLink{{Rel: "alternate", Type: "text/html", Href: item.Link}}
+		}
+		for _, category := range item.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: category})
+		}
+		if item.Summary != "" {
+			entry.Summary = &atomText{Type: "text", Body: item.Summary}
+		}
+		if item.Content != "" {
+			entry.Content = &atomText{Type: "html", Body: item.Content}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func atomAuthor(p *Person) *atomPerson {
+	if p == nil {
+		return nil
+	}
+	return &atomPerson{Name: p.Name, URI: p.URI}
+}
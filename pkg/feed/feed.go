@@ -0,0 +1,86 @@
+package feed
+
+import (
+	"errors"
+	"time"
+)
+
+// Feed is a format-neutral syndication feed
+type Feed struct {
+	ID          string
+	Title       string
+	Description string
+	Link        string // HTML page the feed belongs to
+	Self        string // URL the feed itself is served from
+	Updated     time.Time
+	Author      *Person
+	Items       []*Item
+}
+
+// Person is a feed or item author
+type Person struct {
+	Name string
+	URI  string
+}
+
+// Item is a single feed entry. Content holds HTML.
+type Item struct {
+	ID         string
+	Title      string
+	Link       string
+	Summary    string
+	Content    string
+	Author     *Person
+	Published  time.Time
+	Updated    time.Time
+	Categories []string
+}
+
+// LastUpdated returns the newest update time among the feed's items, or
+// the feed's own Updated time when it is later
+func (f *Feed) LastUpdated() time.Time {
+	updated := f.Updated
+	for _, item := range f.Items {
+		if item.Updated.After(updated) {
+			updated = item.Updated
+		}
+	}
+	return updated
+}
+
+// Formats a feed can be rendered in
+const (
+	FormatRSS  = "rss"
+	FormatAtom = "atom"
+	FormatJSON = "json"
+)
+
+// ErrUnknownFormat is returned when rendering a feed in a format other than
+// FormatRSS, FormatAtom or FormatJSON
+var ErrUnknownFormat = errors.New("unknown feed format")
+
+// ContentType returns the media type of a feed format
+func ContentType(format string) string {
+	switch format {
+	case FormatRSS:
+		return "application/rss+xml; charset=utf-8"
+	case FormatAtom:
+		return "application/atom+xml; charset=utf-8"
+	case FormatJSON:
+		return "application/feed+json; charset=utf-8"
+	}
+	return ""
+}
+
+// Render renders the feed in the given format
+func (f *Feed) Render(format string) ([]byte, error) {
+	switch format {
+	case FormatRSS:
+		return f.RSS()
+	case FormatAtom:
+		return f.Atom()
+	case FormatJSON:
+		return f.JSON()
+	}
+	return nil, ErrUnknownFormat
+}
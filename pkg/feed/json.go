@@ -0,0 +1,76 @@
+package feed
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type jsonFeed struct {
+	Version     string       `json:"version"`
+	Title       string       `json:"title"`
+	HomePageURL string       `json:"home_page_url,omitempty"`
+	FeedURL     string       `json:"feed_url,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Authors     []jsonAuthor `json:"authors,omitempty"`
+	Items       []jsonItem   `json:"items"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type jsonItem struct {
+	ID            string       `json:"id"`
+	URL           string       `json:"url,omitempty"`
+	Title         string       `json:"title"`
+	ContentHTML   string       `json:"content_html,omitempty"`
+	Summary       string       `json:"summary,omitempty"`
+	DatePublished string       `json:"date_published,omitempty"`
+	DateModified  string       `json:"date_modified,omitempty"`
+	Authors       []jsonAuthor `json:"authors,omitempty"`
+	Tags          []string     `json:"tags,omitempty"`
+}
+
+// JSON renders the feed as a JSON Feed 1.1 document
+func (f *Feed) JSON() ([]byte, error) {
+	doc := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageURL: f.Link,
+		FeedURL:     f.Self,
+		Description: f.Description,
+		Authors:     jsonAuthors(f.Author),
+		Items:       []jsonItem{},
+	}
+
+	for _, item := range f.Items {
+		entry := jsonItem{
+			ID:           item.ID,
+			URL:          item.Link,
+			Title:        item.Title,
+			ContentHTML:  item.Content,
+			Summary:      item.Summary,
+			DateModified: jsonTime(item.Updated),
+			Authors:      jsonAuthors(item.Author),
+			Tags:         item.Categories,
+		}
+		if !item.Published.IsZero() {
+			entry.DatePublished = jsonTime(item.Published)
+		}
+		doc.Items = append(doc.Items, entry)
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+func jsonTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func jsonAuthors(p *Person) []jsonAuthor {
+	if p == nil {
+		return nil
+	}
+	return []jsonAuthor{{Name: p.Name, URL: p.URI}}
+}
@@ -0,0 +1,93 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	DCNS    string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Self          *atomLink `xml:"atom:link,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link,omitempty"`
+	GUID        rssGUID  `xml:"guid"`
+	Description string   `xml:"description,omitempty"`
+	Creator     string   `xml:"dc:creator,omitempty"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+	Categories  []string `xml:"category"`
+}
+
+// RSS renders the feed as an RSS 2.0 document. Items carry their HTML
+// content as the description, or the summary when they have none.
+func (f *Feed) RSS() ([]byte, error) {
+	doc := rssDocument{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   f.Description,
+			LastBuildDate: rssTime(f.LastUpdated()),
+		},
+	}
+	if doc.Channel.Description == "" {
+		doc.Channel.Description = f.Title
+	}
+	if f.Self != "" {
+		doc.Channel.Self = &atomLink{Rel: "self", Type: "application/rss+xml", Href: f.Self}
+	}
+
+	for _, item := range f.Items {
+		entry := rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        rssGUID{Value: item.ID},
+			Description: item.Content,
+			Categories:  item.Categories,
+		}
+		if entry.Description == "" {
+			entry.Description = item.Summary
+		}
+		if item.Author != nil {
+			entry.Creator = item.Author.Name
+		}
+		if !item.Published.IsZero() {
+			entry.PubDate = rssTime(item.Published)
+		}
+		doc.Channel.Items = append(doc.Channel.Items, entry)
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+func rssTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC1123Z)
+}
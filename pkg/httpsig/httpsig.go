@@ -0,0 +1,164 @@
+// Package httpsig signs and verifies HTTP requests with HTTP Signatures
+// (draft-cavage-http-signatures) using RSA-SHA256, as spoken by ActivityPub
+// servers.
+package httpsig
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MaxClockSkew is how far a signed request's Date may be from now
+const MaxClockSkew = 12 * time.Hour
+
+// signedHeaders are the headers covered by signatures this package makes,
+// and the minimum it accepts
+var signedHeaders = []string{"(request-target)", "host", "date", "digest"}
+
+// Errors returned by Verify
+var (
+	ErrMissingSignature = errors.New("missing signature")
+	ErrMalformed        = errors.New("malformed signature")
+	ErrUnsupported      = errors.New("unsupported signature algorithm")
+	ErrHeaderNotSigned  = errors.New("required header not signed")
+	ErrDigestMismatch   = errors.New("digest does not match body")
+	ErrExpired          = errors.New("signature date out of range")
+	ErrBadSignature     = errors.New("signature does not verify")
+)
+
+// KeyLookup returns the public key identified by a signature's keyId
+type KeyLookup func(keyID string) (*rsa.PublicKey, error)
+
+// Digest returns the value of the Digest header for body
+func Digest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// Sign adds Date, Digest and Signature headers to req, which will carry
+// body, signing with key under keyID
+func Sign(req *http.Request, body []byte, keyID string, key *rsa.PrivateKey) error {
+	if req.Header.Get("Date") == "" {
+		req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	}
+	req.Header.Set("Digest", Digest(body))
+
+	hash := sha256.Sum256([]byte(signingString(req, signedHeaders)))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+	if err != nil {
+		return fmt.Errorf("sign request: %w", err)
+	}
+
+	req.Header.Set("Signature", fmt.Sprintf(`keyId="%s",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		keyID, strings.Join(signedHeaders, " "), base64.StdEncoding.EncodeToString(signature)))
+	return nil
+}
+
+// Verify checks the Signature header of req, which carried body, and
+// returns the keyId it was signed with. The signature must cover the
+// request target, host, date and digest, the digest must match body and
+// the date must be within MaxClockSkew of now.
+func Verify(req *http.Request, body []byte, lookup KeyLookup) (string, error) {
+	header := req.Header.Get("Signature")
+	if header == "" {
+		return "", ErrMissingSignature
+	}
+
+	params := parseParams(header)
+	keyID, signature := params["keyId"], params["signature"]
+	if keyID == "" || signature == "" {
+		return "", ErrMalformed
+	}
+	if algorithm := params["algorithm"]; algorithm != "" && algorithm != "rsa-sha256" && algorithm != "hs2019" {
+		return "", ErrUnsupported
+	}
+
+	headers := strings.Fields(strings.ToLower(params["headers"]))
+	if len(headers) == 0 {
+		headers = []string{"date"}
+	}
+	for _, required := range signedHeaders {
+		if !contains(headers, required) {
+			return "", ErrHeaderNotSigned
+		}
+	}
+
+	if req.Header.Get("Digest") != Digest(body) {
+		return "", ErrDigestMismatch
+	}
+
+	date, err := http.ParseTime(req.Header.Get("Date"))
+	if err != nil {
+		return "", ErrMalformed
+	}
+	if skew := time.Since(date); skew > MaxClockSkew || skew < -MaxClockSkew {
+		return "", ErrExpired
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return "", ErrMalformed
+	}
+
+	key, err := lookup(keyID)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256([]byte(signingString(req, headers)))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], raw); err != nil {
+		return "", ErrBadSignature
+	}
+	return keyID, nil
+}
+
+// signingString builds the string a signature over headers covers
+func signingString(req *http.Request, headers []string) string {
+	lines := make([]string, 0, len(headers))
+	for _, name := range headers {
+		var value string
+		switch name {
+		case "(request-target)":
+			value = strings.ToLower(req.Method) + " " + req.URL.RequestURI()
+		case "host":
+			value = req.Host
+			if value == "" {
+				value = req.URL.Host
+			}
+		default:
+			value = strings.Join(req.Header.Values(name), ", ")
+		}
+		lines = append(lines, name+": "+value)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseParams splits a Signature header into its key="value" parameters
+func parseParams(header string) map[string]string {
+	params := map[string]string{}
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		params[key] = strings.Trim(value, `"`)
+	}
+	return params
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package httpsig
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testKeyID = "https://remote.example/users/alice#main-key"
+
+var testKey = mustGenerateKey()
+
+func mustGenerateKey() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// signedRequest returns a POST of body to an inbox, signed with testKey
+func signedRequest(t *testing.T, body string) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "https://blogo.example/users/bob/inbox", strings.NewReader(body))
+	if err := Sign(req, []byte(body), testKeyID, testKey); err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	return req
+}
+
+func lookupTestKey(keyID string) (*rsa.PublicKey, error) {
+	if keyID != testKeyID {
+		return nil, errors.New("unknown key")
+	}
+	return &testKey.PublicKey, nil
+}
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+	body := `{"type":"Follow"}`
+	req := signedRequest(t, body)
+
+	keyID, err := Verify(req, []byte(body), lookupTestKey)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if keyID != testKeyID {
+		t.Errorf("keyID = %q, want %q", keyID, testKeyID)
+	}
+}
+
+func TestVerifyRejectsTampering(t *testing.T) {
+	body := `{"type":"Follow"}`
+	otherKey := mustGenerateKey()
+
+	tests := []struct {
+		name   string
+		body   string
+		tamper func(req *http.Request)
+		lookup KeyLookup
+		want   error
+	}{
+		{
+			name: "changed body",
+			body: `{"type":"Delete"}`,
+			want: ErrDigestMismatch,
+		},
+		{
+			name: "changed digest to match changed body",
+			body: `{"type":"Delete"}`,
+			tamper: func(req *http.Request) {
+				req.Header.Set("Digest", Digest([]byte(`{"type":"Delete"}`)))
+			},
+			want: ErrBadSignature,
+		},
+		{
+			name: "changed target",
+			tamper: func(req *http.Request) {
+				req.URL.Path = "/users/carol/inbox"
+			},
+			want: ErrBadSignature,
+		},
+		{
+			name: "changed host",
+			tamper: func(req *http.Request) {
+				req.Host = "other.example"
+			},
+			want: ErrBadSignature,
+		},
+		{
+			name: "changed date",
+			tamper: func(req *http.Request) {
+				req.Header.Set("Date", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+			},
+			want: ErrBadSignature,
+		},
+		{
+			name: "expired date",
+			tamper: func(req *http.Request) {
+				req.Header.Set("Date", time.Now().Add(-2*MaxClockSkew).UTC().Format(http.TimeFormat))
+			},
+			want: ErrExpired,
+		},
+		{
+			name: "signed by another key",
+			lookup: func(string) (*rsa.PublicKey, error) {
+				return &otherKey.PublicKey, nil
+			},
+			want: ErrBadSignature,
+		},
+		{
+			name: "digest not signed",
+			tamper: func(req *http.Request) {
+				signature := req.Header.Get("Signature")
+				req.Header.Set("Signature", strings.Replace(signature, " digest", "", 1))
+			},
+			want: ErrHeaderNotSigned,
+		},
+		{
+			name: "unsupported algorithm",
+			tamper: func(req *http.Request) {
+				signature := req.Header.Get("Signature")
+				req.Header.Set("Signature", strings.Replace(signature, "rsa-sha256", "hmac-sha256", 1))
+			},
+			want: ErrUnsupported,
+		},
+		{
+			name: "no signature",
+			tamper: func(req *http.Request) {
+				req.Header.Del("Signature")
+			},
+			want: ErrMissingSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := signedRequest(t, body)
+			if tt.tamper != nil {
+				tt.tamper(req)
+			}
+			lookup := tt.lookup
+			if lookup == nil {
+				lookup = lookupTestKey
+			}
+			received := body
+			if tt.body != "" {
+				received = tt.body
+			}
+
+			_, err := Verify(req, []byte(received), lookup)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Verify error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package httpsig
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// keyBits is the size of generated RSA keys
+const keyBits = 2048
+
+// ErrInvalidKey is returned for PEM data that holds no usable RSA key
+var ErrInvalidKey = errors.New("invalid key")
+
+// GenerateKey creates a new RSA key pair, returned as PEM-encoded PKCS#1
+// private key and PKIX public key
+func GenerateKey() (privatePEM, publicPEM string, err error) {
+	key, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		return "", "", fmt.Errorf("generate key: %w", err)
+	}
+
+	public, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return "", "", fmt.Errorf("marshal public key: %w", err)
+	}
+
+	privatePEM = string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	publicPEM = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public}))
+	return privatePEM, publicPEM, nil
+}
+
+// ParsePrivateKey reads a PEM-encoded PKCS#1 or PKCS#8 RSA private key
+func ParsePrivateKey(data string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, ErrInvalidKey
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+	return key, nil
+}
+
+// ParsePublicKey reads a PEM-encoded PKIX or PKCS#1 RSA public key
+func ParsePublicKey(data string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, ErrInvalidKey
+	}
+
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+	return key, nil
+}
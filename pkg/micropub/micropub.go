@@ -0,0 +1,200 @@
+// Package micropub parses requests of the Micropub protocol
+// (https://www.w3.org/TR/micropub/) and holds the documents its endpoint
+// responds with.
+package micropub
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// Actions a request can take
+const (
+	ActionCreate   = "create"
+	ActionUpdate   = "update"
+	ActionDelete   = "delete"
+	ActionUndelete = "undelete"
+)
+
+// TypeEntry is the only post type the endpoint creates
+const TypeEntry = "h-entry"
+
+// Error codes of error responses
+const (
+	ErrorInvalidRequest    = "invalid_request"
+	ErrorUnauthorized      = "unauthorized"
+	ErrorForbidden         = "forbidden"
+	ErrorInsufficientScope = "insufficient_scope"
+)
+
+// ErrInvalidRequest is returned for requests that are not valid Micropub
+var ErrInvalidRequest = errors.New("invalid micropub request")
+
+// Properties maps property names to their values. Values are strings, or
+// objects such as {"html": "..."} for content.
+type Properties map[string][]interface{}
+
+// Request is a parsed Micropub request
+type Request struct {
+	Action string
+	URL    string
+
+	// Type and Properties describe the post to create
+	Type       string
+	Properties Properties
+
+	// Replace, Add and Delete describe an update. Delete removes the given
+	// values; DeleteAll removes the named properties entirely.
+	Replace   Properties
+	Add       Properties
+	Delete    Properties
+	DeleteAll []string
+}
+
+// ParseForm parses a form-encoded request. Form requests create posts,
+// whose properties are the form fields, or delete and undelete posts; a
+// field named "category[]" is the same property as "category".
+func ParseForm(form url.Values) (*Request, error) {
+	req := &Request{
+		Action: form.Get("action"),
+		URL:    form.Get("url"),
+	}
+
+	switch req.Action {
+	case "", ActionCreate:
+		req.Action = ActionCreate
+		req.Type = "h-" + form.Get("h")
+		if req.Type == "h-" {
+			req.Type = TypeEntry
+		}
+
+		req.Properties = Properties{}
+		for key, values := range form {
+			name := strings.TrimSuffix(key, "[]")
+			// h, access_token, action and mp-* commands are not properties
+			if name == "h" || name == "access_token" || name == "action" || strings.HasPrefix(name, "mp-") {
+				continue
+			}
+			for _, value := range values {
+				req.Properties[name] = append(req.Properties[name], value)
+			}
+		}
+	case ActionDelete, ActionUndelete:
+		if req.URL == "" {
+			return nil, ErrInvalidRequest
+		}
+	default:
+		// Updates can only be expressed in JSON
+		return nil, ErrInvalidRequest
+	}
+
+	return req, nil
+}
+
+// ParseJSON parses a JSON request
+func ParseJSON(body []byte) (*Request, error) {
+	var doc struct {
+		Action     string          `json:"action"`
+		URL        string          `json:"url"`
+		Type       []string        `json:"type"`
+		Properties Properties      `json:"properties"`
+		Replace    Properties      `json:"replace"`
+		Add        Properties      `json:"add"`
+		Delete     json.RawMessage `json:"delete"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, ErrInvalidRequest
+	}
+
+	req := &Request{
+		Action:     doc.Action,
+		URL:        doc.URL,
+		Properties: doc.Properties,
+		Replace:    doc.Replace,
+		Add:        doc.Add,
+	}
+
+	switch req.Action {
+	case "", ActionCreate:
+		req.Action = ActionCreate
+		if len(doc.Type) != 1 || doc.Properties == nil {
+			return nil, ErrInvalidRequest
+		}
+		req.Type = doc.Type[0]
+	case ActionUpdate:
+		if req.URL == "" {
+			return nil, ErrInvalidRequest
+		}
+		if len(doc.Delete) > 0 {
+			// delete is either a list of property names or an object of
+			// values to remove
+			if err := json.Unmarshal(doc.Delete, &req.DeleteAll); err != nil {
+				if err := json.Unmarshal(doc.Delete, &req.Delete); err != nil {
+					return nil, ErrInvalidRequest
+				}
+			}
+		}
+	case ActionDelete, ActionUndelete:
+		if req.URL == "" {
+			return nil, ErrInvalidRequest
+		}
+	default:
+		return nil, ErrInvalidRequest
+	}
+
+	return req, nil
+}
+
+// Strings returns the text of values: strings as they are, and objects by
+// their "value" or else "html" member. Other values are left out.
+func Strings(values []interface{}) []string {
+	texts := []string{}
+	for _, value := range values {
+		switch v := value.(type) {
+		case string:
+			texts = append(texts, v)
+		case map[string]interface{}:
+			if text, ok := v["value"].(string); ok {
+				texts = append(texts, text)
+			} else if text, ok := v["html"].(string); ok {
+				texts = append(texts, text)
+			}
+		}
+	}
+	return texts
+}
+
+// String returns the first text of a property, or an empty string
+func (p Properties) String(name string) string {
+	if texts := Strings(p[name]); len(texts) > 0 {
+		return texts[0]
+	}
+	return ""
+}
+
+// Entry is a post as returned for q=source
+type Entry struct {
+	Type       []string   `json:"type"`
+	Properties Properties `json:"properties"`
+}
+
+// PostType is a kind of post the endpoint supports
+type PostType struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// Config is the endpoint configuration returned for q=config
+type Config struct {
+	SyndicateTo []interface{} `json:"syndicate-to"`
+	PostTypes   []PostType    `json:"post-types"`
+	Q           []string      `json:"q"`
+}
+
+// Error is the body of an error response
+type Error struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
@@ -0,0 +1,165 @@
+// Package safehttp makes HTTP clients for fetching URLs that come from
+// untrusted input. They only connect to public internet addresses, checked
+// when each connection is dialed so that neither DNS answers nor redirects
+// can point them at loopback, link-local or private networks.
+package safehttp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// maxRedirects is how many redirects a request follows
+const maxRedirects = 5
+
+// Errors returned, wrapped, by requests of clients this package makes
+var (
+	ErrForbiddenAddress = errors.New("address is not public")
+	ErrInsecureURL      = errors.New("url is not https")
+)
+
+// reservedNetworks are the networks outside those the net package already
+// knows as loopback, link-local, multicast or private that are not
+// reachable on the public internet, or that route to other addresses
+var reservedNetworks = parseNetworks(
+	"0.0.0.0/8",       // "this" network
+	"100.64.0.0/10",   // carrier-grade NAT
+	"192.0.0.0/24",    // IETF protocol assignments
+	"192.0.2.0/24",    // documentation
+	"198.18.0.0/15",   // benchmarking
+	"198.51.100.0/24", // documentation
+	"203.0.113.0/24",  // documentation
+	"240.0.0.0/4",     // reserved, and broadcast
+	"64:ff9b::/96",    // NAT64, which reaches IPv4 addresses
+	"64:ff9b:1::/48",  // local-use NAT64
+	"2001:db8::/32",   // documentation
+	"2002::/16",       // 6to4, which reaches IPv4 addresses
+	"fec0::/10",       // deprecated site-local
+)
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
+
+// IsPublic checks if ip is a unicast address on the public internet
+func IsPublic(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, network := range reservedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// Options configures a client
+type Options struct {
+	// Timeout bounds each request, redirects included
+	Timeout time.Duration
+
+	// AllowPrivate lets the client reach any address, for local
+	// development against fake servers. It must stay off in production.
+	AllowPrivate bool
+
+	// HTTPSOnly refuses plain http URLs, redirects included
+	HTTPSOnly bool
+}
+
+// NewClient creates an HTTP client that only reaches public addresses.
+// Proxies from the environment are not used, since the address checked
+// would be the proxy's.
+func NewClient(opts Options) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   opts.Timeout,
+		KeepAlive: 30 * time.Second,
+	}
+	if !opts.AllowPrivate {
+		dialer.Control = checkAddress
+	}
+
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+
+	return &http.Client{
+		Transport: &guardedTransport{base: transport, httpsOnly: opts.HTTPSOnly},
+		Timeout:   opts.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			return nil
+		},
+	}
+}
+
+// checkAddress refuses connections to addresses that are not public. It
+// sees the address actually dialed, after DNS resolution.
+func checkAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublic(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	return nil
+}
+
+// guardedTransport checks the URL of every request, redirects included,
+// before handing it to the base transport
+type guardedTransport struct {
+	base      *http.Transport
+	httpsOnly bool
+}
+
+func (t *guardedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.URL.Scheme {
+	case "https":
+	case "http":
+		if t.httpsOnly {
+			closeBody(req)
+			return nil, ErrInsecureURL
+		}
+	default:
+		closeBody(req)
+		return nil, fmt.Errorf("unsupported url scheme %q", req.URL.Scheme)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// CloseIdleConnections closes the base transport's idle connections
+func (t *guardedTransport) CloseIdleConnections() {
+	t.base.CloseIdleConnections()
+}
+
+// closeBody closes the body of a request that is refused, as RoundTrip
+// must
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
@@ -0,0 +1,105 @@
+package safehttp
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fc00::1", false},
+		{"0.0.0.0", false},
+		{"100.64.0.1", false},
+		{"198.18.0.1", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+		{"64:ff9b::7f00:1", false},
+		{"2002:7f00:1::", false},
+		{"2001:db8::1", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsPublic(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("IsPublic(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+// countingServer is a local server counting the requests it gets
+func countingServer(t *testing.T) (*httptest.Server, *int32) {
+	t.Helper()
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestClientRefusesLoopback(t *testing.T) {
+	server, requests := countingServer(t)
+	client := NewClient(Options{Timeout: 5 * time.Second})
+
+	_, err := client.Get(server.URL)
+	if !errors.Is(err, ErrForbiddenAddress) {
+		t.Fatalf("Get error = %v, want %v", err, ErrForbiddenAddress)
+	}
+	if n := atomic.LoadInt32(requests); n != 0 {
+		t.Errorf("server got %d requests, want none", n)
+	}
+}
+
+func TestClientAllowPrivate(t *testing.T) {
+	server, requests := countingServer(t)
+	client := NewClient(Options{Timeout: 5 * time.Second, AllowPrivate: true})
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("server got %d requests, want 1", n)
+	}
+}
+
+func TestClientHTTPSOnly(t *testing.T) {
+	server, requests := countingServer(t)
+	client := NewClient(Options{Timeout: 5 * time.Second, AllowPrivate: true, HTTPSOnly: true})
+
+	_, err := client.Get(server.URL)
+	if !errors.Is(err, ErrInsecureURL) {
+		t.Fatalf("Get error = %v, want %v", err, ErrInsecureURL)
+	}
+
+	// Redirects to plain http are refused too
+	secure := httptest.NewTLSServer(http.RedirectHandler(server.URL, http.StatusFound))
+	t.Cleanup(secure.Close)
+	client.Transport.(*guardedTransport).base.TLSClientConfig = secure.Client().Transport.(*http.Transport).TLSClientConfig
+
+	_, err = client.Get(secure.URL)
+	if !errors.Is(err, ErrInsecureURL) {
+		t.Fatalf("Get after redirect error = %v, want %v", err, ErrInsecureURL)
+	}
+	if n := atomic.LoadInt32(requests); n != 0 {
+		t.Errorf("http server got %d requests, want none", n)
+	}
+}
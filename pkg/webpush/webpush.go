@@ -0,0 +1,322 @@
+// Package webpush encrypts Web Push messages (RFC 8291, with the aes128gcm
+// content coding of RFC 8188) and identifies the sending server to push
+// services with VAPID (RFC 8292). It also decrypts messages and verifies
+// VAPID headers, which is what a push service and a browser do, for
+// testing.
+//
+// Keys are P-256 keys. Public keys travel as uncompressed points and
+// private keys as their 32-byte scalar, both base64url encoded.
+package webpush
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// recordSize is the record size messages are encrypted with; a message
+	// is a single record
+	recordSize = 4096
+
+	// headerSize is the size of the aes128gcm header carrying the salt,
+	// the record size and the sender's public key
+	headerSize = 16 + 4 + 1 + 65
+
+	// MaxPayloadSize is the largest payload that fits in a message: push
+	// services accept 4096-byte bodies, which hold the header, the
+	// padding delimiter and the authentication tag besides the payload
+	MaxPayloadSize = recordSize - headerSize - 1 - 16
+
+	// maxVAPIDLifetime is the longest a VAPID token may be valid for
+	maxVAPIDLifetime = 24 * time.Hour
+)
+
+var (
+	// ErrInvalidKey is returned for keys that are not valid P-256 keys
+	ErrInvalidKey = errors.New("invalid web push key")
+
+	// ErrPayloadTooLarge is returned for payloads over MaxPayloadSize
+	ErrPayloadTooLarge = errors.New("web push payload too large")
+
+	// ErrDecrypt is returned for messages that cannot be decrypted
+	ErrDecrypt = errors.New("cannot decrypt web push message")
+
+	// ErrInvalidVAPID is returned for missing, malformed or forged VAPID
+	// headers
+	ErrInvalidVAPID = errors.New("invalid vapid authorization")
+)
+
+// GenerateKey creates a new P-256 key pair
+func GenerateKey() (*ecdsa.PrivateKey, error) {
+	return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+}
+
+// EncodePrivateKey encodes a private key as its base64url scalar
+func EncodePrivateKey(key *ecdsa.PrivateKey) string {
+	return base64.RawURLEncoding.EncodeToString(key.D.FillBytes(make([]byte, 32)))
+}
+
+// ParsePrivateKey decodes a private key encoded by EncodePrivateKey
+func ParsePrivateKey(encoded string) (*ecdsa.PrivateKey, error) {
+	d, err := DecodeKey(encoded)
+	if err != nil || len(d) != 32 {
+		return nil, ErrInvalidKey
+	}
+
+	curve := elliptic.P256()
+	scalar := new(big.Int).SetBytes(d)
+	if scalar.Sign() == 0 || scalar.Cmp(curve.Params().N) >= 0 {
+		return nil, ErrInvalidKey
+	}
+
+	key := &ecdsa.PrivateKey{D: scalar}
+	key.PublicKey.Curve = curve
+	key.PublicKey.X, key.PublicKey.Y = curve.ScalarBaseMult(d)
+	return key, nil
+}
+
+// EncodePublicKey encodes a public key as its base64url uncompressed point,
+// the form browsers take as applicationServerKey
+func EncodePublicKey(key *ecdsa.PublicKey) string {
+	return base64.RawURLEncoding.EncodeToString(elliptic.Marshal(elliptic.P256(), key.X, key.Y))
+}
+
+// ParsePublicKey decodes an uncompressed point, such as a subscription's
+// p256dh key
+func ParsePublicKey(point []byte) (*ecdsa.PublicKey, error) {
+	curve := elliptic.P256()
+	x, y := elliptic.Unmarshal(curve, point)
+	if x == nil {
+		return nil, ErrInvalidKey
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+// DecodeKey decodes base64url with or without padding, as browsers and
+// libraries disagree on it, and standard base64 too
+func DecodeKey(encoded string) ([]byte, error) {
+	encoded = strings.TrimRight(strings.TrimSpace(encoded), "=")
+	encoded = strings.NewReplacer("+", "-", "/", "_").Replace(encoded)
+	return base64.RawURLEncoding.DecodeString(encoded)
+}
+
+// Encrypt encrypts a payload for a subscription with the given p256dh
+// public key and auth secret
+func Encrypt(payload, p256dh, authSecret []byte) ([]byte, error) {
+	if len(payload) > MaxPayloadSize {
+		return nil, ErrPayloadTooLarge
+	}
+	receiver, err := ParsePublicKey(p256dh)
+	if err != nil {
+		return nil, err
+	}
+	if len(authSecret) != 16 {
+		return nil, ErrInvalidKey
+	}
+
+	// Every message is encrypted with a fresh key pair and salt
+	sender, err := GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return encrypt(payload, receiver, authSecret, sender, salt)
+}
+
+// encrypt encrypts a payload with the given sender key pair and salt
+func encrypt(payload []byte, receiver *ecdsa.PublicKey, authSecret []byte, sender *ecdsa.PrivateKey, salt []byte) ([]byte, error) {
+	receiverPublic := elliptic.Marshal(elliptic.P256(), receiver.X, receiver.Y)
+	senderPublic := elliptic.Marshal(elliptic.P256(), sender.X, sender.Y)
+	aead, nonce, err := contentKey(sharedSecret(sender, receiver), authSecret, receiverPublic, senderPublic, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	// A single record ends with the last-record delimiter and no padding
+	record := append(append([]byte{}, payload...), 0x02)
+
+	body := make([]byte, 0, headerSize+len(record)+aead.Overhead())
+	body = append(body, salt...)
+	body = binary.BigEndian.AppendUint32(body, recordSize)
+	body = append(body, byte(len(senderPublic)))
+	body = append(body, senderPublic...)
+	return aead.Seal(body, nonce, record, nil), nil
+}
+
+// Decrypt decrypts a message sent to the subscription whose private key
+// and auth secret are given
+func Decrypt(body []byte, receiver *ecdsa.PrivateKey, authSecret []byte) ([]byte, error) {
+	if len(body) < 21 {
+		return nil, ErrDecrypt
+	}
+	salt := body[:16]
+	idLength := int(body[20])
+	if len(body) < 21+idLength {
+		return nil, ErrDecrypt
+	}
+	sender, err := ParsePublicKey(body[21 : 21+idLength])
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	receiverPublic := elliptic.Marshal(elliptic.P256(), receiver.X, receiver.Y)
+	aead, nonce, err := contentKey(sharedSecret(receiver, sender), authSecret, receiverPublic, body[21:21+idLength], salt)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := aead.Open(nil, nonce, body[21+idLength:], nil)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	// Strip the padding back to the delimiter
+	end := len(record) - 1
+	for end >= 0 && record[end] == 0 {
+		end--
+	}
+	if end < 0 || record[end] != 0x02 {
+		return nil, ErrDecrypt
+	}
+	return record[:end], nil
+}
+
+// sharedSecret computes the ECDH secret of a private and a public key
+func sharedSecret(private *ecdsa.PrivateKey, public *ecdsa.PublicKey) []byte {
+	x, _ := elliptic.P256().ScalarMult(public.X, public.Y, private.D.FillBytes(make([]byte, 32)))
+	return x.FillBytes(make([]byte, 32))
+}
+
+// contentKey derives the AES-GCM key and nonce of a message from the ECDH
+// secret, as RFC 8291 section 3.4 describes
+func contentKey(secret, authSecret, receiverPublic, senderPublic, salt []byte) (cipher.AEAD, []byte, error) {
+	keyInfo := append([]byte("WebPush: info\x00"), receiverPublic...)
+	keyInfo = append(keyInfo, senderPublic...)
+	ikm := hkdfExpand(hkdfExtract(authSecret, secret), keyInfo, 32)
+
+	prk := hkdfExtract(salt, ikm)
+	key := hkdfExpand(prk, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce := hkdfExpand(prk, []byte("Content-Encoding: nonce\x00"), 12)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return aead, nonce, nil
+}
+
+// hkdfExtract is HKDF-Extract with SHA-256 (RFC 5869)
+func hkdfExtract(salt, ikm []byte) []byte {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write(ikm)
+	return mac.Sum(nil)
+}
+
+// hkdfExpand is HKDF-Expand with SHA-256 for up to 32 bytes, all Web Push
+// needs
+func hkdfExpand(prk, info []byte, length int) []byte {
+	mac := hmac.New(sha256.New, prk)
+	mac.Write(info)
+	mac.Write([]byte{1})
+	return mac.Sum(nil)[:length]
+}
+
+// VAPIDHeader returns the Authorization header identifying the server
+// holding key to the push service of endpoint. subject is a mailto: or
+// https: URL the push service can reach the server's operator at.
+func VAPIDHeader(endpoint, subject string, key *ecdsa.PrivateKey, expires time.Time) (string, error) {
+	audience, err := origin(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": audience,
+		"exp": expires.Unix(),
+		"sub": subject,
+	}).SignedString(key)
+	if err != nil {
+		return "", fmt.Errorf("sign vapid token: %w", err)
+	}
+	return "vapid t=" + token + ", k=" + EncodePublicKey(&key.PublicKey), nil
+}
+
+// VerifyVAPIDHeader checks the Authorization header of a push sent to
+// endpoint and returns the sender's public key and subject
+func VerifyVAPIDHeader(header, endpoint string) (publicKey, subject string, err error) {
+	params := strings.TrimPrefix(header, "vapid ")
+	ok := params != header
+	if !ok {
+		return "", "", ErrInvalidVAPID
+	}
+	var token string
+	for _, param := range strings.Split(params, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		switch name {
+		case "t":
+			token = value
+		case "k":
+			publicKey = value
+		}
+	}
+
+	point, err := DecodeKey(publicKey)
+	if err != nil {
+		return "", "", ErrInvalidVAPID
+	}
+	key, err := ParsePublicKey(point)
+	if err != nil {
+		return "", "", ErrInvalidVAPID
+	}
+	audience, err := origin(endpoint)
+	if err != nil {
+		return "", "", err
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return key, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodES256.Alg()}), jwt.WithAudience(audience), jwt.WithExpirationRequired())
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %v", ErrInvalidVAPID, err)
+	}
+
+	expires, _ := claims.GetExpirationTime()
+	if time.Until(expires.Time) > maxVAPIDLifetime {
+		return "", "", fmt.Errorf("%w: expires more than 24 hours ahead", ErrInvalidVAPID)
+	}
+	subject, _ = claims.GetSubject()
+	return publicKey, subject, nil
+}
+
+// origin returns the origin of a push endpoint, the audience of its VAPID
+// tokens
+func origin(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("invalid push endpoint %q", endpoint)
+	}
+	return u.Scheme + "://" + u.Host, nil
+}
@@ -0,0 +1,123 @@
+package webpush
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"testing"
+	"time"
+)
+
+// rfc8291 is the example of RFC 8291, Appendix A
+var rfc8291 = struct {
+	plaintext, salt, authSecret          string
+	senderPrivate, receiverPrivate, body string
+}{
+	plaintext:       "When I grow up, I want to be a watermelon",
+	salt:            "DGv6ra1nlYgDCS1FRnbzlw",
+	authSecret:      "BTBZMqHH6r4Tts7J_aSIgg",
+	senderPrivate:   "yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw",
+	receiverPrivate: "q1dXpw3UpT5VOmu_cf_v6ih07Aems3njxI-JWgLcM94",
+	body: "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_" +
+		"yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN",
+}
+
+func mustDecode(t *testing.T, encoded string) []byte {
+	t.Helper()
+	decoded, err := DecodeKey(encoded)
+	if err != nil {
+		t.Fatalf("DecodeKey(%q): %v", encoded, err)
+	}
+	return decoded
+}
+
+func mustParsePrivateKey(t *testing.T, encoded string) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ParsePrivateKey(encoded)
+	if err != nil {
+		t.Fatalf("ParsePrivateKey(%q): %v", encoded, err)
+	}
+	return key
+}
+
+func TestEncryptRFC8291Vector(t *testing.T) {
+	sender := mustParsePrivateKey(t, rfc8291.senderPrivate)
+	receiver := mustParsePrivateKey(t, rfc8291.receiverPrivate)
+
+	body, err := encrypt([]byte(rfc8291.plaintext), &receiver.PublicKey, mustDecode(t, rfc8291.authSecret), sender, mustDecode(t, rfc8291.salt))
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if want := mustDecode(t, rfc8291.body); !bytes.Equal(body, want) {
+		t.Errorf("body =\n%x\nwant\n%x", body, want)
+	}
+}
+
+func TestDecryptRFC8291Vector(t *testing.T) {
+	receiver := mustParsePrivateKey(t, rfc8291.receiverPrivate)
+
+	plaintext, err := Decrypt(mustDecode(t, rfc8291.body), receiver, mustDecode(t, rfc8291.authSecret))
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if string(plaintext) != rfc8291.plaintext {
+		t.Errorf("plaintext = %q, want %q", plaintext, rfc8291.plaintext)
+	}
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	receiver, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	p256dh := mustDecode(t, EncodePublicKey(&receiver.PublicKey))
+	authSecret := []byte("0123456789abcdef")
+	payload := []byte(`{"title":"New follower"}`)
+
+	body, err := Encrypt(payload, p256dh, authSecret)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	plaintext, err := Decrypt(body, receiver, authSecret)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if !bytes.Equal(plaintext, payload) {
+		t.Errorf("plaintext = %q, want %q", plaintext, payload)
+	}
+
+	if _, err := Decrypt(body, receiver, []byte("fedcba9876543210")); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Decrypt with another auth secret error = %v, want %v", err, ErrDecrypt)
+	}
+	if _, err := Encrypt(make([]byte, MaxPayloadSize+1), p256dh, authSecret); !errors.Is(err, ErrPayloadTooLarge) {
+		t.Errorf("Encrypt of an oversized payload error = %v, want %v", err, ErrPayloadTooLarge)
+	}
+}
+
+func TestVAPIDHeader(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	const endpoint = "https://push.example/send/abc"
+
+	header, err := VAPIDHeader(endpoint, "mailto:admin@blogo.example", key, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("VAPIDHeader: %v", err)
+	}
+
+	publicKey, subject, err := VerifyVAPIDHeader(header, endpoint)
+	if err != nil {
+		t.Fatalf("VerifyVAPIDHeader: %v", err)
+	}
+	if publicKey != EncodePublicKey(&key.PublicKey) {
+		t.Errorf("public key = %q, want %q", publicKey, EncodePublicKey(&key.PublicKey))
+	}
+	if subject != "mailto:admin@blogo.example" {
+		t.Errorf("subject = %q, want %q", subject, "mailto:admin@blogo.example")
+	}
+
+	if _, _, err := VerifyVAPIDHeader(header, "https://other.example/send/abc"); !errors.Is(err, ErrInvalidVAPID) {
+		t.Errorf("VerifyVAPIDHeader for another origin error = %v, want %v", err, ErrInvalidVAPID)
+	}
+}
@@ -0,0 +1,359 @@
+// Package xmlrpc decodes XML-RPC method calls and encodes their responses
+// (http://xmlrpc.com/spec.md).
+//
+// Values map onto Go as follows: string, int, bool, float64, time.Time,
+// []byte for base64, map[string]interface{} for structs, []interface{} for
+// arrays and nil for <nil/>.
+package xmlrpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fault codes used for errors in the request itself
+const (
+	FaultParse          = -32700
+	FaultMethodNotFound = -32601
+	FaultInvalidParams  = -32602
+)
+
+// dateTimeFormat is how dateTime.iso8601 values are written
+const dateTimeFormat = "20060102T15:04:05Z"
+
+// dateTimeLayouts are the dateTime.iso8601 forms clients are known to send
+var dateTimeLayouts = []string{
+	"20060102T15:04:05Z07:00",
+	"20060102T15:04:05",
+	"20060102T150405Z07:00",
+	"20060102T150405",
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+}
+
+// ErrInvalidCall is returned for request bodies that are not XML-RPC calls
+var ErrInvalidCall = errors.New("invalid xml-rpc call")
+
+// Fault is an XML-RPC fault, the error response of a method
+type Fault struct {
+	Code   int
+	String string
+}
+
+// Error implements error
+func (f *Fault) Error() string {
+	return fmt.Sprintf("xml-rpc fault %d: %s", f.Code, f.String)
+}
+
+// Call is a decoded method call
+type Call struct {
+	Method string
+	Params []interface{}
+}
+
+// methodCall is the XML form of a call
+type methodCall struct {
+	XMLName    xml.Name `xml:"methodCall"`
+	MethodName string   `xml:"methodName"`
+	Params     []value  `xml:"params>param>value"`
+}
+
+// value is the XML form of a value; exactly one member is set, or none for
+// an untyped value, which is a string
+type value struct {
+	String   *string      `xml:"string"`
+	Int      *string      `xml:"int"`
+	I4       *string      `xml:"i4"`
+	I8       *string      `xml:"i8"`
+	Boolean  *string      `xml:"boolean"`
+	Double   *string      `xml:"double"`
+	DateTime *string      `xml:"dateTime.iso8601"`
+	Base64   *string      `xml:"base64"`
+	Struct   *structValue `xml:"struct"`
+	Array    *arrayValue  `xml:"array"`
+	Nil      *struct{}    `xml:"nil"`
+	Text     string       `xml:",chardata"`
+}
+
+// structValue is the XML form of a struct
+type structValue struct {
+	Members []member `xml:"member"`
+}
+
+// arrayValue is the XML form of an array
+type arrayValue struct {
+	Values []value `xml:"data>value"`
+}
+
+// member is a member of a struct value
+type member struct {
+	Name  string `xml:"name"`
+	Value value  `xml:"value"`
+}
+
+// ParseCall decodes a method call
+func ParseCall(r io.Reader) (*Call, error) {
+	var doc methodCall
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCall, err)
+	}
+
+	call := &Call{Method: strings.TrimSpace(doc.MethodName)}
+	if call.Method == "" {
+		return nil, ErrInvalidCall
+	}
+	for _, v := range doc.Params {
+		param, err := v.decode()
+		if err != nil {
+			return nil, err
+		}
+		call.Params = append(call.Params, param)
+	}
+	return call, nil
+}
+
+// decode converts a value to its Go form
+func (v *value) decode() (interface{}, error) {
+	switch {
+	case v.String != nil:
+		return *v.String, nil
+	case v.Int != nil, v.I4 != nil, v.I8 != nil:
+		text := v.Int
+		if text == nil {
+			text = v.I4
+		}
+		if text == nil {
+			text = v.I8
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(*text))
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad int %q", ErrInvalidCall, *text)
+		}
+		return n, nil
+	case v.Boolean != nil:
+		switch strings.TrimSpace(*v.Boolean) {
+		case "1":
+			return true, nil
+		case "0":
+			return false, nil
+		}
+		return nil, fmt.Errorf("%w: bad boolean %q", ErrInvalidCall, *v.Boolean)
+	case v.Double != nil:
+		f, err := strconv.ParseFloat(strings.TrimSpace(*v.Double), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad double %q", ErrInvalidCall, *v.Double)
+		}
+		return f, nil
+	case v.DateTime != nil:
+		text := strings.TrimSpace(*v.DateTime)
+		for _, layout := range dateTimeLayouts {
+			if t, err := time.Parse(layout, text); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: bad dateTime %q", ErrInvalidCall, text)
+	case v.Base64 != nil:
+		// Line breaks are common in base64 values
+		data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(*v.Base64), ""))
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad base64", ErrInvalidCall)
+		}
+		return data, nil
+	case v.Struct != nil:
+		members := map[string]interface{}{}
+		for _, m := range v.Struct.Members {
+			decoded, err := m.Value.decode()
+			if err != nil {
+				return nil, err
+			}
+			members[m.Name] = decoded
+		}
+		return members, nil
+	case v.Array != nil:
+		items := []interface{}{}
+		for _, item := range v.Array.Values {
+			decoded, err := item.decode()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, decoded)
+		}
+		return items, nil
+	case v.Nil != nil:
+		return nil, nil
+	}
+	return v.Text, nil
+}
+
+// String returns the string parameter at i. Integers are accepted too, as
+// clients disagree on the type of IDs.
+func (c *Call) String(i int) (string, error) {
+	if i < len(c.Params) {
+		switch v := c.Params[i].(type) {
+		case string:
+			return v, nil
+		case int:
+			return strconv.Itoa(v), nil
+		}
+	}
+	return "", c.invalidParam(i, "string")
+}
+
+// Int returns the integer parameter at i. Strings holding an integer are
+// accepted too.
+func (c *Call) Int(i int) (int, error) {
+	if i < len(c.Params) {
+		switch v := c.Params[i].(type) {
+		case int:
+			return v, nil
+		case string:
+			if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+				return n, nil
+			}
+		}
+	}
+	return 0, c.invalidParam(i, "int")
+}
+
+// Bool returns the boolean parameter at i, or fallback when the call has
+// fewer parameters
+func (c *Call) Bool(i int, fallback bool) (bool, error) {
+	if i >= len(c.Params) {
+		return fallback, nil
+	}
+	if v, ok := c.Params[i].(bool); ok {
+		return v, nil
+	}
+	return false, c.invalidParam(i, "boolean")
+}
+
+// Struct returns the struct parameter at i
+func (c *Call) Struct(i int) (map[string]interface{}, error) {
+	if i < len(c.Params) {
+		if v, ok := c.Params[i].(map[string]interface{}); ok {
+			return v, nil
+		}
+	}
+	return nil, c.invalidParam(i, "struct")
+}
+
+// invalidParam returns the fault for a missing or mistyped parameter
+func (c *Call) invalidParam(i int, kind string) *Fault {
+	return &Fault{
+		Code:   FaultInvalidParams,
+		String: fmt.Sprintf("%s expects a %s as parameter %d", c.Method, kind, i+1),
+	}
+}
+
+// WriteResponse writes a method response carrying result
+func WriteResponse(w io.Writer, result interface{}) error {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString("<methodResponse><params><param>")
+	if err := encode(&buf, result); err != nil {
+		return err
+	}
+	buf.WriteString("</param></params></methodResponse>\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// WriteFault writes a fault response
+func WriteFault(w io.Writer, fault *Fault) error {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString("<methodResponse><fault>")
+	if err := encode(&buf, map[string]interface{}{
+		"faultCode":   fault.Code,
+		"faultString": fault.String,
+	}); err != nil {
+		return err
+	}
+	buf.WriteString("</fault></methodResponse>\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// encode writes a Go value as an XML-RPC value. Struct members are written
+// in name order.
+func encode(buf *bytes.Buffer, v interface{}) error {
+	buf.WriteString("<value>")
+	switch v := v.(type) {
+	case nil:
+		buf.WriteString("<nil/>")
+	case string:
+		buf.WriteString("<string>")
+		xml.EscapeText(buf, []byte(v))
+		buf.WriteString("</string>")
+	case int:
+		fmt.Fprintf(buf, "<int>%d</int>", v)
+	case int64:
+		fmt.Fprintf(buf, "<int>%d</int>", v)
+	case bool:
+		if v {
+			buf.WriteString("<boolean>1</boolean>")
+		} else {
+			buf.WriteString("<boolean>0</boolean>")
+		}
+	case float64:
+		fmt.Fprintf(buf, "<double>%s</double>", strconv.FormatFloat(v, 'f', -1, 64))
+	case time.Time:
+		fmt.Fprintf(buf, "<dateTime.iso8601>%s</dateTime.iso8601>", v.UTC().Format(dateTimeFormat))
+	case []byte:
+		fmt.Fprintf(buf, "<base64>%s</base64>", base64.StdEncoding.EncodeToString(v))
+	case []string:
+		buf.WriteString("<array><data>")
+		for _, item := range v {
+			if err := encode(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("</data></array>")
+	case []interface{}:
+		buf.WriteString("<array><data>")
+		for _, item := range v {
+			if err := encode(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("</data></array>")
+	case []map[string]interface{}:
+		buf.WriteString("<array><data>")
+		for _, item := range v {
+			if err := encode(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("</data></array>")
+	case map[string]interface{}:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		buf.WriteString("<struct>")
+		for _, name := range names {
+			buf.WriteString("<member><name>")
+			xml.EscapeText(buf, []byte(name))
+			buf.WriteString("</name>")
+			if err := encode(buf, v[name]); err != nil {
+				return err
+			}
+			buf.WriteString("</member>")
+		}
+		buf.WriteString("</struct>")
+	default:
+		return fmt.Errorf("xmlrpc: cannot encode %T", v)
+	}
+	buf.WriteString("</value>")
+	return nil
+}